@@ -0,0 +1,463 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	adminUsersSuffix    = "/organization/users"
+	adminInvitesSuffix  = "/organization/invites"
+	adminProjectsSuffix = "/organization/projects"
+)
+
+// AdminClient groups the organization administration endpoints.
+// These endpoints require an admin API key, so the parent Client should be configured with one.
+type AdminClient struct {
+	client *Client
+}
+
+// Admin returns the organization administration API namespace.
+func (c *Client) Admin() *AdminClient {
+	return &AdminClient{client: c}
+}
+
+// AdminList is a paginated list returned by the administration endpoints.
+type AdminList[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+
+	httpHeader
+}
+
+// AdminListParams are the pagination parameters of the administration list endpoints.
+type AdminListParams struct {
+	Limit *int
+	After *string
+}
+
+func (p AdminListParams) values() url.Values {
+	urlValues := url.Values{}
+	if p.Limit != nil {
+		urlValues.Add("limit", fmt.Sprintf("%d", *p.Limit))
+	}
+	if p.After != nil {
+		urlValues.Add("after", *p.After)
+	}
+	return urlValues
+}
+
+// AdminDeleteResponse is returned when an administration object is deleted.
+type AdminDeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+
+	httpHeader
+}
+
+// OrganizationRole is the role of a user within the organization.
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleReader OrganizationRole = "reader"
+)
+
+// ProjectRole is the role of a user or service account within a project.
+type ProjectRole string
+
+const (
+	ProjectRoleOwner  ProjectRole = "owner"
+	ProjectRoleMember ProjectRole = "member"
+)
+
+// OrganizationUser represents a member of the organization.
+type OrganizationUser struct {
+	Object  string           `json:"object"`
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Email   string           `json:"email"`
+	Role    OrganizationRole `json:"role"`
+	AddedAt int64            `json:"added_at"`
+
+	httpHeader
+}
+
+// OrganizationUserRequest modifies the role of an organization user.
+type OrganizationUserRequest struct {
+	Role OrganizationRole `json:"role"`
+}
+
+// Invite represents an invitation to join the organization.
+type Invite struct {
+	Object     string           `json:"object"`
+	ID         string           `json:"id"`
+	Email      string           `json:"email"`
+	Role       OrganizationRole `json:"role"`
+	Status     string           `json:"status"`
+	InvitedAt  int64            `json:"invited_at"`
+	ExpiresAt  int64            `json:"expires_at"`
+	AcceptedAt *int64           `json:"accepted_at,omitempty"`
+	Projects   []InviteProject  `json:"projects,omitempty"`
+
+	httpHeader
+}
+
+// InviteProject grants an invited user access to a project.
+type InviteProject struct {
+	ID   string      `json:"id"`
+	Role ProjectRole `json:"role"`
+}
+
+// InviteRequest creates an invitation.
+type InviteRequest struct {
+	Email    string           `json:"email"`
+	Role     OrganizationRole `json:"role"`
+	Projects []InviteProject  `json:"projects,omitempty"`
+}
+
+// Project represents an organization project.
+type Project struct {
+	Object     string `json:"object"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"created_at"`
+	ArchivedAt *int64 `json:"archived_at,omitempty"`
+	Status     string `json:"status"`
+
+	httpHeader
+}
+
+// ProjectRequest creates or modifies a project.
+type ProjectRequest struct {
+	Name string `json:"name"`
+}
+
+// ProjectUser represents a member of a project.
+type ProjectUser struct {
+	Object  string      `json:"object"`
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Email   string      `json:"email"`
+	Role    ProjectRole `json:"role"`
+	AddedAt int64       `json:"added_at"`
+
+	httpHeader
+}
+
+// ProjectUserRequest adds a user to a project or modifies their role.
+// UserID is only used when adding a user.
+type ProjectUserRequest struct {
+	UserID string      `json:"user_id,omitempty"`
+	Role   ProjectRole `json:"role"`
+}
+
+// ProjectAPIKeyOwner describes the user or service account owning a project API key.
+type ProjectAPIKeyOwner struct {
+	Type           string                 `json:"type"`
+	User           *ProjectUser           `json:"user,omitempty"`
+	ServiceAccount *ProjectServiceAccount `json:"service_account,omitempty"`
+}
+
+// ProjectAPIKey represents an API key of a project. The key value itself is redacted.
+type ProjectAPIKey struct {
+	Object        string             `json:"object"`
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	RedactedValue string             `json:"redacted_value"`
+	CreatedAt     int64              `json:"created_at"`
+	Owner         ProjectAPIKeyOwner `json:"owner"`
+
+	httpHeader
+}
+
+// ProjectServiceAccount represents a service account of a project.
+type ProjectServiceAccount struct {
+	Object    string      `json:"object"`
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Role      ProjectRole `json:"role"`
+	CreatedAt int64       `json:"created_at"`
+
+	httpHeader
+}
+
+// ProjectServiceAccountAPIKey is the unredacted key returned when a service account is created.
+type ProjectServiceAccountAPIKey struct {
+	Object    string `json:"object"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// ProjectServiceAccountCreateResponse is returned when a service account is created.
+type ProjectServiceAccountCreateResponse struct {
+	ProjectServiceAccount
+	APIKey ProjectServiceAccountAPIKey `json:"api_key"`
+}
+
+// ProjectServiceAccountRequest creates a service account.
+type ProjectServiceAccountRequest struct {
+	Name string `json:"name"`
+}
+
+func (a *AdminClient) do(ctx context.Context, method, urlSuffix string, body any, v Response) error {
+	setters := []requestOption{}
+	if body != nil {
+		setters = append(setters, withBody(body))
+	}
+	req, err := a.client.newRequest(ctx, method, a.client.fullURL(urlSuffix), setters...)
+	if err != nil {
+		return err
+	}
+	return a.client.sendRequest(req, v)
+}
+
+func withQuery(urlSuffix string, urlValues url.Values) string {
+	if len(urlValues) == 0 {
+		return urlSuffix
+	}
+	return urlSuffix + "?" + urlValues.Encode()
+}
+
+// ListUsers lists the users of the organization, optionally filtered by emails.
+func (a *AdminClient) ListUsers(
+	ctx context.Context,
+	params AdminListParams,
+	emails ...string,
+) (response AdminList[OrganizationUser], err error) {
+	urlValues := params.values()
+	for _, email := range emails {
+		urlValues.Add("emails", email)
+	}
+	err = a.do(ctx, http.MethodGet, withQuery(adminUsersSuffix, urlValues), nil, &response)
+	return
+}
+
+// RetrieveUser retrieves an organization user.
+func (a *AdminClient) RetrieveUser(ctx context.Context, userID string) (response OrganizationUser, err error) {
+	err = a.do(ctx, http.MethodGet, adminUsersSuffix+"/"+userID, nil, &response)
+	return
+}
+
+// ModifyUser modifies the organization role of a user.
+func (a *AdminClient) ModifyUser(
+	ctx context.Context,
+	userID string,
+	request OrganizationUserRequest,
+) (response OrganizationUser, err error) {
+	err = a.do(ctx, http.MethodPost, adminUsersSuffix+"/"+userID, request, &response)
+	return
+}
+
+// DeleteUser removes a user from the organization.
+func (a *AdminClient) DeleteUser(ctx context.Context, userID string) (response AdminDeleteResponse, err error) {
+	err = a.do(ctx, http.MethodDelete, adminUsersSuffix+"/"+userID, nil, &response)
+	return
+}
+
+// ListInvites lists the pending and accepted invites of the organization.
+func (a *AdminClient) ListInvites(ctx context.Context, params AdminListParams) (response AdminList[Invite], err error) {
+	err = a.do(ctx, http.MethodGet, withQuery(adminInvitesSuffix, params.values()), nil, &response)
+	return
+}
+
+// CreateInvite invites a user to the organization.
+func (a *AdminClient) CreateInvite(ctx context.Context, request InviteRequest) (response Invite, err error) {
+	err = a.do(ctx, http.MethodPost, adminInvitesSuffix, request, &response)
+	return
+}
+
+// RetrieveInvite retrieves an invite.
+func (a *AdminClient) RetrieveInvite(ctx context.Context, inviteID string) (response Invite, err error) {
+	err = a.do(ctx, http.MethodGet, adminInvitesSuffix+"/"+inviteID, nil, &response)
+	return
+}
+
+// DeleteInvite deletes a pending invite.
+func (a *AdminClient) DeleteInvite(ctx context.Context, inviteID string) (response AdminDeleteResponse, err error) {
+	err = a.do(ctx, http.MethodDelete, adminInvitesSuffix+"/"+inviteID, nil, &response)
+	return
+}
+
+// ListProjects lists the projects of the organization.
+func (a *AdminClient) ListProjects(
+	ctx context.Context,
+	params AdminListParams,
+	includeArchived bool,
+) (response AdminList[Project], err error) {
+	urlValues := params.values()
+	if includeArchived {
+		urlValues.Add("include_archived", "true")
+	}
+	err = a.do(ctx, http.MethodGet, withQuery(adminProjectsSuffix, urlValues), nil, &response)
+	return
+}
+
+// CreateProject creates a project.
+func (a *AdminClient) CreateProject(ctx context.Context, request ProjectRequest) (response Project, err error) {
+	err = a.do(ctx, http.MethodPost, adminProjectsSuffix, request, &response)
+	return
+}
+
+// RetrieveProject retrieves a project.
+func (a *AdminClient) RetrieveProject(ctx context.Context, projectID string) (response Project, err error) {
+	err = a.do(ctx, http.MethodGet, adminProjectsSuffix+"/"+projectID, nil, &response)
+	return
+}
+
+// ModifyProject modifies a project.
+func (a *AdminClient) ModifyProject(
+	ctx context.Context,
+	projectID string,
+	request ProjectRequest,
+) (response Project, err error) {
+	err = a.do(ctx, http.MethodPost, adminProjectsSuffix+"/"+projectID, request, &response)
+	return
+}
+
+// ArchiveProject archives a project. Archived projects cannot be used or updated.
+func (a *AdminClient) ArchiveProject(ctx context.Context, projectID string) (response Project, err error) {
+	err = a.do(ctx, http.MethodPost, adminProjectsSuffix+"/"+projectID+"/archive", nil, &response)
+	return
+}
+
+// ListProjectUsers lists the users of a project.
+func (a *AdminClient) ListProjectUsers(
+	ctx context.Context,
+	projectID string,
+	params AdminListParams,
+) (response AdminList[ProjectUser], err error) {
+	urlSuffix := withQuery(fmt.Sprintf("%s/%s/users", adminProjectsSuffix, projectID), params.values())
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// CreateProjectUser adds an organization user to a project.
+func (a *AdminClient) CreateProjectUser(
+	ctx context.Context,
+	projectID string,
+	request ProjectUserRequest,
+) (response ProjectUser, err error) {
+	err = a.do(ctx, http.MethodPost, fmt.Sprintf("%s/%s/users", adminProjectsSuffix, projectID), request, &response)
+	return
+}
+
+// RetrieveProjectUser retrieves a project user.
+func (a *AdminClient) RetrieveProjectUser(
+	ctx context.Context,
+	projectID string,
+	userID string,
+) (response ProjectUser, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/users/%s", adminProjectsSuffix, projectID, userID)
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// ModifyProjectUser modifies the project role of a user.
+func (a *AdminClient) ModifyProjectUser(
+	ctx context.Context,
+	projectID string,
+	userID string,
+	request ProjectUserRequest,
+) (response ProjectUser, err error) {
+	request.UserID = ""
+	urlSuffix := fmt.Sprintf("%s/%s/users/%s", adminProjectsSuffix, projectID, userID)
+	err = a.do(ctx, http.MethodPost, urlSuffix, request, &response)
+	return
+}
+
+// DeleteProjectUser removes a user from a project.
+func (a *AdminClient) DeleteProjectUser(
+	ctx context.Context,
+	projectID string,
+	userID string,
+) (response AdminDeleteResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/users/%s", adminProjectsSuffix, projectID, userID)
+	err = a.do(ctx, http.MethodDelete, urlSuffix, nil, &response)
+	return
+}
+
+// ListProjectAPIKeys lists the API keys of a project.
+func (a *AdminClient) ListProjectAPIKeys(
+	ctx context.Context,
+	projectID string,
+	params AdminListParams,
+) (response AdminList[ProjectAPIKey], err error) {
+	urlSuffix := withQuery(fmt.Sprintf("%s/%s/api_keys", adminProjectsSuffix, projectID), params.values())
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// RetrieveProjectAPIKey retrieves a project API key.
+func (a *AdminClient) RetrieveProjectAPIKey(
+	ctx context.Context,
+	projectID string,
+	keyID string,
+) (response ProjectAPIKey, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/api_keys/%s", adminProjectsSuffix, projectID, keyID)
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// DeleteProjectAPIKey deletes a project API key.
+func (a *AdminClient) DeleteProjectAPIKey(
+	ctx context.Context,
+	projectID string,
+	keyID string,
+) (response AdminDeleteResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/api_keys/%s", adminProjectsSuffix, projectID, keyID)
+	err = a.do(ctx, http.MethodDelete, urlSuffix, nil, &response)
+	return
+}
+
+// ListProjectServiceAccounts lists the service accounts of a project.
+func (a *AdminClient) ListProjectServiceAccounts(
+	ctx context.Context,
+	projectID string,
+	params AdminListParams,
+) (response AdminList[ProjectServiceAccount], err error) {
+	urlSuffix := withQuery(fmt.Sprintf("%s/%s/service_accounts", adminProjectsSuffix, projectID), params.values())
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// CreateProjectServiceAccount creates a service account and returns its unredacted API key.
+func (a *AdminClient) CreateProjectServiceAccount(
+	ctx context.Context,
+	projectID string,
+	request ProjectServiceAccountRequest,
+) (response ProjectServiceAccountCreateResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/service_accounts", adminProjectsSuffix, projectID)
+	err = a.do(ctx, http.MethodPost, urlSuffix, request, &response)
+	return
+}
+
+// RetrieveProjectServiceAccount retrieves a project service account.
+func (a *AdminClient) RetrieveProjectServiceAccount(
+	ctx context.Context,
+	projectID string,
+	serviceAccountID string,
+) (response ProjectServiceAccount, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/service_accounts/%s", adminProjectsSuffix, projectID, serviceAccountID)
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// DeleteProjectServiceAccount deletes a project service account.
+func (a *AdminClient) DeleteProjectServiceAccount(
+	ctx context.Context,
+	projectID string,
+	serviceAccountID string,
+) (response AdminDeleteResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/service_accounts/%s", adminProjectsSuffix, projectID, serviceAccountID)
+	err = a.do(ctx, http.MethodDelete, urlSuffix, nil, &response)
+	return
+}
@@ -0,0 +1,129 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestAdminUsersAndInvites(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/organization/users", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("limit") != "10" || query.Get("after") != "user_0" || len(query["emails"]) != 2 {
+			t.Errorf("unexpected users query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[{"object":"organization.user","id":"user_1","role":"owner"}],`+
+			`"first_id":"user_1","last_id":"user_1","has_more":false}`)
+	})
+	server.RegisterHandler("/v1/organization/users/user_1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var request openai.OrganizationUserRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			fmt.Fprintf(w, `{"object":"organization.user","id":"user_1","role":%q}`, request.Role)
+		case http.MethodDelete:
+			fmt.Fprintln(w, `{"object":"organization.user.deleted","id":"user_1","deleted":true}`)
+		default:
+			fmt.Fprintln(w, `{"object":"organization.user","id":"user_1","role":"owner"}`)
+		}
+	})
+	server.RegisterHandler("/v1/organization/invites", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.InviteRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		fmt.Fprintf(w, `{"object":"organization.invite","id":"invite_1","email":%q,"status":"pending"}`, request.Email)
+	})
+
+	ctx := context.Background()
+	admin := client.Admin()
+	limit, after := 10, "user_0"
+	users, err := admin.ListUsers(ctx, openai.AdminListParams{Limit: &limit, After: &after}, "a@b.c", "d@e.f")
+	checks.NoError(t, err, "ListUsers error")
+	if len(users.Data) != 1 || users.Data[0].Role != openai.OrganizationRoleOwner {
+		t.Errorf("unexpected users: %+v", users)
+	}
+
+	_, err = admin.RetrieveUser(ctx, "user_1")
+	checks.NoError(t, err, "RetrieveUser error")
+
+	user, err := admin.ModifyUser(ctx, "user_1", openai.OrganizationUserRequest{Role: openai.OrganizationRoleReader})
+	checks.NoError(t, err, "ModifyUser error")
+	if user.Role != openai.OrganizationRoleReader {
+		t.Errorf("unexpected role: %s", user.Role)
+	}
+
+	deleted, err := admin.DeleteUser(ctx, "user_1")
+	checks.NoError(t, err, "DeleteUser error")
+	if !deleted.Deleted {
+		t.Error("expected user to be deleted")
+	}
+
+	invite, err := admin.CreateInvite(ctx, openai.InviteRequest{Email: "new@b.c", Role: openai.OrganizationRoleReader})
+	checks.NoError(t, err, "CreateInvite error")
+	if invite.Email != "new@b.c" {
+		t.Errorf("unexpected invite: %+v", invite)
+	}
+}
+
+func TestAdminProjects(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/organization/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("include_archived") != "true" {
+				t.Errorf("unexpected projects query: %s", r.URL.RawQuery)
+			}
+			fmt.Fprintln(w, `{"object":"list","data":[{"id":"proj_1","name":"p"}]}`)
+			return
+		}
+		fmt.Fprintln(w, `{"object":"organization.project","id":"proj_1","name":"p","status":"active"}`)
+	})
+	server.RegisterHandler("/v1/organization/projects/proj_1/archive", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"object":"organization.project","id":"proj_1","status":"archived","archived_at":1}`)
+	})
+	server.RegisterHandler("/v1/organization/projects/proj_1/api_keys", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"object":"list","data":[{"id":"key_1","redacted_value":"sk-abc...def",`+
+			`"owner":{"type":"service_account","service_account":{"id":"svc_1"}}}]}`)
+	})
+	server.RegisterHandler("/v1/organization/projects/proj_1/service_accounts", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"object":"organization.project.service_account","id":"svc_1","name":"bot",`+
+			`"api_key":{"object":"organization.project.service_account.api_key","id":"key_1","value":"sk-secret"}}`)
+	})
+
+	ctx := context.Background()
+	admin := client.Admin()
+	projects, err := admin.ListProjects(ctx, openai.AdminListParams{}, true)
+	checks.NoError(t, err, "ListProjects error")
+	if len(projects.Data) != 1 {
+		t.Errorf("unexpected projects: %+v", projects)
+	}
+
+	_, err = admin.CreateProject(ctx, openai.ProjectRequest{Name: "p"})
+	checks.NoError(t, err, "CreateProject error")
+
+	project, err := admin.ArchiveProject(ctx, "proj_1")
+	checks.NoError(t, err, "ArchiveProject error")
+	if project.ArchivedAt == nil {
+		t.Error("expected archived_at to be set")
+	}
+
+	keys, err := admin.ListProjectAPIKeys(ctx, "proj_1", openai.AdminListParams{})
+	checks.NoError(t, err, "ListProjectAPIKeys error")
+	if keys.Data[0].Owner.ServiceAccount == nil || keys.Data[0].Owner.ServiceAccount.ID != "svc_1" {
+		t.Errorf("unexpected api keys: %+v", keys)
+	}
+
+	account, err := admin.CreateProjectServiceAccount(ctx, "proj_1", openai.ProjectServiceAccountRequest{Name: "bot"})
+	checks.NoError(t, err, "CreateProjectServiceAccount error")
+	if account.APIKey.Value != "sk-secret" || account.Name != "bot" {
+		t.Errorf("unexpected service account: %+v", account)
+	}
+}
@@ -0,0 +1,202 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultAgentMaxSteps = 10
+
+var (
+	ErrAgentStepLimit  = errors.New("agent reached its step limit")
+	ErrAgentTokenLimit = errors.New("agent reached its token limit")
+	ErrAgentTimeLimit  = errors.New("agent reached its time limit")
+)
+
+// AgentEventType is the kind of an AgentEvent.
+type AgentEventType string
+
+const (
+	AgentEventStepStart  AgentEventType = "step_start"
+	AgentEventModelReply AgentEventType = "model_reply"
+	AgentEventToolCall   AgentEventType = "tool_call"
+	AgentEventToolResult AgentEventType = "tool_result"
+	AgentEventFinish     AgentEventType = "finish"
+)
+
+// AgentEvent traces a step of an agent run.
+type AgentEvent struct {
+	Type AgentEventType
+	// Step is the index of the step, from 0.
+	Step int
+	// Elapsed is the time since the run started.
+	Elapsed time.Duration
+	// Message is the reply of the model, for AgentEventModelReply.
+	Message *ChatCompletionMessage
+	// Usage is the usage of the step, for AgentEventModelReply, or of the run, for AgentEventFinish.
+	Usage Usage
+	// ToolCall is the call being run, for AgentEventToolCall and AgentEventToolResult.
+	ToolCall *ToolCall
+	// Result is the output of the tool, for AgentEventToolResult.
+	Result string
+	// Err is the error of the tool, for AgentEventToolResult, or of the run, for AgentEventFinish.
+	Err error
+}
+
+// AgentStep is a completed step, passed to Agent.StopWhen.
+type AgentStep struct {
+	Index   int
+	Message ChatCompletionMessage
+	Usage   Usage
+}
+
+// AgentResult is the outcome of an agent run.
+type AgentResult struct {
+	// Messages is the conversation, including the input messages.
+	Messages []ChatCompletionMessage
+	// Final is the last reply of the model.
+	Final ChatCompletionMessage
+	Steps int
+	// Usage is the total usage of all steps.
+	Usage Usage
+}
+
+// Agent runs a tool-augmented conversation: it sends the conversation to the model, runs the
+// tools the model calls, appends their results and repeats until the model answers without
+// calling tools.
+type Agent struct {
+	Client *Client
+	// Request is the template of every request, e.g. the model and sampling parameters. Its
+	// messages are prepended to the conversation and the registry tools are added to its tools.
+	Request ChatCompletionRequest
+	Tools   *ToolRegistry
+
+	// MaxSteps bounds the number of model calls. Defaults to 10.
+	MaxSteps int
+	// MaxTokens, if positive, bounds the total tokens used by the run.
+	MaxTokens int
+	// MaxDuration, if positive, bounds the wall-clock time of the run.
+	MaxDuration time.Duration
+	// StopWhen, if set, is called after every model reply and ends the run when it returns true.
+	StopWhen func(step AgentStep) bool
+	// OnEvent, if set, receives trace events for every step.
+	OnEvent func(event AgentEvent)
+}
+
+// Run runs the agent on the conversation in messages. When a limit is reached, the result so
+// far is returned with ErrAgentStepLimit, ErrAgentTokenLimit or ErrAgentTimeLimit. Tool errors
+// are reported to the model as the tool result, so that it can recover from them.
+//
+//nolint:gocognit // the loop is easier to follow in one piece
+func (a *Agent) Run(ctx context.Context, messages ...ChatCompletionMessage) (result AgentResult, err error) {
+	start := time.Now()
+	if a.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.MaxDuration)
+		defer cancel()
+	}
+	defer func() {
+		a.emit(AgentEvent{Type: AgentEventFinish, Step: result.Steps, Elapsed: time.Since(start),
+			Usage: result.Usage, Err: err})
+	}()
+
+	maxSteps := a.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = defaultAgentMaxSteps
+	}
+	result.Messages = append(append([]ChatCompletionMessage(nil), a.Request.Messages...), messages...)
+
+	for step := 0; ; step++ {
+		if step >= maxSteps {
+			return result, ErrAgentStepLimit
+		}
+		a.emit(AgentEvent{Type: AgentEventStepStart, Step: step, Elapsed: time.Since(start)})
+
+		request := a.Request
+		request.Messages = result.Messages
+		if a.Tools != nil {
+			request.Tools = append(append([]Tool(nil), a.Request.Tools...), a.Tools.Tools()...)
+		}
+		var response ChatCompletionResponse
+		response, err = a.Client.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return result, a.limitError(ctx, err)
+		}
+		if len(response.Choices) == 0 {
+			return result, ErrNoChoices
+		}
+
+		message := response.Choices[0].Message
+		result.Steps++
+		result.Final = message
+		result.Messages = append(result.Messages, message)
+		addUsage(&result.Usage, response.Usage)
+		a.emit(AgentEvent{Type: AgentEventModelReply, Step: step, Elapsed: time.Since(start),
+			Message: &message, Usage: response.Usage})
+
+		if a.StopWhen != nil && a.StopWhen(AgentStep{Index: step, Message: message, Usage: response.Usage}) {
+			return result, nil
+		}
+		if len(message.ToolCalls) == 0 {
+			return result, nil
+		}
+		if a.MaxTokens > 0 && result.Usage.TotalTokens >= a.MaxTokens {
+			return result, ErrAgentTokenLimit
+		}
+
+		for i := range message.ToolCalls {
+			toolCall := message.ToolCalls[i]
+			a.emit(AgentEvent{Type: AgentEventToolCall, Step: step, Elapsed: time.Since(start), ToolCall: &toolCall})
+			output, toolErr := a.callTool(ctx, toolCall)
+			if ctx.Err() != nil {
+				return result, a.limitError(ctx, ctx.Err())
+			}
+			a.emit(AgentEvent{Type: AgentEventToolResult, Step: step, Elapsed: time.Since(start),
+				ToolCall: &toolCall, Result: output, Err: toolErr})
+			result.Messages = append(result.Messages, ChatCompletionMessage{
+				Role:       ChatMessageRoleTool,
+				Content:    output,
+				ToolCallID: toolCall.ID,
+			})
+		}
+	}
+}
+
+func (a *Agent) callTool(ctx context.Context, toolCall ToolCall) (string, error) {
+	if a.Tools == nil {
+		return toolErrorResult(ErrToolNotFound), ErrToolNotFound
+	}
+	output, err := a.Tools.Call(ctx, toolCall)
+	if err != nil {
+		return toolErrorResult(err), err
+	}
+	return output, nil
+}
+
+// toolErrorResult is the tool result reporting err to the model.
+func toolErrorResult(err error) string {
+	result, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(result)
+}
+
+func (a *Agent) limitError(ctx context.Context, err error) error {
+	if a.MaxDuration > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrAgentTimeLimit, err)
+	}
+	return err
+}
+
+func (a *Agent) emit(event AgentEvent) {
+	if a.OnEvent != nil {
+		a.OnEvent(event)
+	}
+}
+
+func addUsage(total *Usage, usage Usage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+}
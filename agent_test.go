@@ -0,0 +1,154 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// registerAgentModel answers with a tool call as long as the last message is not a tool result.
+func registerAgentModel(t *testing.T, server *test.ServerTest) {
+	t.Helper()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Error(err)
+			return
+		}
+		message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant}
+		last := request.Messages[len(request.Messages)-1]
+		if last.Role == openai.ChatMessageRoleTool {
+			message.Content = "result: " + last.Content
+		} else {
+			message.ToolCalls = []openai.ToolCall{{
+				ID:       "call_1",
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: "add", Arguments: `{"a":1,"b":2}`},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Choices: []openai.ChatCompletionChoice{{Message: message}},
+			Usage:   openai.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		})
+	})
+}
+
+func newAddRegistry(t *testing.T) *openai.ToolRegistry {
+	registry := openai.NewToolRegistry()
+	err := registry.Register(openai.FunctionDefinition{Name: "add"}, func(_ context.Context, arguments string) (string, error) {
+		var args struct{ A, B int }
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", err
+		}
+		result, _ := json.Marshal(args.A + args.B)
+		return string(result), nil
+	})
+	checks.NoError(t, err, "Register error")
+	return registry
+}
+
+func TestAgentRun(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	registerAgentModel(t, server)
+
+	var events []openai.AgentEventType
+	agent := &openai.Agent{
+		Client:  client,
+		Request: openai.ChatCompletionRequest{Model: openai.GPT4oMini},
+		Tools:   newAddRegistry(t),
+		OnEvent: func(event openai.AgentEvent) { events = append(events, event.Type) },
+	}
+	result, err := agent.Run(context.Background(), openai.ChatCompletionMessage{
+		Role: openai.ChatMessageRoleUser, Content: "1+2?",
+	})
+	checks.NoError(t, err, "Run error")
+	if result.Final.Content != "result: 3" || result.Steps != 2 || len(result.Messages) != 4 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Usage.TotalTokens != 30 {
+		t.Errorf("unexpected usage %+v", result.Usage)
+	}
+	want := []openai.AgentEventType{
+		openai.AgentEventStepStart, openai.AgentEventModelReply, openai.AgentEventToolCall, openai.AgentEventToolResult,
+		openai.AgentEventStepStart, openai.AgentEventModelReply, openai.AgentEventFinish,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("unexpected events %v", events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, events[i], want[i])
+		}
+	}
+}
+
+func TestAgentLimits(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	registerAgentModel(t, server)
+	user := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "1+2?"}
+
+	agent := &openai.Agent{Client: client, Tools: newAddRegistry(t), MaxSteps: 1}
+	result, err := agent.Run(context.Background(), user)
+	checks.ErrorIs(t, err, openai.ErrAgentStepLimit, "expected the step limit")
+	if result.Steps != 1 {
+		t.Errorf("unexpected steps %d", result.Steps)
+	}
+
+	agent = &openai.Agent{Client: client, Tools: newAddRegistry(t), MaxTokens: 10}
+	_, err = agent.Run(context.Background(), user)
+	checks.ErrorIs(t, err, openai.ErrAgentTokenLimit, "expected the token limit")
+
+	agent = &openai.Agent{Client: client, Tools: newAddRegistry(t), StopWhen: func(step openai.AgentStep) bool {
+		return len(step.Message.ToolCalls) > 0
+	}}
+	result, err = agent.Run(context.Background(), user)
+	checks.NoError(t, err, "Run error")
+	if result.Steps != 1 || len(result.Final.ToolCalls) != 1 {
+		t.Errorf("StopWhen should end the run, got %+v", result)
+	}
+
+	slow := openai.NewToolRegistry()
+	checks.NoError(t, slow.Register(openai.FunctionDefinition{Name: "add"}, func(ctx context.Context, _ string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}), "Register error")
+	agent = &openai.Agent{Client: client, Tools: slow, MaxDuration: 50 * time.Millisecond}
+	_, err = agent.Run(context.Background(), user)
+	checks.ErrorIs(t, err, openai.ErrAgentTimeLimit, "expected the time limit")
+}
+
+func TestAgentReportsToolErrors(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	registerAgentModel(t, server)
+
+	var toolErr error
+	agent := &openai.Agent{
+		Client: client,
+		Tools:  openai.NewToolRegistry(),
+		OnEvent: func(event openai.AgentEvent) {
+			if event.Type == openai.AgentEventToolResult {
+				toolErr = event.Err
+			}
+		},
+	}
+	result, err := agent.Run(context.Background(), openai.ChatCompletionMessage{
+		Role: openai.ChatMessageRoleUser, Content: "1+2?",
+	})
+	checks.NoError(t, err, "Run error")
+	if !errors.Is(toolErr, openai.ErrToolNotFound) {
+		t.Errorf("expected a tool not found event, got %v", toolErr)
+	}
+	if result.Final.Content != `result: {"error":"tool not found: \"add\""}` {
+		t.Errorf("unexpected final answer %q", result.Final.Content)
+	}
+}
@@ -0,0 +1,245 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// anthropicStreamEvent is the union of the events of an Anthropic Messages stream,
+// see https://docs.anthropic.com/en/docs/build-with-claude/streaming.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		ID    string         `json:"id"`
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message,omitempty"`
+	Index        int `json:"index"`
+	ContentBlock *struct {
+		Type     string `json:"type"`
+		ID       string `json:"id,omitempty"`
+		Name     string `json:"name,omitempty"`
+		Text     string `json:"text,omitempty"`
+		Thinking string `json:"thinking,omitempty"`
+		Data     string `json:"data,omitempty"`
+		// Input is the input of a tool_use block, usually empty and streamed by
+		// input_json_delta events.
+		Input json.RawMessage `json:"input,omitempty"`
+	} `json:"content_block,omitempty"`
+	Delta *struct {
+		Type        string `json:"type,omitempty"`
+		Text        string `json:"text,omitempty"`
+		PartialJSON string `json:"partial_json,omitempty"`
+		Thinking    string `json:"thinking,omitempty"`
+		Signature   string `json:"signature,omitempty"`
+		StopReason  string `json:"stop_reason,omitempty"`
+	} `json:"delta,omitempty"`
+	Usage *anthropicUsage `json:"usage,omitempty"`
+	Error *APIError       `json:"error,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicStreamDecoder converts the events of an Anthropic Messages stream into chat
+// completion chunks. Events of OpenAI-compatible streams have no event type and are left
+// to the regular decoding. Like the finish reasons of other providers, the stop reason is
+// kept raw, e.g. "tool_use"; FinishReason.Normalized maps it to its OpenAI equivalent.
+type anthropicStreamDecoder struct {
+	id          string
+	model       string
+	inputTokens int
+	// toolIndexes maps the index of a tool_use content block to the index of its tool call.
+	toolIndexes map[int]int
+	// toolArguments accumulates the partial JSON of the input of each tool_use content block.
+	toolArguments map[int]*strings.Builder
+	// thinkingIndexes maps the index of a thinking content block to the index of its thinking block.
+	thinkingIndexes map[int]int
+}
+
+func newAnthropicStreamDecoder() *anthropicStreamDecoder {
+	return &anthropicStreamDecoder{
+		toolIndexes:     make(map[int]int),
+		toolArguments:   make(map[int]*strings.Builder),
+		thinkingIndexes: make(map[int]int),
+	}
+}
+
+// thinkingChunk returns the chunk for a delta of the thinking block at the content block index.
+func (d *anthropicStreamDecoder) thinkingChunk(index int, block ThinkingBlock) *ChatCompletionStreamResponse {
+	thinkingIndex, ok := d.thinkingIndexes[index]
+	if !ok {
+		thinkingIndex = len(d.thinkingIndexes)
+		d.thinkingIndexes[index] = thinkingIndex
+	}
+	block.Index = &thinkingIndex
+	return d.chunk(ChatCompletionStreamChoice{
+		Delta: ChatCompletionStreamChoiceDelta{
+			ReasoningContent: block.Thinking,
+			ThinkingBlocks:   []ThinkingBlock{block},
+		},
+	})
+}
+
+// toolArgumentsChunk returns the chunk for a fragment of the arguments of the tool call at the
+// content block index, as in OpenAI streams, where only the first delta of a tool call has its
+// ID, type and name.
+func (d *anthropicStreamDecoder) toolArgumentsChunk(index int, arguments string) *ChatCompletionStreamResponse {
+	toolIndex := d.toolIndexes[index]
+	d.toolArguments[index].WriteString(arguments)
+	return d.chunk(ChatCompletionStreamChoice{
+		Delta: ChatCompletionStreamChoiceDelta{ToolCalls: []ToolCall{{
+			Index:    &toolIndex,
+			Function: FunctionCall{Arguments: arguments},
+		}}},
+	})
+}
+
+func (d *anthropicStreamDecoder) chunk(choice ChatCompletionStreamChoice) *ChatCompletionStreamResponse {
+	return &ChatCompletionStreamResponse{
+		ID:      d.id,
+		Object:  "chat.completion.chunk",
+		Model:   d.model,
+		Choices: []ChatCompletionStreamChoice{choice},
+	}
+}
+
+// decode returns the chunk for an event, or a nil chunk for events that carry nothing for
+// the caller. handled is false for events that are not Anthropic events.
+//
+//nolint:gocognit,gocyclo // one case per event and delta type
+func (d *anthropicStreamDecoder) decode(
+	eventType string,
+	data []byte,
+) (response *ChatCompletionStreamResponse, handled bool, err error) {
+	switch eventType {
+	case "message_start", "content_block_start", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop", "ping", "error":
+	default:
+		return nil, false, nil
+	}
+
+	var event anthropicStreamEvent
+	if err = json.Unmarshal(data, &event); err != nil {
+		return nil, true, &MalformedChunkError{Raw: append([]byte(nil), data...), Err: err}
+	}
+
+	switch eventType {
+	case "message_start":
+		if event.Message == nil {
+			return nil, true, nil
+		}
+		d.id = event.Message.ID
+		d.model = event.Message.Model
+		d.inputTokens = event.Message.Usage.InputTokens
+		return d.chunk(ChatCompletionStreamChoice{
+			Delta: ChatCompletionStreamChoiceDelta{Role: ChatMessageRoleAssistant},
+		}), true, nil
+
+	case "content_block_start":
+		block := event.ContentBlock
+		if block == nil {
+			return nil, true, nil
+		}
+		switch block.Type {
+		case "tool_use":
+			toolIndex := len(d.toolIndexes)
+			d.toolIndexes[event.Index] = toolIndex
+			arguments := &strings.Builder{}
+			d.toolArguments[event.Index] = arguments
+			// The input is streamed afterwards unless the block already carries it.
+			input := string(bytes.TrimSpace(block.Input))
+			if input == "{}" || input == "null" {
+				input = ""
+			}
+			arguments.WriteString(input)
+			return d.chunk(ChatCompletionStreamChoice{
+				Delta: ChatCompletionStreamChoiceDelta{ToolCalls: []ToolCall{{
+					Index:    &toolIndex,
+					ID:       block.ID,
+					Type:     ToolTypeFunction,
+					Function: FunctionCall{Name: block.Name, Arguments: input},
+				}}},
+			}), true, nil
+		case "text":
+			if block.Text != "" {
+				return d.chunk(ChatCompletionStreamChoice{
+					Delta: ChatCompletionStreamChoiceDelta{Content: block.Text},
+				}), true, nil
+			}
+		case "thinking":
+			return d.thinkingChunk(event.Index, ThinkingBlock{
+				Type:     ThinkingBlockTypeThinking,
+				Thinking: block.Thinking,
+			}), true, nil
+		case "redacted_thinking":
+			return d.thinkingChunk(event.Index, ThinkingBlock{
+				Type: ThinkingBlockTypeRedactedThinking,
+				Data: block.Data,
+			}), true, nil
+		}
+		return nil, true, nil
+
+	case "content_block_delta":
+		if event.Delta == nil {
+			return nil, true, nil
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			return d.chunk(ChatCompletionStreamChoice{
+				Delta: ChatCompletionStreamChoiceDelta{Content: event.Delta.Text},
+			}), true, nil
+		case "thinking_delta":
+			return d.thinkingChunk(event.Index, ThinkingBlock{
+				Type:     ThinkingBlockTypeThinking,
+				Thinking: event.Delta.Thinking,
+			}), true, nil
+		case "signature_delta":
+			return d.thinkingChunk(event.Index, ThinkingBlock{
+				Type:      ThinkingBlockTypeThinking,
+				Signature: event.Delta.Signature,
+			}), true, nil
+		case "input_json_delta":
+			if _, ok := d.toolArguments[event.Index]; !ok || event.Delta.PartialJSON == "" {
+				return nil, true, nil
+			}
+			return d.toolArgumentsChunk(event.Index, event.Delta.PartialJSON), true, nil
+		}
+		return nil, true, nil
+
+	case "content_block_stop":
+		// Tools without parameters get no input deltas; OpenAI sends "{}" as their arguments.
+		arguments, ok := d.toolArguments[event.Index]
+		if ok && arguments.Len() == 0 {
+			return d.toolArgumentsChunk(event.Index, "{}"), true, nil
+		}
+		return nil, true, nil
+
+	case "message_delta":
+		response = d.chunk(ChatCompletionStreamChoice{})
+		if event.Delta != nil && event.Delta.StopReason != "" {
+			response.Choices[0].FinishReason = FinishReason(event.Delta.StopReason)
+		}
+		if event.Usage != nil {
+			response.Usage = &Usage{
+				PromptTokens:     d.inputTokens,
+				CompletionTokens: event.Usage.OutputTokens,
+				TotalTokens:      d.inputTokens + event.Usage.OutputTokens,
+			}
+		}
+		return response, true, nil
+
+	case "message_stop":
+		return nil, true, io.EOF
+
+	case "error":
+		if event.Error != nil {
+			return nil, true, event.Error
+		}
+	}
+	return nil, true, nil
+}
@@ -0,0 +1,184 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const anthropicStreamBody = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4","content":[],"usage":{"input_tokens":25,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type": "ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me check."}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":15}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+func setupAnthropicStreamServer(body string) (*openai.Client, func()) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, body)
+	}))
+	config := openai.DefaultAnthropicConfig("test-key", ts.URL)
+	return openai.NewClientWithConfig(config), ts.Close
+}
+
+func TestAnthropicChatCompletionStream(t *testing.T) {
+	client, teardown := setupAnthropicStreamServer(anthropicStreamBody)
+	defer teardown()
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather in Paris?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var chunks []openai.ChatCompletionStreamResponse
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) != 6 {
+		t.Fatalf("expected 6 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if chunks[0].ID != "msg_1" || chunks[0].Model != "claude-sonnet-4" ||
+		chunks[0].Choices[0].Delta.Role != openai.ChatMessageRoleAssistant {
+		t.Errorf("unexpected first chunk: %+v", chunks[0])
+	}
+	if chunks[1].Choices[0].Delta.Content != "Let me check." {
+		t.Errorf("unexpected text chunk: %+v", chunks[1])
+	}
+	toolCall := chunks[2].Choices[0].Delta.ToolCalls[0]
+	if *toolCall.Index != 0 || toolCall.ID != "toolu_1" || toolCall.Function.Name != "get_weather" {
+		t.Errorf("unexpected tool call start: %+v", toolCall)
+	}
+	arguments := chunks[3].Choices[0].Delta.ToolCalls[0].Function.Arguments +
+		chunks[4].Choices[0].Delta.ToolCalls[0].Function.Arguments
+	if arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool arguments: %s", arguments)
+	}
+	last := chunks[5]
+	if last.Choices[0].FinishReason != "tool_use" ||
+		last.Choices[0].FinishReason.Normalized() != openai.FinishReasonToolCalls ||
+		last.Usage == nil || last.Usage.PromptTokens != 25 || last.Usage.CompletionTokens != 15 {
+		t.Errorf("unexpected final chunk: %+v", last)
+	}
+	if !stream.IsComplete() {
+		t.Error("expected stream to be complete after message_stop")
+	}
+}
+
+func TestAnthropicChatCompletionStreamError(t *testing.T) {
+	client, teardown := setupAnthropicStreamServer("event: error\n" +
+		`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n")
+	defer teardown()
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "overloaded_error" || apiErr.Message != "Overloaded" {
+		t.Errorf("expected overloaded APIError, got %v", err)
+	}
+}
+
+func TestAnthropicChatCompletionStreamToolArguments(t *testing.T) {
+	client, teardown := setupAnthropicStreamServer(`event: message_start
+data: {"type":"message_start","message":{"id":"msg_2","type":"message","role":"assistant","model":"claude-sonnet-4","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_time","input":{}}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_2","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\": \"Pa"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"ris\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":20}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	defer teardown()
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    "claude-sonnet-4",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Time and weather?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	collected, err := stream.CollectMessage()
+	checks.NoError(t, err, "CollectMessage error")
+	toolCalls := collected.Message.ToolCalls
+	if len(toolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", toolCalls)
+	}
+	if toolCalls[0].ID != "toolu_1" || toolCalls[0].Function.Name != "get_time" ||
+		toolCalls[0].Function.Arguments != "{}" {
+		t.Errorf("unexpected tool call without input: %+v", toolCalls[0])
+	}
+	if toolCalls[1].ID != "toolu_2" || toolCalls[1].Type != openai.ToolTypeFunction ||
+		toolCalls[1].Function.Arguments != `{"city": "Paris"}` {
+		t.Errorf("unexpected tool call: %+v", toolCalls[1])
+	}
+	if collected.FinishReason.Normalized() != openai.FinishReasonToolCalls {
+		t.Errorf("unexpected finish reason %q", collected.FinishReason)
+	}
+}
@@ -0,0 +1,48 @@
+package openai
+
+// AnthropicThinkingType enables or disables extended thinking of Anthropic models.
+type AnthropicThinkingType string
+
+const (
+	AnthropicThinkingEnabled  AnthropicThinkingType = "enabled"
+	AnthropicThinkingDisabled AnthropicThinkingType = "disabled"
+)
+
+// AnthropicMinThinkingBudget is the minimum thinking budget accepted by Anthropic.
+const AnthropicMinThinkingBudget = 1024
+
+// AnthropicThinking configures extended thinking of Anthropic models,
+// see https://docs.anthropic.com/en/docs/build-with-claude/extended-thinking.
+type AnthropicThinking struct {
+	Type AnthropicThinkingType `json:"type"`
+	// BudgetTokens is the number of tokens the model may use to think. It must be at least
+	// AnthropicMinThinkingBudget and below the max tokens of the request.
+	BudgetTokens int `json:"budget_tokens,omitempty"`
+}
+
+// NewAnthropicThinking enables extended thinking with a budget of budgetTokens.
+func NewAnthropicThinking(budgetTokens int) *AnthropicThinking {
+	return &AnthropicThinking{Type: AnthropicThinkingEnabled, BudgetTokens: budgetTokens}
+}
+
+// ThinkingBlockType is the type of a thinking block.
+type ThinkingBlockType string
+
+const (
+	ThinkingBlockTypeThinking         ThinkingBlockType = "thinking"
+	ThinkingBlockTypeRedactedThinking ThinkingBlockType = "redacted_thinking"
+)
+
+// ThinkingBlock is a block of the extended thinking of a model. Thinking blocks of assistant
+// messages must be sent back unchanged, signature included, when continuing a conversation
+// with tool results.
+type ThinkingBlock struct {
+	// Index is not nil only in chat completion chunk object. Deltas with the same index belong
+	// to the same block and are concatenated.
+	Index     *int              `json:"index,omitempty"`
+	Type      ThinkingBlockType `json:"type"`
+	Thinking  string            `json:"thinking,omitempty"`
+	Signature string            `json:"signature,omitempty"`
+	// Data is the encrypted content of a redacted_thinking block.
+	Data string `json:"data,omitempty"`
+}
@@ -0,0 +1,134 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const anthropicThinkingStreamBody = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4","usage":{"input_tokens":10}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Need the "}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"weather."}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig1"}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{}"}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"redacted_thinking","data":"enc"}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+func TestAnthropicThinkingStream(t *testing.T) {
+	client, teardown := setupAnthropicStreamServer(anthropicThinkingStreamBody)
+	defer teardown()
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:     "claude-sonnet-4",
+		MaxTokens: 4096,
+		Thinking:  openai.NewAnthropicThinking(2048),
+		Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var reasoning string
+	var blocks []openai.ThinkingBlock
+	var toolCalls int
+	for {
+		response, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		delta := response.Choices[0].Delta
+		reasoning += delta.ReasoningContent
+		toolCalls += len(delta.ToolCalls)
+		for _, block := range delta.ThinkingBlocks {
+			if *block.Index == len(blocks) {
+				blocks = append(blocks, openai.ThinkingBlock{Type: block.Type})
+			}
+			current := &blocks[*block.Index]
+			current.Thinking += block.Thinking
+			current.Signature += block.Signature
+			current.Data += block.Data
+		}
+	}
+
+	if reasoning != "Need the weather." || toolCalls != 2 {
+		t.Errorf("unexpected reasoning %q or tool call deltas %d", reasoning, toolCalls)
+	}
+	want := []openai.ThinkingBlock{
+		{Type: openai.ThinkingBlockTypeThinking, Thinking: "Need the weather.", Signature: "sig1"},
+		{Type: openai.ThinkingBlockTypeRedactedThinking, Data: "enc"},
+	}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("unexpected thinking blocks: %+v", blocks)
+	}
+}
+
+func TestAnthropicThinkingRequest(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		thinking, _ := request["thinking"].(map[string]any)
+		if thinking["type"] != "enabled" || thinking["budget_tokens"] != float64(2048) {
+			t.Errorf("unexpected thinking: %v", request["thinking"])
+		}
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"Sunny",`+
+			`"thinking_blocks":[{"type":"thinking","thinking":"Check","signature":"sig"}]}}]}`)
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:     "claude-sonnet-4",
+		MaxTokens: 4096,
+		Thinking:  openai.NewAnthropicThinking(2048),
+		Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Weather?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	want := []openai.ThinkingBlock{{Type: openai.ThinkingBlockTypeThinking, Thinking: "Check", Signature: "sig"}}
+	if !reflect.DeepEqual(resp.Choices[0].Message.ThinkingBlocks, want) {
+		t.Errorf("unexpected thinking blocks: %+v", resp.Choices[0].Message.ThinkingBlocks)
+	}
+}
+
+func TestAnthropicThinkingValidate(t *testing.T) {
+	request := openai.ChatCompletionRequest{
+		Model:     "claude-sonnet-4",
+		MaxTokens: 2000,
+		Thinking:  openai.NewAnthropicThinking(2048),
+		Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	}
+	if err := request.Validate(); !errors.Is(err, openai.ErrParameterOutOfRange) {
+		t.Errorf("expected ErrParameterOutOfRange, got %v", err)
+	}
+	request.Thinking = openai.NewAnthropicThinking(1024)
+	checks.NoError(t, request.Validate())
+}
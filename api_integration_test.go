@@ -294,12 +294,7 @@ func TestChatCompletionStructuredOutputsFunctionCalling(t *testing.T) {
 					},
 				},
 			},
-			ToolChoice: openai.ToolChoice{
-				Type: openai.ToolTypeFunction,
-				Function: openai.ToolFunction{
-					Name: "display_cases",
-				},
-			},
+			ToolChoice: openai.ToolChoiceFunction("display_cases"),
 		},
 	)
 	checks.NoError(t, err, "CreateChatCompletion (use structured outputs response) returned error")
@@ -0,0 +1,38 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+)
+
+type apiVersionContextKey struct{}
+
+// WithAPIVersion returns a context overriding the api-version query parameter of the requests
+// made with it, e.g. to call an Azure OpenAI endpoint only available in a preview version
+// without a second client:
+//
+//	ctx := openai.WithAPIVersion(ctx, "2025-04-01-preview")
+//	resp, err := client.CreateChatCompletion(ctx, request)
+//
+// It takes precedence over ClientConfig.APIVersion and ClientConfig.APIVersions.
+func WithAPIVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionContextKey{}, version)
+}
+
+// APIVersionFromContext returns the API version set with WithAPIVersion, if any.
+func APIVersionFromContext(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(apiVersionContextKey{}).(string)
+	return version, ok && version != ""
+}
+
+// overrideAPIVersion sets the api-version query parameter of req to the version of its
+// context, if any.
+func overrideAPIVersion(req *http.Request) {
+	version, ok := APIVersionFromContext(req.Context())
+	if !ok {
+		return
+	}
+	query := req.URL.Query()
+	query.Set("api-version", version)
+	req.URL.RawQuery = query.Encode()
+}
@@ -0,0 +1,55 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestWithAPIVersion(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var versions []string
+	server.RegisterHandler("/openai/deployments/gpt-4o/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		versions = append(versions, r.URL.Query()["api-version"]...)
+		fmt.Fprint(w, `{"id":"1","choices":[]}`)
+	})
+	config := openai.DefaultAzureConfig(test.GetTestToken(), ts.URL)
+	config.APIVersions = map[string]string{"/chat": "2024-10-21"}
+	client := openai.NewClientWithConfig(config)
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), request)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	ctx := openai.WithAPIVersion(context.Background(), "2025-04-01-preview")
+	_, err = client.CreateChatCompletion(ctx, request)
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	if len(versions) != 2 || versions[0] != "2024-10-21" || versions[1] != "2025-04-01-preview" {
+		t.Errorf("unexpected api versions %q", versions)
+	}
+}
+
+func TestAPIVersionFromContext(t *testing.T) {
+	if _, ok := openai.APIVersionFromContext(context.Background()); ok {
+		t.Error("expected no version in a background context")
+	}
+	if _, ok := openai.APIVersionFromContext(openai.WithAPIVersion(context.Background(), "")); ok {
+		t.Error("expected an empty version to be ignored")
+	}
+	version, ok := openai.APIVersionFromContext(openai.WithAPIVersion(context.Background(), "2025-01-01"))
+	if !ok || version != "2025-01-01" {
+		t.Errorf("unexpected version %q", version)
+	}
+}
@@ -44,16 +44,41 @@ const (
 type AssistantTool struct {
 	Type     AssistantToolType   `json:"type"`
 	Function *FunctionDefinition `json:"function,omitempty"`
+	// FileSearch holds the optional settings of a file_search tool.
+	FileSearch *AssistantToolFileSearchOptions `json:"file_search,omitempty"`
+}
+
+// AssistantToolFileSearchOptions overrides the defaults of the file_search tool.
+type AssistantToolFileSearchOptions struct {
+	// MaxNumResults is the maximum number of results the file search tool should output, between 1 and 50.
+	MaxNumResults int `json:"max_num_results,omitempty"`
+	// RankingOptions controls how search results are ranked.
+	RankingOptions *FileSearchRankingOptions `json:"ranking_options,omitempty"`
+}
+
+// FileSearchRankingOptions configures the ranker used by the file_search tool.
+type FileSearchRankingOptions struct {
+	// Ranker is "auto" or "default_2024_08_21".
+	Ranker string `json:"ranker,omitempty"`
+	// ScoreThreshold is a value between 0 and 1 under which results are discarded.
+	ScoreThreshold float64 `json:"score_threshold"`
 }
 
 type AssistantToolFileSearch struct {
 	VectorStoreIDs []string `json:"vector_store_ids"`
+	// VectorStores creates new vector stores from file IDs and attaches them to the assistant.
+	// Only valid in requests.
+	VectorStores []VectorStoreToolResources `json:"vector_stores,omitempty"`
 }
 
 type AssistantToolCodeInterpreter struct {
 	FileIDs []string `json:"file_ids"`
 }
 
+// AssistantResponseFormatAuto lets the model pick the response format. Any other response format is
+// expressed with a ChatCompletionResponseFormat value.
+const AssistantResponseFormatAuto = "auto"
+
 type AssistantToolResource struct {
 	FileSearch      *AssistantToolFileSearch      `json:"file_search,omitempty"`
 	CodeInterpreter *AssistantToolCodeInterpreter `json:"code_interpreter,omitempty"`
@@ -136,6 +161,9 @@ type AssistantFilesList struct {
 
 // CreateAssistant creates a new assistant.
 func (c *Client) CreateAssistant(ctx context.Context, request AssistantRequest) (response Assistant, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(assistantsSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
 	if err != nil {
@@ -168,6 +196,9 @@ func (c *Client) ModifyAssistant(
 	assistantID string,
 	request AssistantRequest,
 ) (response Assistant, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	urlSuffix := fmt.Sprintf("%s/%s", assistantsSuffix, assistantID)
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
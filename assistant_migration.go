@@ -0,0 +1,189 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxCodeInterpreterFiles is the number of files the code_interpreter tool of a v2 assistant
+// accepts.
+const maxCodeInterpreterFiles = 20
+
+// ErrAssistantNotMigrated is wrapped by the errors of VerifyAssistantMigration.
+var ErrAssistantNotMigrated = errors.New("assistant is not migrated to the v2 tools")
+
+// AssistantMigrationRequest describes the migration of an assistant from the v1 retrieval tool
+// to the v2 file_search tool.
+type AssistantMigrationRequest struct {
+	AssistantID string
+	// FileIDs are the files of the v1 assistant, by default its FileIDs. The v2 API does not
+	// return them: list them beforehand with ListAssistantFiles on a client whose
+	// AssistantVersion is "v1".
+	FileIDs []string
+	// VectorStoreName names the vector store created for the files, by default after the
+	// assistant.
+	VectorStoreName string
+}
+
+// AssistantMigration is the result of MigrateAssistantToV2.
+type AssistantMigration struct {
+	// Assistant is the assistant after the migration.
+	Assistant Assistant
+	// VectorStore is the vector store created for the files of the retrieval tool, if any.
+	VectorStore *VectorStore
+	// Migrated is false if the assistant had nothing to migrate.
+	Migrated bool
+}
+
+// MigrateAssistantToV2 replaces the retrieval tool of a v1 assistant with the file_search tool:
+// the files of the assistant are added to a new vector store, attached to the assistant as its
+// file_search tool resource. The files also become the code_interpreter tool resource if the
+// assistant has that tool without files, since v1 shared the files between both tools.
+// The files are processed by the vector store asynchronously, see VerifyAssistantMigration.
+func (c *Client) MigrateAssistantToV2(
+	ctx context.Context,
+	request AssistantMigrationRequest,
+) (migration AssistantMigration, err error) {
+	assistant, err := c.RetrieveAssistant(ctx, request.AssistantID)
+	if err != nil {
+		return
+	}
+	migration.Assistant = assistant
+	fileIDs := request.FileIDs
+	if fileIDs == nil {
+		fileIDs = assistant.FileIDs
+	}
+
+	var tools []AssistantTool
+	var hasRetrieval, hasFileSearch, hasCodeInterpreter bool
+	for _, tool := range assistant.Tools {
+		switch tool.Type {
+		case AssistantToolTypeRetrieval:
+			hasRetrieval = true
+			continue
+		case AssistantToolTypeFileSearch:
+			hasFileSearch = true
+		case AssistantToolTypeCodeInterpreter:
+			hasCodeInterpreter = true
+		}
+		tools = append(tools, tool)
+	}
+	if !hasRetrieval {
+		return
+	}
+	if !hasFileSearch {
+		tools = append(tools, AssistantTool{Type: AssistantToolTypeFileSearch})
+	}
+
+	resources := AssistantToolResource{}
+	if assistant.ToolResources != nil {
+		resources = *assistant.ToolResources
+	}
+	if len(fileIDs) > 0 {
+		if resources.FileSearch == nil || len(resources.FileSearch.VectorStoreIDs) == 0 {
+			var store VectorStore
+			store, err = c.createMigrationVectorStore(ctx, request, assistant, fileIDs)
+			if err != nil {
+				return
+			}
+			migration.VectorStore = &store
+			resources.FileSearch = &AssistantToolFileSearch{VectorStoreIDs: []string{store.ID}}
+		}
+		if hasCodeInterpreter && (resources.CodeInterpreter == nil || len(resources.CodeInterpreter.FileIDs) == 0) {
+			codeFiles := fileIDs
+			if len(codeFiles) > maxCodeInterpreterFiles {
+				codeFiles = codeFiles[:maxCodeInterpreterFiles]
+			}
+			resources.CodeInterpreter = &AssistantToolCodeInterpreter{FileIDs: codeFiles}
+		}
+	}
+
+	migration.Assistant, err = c.ModifyAssistant(ctx, assistant.ID, AssistantRequest{
+		Model:         assistant.Model,
+		Tools:         tools,
+		ToolResources: &resources,
+	})
+	if err != nil {
+		return
+	}
+	migration.Migrated = true
+	return
+}
+
+func (c *Client) createMigrationVectorStore(
+	ctx context.Context,
+	request AssistantMigrationRequest,
+	assistant Assistant,
+	fileIDs []string,
+) (VectorStore, error) {
+	name := request.VectorStoreName
+	if name == "" {
+		name = assistant.ID
+		if assistant.Name != nil && *assistant.Name != "" {
+			name = *assistant.Name
+		}
+		name += " files"
+	}
+	return c.CreateVectorStore(ctx, VectorStoreRequest{
+		Name:     name,
+		FileIDs:  fileIDs,
+		Metadata: map[string]any{"migrated_from_assistant": assistant.ID},
+	})
+}
+
+// VerifyAssistantMigration checks that an assistant uses the v2 tools: it has no retrieval tool,
+// its file_search and code_interpreter tools have their resources, at most 20 code_interpreter
+// files, and its vector stores exist without failed files. Files still being processed are not
+// an error. The error wraps ErrAssistantNotMigrated and lists the problems found.
+func (c *Client) VerifyAssistantMigration(ctx context.Context, assistantID string) error {
+	assistant, err := c.RetrieveAssistant(ctx, assistantID)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	var vectorStoreIDs []string
+	if assistant.ToolResources != nil && assistant.ToolResources.FileSearch != nil {
+		vectorStoreIDs = assistant.ToolResources.FileSearch.VectorStoreIDs
+	}
+	for _, tool := range assistant.Tools {
+		switch tool.Type {
+		case AssistantToolTypeRetrieval:
+			problems = append(problems, "the retrieval tool is not supported by v2")
+		case AssistantToolTypeFileSearch:
+			if len(vectorStoreIDs) == 0 {
+				problems = append(problems, "the file_search tool has no vector store")
+			}
+		case AssistantToolTypeCodeInterpreter:
+			if assistant.ToolResources != nil && assistant.ToolResources.CodeInterpreter != nil &&
+				len(assistant.ToolResources.CodeInterpreter.FileIDs) > maxCodeInterpreterFiles {
+				problems = append(problems, fmt.Sprintf("the code_interpreter tool has more than %d files",
+					maxCodeInterpreterFiles))
+			}
+		}
+	}
+	if len(assistant.FileIDs) > 0 && len(vectorStoreIDs) == 0 &&
+		(assistant.ToolResources == nil || assistant.ToolResources.CodeInterpreter == nil) {
+		problems = append(problems, "the v1 file_ids are not attached to a tool")
+	}
+
+	for _, id := range vectorStoreIDs {
+		store, storeErr := c.RetrieveVectorStore(ctx, id)
+		if storeErr != nil {
+			return storeErr
+		}
+		if store.Status == "expired" {
+			problems = append(problems, fmt.Sprintf("vector store %s has expired", id))
+		}
+		if store.FileCounts.Failed > 0 {
+			problems = append(problems, fmt.Sprintf("vector store %s has %d failed files", id, store.FileCounts.Failed))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s: %s", ErrAssistantNotMigrated, assistantID, strings.Join(problems, "; "))
+	}
+	return nil
+}
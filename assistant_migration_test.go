@@ -0,0 +1,119 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestMigrateAssistantToV2(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	assistant := `{"id":"asst_1","object":"assistant","name":"Helper","model":"gpt-4-turbo",` +
+		`"tools":[{"type":"retrieval"},{"type":"code_interpreter"}],"file_ids":["file-1","file-2"]}`
+	var modified struct {
+		Model         string                        `json:"model"`
+		Tools         []openai.AssistantTool        `json:"tools"`
+		ToolResources *openai.AssistantToolResource `json:"tool_resources"`
+	}
+	var storeRequest openai.VectorStoreRequest
+	server.RegisterHandler("/v1/assistants/asst_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, assistant)
+			return
+		}
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&modified), "Decode error")
+		response := openai.Assistant{ID: "asst_1", Model: modified.Model, Tools: modified.Tools,
+			ToolResources: modified.ToolResources}
+		checks.NoError(t, json.NewEncoder(w).Encode(response), "Encode error")
+	})
+	server.RegisterHandler("/v1/vector_stores", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&storeRequest), "Decode error")
+		fmt.Fprint(w, `{"id":"vs_1","object":"vector_store","status":"in_progress"}`)
+	})
+
+	migration, err := client.MigrateAssistantToV2(context.Background(), openai.AssistantMigrationRequest{
+		AssistantID: "asst_1",
+	})
+	checks.NoError(t, err, "MigrateAssistantToV2 error")
+
+	if !migration.Migrated || migration.VectorStore == nil || migration.VectorStore.ID != "vs_1" {
+		t.Fatalf("unexpected migration %+v", migration)
+	}
+	if storeRequest.Name != "Helper files" || strings.Join(storeRequest.FileIDs, ",") != "file-1,file-2" {
+		t.Errorf("unexpected vector store request %+v", storeRequest)
+	}
+	if modified.Model != "gpt-4-turbo" || len(modified.Tools) != 2 ||
+		modified.Tools[0].Type != openai.AssistantToolTypeCodeInterpreter ||
+		modified.Tools[1].Type != openai.AssistantToolTypeFileSearch {
+		t.Errorf("unexpected tools %+v", modified.Tools)
+	}
+	resources := migration.Assistant.ToolResources
+	if resources == nil || resources.FileSearch.VectorStoreIDs[0] != "vs_1" ||
+		len(resources.CodeInterpreter.FileIDs) != 2 {
+		t.Errorf("unexpected tool resources %+v", resources)
+	}
+}
+
+func TestMigrateAssistantToV2NothingToMigrate(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/assistants/asst_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s request", r.Method)
+		}
+		fmt.Fprint(w, `{"id":"asst_1","model":"gpt-4o","tools":[{"type":"file_search"}]}`)
+	})
+
+	migration, err := client.MigrateAssistantToV2(context.Background(), openai.AssistantMigrationRequest{
+		AssistantID: "asst_1",
+	})
+	checks.NoError(t, err, "MigrateAssistantToV2 error")
+	if migration.Migrated || migration.VectorStore != nil {
+		t.Errorf("expected nothing to migrate, got %+v", migration)
+	}
+}
+
+func TestVerifyAssistantMigration(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	assistants := map[string]string{
+		"asst_ok": `{"id":"asst_ok","model":"gpt-4o","tools":[{"type":"file_search"}],` +
+			`"tool_resources":{"file_search":{"vector_store_ids":["vs_ok"]}}}`,
+		"asst_v1": `{"id":"asst_v1","model":"gpt-4o","tools":[{"type":"retrieval"},{"type":"file_search"}]}`,
+		"asst_failed": `{"id":"asst_failed","model":"gpt-4o","tools":[{"type":"file_search"}],` +
+			`"tool_resources":{"file_search":{"vector_store_ids":["vs_failed"]}}}`,
+	}
+	for id, body := range assistants {
+		body := body
+		server.RegisterHandler("/v1/assistants/"+id, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+	server.RegisterHandler("/v1/vector_stores/vs_ok", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"vs_ok","status":"in_progress","file_counts":{"in_progress":1,"total":1}}`)
+	})
+	server.RegisterHandler("/v1/vector_stores/vs_failed", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"vs_failed","status":"completed","file_counts":{"failed":2,"total":3}}`)
+	})
+
+	ctx := context.Background()
+	checks.NoError(t, client.VerifyAssistantMigration(ctx, "asst_ok"), "VerifyAssistantMigration error")
+
+	err := client.VerifyAssistantMigration(ctx, "asst_v1")
+	if !errors.Is(err, openai.ErrAssistantNotMigrated) || !strings.Contains(err.Error(), "retrieval") ||
+		!strings.Contains(err.Error(), "no vector store") {
+		t.Errorf("unexpected error for a v1 assistant: %v", err)
+	}
+	err = client.VerifyAssistantMigration(ctx, "asst_failed")
+	if !errors.Is(err, openai.ErrAssistantNotMigrated) || !strings.Contains(err.Error(), "2 failed files") {
+		t.Errorf("unexpected error for failed files: %v", err)
+	}
+}
@@ -445,3 +445,36 @@ When asked a question, write and run Python code to answer the question.`
 	err = client.DeleteAssistantFile(ctx, assistantID, assistantFileID)
 	checks.NoError(t, err, "DeleteAssistantFile error")
 }
+
+func TestAssistantFileSearchOptions(t *testing.T) {
+	request := openai.AssistantRequest{
+		Model: openai.GPT4o,
+		Tools: []openai.AssistantTool{{
+			Type: openai.AssistantToolTypeFileSearch,
+			FileSearch: &openai.AssistantToolFileSearchOptions{
+				MaxNumResults:  10,
+				RankingOptions: &openai.FileSearchRankingOptions{Ranker: "auto", ScoreThreshold: 0.5},
+			},
+		}},
+		ToolResources: &openai.AssistantToolResource{
+			FileSearch: &openai.AssistantToolFileSearch{
+				VectorStores: []openai.VectorStoreToolResources{{FileIDs: []string{"file-abc123"}}},
+			},
+			CodeInterpreter: &openai.AssistantToolCodeInterpreter{FileIDs: []string{"file-def456"}},
+		},
+		ResponseFormat: openai.AssistantResponseFormatAuto,
+	}
+	data, err := json.Marshal(request)
+	checks.NoError(t, err, "marshal assistant request")
+
+	var decoded openai.Assistant
+	checks.NoError(t, json.Unmarshal(data, &decoded), "unmarshal assistant")
+	fileSearch := decoded.Tools[0].FileSearch
+	if fileSearch == nil || fileSearch.MaxNumResults != 10 || fileSearch.RankingOptions.ScoreThreshold != 0.5 {
+		t.Errorf("file_search options not round-tripped: %s", data)
+	}
+	if len(decoded.ToolResources.FileSearch.VectorStores) != 1 ||
+		decoded.ToolResources.CodeInterpreter.FileIDs[0] != "file-def456" {
+		t.Errorf("tool_resources not round-tripped: %s", data)
+	}
+}
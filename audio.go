@@ -43,6 +43,14 @@ type AudioRequest struct {
 
 	// Reader is an optional io.Reader when you do not want to use an existing file.
 	Reader io.Reader
+	// FileName overrides the name of the uploaded file, whose extension tells the API the audio format.
+	FileName string
+	// AudioFormat is the format of the audio of Reader, e.g. "mp3" or "wav", used to name the
+	// uploaded file when neither FileName nor FilePath is set.
+	AudioFormat string
+	// StreamUpload sends the form in a chunked request body as the audio is read, instead of
+	// buffering it in memory first. Streamed uploads are not retried.
+	StreamUpload bool
 
 	Prompt                 string
 	Temperature            float32
@@ -114,11 +122,22 @@ func (c *Client) callAudioAPI(
 	request AudioRequest,
 	endpointSuffix string,
 ) (response AudioResponse, err error) {
-	var formBody bytes.Buffer
-	builder := c.createFormBuilder(&formBody)
-
-	if err = audioMultipartForm(request, builder); err != nil {
-		return AudioResponse{}, err
+	request.Model = c.config.mapModel(request.Model)
+	var body io.Reader
+	var contentType string
+	if request.StreamUpload {
+		formReader, formContentType := c.newStreamedForm(func(builder utils.FormBuilder) error {
+			return audioMultipartForm(request, builder)
+		})
+		defer formReader.Close()
+		body, contentType = formReader, formContentType
+	} else {
+		formBody := &bytes.Buffer{}
+		builder := c.createFormBuilder(formBody)
+		if err = audioMultipartForm(request, builder); err != nil {
+			return AudioResponse{}, err
+		}
+		body, contentType = formBody, builder.FormDataContentType()
 	}
 
 	urlSuffix := fmt.Sprintf("/audio/%s", endpointSuffix)
@@ -126,8 +145,8 @@ func (c *Client) callAudioAPI(
 		ctx,
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
-		withBody(&formBody),
-		withContentType(builder.FormDataContentType()),
+		withBody(body),
+		withContentType(contentType),
 	)
 	if err != nil {
 		return AudioResponse{}, err
@@ -209,10 +228,23 @@ func audioMultipartForm(request AudioRequest, b utils.FormBuilder) error {
 	return b.Close()
 }
 
+// fileName returns the name of the uploaded file.
+func (r AudioRequest) fileName() string {
+	switch {
+	case r.FileName != "":
+		return r.FileName
+	case r.FilePath != "":
+		return r.FilePath
+	case r.AudioFormat != "":
+		return "audio." + r.AudioFormat
+	}
+	return ""
+}
+
 // createFileField creates the "file" form field from either an existing file or by using the reader.
 func createFileField(request AudioRequest, b utils.FormBuilder) error {
 	if request.Reader != nil {
-		err := b.CreateFormFileReader("file", request.Reader, request.FilePath)
+		err := b.CreateFormFileReader("file", request.Reader, request.fileName())
 		if err != nil {
 			return fmt.Errorf("creating form using reader: %w", err)
 		}
@@ -225,7 +257,11 @@ func createFileField(request AudioRequest, b utils.FormBuilder) error {
 	}
 	defer f.Close()
 
-	err = b.CreateFormFile("file", f)
+	if request.FileName != "" {
+		err = b.CreateFormFileReader("file", f, request.FileName)
+	} else {
+		err = b.CreateFormFile("file", f)
+	}
 	if err != nil {
 		return fmt.Errorf("creating form file: %w", err)
 	}
@@ -158,3 +158,56 @@ func handleAudioEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func TestAudioReaderStreamUpload(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	audio := []byte("some mp3 binary data")
+	var gotName string
+	var gotContent []byte
+	var gotLength int64
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		gotLength = r.ContentLength
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		gotName = header.Filename
+		gotContent, _ = io.ReadAll(file)
+		w.Write([]byte(`{"text":"hello"}`))
+	})
+
+	resp, err := client.CreateTranscription(context.Background(), openai.AudioRequest{
+		Model:        openai.Whisper1,
+		Reader:       bytes.NewReader(audio),
+		AudioFormat:  "mp3",
+		StreamUpload: true,
+	})
+	checks.NoError(t, err, "CreateTranscription error")
+	if resp.Text != "hello" {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if gotName != "audio.mp3" {
+		t.Errorf("unexpected file name %q", gotName)
+	}
+	if !bytes.Equal(gotContent, audio) {
+		t.Errorf("unexpected file content %q", gotContent)
+	}
+	if gotLength != -1 {
+		t.Errorf("expected a chunked body, got content length %d", gotLength)
+	}
+
+	path := filepath.Join(t.TempDir(), "recording.bin")
+	test.CreateTestFile(t, path)
+	_, err = client.CreateTranscription(context.Background(), openai.AudioRequest{
+		Model:    openai.Whisper1,
+		FilePath: path,
+		FileName: "recording.wav",
+	})
+	checks.NoError(t, err, "CreateTranscription error")
+	if gotName != "recording.wav" {
+		t.Errorf("FileName should override the file path, got %q", gotName)
+	}
+}
@@ -0,0 +1,382 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAudioChunkFormat   = errors.New("chunked audio supports only the json, verbose_json and text response formats")
+	ErrAudioChunkTooSmall = errors.New("maximum chunk size is too small for the audio")
+	ErrWAVInvalid         = errors.New("invalid WAV audio")
+	ErrAudioEmpty         = errors.New("audio is empty")
+	ErrAudioUnsplittable  = errors.New("audio container cannot be split, only WAV and MP3 audio can")
+)
+
+// AudioMaxFileSize is the largest audio file accepted by the transcription and translation APIs.
+const AudioMaxFileSize = 25 << 20
+
+const (
+	defaultAudioChunkConcurrency   = 4
+	defaultAudioChunkSilenceWindow = 100 * time.Millisecond
+	// audioChunkSilenceSearch is the fraction of the end of a chunk searched for silence.
+	audioChunkSilenceSearch = 5
+)
+
+// AudioChunkOptions configures how audio is split into chunks by SplitAudio and the chunked
+// transcription and translation calls.
+type AudioChunkOptions struct {
+	// MaxChunkSize is the largest size of a chunk in bytes, AudioMaxFileSize by default.
+	MaxChunkSize int
+	// SplitOnSilence cuts 16-bit PCM WAV audio at the quietest moment of the last fifth of each
+	// chunk rather than at the size limit, so that words are not cut in half.
+	SplitOnSilence bool
+	// SilenceWindow is the length of audio compared when looking for silence, 100ms by default.
+	SilenceWindow time.Duration
+	// Concurrency is the number of chunks sent to the API at once, 4 by default.
+	Concurrency int
+}
+
+// AudioChunk is a segment of audio small enough for the API.
+type AudioChunk struct {
+	Data []byte
+	// Offset and Duration place the chunk in the original audio. Both are zero for MP3, which is
+	// split at byte boundaries without decoding it.
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// SplitAudio splits audio into chunks of at most MaxChunkSize bytes. WAV audio is split at frame
+// boundaries and each chunk is a WAV file of its own. Other audio is taken for MP3 and split at
+// byte boundaries, which MP3 decoders resynchronize on. M4A, Ogg, WebM and FLAC audio cannot
+// be split this way: larger than MaxChunkSize, it returns an error wrapping
+// ErrAudioUnsplittable, convert it to WAV or MP3 first.
+func SplitAudio(data []byte, options AudioChunkOptions) ([]AudioChunk, error) {
+	maxSize := options.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = AudioMaxFileSize
+	}
+	if isWAV(data) {
+		return splitWAV(data, maxSize, options)
+	}
+	if container := audioContainer(data); container != "" && len(data) > maxSize {
+		return nil, fmt.Errorf("%w: %s", ErrAudioUnsplittable, container)
+	}
+
+	var chunks []AudioChunk
+	for start := 0; start < len(data); start += maxSize {
+		end := start + maxSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, AudioChunk{Data: data[start:end]})
+	}
+	return chunks, nil
+}
+
+// CreateChunkedTranscription transcribes audio of any length by splitting it with SplitAudio,
+// transcribing the chunks concurrently and stitching the transcripts back together, with the
+// timestamps of segments and words shifted to the position of their chunk. The audio is read
+// into memory from Reader, or from FilePath when Reader is nil.
+func (c *Client) CreateChunkedTranscription(
+	ctx context.Context,
+	request AudioRequest,
+	options AudioChunkOptions,
+) (AudioResponse, error) {
+	return c.callChunkedAudioAPI(ctx, request, options, c.CreateTranscription)
+}
+
+// CreateChunkedTranslation translates audio of any length into English the way
+// CreateChunkedTranscription transcribes it.
+func (c *Client) CreateChunkedTranslation(
+	ctx context.Context,
+	request AudioRequest,
+	options AudioChunkOptions,
+) (AudioResponse, error) {
+	return c.callChunkedAudioAPI(ctx, request, options, c.CreateTranslation)
+}
+
+func (c *Client) callChunkedAudioAPI(
+	ctx context.Context,
+	request AudioRequest,
+	options AudioChunkOptions,
+	call func(context.Context, AudioRequest) (AudioResponse, error),
+) (AudioResponse, error) {
+	if !request.HasJSONResponse() && request.Format != AudioResponseFormatText {
+		return AudioResponse{}, ErrAudioChunkFormat
+	}
+
+	var data []byte
+	var err error
+	if request.Reader != nil {
+		data, err = io.ReadAll(request.Reader)
+	} else {
+		data, err = os.ReadFile(request.FilePath)
+	}
+	if err != nil {
+		return AudioResponse{}, fmt.Errorf("reading audio: %w", err)
+	}
+	chunks, err := SplitAudio(data, options)
+	if err != nil {
+		return AudioResponse{}, err
+	}
+	if len(chunks) == 0 {
+		return AudioResponse{}, ErrAudioEmpty
+	}
+
+	name := request.fileName()
+	if name == "" && isWAV(data) {
+		name = "audio.wav"
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultAudioChunkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	responses := make([]AudioResponse, len(chunks))
+	errs := make([]error, len(chunks))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		chunkRequest := request
+		chunkRequest.Reader = bytes.NewReader(chunk.Data)
+		chunkRequest.FilePath = ""
+		chunkRequest.FileName = name
+
+		wg.Add(1)
+		go func(i int, chunkRequest AudioRequest) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			responses[i], errs[i] = call(ctx, chunkRequest)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, chunkRequest)
+	}
+	wg.Wait()
+
+	// Report the error which canceled the other chunks rather than the cancellation.
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return AudioResponse{}, fmt.Errorf("audio chunk %d: %w", i, err)
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			return AudioResponse{}, fmt.Errorf("audio chunk %d: %w", i, err)
+		}
+	}
+	return stitchAudioResponses(chunks, responses), nil
+}
+
+// stitchAudioResponses joins the responses of chunks, shifting their timestamps by the offset of
+// the chunk. Chunks without a known offset are placed after the durations reported by the API.
+func stitchAudioResponses(chunks []AudioChunk, responses []AudioResponse) AudioResponse {
+	stitched := responses[0]
+	stitched.Segments = nil
+	stitched.Words = nil
+
+	texts := make([]string, 0, len(responses))
+	var offset float64
+	for i, response := range responses {
+		if chunks[i].Duration > 0 {
+			offset = chunks[i].Offset.Seconds()
+		}
+		for _, segment := range response.Segments {
+			segment.ID = len(stitched.Segments)
+			segment.Start += offset
+			segment.End += offset
+			stitched.Segments = append(stitched.Segments, segment)
+		}
+		for _, word := range response.Words {
+			word.Start += offset
+			word.End += offset
+			stitched.Words = append(stitched.Words, word)
+		}
+		if text := strings.TrimSpace(response.Text); text != "" {
+			texts = append(texts, text)
+		}
+
+		duration := response.Duration
+		if chunks[i].Duration > 0 {
+			duration = chunks[i].Duration.Seconds()
+		}
+		offset += duration
+	}
+	stitched.Text = strings.Join(texts, " ")
+	stitched.Duration = offset
+	return stitched
+}
+
+// wavAudio is the format and samples of a WAV file.
+type wavAudio struct {
+	format        []byte // body of the fmt chunk
+	audioFormat   uint16
+	sampleRate    uint32
+	blockAlign    uint16
+	bitsPerSample uint16
+	samples       []byte
+}
+
+const wavPCMFormat = 1
+
+func isWAV(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WAVE"
+}
+
+// audioContainer returns the name of the container of data if it is one whose frames cannot be
+// decoded when split at byte boundaries.
+func audioContainer(data []byte) string {
+	switch {
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return "m4a"
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "ogg"
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "webm"
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "flac"
+	}
+	return ""
+}
+
+func parseWAV(data []byte) (wavAudio, error) {
+	var wav wavAudio
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		if size < len(body) {
+			body = body[:size]
+		}
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return wav, fmt.Errorf("%w: short fmt chunk", ErrWAVInvalid)
+			}
+			wav.format = body
+			wav.audioFormat = binary.LittleEndian.Uint16(body[0:2])
+			wav.sampleRate = binary.LittleEndian.Uint32(body[4:8])
+			wav.blockAlign = binary.LittleEndian.Uint16(body[12:14])
+			wav.bitsPerSample = binary.LittleEndian.Uint16(body[14:16])
+		case "data":
+			wav.samples = body
+		}
+		pos += 8 + size + size&1
+	}
+
+	if wav.format == nil || wav.samples == nil {
+		return wav, fmt.Errorf("%w: missing fmt or data chunk", ErrWAVInvalid)
+	}
+	if wav.sampleRate == 0 || wav.blockAlign == 0 {
+		return wav, fmt.Errorf("%w: zero sample rate or block size", ErrWAVInvalid)
+	}
+	return wav, nil
+}
+
+// encode returns a WAV file of the given samples in the format of w.
+func (w wavAudio) encode(samples []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(w.headerSize() + len(samples))
+	buf.WriteString("RIFF")
+	writeUint32(&buf, w.headerSize()-8+len(samples))
+	buf.WriteString("WAVEfmt ")
+	writeUint32(&buf, len(w.format))
+	buf.Write(w.format)
+	if len(w.format)%2 == 1 {
+		buf.WriteByte(0)
+	}
+	buf.WriteString("data")
+	writeUint32(&buf, len(samples))
+	buf.Write(samples)
+	return buf.Bytes()
+}
+
+func writeUint32(buf *bytes.Buffer, n int) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(n))
+	buf.Write(b[:])
+}
+
+func (w wavAudio) headerSize() int {
+	return 12 + 8 + len(w.format) + len(w.format)%2 + 8
+}
+
+func (w wavAudio) duration(frames int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(w.sampleRate)
+}
+
+func splitWAV(data []byte, maxSize int, options AudioChunkOptions) ([]AudioChunk, error) {
+	wav, err := parseWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	frameSize := int(wav.blockAlign)
+	maxFrames := (maxSize - wav.headerSize()) / frameSize
+	if maxFrames <= 0 {
+		return nil, ErrAudioChunkTooSmall
+	}
+	totalFrames := len(wav.samples) / frameSize
+
+	var chunks []AudioChunk
+	for start := 0; start < totalFrames; {
+		end := start + maxFrames
+		if end >= totalFrames {
+			end = totalFrames
+		} else if options.SplitOnSilence && wav.audioFormat == wavPCMFormat && wav.bitsPerSample == 16 {
+			end = wav.quietestFrame(end-maxFrames/audioChunkSilenceSearch, end, options.SilenceWindow)
+		}
+		chunks = append(chunks, AudioChunk{
+			Data:     wav.encode(wav.samples[start*frameSize : end*frameSize]),
+			Offset:   wav.duration(start),
+			Duration: wav.duration(end - start),
+		})
+		start = end
+	}
+	return chunks, nil
+}
+
+// quietestFrame returns the middle of the window of 16-bit samples between the frames from and to
+// with the least energy.
+func (w wavAudio) quietestFrame(from, to int, window time.Duration) int {
+	if window <= 0 {
+		window = defaultAudioChunkSilenceWindow
+	}
+	windowFrames := int(time.Duration(w.sampleRate) * window / time.Second)
+	if windowFrames <= 0 || to-from < windowFrames {
+		return to
+	}
+
+	frameSize := int(w.blockAlign)
+	best, bestEnergy := to, int64(-1)
+	for start := from; start+windowFrames <= to; start += windowFrames {
+		var energy int64
+		samples := w.samples[start*frameSize : (start+windowFrames)*frameSize]
+		for i := 0; i+1 < len(samples); i += 2 {
+			sample := int64(int16(binary.LittleEndian.Uint16(samples[i : i+2])))
+			energy += sample * sample
+		}
+		if bestEnergy < 0 || energy < bestEnergy {
+			best, bestEnergy = start+windowFrames/2, energy
+		}
+	}
+	return best
+}
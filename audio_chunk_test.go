@@ -0,0 +1,194 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const testWAVRate = 1000
+
+// testWAV returns mono 16-bit PCM audio at testWAVRate Hz which is loud except between the
+// frames silenceFrom and silenceTo.
+func testWAV(frames, silenceFrom, silenceTo int) []byte {
+	var buf bytes.Buffer
+	write := func(v any) { _ = binary.Write(&buf, binary.LittleEndian, v) }
+	buf.WriteString("RIFF")
+	write(uint32(36 + frames*2))
+	buf.WriteString("WAVEfmt ")
+	write(uint32(16))
+	write(uint16(1))               // PCM
+	write(uint16(1))               // channels
+	write(uint32(testWAVRate))     // sample rate
+	write(uint32(testWAVRate * 2)) // byte rate
+	write(uint16(2))               // block align
+	write(uint16(16))              // bits per sample
+	buf.WriteString("data")
+	write(uint32(frames * 2))
+	for i := 0; i < frames; i++ {
+		sample := int16(10000)
+		if i%2 == 1 {
+			sample = -sample
+		}
+		if i >= silenceFrom && i < silenceTo {
+			sample = 0
+		}
+		write(sample)
+	}
+	return buf.Bytes()
+}
+
+func TestSplitAudioOnSilence(t *testing.T) {
+	audio := testWAV(3000, 1700, 1800)
+	chunks, err := openai.SplitAudio(audio, openai.AudioChunkOptions{
+		MaxChunkSize:   44 + 2000*2,
+		SplitOnSilence: true,
+	})
+	checks.NoError(t, err, "SplitAudio error")
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Duration != 1750*time.Millisecond || chunks[1].Offset != 1750*time.Millisecond {
+		t.Errorf("expected a cut in the silence at 1.75s, got %v and %v", chunks[0].Duration, chunks[1].Offset)
+	}
+	if chunks[1].Duration != 1250*time.Millisecond {
+		t.Errorf("unexpected duration of the last chunk %v", chunks[1].Duration)
+	}
+	for i, chunk := range chunks {
+		if string(chunk.Data[:4]) != "RIFF" || len(chunk.Data) != 44+int(chunk.Duration/time.Millisecond)*2 {
+			t.Errorf("chunk %d is not a WAV file of its samples", i)
+		}
+	}
+
+	chunks, err = openai.SplitAudio(audio, openai.AudioChunkOptions{MaxChunkSize: 44 + 2000*2})
+	checks.NoError(t, err, "SplitAudio error")
+	if chunks[0].Duration != 2*time.Second {
+		t.Errorf("expected a cut at the size limit, got %v", chunks[0].Duration)
+	}
+
+	_, err = openai.SplitAudio(audio, openai.AudioChunkOptions{MaxChunkSize: 40})
+	checks.ErrorIs(t, err, openai.ErrAudioChunkTooSmall, "SplitAudio should fail for tiny chunks")
+
+	_, err = openai.SplitAudio([]byte("RIFF\x00\x00\x00\x00WAVE"), openai.AudioChunkOptions{})
+	checks.ErrorIs(t, err, openai.ErrWAVInvalid, "SplitAudio should fail for WAV without data")
+}
+
+func TestSplitAudioBytes(t *testing.T) {
+	chunks, err := openai.SplitAudio([]byte("0123456789"), openai.AudioChunkOptions{MaxChunkSize: 4})
+	checks.NoError(t, err, "SplitAudio error")
+	if len(chunks) != 3 || string(chunks[2].Data) != "89" || chunks[1].Offset != 0 {
+		t.Errorf("unexpected chunks %+v", chunks)
+	}
+}
+
+func TestSplitAudioContainers(t *testing.T) {
+	ogg := append([]byte("OggS"), make([]byte, 20)...)
+	_, err := openai.SplitAudio(ogg, openai.AudioChunkOptions{MaxChunkSize: 10})
+	checks.ErrorIs(t, err, openai.ErrAudioUnsplittable, "Ogg audio should not be split at byte offsets")
+
+	// Audio small enough for a single chunk is sent as is.
+	chunks, err := openai.SplitAudio(ogg, openai.AudioChunkOptions{})
+	checks.NoError(t, err, "SplitAudio error")
+	if len(chunks) != 1 || len(chunks[0].Data) != len(ogg) {
+		t.Errorf("unexpected chunks %+v", chunks)
+	}
+
+	m4a := append([]byte("\x00\x00\x00\x20ftypM4A "), make([]byte, 20)...)
+	_, err = openai.SplitAudio(m4a, openai.AudioChunkOptions{MaxChunkSize: 10})
+	checks.ErrorIs(t, err, openai.ErrAudioUnsplittable, "M4A audio should not be split at byte offsets")
+}
+
+func TestCreateChunkedTranscription(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if header.Filename != "audio.wav" {
+			http.Error(w, "unexpected file name "+header.Filename, http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		frames := (len(data) - 44) / 2
+		duration := float64(frames) / testWAVRate
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"text":     fmt.Sprintf(" %d frames ", frames),
+			"duration": duration,
+			"segments": []map[string]any{{"id": 0, "start": 0, "end": duration, "text": "segment"}},
+			"words":    []map[string]any{{"word": "word", "start": 0.5, "end": 0.6}},
+		})
+	})
+
+	resp, err := client.CreateChunkedTranscription(context.Background(), openai.AudioRequest{
+		Model:  openai.Whisper1,
+		Reader: bytes.NewReader(testWAV(3000, 1700, 1800)),
+		Format: openai.AudioResponseFormatVerboseJSON,
+	}, openai.AudioChunkOptions{MaxChunkSize: 44 + 2000*2, SplitOnSilence: true})
+	checks.NoError(t, err, "CreateChunkedTranscription error")
+
+	if resp.Text != "1750 frames 1250 frames" {
+		t.Errorf("unexpected text %q", resp.Text)
+	}
+	if resp.Duration != 3 {
+		t.Errorf("unexpected duration %v", resp.Duration)
+	}
+	if len(resp.Segments) != 2 || resp.Segments[1].ID != 1 ||
+		resp.Segments[1].Start != 1.75 || resp.Segments[1].End != 3 {
+		t.Errorf("unexpected segments %+v", resp.Segments)
+	}
+	if len(resp.Words) != 2 || math.Abs(resp.Words[1].Start-2.25) > 1e-9 {
+		t.Errorf("unexpected words %+v", resp.Words)
+	}
+}
+
+func TestCreateChunkedTranscriptionErrors(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/audio/transcriptions", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":{"message":"bad audio"}}`, http.StatusBadRequest)
+	})
+
+	_, err := client.CreateChunkedTranscription(context.Background(), openai.AudioRequest{
+		Model:  openai.Whisper1,
+		Reader: bytes.NewReader([]byte("audio")),
+		Format: openai.AudioResponseFormatSRT,
+	}, openai.AudioChunkOptions{})
+	checks.ErrorIs(t, err, openai.ErrAudioChunkFormat, "SRT should not be supported")
+
+	_, err = client.CreateChunkedTranscription(context.Background(), openai.AudioRequest{
+		Model:       openai.Whisper1,
+		Reader:      bytes.NewReader([]byte("0123456789")),
+		AudioFormat: "mp3",
+	}, openai.AudioChunkOptions{MaxChunkSize: 2, Concurrency: 2})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "bad audio" {
+		t.Errorf("expected the API error of a chunk, got %v", err)
+	}
+
+	_, err = client.CreateChunkedTranscription(context.Background(), openai.AudioRequest{
+		Model:  openai.Whisper1,
+		Reader: bytes.NewReader(nil),
+	}, openai.AudioChunkOptions{})
+	checks.ErrorIs(t, err, openai.ErrAudioEmpty, "empty audio should be rejected")
+
+	_, err = client.CreateChunkedTranscription(context.Background(), openai.AudioRequest{
+		Model:  openai.Whisper1,
+		Reader: bytes.NewReader(testWAV(0, 0, 0)),
+	}, openai.AudioChunkOptions{})
+	checks.ErrorIs(t, err, openai.ErrAudioEmpty, "WAV audio without samples should be rejected")
+}
@@ -0,0 +1,61 @@
+package openai
+
+import "net/http"
+
+// AuthStyle is how the API key of a client is sent, for gateways and providers with
+// nonstandard authentication. Set ClientConfig.AuthStyle to override the style of the APIType.
+type AuthStyle struct {
+	// Header is the name of the header carrying the key, Authorization by default.
+	Header string
+	// Scheme, if set, prefixes the key in the header, e.g. "Bearer".
+	Scheme string
+	// QueryParam, if set, sends the key as this query parameter instead of a header.
+	QueryParam string
+}
+
+// AuthBearer sends the key as "Authorization: Bearer <key>", as the OpenAI API expects.
+func AuthBearer() *AuthStyle {
+	return &AuthStyle{Header: "Authorization", Scheme: "Bearer"}
+}
+
+// AuthAPIKeyHeader sends the key in the api-key header, as Azure OpenAI expects.
+func AuthAPIKeyHeader() *AuthStyle {
+	return &AuthStyle{Header: AzureAPIKeyHeader}
+}
+
+// AuthXAPIKeyHeader sends the key in the x-api-key header, as Anthropic and many gateways
+// expect.
+func AuthXAPIKeyHeader() *AuthStyle {
+	return &AuthStyle{Header: "x-api-key"}
+}
+
+// AuthHeader sends the key as is in the header name.
+func AuthHeader(name string) *AuthStyle {
+	return &AuthStyle{Header: name}
+}
+
+// AuthQueryParam sends the key as the query parameter name, e.g. "key".
+func AuthQueryParam(name string) *AuthStyle {
+	return &AuthStyle{QueryParam: name}
+}
+
+// apply sets token on req. Nothing is sent for an empty token.
+func (a *AuthStyle) apply(req *http.Request, token string) {
+	if token == "" {
+		return
+	}
+	if a.QueryParam != "" {
+		query := req.URL.Query()
+		query.Set(a.QueryParam, token)
+		req.URL.RawQuery = query.Encode()
+		return
+	}
+	header := a.Header
+	if header == "" {
+		header = "Authorization"
+	}
+	if a.Scheme != "" {
+		token = a.Scheme + " " + token
+	}
+	req.Header.Set(header, token)
+}
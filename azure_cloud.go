@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrAzureCloudMismatch is returned by the requests of a client whose BaseURL is not an
+// endpoint of its AzureCloud, so that requests meant for a sovereign cloud are never sent to
+// another one.
+var ErrAzureCloudMismatch = errors.New("base URL does not match the Azure cloud")
+
+// AzureCloud describes the endpoints of an Azure cloud, see
+// https://learn.microsoft.com/en-us/azure/ai-services/openai/azure-government.
+type AzureCloud struct {
+	Name string
+	// EndpointSuffixes are the domains of the Azure OpenAI resources of the cloud. The first one
+	// is used by ResourceURL.
+	EndpointSuffixes []string
+	// TokenAudience is the audience of the Microsoft Entra ID (Azure AD) tokens of the cloud.
+	TokenAudience string
+	// AuthorityHost is the Microsoft Entra ID authority of the cloud.
+	AuthorityHost string
+}
+
+var (
+	AzurePublicCloud = AzureCloud{
+		Name:             "AzurePublicCloud",
+		EndpointSuffixes: []string{"openai.azure.com", "cognitiveservices.azure.com", "services.ai.azure.com"},
+		TokenAudience:    "https://cognitiveservices.azure.com",
+		AuthorityHost:    "https://login.microsoftonline.com/",
+	}
+	AzureGovernmentCloud = AzureCloud{
+		Name:             "AzureUSGovernment",
+		EndpointSuffixes: []string{"openai.azure.us", "cognitiveservices.azure.us"},
+		TokenAudience:    "https://cognitiveservices.azure.us",
+		AuthorityHost:    "https://login.microsoftonline.us/",
+	}
+	AzureChinaCloud = AzureCloud{
+		Name:             "AzureChinaCloud",
+		EndpointSuffixes: []string{"openai.azure.cn", "cognitiveservices.azure.cn"},
+		TokenAudience:    "https://cognitiveservices.azure.cn",
+		AuthorityHost:    "https://login.chinacloudapi.cn/",
+	}
+)
+
+// TokenScope returns the scope to request Azure AD tokens for, e.g. with the azidentity
+// credentials of the Azure SDK.
+func (c AzureCloud) TokenScope() string {
+	return strings.TrimRight(c.TokenAudience, "/") + "/.default"
+}
+
+// ResourceURL returns the endpoint of the Azure OpenAI resource named resource.
+func (c AzureCloud) ResourceURL(resource string) string {
+	if len(c.EndpointSuffixes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("https://%s.%s", resource, c.EndpointSuffixes[0])
+}
+
+// ValidateBaseURL returns an error wrapping ErrAzureCloudMismatch unless baseURL is an HTTPS
+// endpoint under one of the EndpointSuffixes of the cloud.
+func (c AzureCloud) ValidateBaseURL(baseURL string) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if parsed.Scheme == "https" {
+		for _, suffix := range c.EndpointSuffixes {
+			if strings.HasSuffix(host, "."+strings.ToLower(suffix)) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %q is not an endpoint of %s", ErrAzureCloudMismatch, baseURL, c.Name)
+}
+
+// DefaultAzureCloudConfig returns a config for the Azure OpenAI resource named resource in
+// cloud, e.g. AzureGovernmentCloud. To authenticate with Azure AD instead of an API key, set
+// APIType to APITypeAzureAD and pass a token requested for cloud.TokenScope().
+func DefaultAzureCloudConfig(apiKey, resource string, cloud AzureCloud) ClientConfig {
+	config := DefaultAzureConfig(apiKey, cloud.ResourceURL(resource))
+	config.AzureCloud = &cloud
+	return config
+}
@@ -0,0 +1,74 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+type recordingDoer struct {
+	requests []*http.Request
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+	return nil, errors.New("not sent")
+}
+
+func TestAzureCloudValidateBaseURL(t *testing.T) {
+	for _, test := range []struct {
+		cloud   openai.AzureCloud
+		baseURL string
+		valid   bool
+	}{
+		{openai.AzurePublicCloud, "https://my-resource.openai.azure.com/", true},
+		{openai.AzurePublicCloud, "https://my-resource.cognitiveservices.azure.com", true},
+		{openai.AzureGovernmentCloud, "https://my-resource.openai.azure.us", true},
+		{openai.AzureChinaCloud, "https://My-Resource.OpenAI.Azure.CN", true},
+		{openai.AzureGovernmentCloud, "https://my-resource.openai.azure.com", false},
+		{openai.AzureChinaCloud, "https://my-resource.openai.azure.us", false},
+		{openai.AzureGovernmentCloud, "http://my-resource.openai.azure.us", false},
+		{openai.AzureGovernmentCloud, "https://openai.azure.us.example.com", false},
+	} {
+		err := test.cloud.ValidateBaseURL(test.baseURL)
+		if test.valid {
+			checks.NoError(t, err, test.baseURL)
+		} else if !errors.Is(err, openai.ErrAzureCloudMismatch) {
+			t.Errorf("expected ErrAzureCloudMismatch for %s in %s, got %v", test.baseURL, test.cloud.Name, err)
+		}
+	}
+}
+
+func TestAzureCloudTokenScope(t *testing.T) {
+	if scope := openai.AzureGovernmentCloud.TokenScope(); scope != "https://cognitiveservices.azure.us/.default" {
+		t.Errorf("unexpected government scope %q", scope)
+	}
+	if scope := openai.AzureChinaCloud.TokenScope(); scope != "https://cognitiveservices.azure.cn/.default" {
+		t.Errorf("unexpected china scope %q", scope)
+	}
+}
+
+func TestDefaultAzureCloudConfig(t *testing.T) {
+	doer := &recordingDoer{}
+	config := openai.DefaultAzureCloudConfig("key", "my-resource", openai.AzureGovernmentCloud)
+	config.HTTPClient = doer
+	client := openai.NewClientWithConfig(config)
+	_, _ = client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if len(doer.requests) != 1 || doer.requests[0].URL.Host != "my-resource.openai.azure.us" {
+		t.Fatalf("unexpected requests %+v", doer.requests)
+	}
+
+	config.BaseURL = "https://my-resource.openai.azure.com"
+	client = openai.NewClientWithConfig(config)
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{Model: "gpt-4o"})
+	if !errors.Is(err, openai.ErrAzureCloudMismatch) {
+		t.Errorf("expected ErrAzureCloudMismatch, got %v", err)
+	}
+	if len(doer.requests) != 1 {
+		t.Errorf("expected the mismatched request not to be sent, got %d requests", len(doer.requests))
+	}
+}
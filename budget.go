@@ -0,0 +1,161 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned for the requests rejected because a Budget is spent.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// BudgetWindow is the period of a budget limit.
+type BudgetWindow string
+
+const (
+	// BudgetWindowMinute is the current minute.
+	BudgetWindowMinute BudgetWindow = "minute"
+	// BudgetWindowDay is the current UTC day.
+	BudgetWindowDay BudgetWindow = "day"
+)
+
+// BudgetAlert describes a request made once a limit of a Budget was reached.
+type BudgetAlert struct {
+	Window BudgetWindow
+	// Limit and Spent are in USD.
+	Limit float64
+	Spent float64
+	// Rejected reports whether the request was rejected, false if the budget only flags it.
+	Rejected bool
+}
+
+// Budget is a spend guardrail tracking the estimated cost of the responses, from their usage
+// and a price table, per minute and per UTC day. Once a limit is reached, further requests are
+// rejected with ErrBudgetExceeded until the window ends, or only flagged. Attach it to every
+// request of a client with ClientConfig.Budget, or to some requests with WithBudget. A Budget
+// can be shared by several clients and is safe for concurrent use.
+//
+// The spend is estimated once responses are read, so concurrent requests and the request
+// reaching a limit can overshoot it.
+type Budget struct {
+	// PerMinute and PerDay are the limits in USD. Zero means no limit.
+	PerMinute float64
+	PerDay    float64
+	// FlagOnly lets the requests over a limit through, only calling OnExceeded.
+	FlagOnly bool
+	// OnExceeded, if set, is called for each request made over a limit, e.g. for alerting.
+	OnExceeded func(BudgetAlert)
+	// Prices are the prices of the models, DefaultModelPrices if nil. Responses of models
+	// without a price are not counted.
+	Prices map[string]ModelPrice
+
+	mu          sync.Mutex
+	minute      time.Time
+	day         time.Time
+	minuteSpent float64
+	daySpent    float64
+}
+
+// Spent returns the spend of the current minute and UTC day in USD.
+func (b *Budget) Spent() (minute, day float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(time.Now())
+	return b.minuteSpent, b.daySpent
+}
+
+// Add adds cost in USD to the spend, e.g. for calls not made through a client.
+func (b *Budget) Add(cost float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(time.Now())
+	b.minuteSpent += cost
+	b.daySpent += cost
+}
+
+// Record adds the estimated cost of usage with model to the spend and returns it.
+func (b *Budget) Record(model string, usage Usage) float64 {
+	cost, ok := EstimateCost(b.Prices, model, usage)
+	if ok && cost > 0 {
+		b.Add(cost)
+	}
+	return cost
+}
+
+// roll resets the spend of the windows that ended before now.
+func (b *Budget) roll(now time.Time) {
+	if minute := now.Truncate(time.Minute); !minute.Equal(b.minute) {
+		b.minute, b.minuteSpent = minute, 0
+	}
+	year, month, day := now.UTC().Date()
+	if start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); !start.Equal(b.day) {
+		b.day, b.daySpent = start, 0
+	}
+}
+
+// check returns the alert of the first limit reached, if any.
+func (b *Budget) check() (BudgetAlert, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(time.Now())
+	alert := BudgetAlert{Rejected: !b.FlagOnly}
+	switch {
+	case b.PerMinute > 0 && b.minuteSpent >= b.PerMinute:
+		alert.Window, alert.Limit, alert.Spent = BudgetWindowMinute, b.PerMinute, b.minuteSpent
+	case b.PerDay > 0 && b.daySpent >= b.PerDay:
+		alert.Window, alert.Limit, alert.Spent = BudgetWindowDay, b.PerDay, b.daySpent
+	default:
+		return BudgetAlert{}, false
+	}
+	return alert, true
+}
+
+type budgetContextKey struct{}
+
+// WithBudget returns a context whose requests are accounted to and limited by budget, in
+// addition to the budget of the client if any.
+func WithBudget(ctx context.Context, budget *Budget) context.Context {
+	return context.WithValue(ctx, budgetContextKey{}, budget)
+}
+
+// budgetDoer enforces the budget of a client and the budget of the context of each request.
+type budgetDoer struct {
+	doer   HTTPDoer
+	budget *Budget
+}
+
+func (d *budgetDoer) Do(req *http.Request) (*http.Response, error) {
+	budgets := make([]*Budget, 0, 2)
+	if d.budget != nil {
+		budgets = append(budgets, d.budget)
+	}
+	if budget, _ := req.Context().Value(budgetContextKey{}).(*Budget); budget != nil && budget != d.budget {
+		budgets = append(budgets, budget)
+	}
+	if len(budgets) == 0 {
+		return d.doer.Do(req)
+	}
+
+	for _, budget := range budgets {
+		alert, exceeded := budget.check()
+		if !exceeded {
+			continue
+		}
+		if budget.OnExceeded != nil {
+			budget.OnExceeded(alert)
+		}
+		if alert.Rejected {
+			return nil, fmt.Errorf("%w: spent $%.4f of the $%.4f budget of the %s",
+				ErrBudgetExceeded, alert.Spent, alert.Limit, alert.Window)
+		}
+	}
+	observer := &usageObserverDoer{doer: d.doer, observe: func(_ *http.Request, model string, usage Usage) {
+		for _, budget := range budgets {
+			budget.Record(model, usage)
+		}
+	}}
+	return observer.Do(req)
+}
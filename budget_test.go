@@ -0,0 +1,111 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// setupBudgetServer answers chat completions with a usage of one million prompt tokens of
+// gpt-4o, costing $2.50.
+func setupBudgetServer(t *testing.T, budget *openai.Budget) *openai.Client {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.Budget = budget
+	})
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"model\":\"gpt-4o-2024-08-06\",\"choices\":[],"+
+				"\"usage\":{\"prompt_tokens\":1000000,\"completion_tokens\":0,\"total_tokens\":1000000}}\n\n"+
+				"data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","model":"gpt-4o-2024-08-06","choices":[],`+
+			`"usage":{"prompt_tokens":1000000,"completion_tokens":0,"total_tokens":1000000}}`)
+	})
+	return client
+}
+
+var budgetRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4o,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+}
+
+func TestBudgetRejectsRequestsOverLimit(t *testing.T) {
+	var alerts []openai.BudgetAlert
+	budget := &openai.Budget{PerDay: 2, OnExceeded: func(alert openai.BudgetAlert) {
+		alerts = append(alerts, alert)
+	}}
+	client := setupBudgetServer(t, budget)
+
+	_, err := client.CreateChatCompletion(context.Background(), budgetRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if _, day := budget.Spent(); day != 2.5 {
+		t.Errorf("expected $2.50 spent, got %v", day)
+	}
+	_, err = client.CreateChatCompletion(context.Background(), budgetRequest)
+	if !errors.Is(err, openai.ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	want := openai.BudgetAlert{Window: openai.BudgetWindowDay, Limit: 2, Spent: 2.5, Rejected: true}
+	if len(alerts) != 1 || alerts[0] != want {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+}
+
+func TestBudgetFlagOnly(t *testing.T) {
+	var alerts []openai.BudgetAlert
+	budget := &openai.Budget{PerMinute: 1, FlagOnly: true, OnExceeded: func(alert openai.BudgetAlert) {
+		alerts = append(alerts, alert)
+	}}
+	client := setupBudgetServer(t, budget)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateChatCompletion(context.Background(), budgetRequest)
+		checks.NoError(t, err, "CreateChatCompletion error")
+	}
+	if len(alerts) != 1 || alerts[0].Window != openai.BudgetWindowMinute || alerts[0].Rejected {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+	if minute, day := budget.Spent(); minute != 5 || day != 5 {
+		t.Errorf("expected $5 spent, got %v and %v", minute, day)
+	}
+}
+
+func TestBudgetFromContextCountsStreams(t *testing.T) {
+	client := setupBudgetServer(t, nil)
+	budget := &openai.Budget{PerDay: 10}
+	ctx := openai.WithBudget(context.Background(), budget)
+
+	stream, err := client.CreateChatCompletionStream(ctx, budgetRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	stream.Close()
+	_, err = client.CreateChatCompletion(context.Background(), budgetRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	if _, day := budget.Spent(); day != 2.5 {
+		t.Errorf("expected only the stream to be accounted, got $%v", day)
+	}
+}
+
+func TestBudgetAdd(t *testing.T) {
+	budget := &openai.Budget{}
+	budget.Add(0.25)
+	if cost := budget.Record("unknown-model", openai.Usage{PromptTokens: 100}); cost != 0 {
+		t.Errorf("expected no cost for an unknown model, got %v", cost)
+	}
+	if minute, day := budget.Spent(); minute != 0.25 || day != 0.25 {
+		t.Errorf("unexpected spend %v and %v", minute, day)
+	}
+}
@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cancelOnDoneTimeout bounds the cancel request sent by CancelOnDone.
+const cancelOnDoneTimeout = 30 * time.Second
+
+// Canceler cancels server-side work, such as a background response, a run, a batch or a
+// fine-tuning job.
+type Canceler interface {
+	Cancel(ctx context.Context) error
+}
+
+// CancelerFunc is a function used as a Canceler.
+type CancelerFunc func(ctx context.Context) error
+
+func (f CancelerFunc) Cancel(ctx context.Context) error {
+	return f(ctx)
+}
+
+// ResponseCanceler returns the Canceler of a background response.
+func (c *Client) ResponseCanceler(responseID string) Canceler {
+	return CancelerFunc(func(ctx context.Context) error {
+		_, err := c.CancelResponse(ctx, responseID)
+		return err
+	})
+}
+
+// RunCanceler returns the Canceler of a run.
+func (c *Client) RunCanceler(threadID, runID string) Canceler {
+	return CancelerFunc(func(ctx context.Context) error {
+		_, err := c.CancelRun(ctx, threadID, runID)
+		return err
+	})
+}
+
+// BatchCanceler returns the Canceler of a batch.
+func (c *Client) BatchCanceler(batchID string) Canceler {
+	return CancelerFunc(func(ctx context.Context) error {
+		_, err := c.CancelBatch(ctx, batchID)
+		return err
+	})
+}
+
+// FineTuningJobCanceler returns the Canceler of a fine-tuning job.
+func (c *Client) FineTuningJobCanceler(fineTuningJobID string) Canceler {
+	return CancelerFunc(func(ctx context.Context) error {
+		_, err := c.CancelFineTuningJob(ctx, fineTuningJobID)
+		return err
+	})
+}
+
+// VectorStoreFileBatchCanceler returns the Canceler of a vector store file batch.
+func (c *Client) VectorStoreFileBatchCanceler(vectorStoreID, batchID string) Canceler {
+	return CancelerFunc(func(ctx context.Context) error {
+		_, err := c.CancelVectorStoreFileBatch(ctx, vectorStoreID, batchID)
+		return err
+	})
+}
+
+// CancelOnDone cancels the work of canceler once ctx is done, so that abandoning a call such as
+// waiting for a run does not leave the work running and billed on the server. The cancel
+// request is sent with a new context, as ctx is already done, and its error is passed to
+// onError if not nil. Calling stop after the work completed prevents the cancellation; stop
+// reports whether it did so, like context.AfterFunc.
+func CancelOnDone(ctx context.Context, canceler Canceler, onError func(error)) (stop func() bool) {
+	const (
+		waiting int32 = iota
+		canceling
+		stopped
+	)
+	var state int32
+	stopCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			if !atomic.CompareAndSwapInt32(&state, waiting, canceling) {
+				return
+			}
+			cancelCtx, cancel := context.WithTimeout(context.Background(), cancelOnDoneTimeout)
+			defer cancel()
+			if err := canceler.Cancel(cancelCtx); err != nil && onError != nil {
+				onError(err)
+			}
+		case <-stopCh:
+		}
+	}()
+
+	var once sync.Once
+	return func() bool {
+		prevented := atomic.CompareAndSwapInt32(&state, waiting, stopped)
+		once.Do(func() { close(stopCh) })
+		return prevented
+	}
+}
@@ -0,0 +1,122 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCancelers(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	testCases := []struct {
+		path     string
+		canceler openai.Canceler
+	}{
+		{"/v1/responses/resp_1/cancel", client.ResponseCanceler("resp_1")},
+		{"/v1/threads/thread_1/runs/run_1/cancel", client.RunCanceler("thread_1", "run_1")},
+		{"/v1/batches/batch_1/cancel", client.BatchCanceler("batch_1")},
+		{"/v1/fine_tuning/jobs/ftjob_1/cancel", client.FineTuningJobCanceler("ftjob_1")},
+		{
+			"/v1/vector_stores/vs_1/file_batches/vsfb_1/cancel",
+			client.VectorStoreFileBatchCanceler("vs_1", "vsfb_1"),
+		},
+	}
+	for _, tc := range testCases {
+		var calls int32
+		server.RegisterHandler(tc.path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("unexpected method %s for %s", r.Method, r.URL.Path)
+			}
+			atomic.AddInt32(&calls, 1)
+			fmt.Fprint(w, `{"id":"1","status":"cancelling"}`)
+		})
+		checks.NoError(t, tc.canceler.Cancel(context.Background()), "Cancel error")
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Errorf("expected a cancel request to %s", tc.path)
+		}
+	}
+}
+
+func TestCancelResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/responses/resp_1/cancel", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"cancelled"}`)
+	})
+
+	response, err := client.CancelResponse(context.Background(), "resp_1")
+	checks.NoError(t, err, "CancelResponse error")
+	if response.Status != "cancelled" {
+		t.Errorf("unexpected status %q", response.Status)
+	}
+}
+
+func TestCancelOnDone(t *testing.T) {
+	canceled := make(chan struct{})
+	canceler := openai.CancelerFunc(func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Error("expected a live context for the cancel request")
+		}
+		close(canceled)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := openai.CancelOnDone(ctx, canceler, nil)
+	cancel()
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the work to be canceled")
+	}
+	if stop() {
+		t.Error("expected stop to report the cancellation already happened")
+	}
+}
+
+func TestCancelOnDoneStopped(t *testing.T) {
+	var calls int32
+	canceler := openai.CancelerFunc(func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := openai.CancelOnDone(ctx, canceler, nil)
+	if !stop() {
+		t.Error("expected stop to prevent the cancellation")
+	}
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Error("unexpected cancellation after stop")
+	}
+}
+
+func TestCancelOnDoneError(t *testing.T) {
+	errCancel := errors.New("cancel failed")
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	openai.CancelOnDone(ctx, openai.CancelerFunc(func(context.Context) error {
+		return errCancel
+	}), func(err error) { errs <- err })
+	cancel()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, errCancel) {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the cancel error")
+	}
+}
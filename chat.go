@@ -1,10 +1,14 @@
 package openai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+
+	utils "github.com/sashabaranov/go-openai/internal"
 )
 
 // Chat message role defined by the OpenAI API.
@@ -25,21 +29,32 @@ var (
 	ErrContentFieldsMisused             = errors.New("can't use both Content and MultiContent properties simultaneously")
 )
 
+// ContentFilterSeverity is the severity level of a category of the Azure OpenAI content
+// filters.
+type ContentFilterSeverity = string
+
+const (
+	ContentFilterSeveritySafe   ContentFilterSeverity = "safe"
+	ContentFilterSeverityLow    ContentFilterSeverity = "low"
+	ContentFilterSeverityMedium ContentFilterSeverity = "medium"
+	ContentFilterSeverityHigh   ContentFilterSeverity = "high"
+)
+
 type Hate struct {
-	Filtered bool   `json:"filtered"`
-	Severity string `json:"severity,omitempty"`
+	Filtered bool                  `json:"filtered"`
+	Severity ContentFilterSeverity `json:"severity,omitempty"`
 }
 type SelfHarm struct {
-	Filtered bool   `json:"filtered"`
-	Severity string `json:"severity,omitempty"`
+	Filtered bool                  `json:"filtered"`
+	Severity ContentFilterSeverity `json:"severity,omitempty"`
 }
 type Sexual struct {
-	Filtered bool   `json:"filtered"`
-	Severity string `json:"severity,omitempty"`
+	Filtered bool                  `json:"filtered"`
+	Severity ContentFilterSeverity `json:"severity,omitempty"`
 }
 type Violence struct {
-	Filtered bool   `json:"filtered"`
-	Severity string `json:"severity,omitempty"`
+	Filtered bool                  `json:"filtered"`
+	Severity ContentFilterSeverity `json:"severity,omitempty"`
 }
 
 type JailBreak struct {
@@ -52,13 +67,67 @@ type Profanity struct {
 	Detected bool `json:"detected"`
 }
 
+// ContentFilterDetection is the result of a filter detecting content, such as protected
+// material or indirect attacks.
+type ContentFilterDetection struct {
+	Filtered bool `json:"filtered"`
+	Detected bool `json:"detected"`
+}
+
+// ProtectedMaterialCode is the result of the protected material filter for code, with the
+// citation of the public repository the code comes from.
+type ProtectedMaterialCode struct {
+	Filtered bool                           `json:"filtered"`
+	Detected bool                           `json:"detected"`
+	Citation *ProtectedMaterialCodeCitation `json:"citation,omitempty"`
+}
+
+type ProtectedMaterialCodeCitation struct {
+	URL     string `json:"URL,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+// CustomBlocklists is the result of the custom blocklists of an Azure OpenAI deployment.
+type CustomBlocklists struct {
+	Filtered bool                     `json:"filtered"`
+	Details  []CustomBlocklistDetails `json:"details,omitempty"`
+}
+
+type CustomBlocklistDetails struct {
+	ID       string `json:"id"`
+	Filtered bool   `json:"filtered"`
+}
+
+// ContentFilterError is set instead of the results when the content filters could not run.
+type ContentFilterError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ContentFilterResults are the results of the Azure OpenAI content filters. The categories
+// that were not evaluated are nil, or zero for the oldest ones.
 type ContentFilterResults struct {
-	Hate      Hate      `json:"hate,omitempty"`
-	SelfHarm  SelfHarm  `json:"self_harm,omitempty"`
-	Sexual    Sexual    `json:"sexual,omitempty"`
-	Violence  Violence  `json:"violence,omitempty"`
-	JailBreak JailBreak `json:"jailbreak,omitempty"`
-	Profanity Profanity `json:"profanity,omitempty"`
+	Hate                  Hate                    `json:"hate,omitempty"`
+	SelfHarm              SelfHarm                `json:"self_harm,omitempty"`
+	Sexual                Sexual                  `json:"sexual,omitempty"`
+	Violence              Violence                `json:"violence,omitempty"`
+	JailBreak             JailBreak               `json:"jailbreak,omitempty"`
+	Profanity             Profanity               `json:"profanity,omitempty"`
+	IndirectAttack        *ContentFilterDetection `json:"indirect_attack,omitempty"`
+	ProtectedMaterialText *ContentFilterDetection `json:"protected_material_text,omitempty"`
+	ProtectedMaterialCode *ProtectedMaterialCode  `json:"protected_material_code,omitempty"`
+	CustomBlocklists      *CustomBlocklists       `json:"custom_blocklists,omitempty"`
+	Error                 *ContentFilterError     `json:"error,omitempty"`
+}
+
+// Filtered reports whether any content filter filtered the content.
+func (r ContentFilterResults) Filtered() bool {
+	return r.Hate.Filtered || r.SelfHarm.Filtered || r.Sexual.Filtered || r.Violence.Filtered ||
+		r.JailBreak.Filtered || r.Profanity.Filtered ||
+		(r.IndirectAttack != nil && r.IndirectAttack.Filtered) ||
+		(r.ProtectedMaterialText != nil && r.ProtectedMaterialText.Filtered) ||
+		(r.ProtectedMaterialCode != nil && r.ProtectedMaterialCode.Filtered) ||
+		(r.CustomBlocklists != nil && r.CustomBlocklists.Filtered)
 }
 
 type PromptAnnotation struct {
@@ -82,20 +151,19 @@ type ChatMessageImageURL struct {
 type ChatMessagePartType string
 
 const (
-	ChatMessagePartTypeText     ChatMessagePartType = "text"
-	ChatMessagePartTypeImageURL ChatMessagePartType = "image_url"
+	ChatMessagePartTypeText       ChatMessagePartType = "text"
+	ChatMessagePartTypeImageURL   ChatMessagePartType = "image_url"
+	ChatMessagePartTypeInputAudio ChatMessagePartType = "input_audio"
+	ChatMessagePartTypeFile       ChatMessagePartType = "file"
+	ChatMessagePartTypeRefusal    ChatMessagePartType = "refusal"
 )
 
-type ChatMessagePart struct {
-	Type     ChatMessagePartType  `json:"type,omitempty"`
-	Text     string               `json:"text,omitempty"`
-	ImageURL *ChatMessageImageURL `json:"image_url,omitempty"`
-}
-
 type ChatCompletionMessage struct {
-	Role         string `json:"role"`
-	Content      string `json:"content,omitempty"`
-	Refusal      string `json:"refusal,omitempty"`
+	Role    string `json:"role"`
+	Content string `json:"content,omitempty"`
+	Refusal string `json:"refusal,omitempty"`
+	// MultiContent is the content of multi-part messages, such as text and images. Only one of
+	// Content and MultiContent may be set.
 	MultiContent []ChatMessagePart
 
 	// This property isn't in the official documentation, but it's in
@@ -110,6 +178,9 @@ type ChatCompletionMessage struct {
 	// - https://api-docs.deepseek.com/api/create-chat-completion#responses
 	ReasoningContent string `json:"reasoning_content,omitempty"`
 
+	// ThinkingBlocks are the extended thinking blocks of Anthropic models.
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
+
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
 
 	// For Role=assistant prompts this may be set to the tool calls generated by the model, such as function calls.
@@ -131,6 +202,7 @@ func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 			MultiContent     []ChatMessagePart `json:"content,omitempty"`
 			Name             string            `json:"name,omitempty"`
 			ReasoningContent string            `json:"reasoning_content,omitempty"`
+			ThinkingBlocks   []ThinkingBlock   `json:"thinking_blocks,omitempty"`
 			FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
 			ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
 			ToolCallID       string            `json:"tool_call_id,omitempty"`
@@ -145,6 +217,7 @@ func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 		MultiContent     []ChatMessagePart `json:"-"`
 		Name             string            `json:"name,omitempty"`
 		ReasoningContent string            `json:"reasoning_content,omitempty"`
+		ThinkingBlocks   []ThinkingBlock   `json:"thinking_blocks,omitempty"`
 		FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
 		ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
 		ToolCallID       string            `json:"tool_call_id,omitempty"`
@@ -154,37 +227,44 @@ func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
 
 func (m *ChatCompletionMessage) UnmarshalJSON(bs []byte) error {
 	msg := struct {
-		Role             string `json:"role"`
-		Content          string `json:"content"`
-		Refusal          string `json:"refusal,omitempty"`
-		MultiContent     []ChatMessagePart
-		Name             string        `json:"name,omitempty"`
-		ReasoningContent string        `json:"reasoning_content,omitempty"`
-		FunctionCall     *FunctionCall `json:"function_call,omitempty"`
-		ToolCalls        []ToolCall    `json:"tool_calls,omitempty"`
-		ToolCallID       string        `json:"tool_call_id,omitempty"`
+		Role             string          `json:"role"`
+		Content          json.RawMessage `json:"content"`
+		Refusal          string          `json:"refusal,omitempty"`
+		Name             string          `json:"name,omitempty"`
+		ReasoningContent string          `json:"reasoning_content,omitempty"`
+		ThinkingBlocks   []ThinkingBlock `json:"thinking_blocks,omitempty"`
+		FunctionCall     *FunctionCall   `json:"function_call,omitempty"`
+		ToolCalls        []ToolCall      `json:"tool_calls,omitempty"`
+		ToolCallID       string          `json:"tool_call_id,omitempty"`
 	}{}
+	if err := json.Unmarshal(bs, &msg); err != nil {
+		return err
+	}
 
-	if err := json.Unmarshal(bs, &msg); err == nil {
-		*m = ChatCompletionMessage(msg)
-		return nil
+	*m = ChatCompletionMessage{
+		Role:             msg.Role,
+		Refusal:          msg.Refusal,
+		Name:             msg.Name,
+		ReasoningContent: msg.ReasoningContent,
+		ThinkingBlocks:   msg.ThinkingBlocks,
+		FunctionCall:     msg.FunctionCall,
+		ToolCalls:        msg.ToolCalls,
+		ToolCallID:       msg.ToolCallID,
 	}
-	multiMsg := struct {
-		Role             string `json:"role"`
-		Content          string
-		Refusal          string            `json:"refusal,omitempty"`
-		MultiContent     []ChatMessagePart `json:"content"`
-		Name             string            `json:"name,omitempty"`
-		ReasoningContent string            `json:"reasoning_content,omitempty"`
-		FunctionCall     *FunctionCall     `json:"function_call,omitempty"`
-		ToolCalls        []ToolCall        `json:"tool_calls,omitempty"`
-		ToolCallID       string            `json:"tool_call_id,omitempty"`
-	}{}
-	if err := json.Unmarshal(bs, &multiMsg); err != nil {
-		return err
+	content := bytes.TrimSpace(msg.Content)
+	switch {
+	case len(content) == 0 || bytes.Equal(content, []byte("null")):
+		return nil
+	case content[0] == '[':
+		parts, err := unmarshalChatMessageParts(content)
+		if err != nil {
+			return err
+		}
+		m.MultiContent = parts
+		return nil
+	default:
+		return json.Unmarshal(content, &m.Content)
 	}
-	*m = ChatCompletionMessage(multiMsg)
-	return nil
 }
 
 type ToolCall struct {
@@ -221,6 +301,24 @@ type ChatCompletionResponseFormatJSONSchema struct {
 	Strict      bool           `json:"strict"`
 }
 
+// UnmarshalJSON decodes a response format returned by the API, such as the one of a run,
+// keeping its schema as a json.RawMessage.
+func (s *ChatCompletionResponseFormatJSONSchema) UnmarshalJSON(data []byte) error {
+	type alias ChatCompletionResponseFormatJSONSchema
+	decoded := struct {
+		*alias
+		Schema json.RawMessage `json:"schema"`
+	}{alias: (*alias)(s)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	s.Schema = nil
+	if len(decoded.Schema) > 0 && string(decoded.Schema) != "null" {
+		s.Schema = decoded.Schema
+	}
+	return nil
+}
+
 // ChatCompletionRequest represents a request structure for chat completion API.
 type ChatCompletionRequest struct {
 	Model    string                  `json:"model"`
@@ -237,7 +335,7 @@ type ChatCompletionRequest struct {
 	TopP                float32                       `json:"top_p,omitempty"`
 	N                   int                           `json:"n,omitempty"`
 	Stream              bool                          `json:"stream,omitempty"`
-	Stop                []string                      `json:"stop,omitempty"`
+	Stop                StringArray                   `json:"stop,omitempty"`
 	PresencePenalty     float32                       `json:"presence_penalty,omitempty"`
 	ResponseFormat      *ChatCompletionResponseFormat `json:"response_format,omitempty"`
 	Seed                *int                          `json:"seed,omitempty"`
@@ -255,16 +353,22 @@ type ChatCompletionRequest struct {
 	// logprobs must be set to true if this parameter is used.
 	TopLogProbs int    `json:"top_logprobs,omitempty"`
 	User        string `json:"user,omitempty"`
+	// SafetyIdentifier is a stable, hashed identifier of the end user, used by abuse monitoring.
+	SafetyIdentifier string `json:"safety_identifier,omitempty"`
+	// PromptCacheKey groups the requests sharing a long prompt prefix, improving their cache
+	// hit rate.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
 	// Deprecated: use Tools instead.
 	Functions []FunctionDefinition `json:"functions,omitempty"`
 	// Deprecated: use ToolChoice instead.
 	FunctionCall any    `json:"function_call,omitempty"`
 	Tools        []Tool `json:"tools,omitempty"`
-	// This can be either a string or an ToolChoice object.
-	ToolChoice any `json:"tool_choice,omitempty"`
+	// ToolChoice controls which tool the model calls, see ToolChoiceFunction and NewToolChoice.
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
 	// Options for streaming response. Only set this when you set stream: true.
 	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 	// Disable the default behavior of parallel tool calls by setting it: false.
+	// Only bool and *bool values are accepted.
 	ParallelToolCalls any `json:"parallel_tool_calls,omitempty"`
 	// Store can be set to true to store the output of this completion request for use in distillations and evals.
 	// https://platform.openai.com/docs/api-reference/chat/create#chat-create-store
@@ -275,10 +379,39 @@ type ChatCompletionRequest struct {
 	Metadata map[string]string `json:"metadata,omitempty"`
 	// Configuration for a predicted output.
 	Prediction *Prediction `json:"prediction,omitempty"`
+	// Thinking enables extended thinking of Anthropic models.
+	Thinking *AnthropicThinking `json:"thinking,omitempty"`
 
 	ExtraBody map[string]interface{} `json:"-"` // Arbitrary extra parameters, not serialized directly
 }
 
+// MarshalJSON serializes the request and merges ExtraBody into the top-level
+// object, so arbitrary provider parameters are sent alongside the typed fields.
+func (r ChatCompletionRequest) MarshalJSON() ([]byte, error) {
+	return r.marshalWith(&utils.JSONMarshaller{})
+}
+
+// marshalWith serializes the request with marshaler.
+func (r ChatCompletionRequest) marshalWith(marshaler JSONMarshaler) ([]byte, error) {
+	type alias ChatCompletionRequest
+	data, err := marshaler.Marshal(alias(r))
+	if err != nil || len(r.ExtraBody) == 0 {
+		return data, err
+	}
+
+	// Raw values keep the numbers of the typed fields exact, such as a seed above 2^53.
+	body := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	for key, value := range r.ExtraBody {
+		if body[key], err = marshaler.Marshal(value); err != nil {
+			return nil, fmt.Errorf("marshaling extra body %q: %w", key, err)
+		}
+	}
+	return marshaler.Marshal(body)
+}
+
 type StreamOptions struct {
 	// If set, an additional chunk will be streamed before the data: [DONE] message.
 	// The usage field on this chunk shows the token usage statistics for the entire request,
@@ -298,9 +431,48 @@ type Tool struct {
 	Function *FunctionDefinition `json:"function,omitempty"`
 }
 
+// ToolChoiceMode lets the model decide whether to call tools.
+type ToolChoiceMode string
+
+const (
+	ToolChoiceModeAuto     ToolChoiceMode = "auto"
+	ToolChoiceModeNone     ToolChoiceMode = "none"
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+)
+
+// ToolChoice is either a mode, sent as a string, or a specific function the model must call.
 type ToolChoice struct {
-	Type     ToolType     `json:"type"`
-	Function ToolFunction `json:"function,omitempty"`
+	Mode     ToolChoiceMode `json:"-"`
+	Type     ToolType       `json:"type"`
+	Function ToolFunction   `json:"function,omitempty"`
+}
+
+// NewToolChoice returns the tool choice for a mode.
+func NewToolChoice(mode ToolChoiceMode) *ToolChoice {
+	return &ToolChoice{Mode: mode}
+}
+
+// ToolChoiceFunction returns the tool choice forcing a call to the function name.
+func ToolChoiceFunction(name string) *ToolChoice {
+	return &ToolChoice{Type: ToolTypeFunction, Function: ToolFunction{Name: name}}
+}
+
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Mode != "" {
+		return json.Marshal(c.Mode)
+	}
+	type alias ToolChoice
+	return json.Marshal(alias(c))
+}
+
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode ToolChoiceMode
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = ToolChoice{Mode: mode}
+		return nil
+	}
+	type alias ToolChoice
+	return json.Unmarshal(data, (*alias)(c))
 }
 
 type ToolFunction struct {
@@ -310,7 +482,9 @@ type ToolFunction struct {
 type FunctionDefinition struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
-	Strict      bool   `json:"strict,omitempty"`
+	// Strict enables structured outputs for the function call arguments.
+	// Strict schemas are validated client-side, see ValidateStrictSchema.
+	Strict bool `json:"strict,omitempty"`
 	// Parameters is an object describing the function.
 	// You can pass json.RawMessage to describe the schema,
 	// or you can pass in a struct which serializes to the proper JSON schema.
@@ -392,15 +566,31 @@ type ChatCompletionResponse struct {
 	Usage               Usage                  `json:"usage"`
 	SystemFingerprint   string                 `json:"system_fingerprint"`
 	PromptFilterResults []PromptFilterResult   `json:"prompt_filter_results,omitempty"`
+	// Citations and SearchResults are the sources of the answer, returned by Perplexity.
+	Citations     []string       `json:"citations,omitempty"`
+	SearchResults []SearchResult `json:"search_results,omitempty"`
+	// Metadata is the metadata of a stored chat completion.
+	Metadata map[string]string `json:"metadata,omitempty"`
 
 	httpHeader
 }
 
+// SearchResult is a web page used as a source of an answer by search-augmented providers such as Perplexity.
+type SearchResult struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	// Date is the publication date of the page, if known.
+	Date        string `json:"date,omitempty"`
+	LastUpdated string `json:"last_updated,omitempty"`
+}
+
 // CreateChatCompletion — API call to Create a completion for the chat message.
 func (c *Client) CreateChatCompletion(
 	ctx context.Context,
 	request ChatCompletionRequest,
 ) (response ChatCompletionResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
+	c.config.filterMessages(&request)
 	if request.Stream {
 		err = ErrChatCompletionStreamNotSupported
 		return
@@ -417,34 +607,24 @@ func (c *Client) CreateChatCompletion(
 		return
 	}
 
-	// Serialize the request
-	body := make(map[string]interface{})
-	// Add standard fields
-	body["model"] = request.Model
-	body["messages"] = request.Messages
-	body["max_tokens"] = request.MaxTokens
-	body["temperature"] = request.Temperature
-	body["top_p"] = request.TopP
-	body["presence_penalty"] = request.PresencePenalty
-	body["frequency_penalty"] = request.FrequencyPenalty
-	body["stop"] = request.Stop
-	body["user"] = request.User
-
-	// Add extra fields from ExtraBody
-	for key, value := range request.ExtraBody {
-		body[key] = value
+	if err = validateToolSchemas(request); err != nil {
+		return
 	}
 
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
 		c.fullURL(urlSuffix, withModel(request.Model)),
-		withBody(body), // Pass the serialized body with ExtraBody included
+		withBody(request),
 	)
 	if err != nil {
 		return
 	}
 
 	err = c.sendRequest(req, &response)
+	if err != nil {
+		return
+	}
+	err = c.filterResponse(ctx, &response)
 	return
 }
@@ -0,0 +1,110 @@
+package openai
+
+import "encoding/json"
+
+// ChatRequestBuilder assembles a ChatCompletionRequest for the most common call shapes:
+//
+//	request := openai.NewChatRequest(openai.GPT4oMini).
+//		System("You are a helpful assistant.").
+//		User("What is the weather in Paris?").
+//		Function("get_weather", "Get the current weather", params).
+//		Build()
+//
+// Fields without a builder method can be set on the built request.
+type ChatRequestBuilder struct {
+	request ChatCompletionRequest
+}
+
+// NewChatRequest starts building a chat completion request for model.
+func NewChatRequest(model string) *ChatRequestBuilder {
+	return &ChatRequestBuilder{request: ChatCompletionRequest{Model: model}}
+}
+
+// Message appends a message.
+func (b *ChatRequestBuilder) Message(message ChatCompletionMessage) *ChatRequestBuilder {
+	b.request.Messages = append(b.request.Messages, message)
+	return b
+}
+
+// System appends a system message.
+func (b *ChatRequestBuilder) System(content string) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{Role: ChatMessageRoleSystem, Content: content})
+}
+
+// Developer appends a developer message, which replaces system messages for reasoning models.
+func (b *ChatRequestBuilder) Developer(content string) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{Role: ChatMessageRoleDeveloper, Content: content})
+}
+
+// User appends a user message.
+func (b *ChatRequestBuilder) User(content string) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{Role: ChatMessageRoleUser, Content: content})
+}
+
+// UserImage appends a user message with a text and an image, given by URL or as a data URL.
+func (b *ChatRequestBuilder) UserImage(text, imageURL string, detail ImageURLDetail) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{
+		Role: ChatMessageRoleUser,
+		MultiContent: []ChatMessagePart{
+			TextPart{Text: text},
+			ImagePart{URL: imageURL, Detail: detail},
+		},
+	})
+}
+
+// Assistant appends an assistant message, e.g. a previous answer of the model.
+func (b *ChatRequestBuilder) Assistant(content string) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{Role: ChatMessageRoleAssistant, Content: content})
+}
+
+// ToolResult appends the result of the tool call toolCallID.
+func (b *ChatRequestBuilder) ToolResult(toolCallID, content string) *ChatRequestBuilder {
+	return b.Message(ChatCompletionMessage{Role: ChatMessageRoleTool, Content: content, ToolCallID: toolCallID})
+}
+
+// Tool adds a tool the model may call.
+func (b *ChatRequestBuilder) Tool(tool Tool) *ChatRequestBuilder {
+	b.request.Tools = append(b.request.Tools, tool)
+	return b
+}
+
+// Function adds a function tool. parameters is its JSON schema, e.g. a jsonschema.Definition.
+func (b *ChatRequestBuilder) Function(name, description string, parameters any) *ChatRequestBuilder {
+	return b.Tool(Tool{
+		Type:     ToolTypeFunction,
+		Function: &FunctionDefinition{Name: name, Description: description, Parameters: parameters},
+	})
+}
+
+// JSONSchema requests structured output following schema in strict mode.
+func (b *ChatRequestBuilder) JSONSchema(name string, schema json.Marshaler) *ChatRequestBuilder {
+	b.request.ResponseFormat = ResponseFormatJSONSchema(name, schema, true)
+	return b
+}
+
+// JSONObject requests a JSON object answer (JSON mode). The messages must ask for JSON.
+func (b *ChatRequestBuilder) JSONObject() *ChatRequestBuilder {
+	b.request.ResponseFormat = ResponseFormatJSONObject()
+	return b
+}
+
+// Temperature sets the sampling temperature.
+func (b *ChatRequestBuilder) Temperature(temperature float32) *ChatRequestBuilder {
+	b.request.Temperature = temperature
+	return b
+}
+
+// MaxCompletionTokens bounds the number of generated tokens, including reasoning tokens.
+func (b *ChatRequestBuilder) MaxCompletionTokens(tokens int) *ChatRequestBuilder {
+	b.request.MaxCompletionTokens = tokens
+	return b
+}
+
+// Build returns the request. The builder can be reused; later changes do not affect the
+// returned request.
+func (b *ChatRequestBuilder) Build() ChatCompletionRequest {
+	request := b.request
+	request.Messages = append([]ChatCompletionMessage(nil), b.request.Messages...)
+	request.Tools = append([]Tool(nil), b.request.Tools...)
+	return request
+}
@@ -0,0 +1,78 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func TestChatRequestBuilder(t *testing.T) {
+	params := jsonschema.Definition{
+		Type:       jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{"city": {Type: jsonschema.String}},
+	}
+	builder := openai.NewChatRequest(openai.GPT4oMini).
+		System("be brief").
+		User("weather?").
+		Function("get_weather", "Get the weather", params).
+		Temperature(0.2).
+		MaxCompletionTokens(100)
+	request := builder.Build()
+
+	want := openai.ChatCompletionRequest{
+		Model: openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "be brief"},
+			{Role: openai.ChatMessageRoleUser, Content: "weather?"},
+		},
+		Tools: []openai.Tool{{
+			Type:     openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{Name: "get_weather", Description: "Get the weather", Parameters: params},
+		}},
+		Temperature:         0.2,
+		MaxCompletionTokens: 100,
+	}
+	if !reflect.DeepEqual(request, want) {
+		t.Errorf("Build() = %+v, want %+v", request, want)
+	}
+
+	builder.Assistant("calling").ToolResult("call_1", "sunny")
+	if len(request.Messages) != 2 {
+		t.Error("later builder calls should not change a built request")
+	}
+	if err := request.Validate(); err != nil {
+		t.Errorf("built request is invalid: %v", err)
+	}
+}
+
+func TestChatRequestBuilderContent(t *testing.T) {
+	schema := &jsonschema.Definition{Type: jsonschema.Object}
+	request := openai.NewChatRequest(openai.GPT4oMini).
+		Developer("answer in JSON").
+		UserImage("what is this?", "https://example.com/cat.png", openai.ImageURLDetailLow).
+		JSONSchema("answer", schema).
+		Build()
+
+	data, err := json.Marshal(request.Messages[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"role":"user","content":[{"type":"text","text":"what is this?"},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"low"}}]}`
+	if string(data) != want {
+		t.Errorf("unexpected image message %s", data)
+	}
+	format := request.ResponseFormat
+	if format.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || !format.JSONSchema.Strict ||
+		format.JSONSchema.Name != "answer" {
+		t.Errorf("unexpected response format %+v", format)
+	}
+
+	request = openai.NewChatRequest(openai.GPT4oMini).JSONObject().Build()
+	if request.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONObject {
+		t.Errorf("unexpected response format %+v", request.ResponseFormat)
+	}
+}
@@ -0,0 +1,144 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrChatMessagePartUnknown = errors.New("unknown chat message part type")
+
+// ChatMessagePart is a part of the content of a multi-part message: a TextPart, an ImagePart,
+// an AudioPart, a FilePart or a RefusalPart.
+type ChatMessagePart interface {
+	PartType() ChatMessagePartType
+}
+
+// TextPart is a text part of a message.
+type TextPart struct {
+	Text string
+}
+
+func (TextPart) PartType() ChatMessagePartType { return ChatMessagePartTypeText }
+
+func (p TextPart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type ChatMessagePartType `json:"type"`
+		Text string              `json:"text"`
+	}{p.PartType(), p.Text})
+}
+
+// ImagePart is an image part of a user message. URL is a URL or a base64 data URL.
+type ImagePart struct {
+	URL    string
+	Detail ImageURLDetail
+}
+
+func (ImagePart) PartType() ChatMessagePartType { return ChatMessagePartTypeImageURL }
+
+func (p ImagePart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     ChatMessagePartType `json:"type"`
+		ImageURL ChatMessageImageURL `json:"image_url"`
+	}{p.PartType(), ChatMessageImageURL{URL: p.URL, Detail: p.Detail}})
+}
+
+// AudioPart is an audio part of a user message. Data is the base64 encoded audio and Format
+// is "wav" or "mp3".
+type AudioPart struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+func (AudioPart) PartType() ChatMessagePartType { return ChatMessagePartTypeInputAudio }
+
+func (p AudioPart) MarshalJSON() ([]byte, error) {
+	type audio AudioPart
+	return json.Marshal(struct {
+		Type       ChatMessagePartType `json:"type"`
+		InputAudio audio               `json:"input_audio"`
+	}{p.PartType(), audio(p)})
+}
+
+// FilePart is a file part of a user message, either an uploaded file or base64 encoded
+// FileData with its Filename.
+type FilePart struct {
+	FileID   string `json:"file_id,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+func (FilePart) PartType() ChatMessagePartType { return ChatMessagePartTypeFile }
+
+func (p FilePart) MarshalJSON() ([]byte, error) {
+	type file FilePart
+	return json.Marshal(struct {
+		Type ChatMessagePartType `json:"type"`
+		File file                `json:"file"`
+	}{p.PartType(), file(p)})
+}
+
+// RefusalPart is a refusal part of an assistant message.
+type RefusalPart struct {
+	Refusal string
+}
+
+func (RefusalPart) PartType() ChatMessagePartType { return ChatMessagePartTypeRefusal }
+
+func (p RefusalPart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    ChatMessagePartType `json:"type"`
+		Refusal string              `json:"refusal"`
+	}{p.PartType(), p.Refusal})
+}
+
+// unmarshalChatMessageParts decodes the parts of a multi-part content. Plain strings in the
+// array are decoded as text parts.
+func unmarshalChatMessageParts(data []byte) ([]ChatMessagePart, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+	parts := make([]ChatMessagePart, 0, len(raws))
+	for _, raw := range raws {
+		part, err := unmarshalChatMessagePart(raw)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+func unmarshalChatMessagePart(data []byte) (ChatMessagePart, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return TextPart{Text: text}, nil
+	}
+
+	var part struct {
+		Type       ChatMessagePartType `json:"type"`
+		Text       string              `json:"text"`
+		ImageURL   ChatMessageImageURL `json:"image_url"`
+		InputAudio AudioPart           `json:"input_audio"`
+		File       FilePart            `json:"file"`
+		Refusal    string              `json:"refusal"`
+	}
+	if err := json.Unmarshal(data, &part); err != nil {
+		return nil, err
+	}
+	switch part.Type {
+	case ChatMessagePartTypeText:
+		return TextPart{Text: part.Text}, nil
+	case ChatMessagePartTypeImageURL:
+		return ImagePart{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail}, nil
+	case ChatMessagePartTypeInputAudio:
+		return part.InputAudio, nil
+	case ChatMessagePartTypeFile:
+		return part.File, nil
+	case ChatMessagePartTypeRefusal:
+		return RefusalPart{Refusal: part.Refusal}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrChatMessagePartUnknown, part.Type)
+	}
+}
@@ -0,0 +1,62 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestChatMessagePartsRoundTrip(t *testing.T) {
+	message := openai.ChatCompletionMessage{
+		Role: openai.ChatMessageRoleUser,
+		MultiContent: []openai.ChatMessagePart{
+			openai.TextPart{Text: "Describe these"},
+			openai.ImagePart{URL: "https://example.com/cat.png", Detail: openai.ImageURLDetailAuto},
+			openai.AudioPart{Data: "UklGRg==", Format: "wav"},
+			openai.FilePart{FileID: "file_1"},
+			openai.FilePart{FileData: "data:application/pdf;base64,JVBE", Filename: "a.pdf"},
+		},
+	}
+	b, err := json.Marshal(message)
+	checks.NoError(t, err)
+	want := `{"role":"user","content":[{"type":"text","text":"Describe these"},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/cat.png","detail":"auto"}},` +
+		`{"type":"input_audio","input_audio":{"data":"UklGRg==","format":"wav"}},` +
+		`{"type":"file","file":{"file_id":"file_1"}},` +
+		`{"type":"file","file":{"file_data":"data:application/pdf;base64,JVBE","filename":"a.pdf"}}]}`
+	if string(b) != want {
+		t.Fatalf("unexpected message:\n got %s\nwant %s", b, want)
+	}
+
+	var decoded openai.ChatCompletionMessage
+	checks.NoError(t, json.Unmarshal(b, &decoded))
+	if !reflect.DeepEqual(decoded, message) {
+		t.Errorf("unexpected decoded message: %+v", decoded)
+	}
+}
+
+func TestChatMessagePartsUnmarshal(t *testing.T) {
+	var message openai.ChatCompletionMessage
+	err := json.Unmarshal([]byte(`{"role":"assistant","content":[`+
+		`"legacy text",{"type":"refusal","refusal":"I can't"}]}`), &message)
+	checks.NoError(t, err)
+	want := []openai.ChatMessagePart{openai.TextPart{Text: "legacy text"}, openai.RefusalPart{Refusal: "I can't"}}
+	if !reflect.DeepEqual(message.MultiContent, want) {
+		t.Errorf("unexpected parts: %+v", message.MultiContent)
+	}
+
+	err = json.Unmarshal([]byte(`{"role":"assistant","content":null}`), &message)
+	checks.NoError(t, err)
+	if message.Content != "" || message.MultiContent != nil {
+		t.Errorf("unexpected message: %+v", message)
+	}
+
+	err = json.Unmarshal([]byte(`{"role":"user","content":[{"type":"video"}]}`), &message)
+	if !errors.Is(err, openai.ErrChatMessagePartUnknown) {
+		t.Errorf("expected ErrChatMessagePartUnknown, got %v", err)
+	}
+}
@@ -2,7 +2,13 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"reflect"
+
+	utils "github.com/sashabaranov/go-openai/internal"
 )
 
 type ChatCompletionStreamChoiceDelta struct {
@@ -17,6 +23,9 @@ type ChatCompletionStreamChoiceDelta struct {
 	// the doc from deepseek:
 	// - https://api-docs.deepseek.com/api/create-chat-completion#responses
 	ReasoningContent string `json:"reasoning_content,omitempty"`
+
+	// ThinkingBlocks are deltas of the extended thinking blocks of Anthropic models.
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
 }
 
 type ChatCompletionStreamChoiceLogprobs struct {
@@ -43,6 +52,16 @@ type ChatCompletionStreamChoice struct {
 	Logprobs             *ChatCompletionStreamChoiceLogprobs `json:"logprobs,omitempty"`
 	FinishReason         FinishReason                        `json:"finish_reason"`
 	ContentFilterResults ContentFilterResults                `json:"content_filter_results,omitempty"`
+	// ContentFilterOffsets locates the content checked by the asynchronous content filter of
+	// Azure OpenAI, whose results are sent in chunks without delta.
+	ContentFilterOffsets *ContentFilterOffsets `json:"content_filter_offsets,omitempty"`
+}
+
+// ContentFilterOffsets are character offsets in the streamed completion.
+type ContentFilterOffsets struct {
+	CheckOffset int `json:"check_offset"`
+	StartOffset int `json:"start_offset"`
+	EndOffset   int `json:"end_offset"`
 }
 
 type PromptFilterResult struct {
@@ -63,12 +82,153 @@ type ChatCompletionStreamResponse struct {
 	// When present, it contains a null value except for the last chunk which contains the token usage statistics
 	// for the entire request.
 	Usage *Usage `json:"usage,omitempty"`
+	// Citations and SearchResults are the sources of the answer, returned by Perplexity.
+	Citations     []string       `json:"citations,omitempty"`
+	SearchResults []SearchResult `json:"search_results,omitempty"`
+	// ExtraFields holds the top-level keys of the chunk that have no field in this struct,
+	// such as provider extensions like SGLang's matched_stop or vLLM metrics.
+	ExtraFields map[string]json.RawMessage `json:"-"`
+}
+
+var chatCompletionStreamResponseFields = jsonFieldNames(reflect.TypeOf(ChatCompletionStreamResponse{}))
+
+func (r *ChatCompletionStreamResponse) UnmarshalJSON(data []byte) error {
+	return r.unmarshalWith(&utils.JSONUnmarshaler{}, data)
+}
+
+// unmarshalWith decodes the chunk with unmarshaler, then collects its ExtraFields.
+func (r *ChatCompletionStreamResponse) unmarshalWith(unmarshaler JSONUnmarshaler, data []byte) error {
+	type alias ChatCompletionStreamResponse
+	if err := unmarshaler.Unmarshal(data, (*alias)(r)); err != nil {
+		return err
+	}
+
+	r.ExtraFields = nil
+	scanJSONObject(data, func(key, value []byte) {
+		if chatCompletionStreamResponseFields[string(key)] {
+			return
+		}
+		if r.ExtraFields == nil {
+			r.ExtraFields = make(map[string]json.RawMessage)
+		}
+		r.ExtraFields[string(key)] = append(json.RawMessage(nil), value...)
+	})
+	return nil
+}
+
+// reset zeroes the chunk for decoding the next one into it, keeping the capacity of Choices.
+func (r *ChatCompletionStreamResponse) reset() {
+	choices := r.Choices[:cap(r.Choices)]
+	for i := range choices {
+		choices[i] = ChatCompletionStreamChoice{}
+	}
+	*r = ChatCompletionStreamResponse{Choices: choices[:0]}
+}
+
+// MarshalJSON serializes the chunk including its ExtraFields.
+func (r ChatCompletionStreamResponse) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionStreamResponse
+	data, err := json.Marshal(alias(r))
+	if err != nil || len(r.ExtraFields) == 0 {
+		return data, err
+	}
+
+	body := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	for key, value := range r.ExtraFields {
+		if _, exists := body[key]; !exists {
+			body[key] = value
+		}
+	}
+	return json.Marshal(body)
 }
 
 // ChatCompletionStream
 // Note: Perhaps it is more elegant to abstract Stream using generics.
 type ChatCompletionStream struct {
 	*streamReader[ChatCompletionStreamResponse]
+
+	systemFingerprint string
+	usage             *Usage
+	responseFilter    *streamResponseFilter
+}
+
+// Recv reads the next chunk of the stream and records its system fingerprint.
+func (stream *ChatCompletionStream) Recv() (response ChatCompletionStreamResponse, err error) {
+	err = stream.RecvInto(&response)
+	return
+}
+
+// RecvInto decodes the next chunk into response, reusing its slices, and records its system
+// fingerprint and usage. The response filters of the client are applied to the chunk.
+func (stream *ChatCompletionStream) RecvInto(response *ChatCompletionStreamResponse) error {
+	err := stream.streamReader.RecvInto(response)
+	if err != nil {
+		return err
+	}
+	if response.SystemFingerprint != "" {
+		stream.systemFingerprint = response.SystemFingerprint
+	}
+	if response.Usage != nil {
+		usage := *response.Usage
+		stream.usage = &usage
+	}
+	if stream.responseFilter != nil {
+		return stream.responseFilter.apply(response)
+	}
+	return nil
+}
+
+// SystemFingerprint returns the most recent system fingerprint received on the stream.
+// It is empty until a chunk carrying a fingerprint has been received.
+func (stream *ChatCompletionStream) SystemFingerprint() string {
+	return stream.systemFingerprint
+}
+
+// Usage returns the token usage of the stream, sent in its final chunk, whose Choices are
+// empty, when StreamOptions.IncludeUsage or ClientConfig.StreamIncludeUsage is set. It is nil
+// until that chunk has been received.
+func (stream *ChatCompletionStream) Usage() *Usage {
+	return stream.usage
+}
+
+// CollectedMessage is the message of a chat completion stream read to the end.
+type CollectedMessage struct {
+	Message      ChatCompletionMessage
+	FinishReason FinishReason
+	// Usage is nil unless the usage of the stream was requested, see Usage.
+	Usage *Usage
+}
+
+// CollectMessage reads the stream to the end and returns the message of its first choice,
+// with the deltas of its content and tool calls merged, and the usage of the stream. The
+// stream is not closed. On error, the message received so far is returned with the error.
+func (stream *ChatCompletionStream) CollectMessage() (CollectedMessage, error) {
+	var accumulator ChoiceAccumulator
+	var chunk ChatCompletionStreamResponse
+	collect := func() CollectedMessage {
+		return CollectedMessage{
+			Message:      accumulator.Message(),
+			FinishReason: accumulator.FinishReason,
+			Usage:        stream.usage,
+		}
+	}
+	for {
+		err := stream.RecvInto(&chunk)
+		if errors.Is(err, io.EOF) {
+			return collect(), nil
+		}
+		if err != nil {
+			return collect(), err
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Index == 0 {
+				accumulator.Add(choice)
+			}
+		}
+	}
 }
 
 // CreateChatCompletionStream — API call to create a chat completion w/ streaming
@@ -79,6 +239,11 @@ func (c *Client) CreateChatCompletionStream(
 	ctx context.Context,
 	request ChatCompletionRequest,
 ) (stream *ChatCompletionStream, err error) {
+	if c.config.FakeStreaming != nil {
+		return c.createFakeChatCompletionStream(ctx, request)
+	}
+	request.Model = c.config.mapModel(request.Model)
+	c.config.filterMessages(&request)
 	urlSuffix := chatCompletionsSuffix
 	if !checkEndpointSupportsModel(urlSuffix, request.Model) {
 		err = ErrChatCompletionInvalidModel
@@ -86,11 +251,18 @@ func (c *Client) CreateChatCompletionStream(
 	}
 
 	request.Stream = true
+	if c.config.StreamIncludeUsage && request.StreamOptions == nil {
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
 	reasoningValidator := NewReasoningValidator()
 	if err = reasoningValidator.Validate(request); err != nil {
 		return
 	}
 
+	if err = validateToolSchemas(request); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -105,8 +277,12 @@ func (c *Client) CreateChatCompletionStream(
 	if err != nil {
 		return
 	}
+	if c.config.APIType == APITypeAnthropic {
+		resp.eventDecoder = newAnthropicStreamDecoder().decode
+	}
 	stream = &ChatCompletionStream{
-		streamReader: resp,
+		streamReader:   resp,
+		responseFilter: c.newStreamResponseFilter(ctx),
 	}
 	return
 }
@@ -0,0 +1,207 @@
+package openai
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ChoiceAccumulator accumulates the deltas of one choice of a chat completion stream.
+type ChoiceAccumulator struct {
+	Index            int
+	Role             string
+	Content          string
+	Refusal          string
+	ReasoningContent string
+	// ToolCalls are merged by their index, concatenating the fragments of their arguments.
+	ToolCalls    []ToolCall
+	FunctionCall *FunctionCall
+	FinishReason FinishReason
+	// Logprobs are the log probabilities of the content tokens, if requested.
+	Logprobs []ChatCompletionTokenLogprob
+}
+
+// Add adds the delta of choice to the accumulator.
+func (a *ChoiceAccumulator) Add(choice ChatCompletionStreamChoice) {
+	delta := choice.Delta
+	a.Index = choice.Index
+	if delta.Role != "" {
+		a.Role = delta.Role
+	}
+	a.Content += delta.Content
+	a.Refusal += delta.Refusal
+	a.ReasoningContent += delta.ReasoningContent
+	if delta.FunctionCall != nil {
+		if a.FunctionCall == nil {
+			a.FunctionCall = &FunctionCall{}
+		}
+		if delta.FunctionCall.Name != "" {
+			a.FunctionCall.Name = delta.FunctionCall.Name
+		}
+		a.FunctionCall.Arguments += delta.FunctionCall.Arguments
+	}
+	for i, toolCall := range delta.ToolCalls {
+		index := i
+		if toolCall.Index != nil {
+			index = *toolCall.Index
+		}
+		a.addToolCall(index, toolCall)
+	}
+	if choice.FinishReason != "" {
+		a.FinishReason = choice.FinishReason
+	}
+	if choice.Logprobs != nil {
+		a.Logprobs = append(a.Logprobs, choice.Logprobs.Content...)
+	}
+}
+
+func (a *ChoiceAccumulator) addToolCall(index int, fragment ToolCall) {
+	var toolCall *ToolCall
+	for i := range a.ToolCalls {
+		if *a.ToolCalls[i].Index == index {
+			toolCall = &a.ToolCalls[i]
+			break
+		}
+	}
+	if toolCall == nil {
+		a.ToolCalls = append(a.ToolCalls, ToolCall{Index: &index})
+		toolCall = &a.ToolCalls[len(a.ToolCalls)-1]
+	}
+	if fragment.ID != "" {
+		toolCall.ID = fragment.ID
+	}
+	if fragment.Type != "" {
+		toolCall.Type = fragment.Type
+	}
+	if fragment.Function.Name != "" {
+		toolCall.Function.Name = fragment.Function.Name
+	}
+	toolCall.Function.Arguments += fragment.Function.Arguments
+}
+
+// Message returns the message accumulated so far. The tool calls have no index, as in
+// responses of CreateChatCompletion.
+func (a *ChoiceAccumulator) Message() ChatCompletionMessage {
+	message := ChatCompletionMessage{
+		Role:             a.Role,
+		Content:          a.Content,
+		Refusal:          a.Refusal,
+		ReasoningContent: a.ReasoningContent,
+		FunctionCall:     a.FunctionCall,
+	}
+	for _, toolCall := range a.ToolCalls {
+		toolCall.Index = nil
+		message.ToolCalls = append(message.ToolCalls, toolCall)
+	}
+	return message
+}
+
+// ChoiceDemux demultiplexes a chat completion stream requested with N > 1, whose chunks
+// interleave the deltas of the choices, into one accumulator and optionally one sub-stream per
+// choice. It is safe for concurrent use, so sub-streams can be read from different goroutines.
+type ChoiceDemux struct {
+	mu           sync.Mutex
+	stream       *ChatCompletionStream
+	accumulators map[int]*ChoiceAccumulator
+	subscribed   map[int]bool
+	pending      map[int][]ChatCompletionStreamChoice
+	usage        *Usage
+	err          error
+}
+
+// NewChoiceDemux returns a demultiplexer reading stream.
+func NewChoiceDemux(stream *ChatCompletionStream) *ChoiceDemux {
+	return &ChoiceDemux{
+		stream:       stream,
+		accumulators: make(map[int]*ChoiceAccumulator),
+		subscribed:   make(map[int]bool),
+		pending:      make(map[int][]ChatCompletionStreamChoice),
+	}
+}
+
+// ChoiceStream is the sub-stream of the deltas of one choice, see ChoiceDemux.Choice.
+type ChoiceStream struct {
+	demux *ChoiceDemux
+	index int
+}
+
+// Choice returns the sub-stream of the choice with index. It receives the deltas read after it
+// was created, so create the sub-streams before reading any of them. Deltas of the other choices
+// read meanwhile are buffered for their sub-streams.
+func (d *ChoiceDemux) Choice(index int) *ChoiceStream {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribed[index] = true
+	return &ChoiceStream{demux: d, index: index}
+}
+
+// Recv returns the next delta of the choice, reading the stream as needed. It returns io.EOF
+// once the stream ended and all deltas of the choice were received.
+func (s *ChoiceStream) Recv() (ChatCompletionStreamChoice, error) {
+	d := s.demux
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for {
+		if queue := d.pending[s.index]; len(queue) > 0 {
+			choice := queue[0]
+			queue[0] = ChatCompletionStreamChoice{}
+			d.pending[s.index] = queue[1:]
+			return choice, nil
+		}
+		if d.err != nil {
+			return ChatCompletionStreamChoice{}, d.err
+		}
+		d.readChunk()
+	}
+}
+
+// readChunk reads the next chunk of the stream and dispatches its choices.
+func (d *ChoiceDemux) readChunk() {
+	chunk, err := d.stream.Recv()
+	if err != nil {
+		d.err = err
+		return
+	}
+	if chunk.Usage != nil {
+		d.usage = chunk.Usage
+	}
+	for _, choice := range chunk.Choices {
+		accumulator, ok := d.accumulators[choice.Index]
+		if !ok {
+			accumulator = &ChoiceAccumulator{Index: choice.Index}
+			d.accumulators[choice.Index] = accumulator
+		}
+		accumulator.Add(choice)
+		if d.subscribed[choice.Index] {
+			d.pending[choice.Index] = append(d.pending[choice.Index], choice)
+		}
+	}
+}
+
+// Accumulate reads the rest of the stream and returns the accumulators of the choices ordered
+// by index. It returns the error ending the stream, if not io.EOF, with the choices accumulated
+// until then.
+func (d *ChoiceDemux) Accumulate() ([]*ChoiceAccumulator, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for d.err == nil {
+		d.readChunk()
+	}
+	choices := make([]*ChoiceAccumulator, 0, len(d.accumulators))
+	for _, accumulator := range d.accumulators {
+		choices = append(choices, accumulator)
+	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Index < choices[j].Index })
+	if errors.Is(d.err, io.EOF) {
+		return choices, nil
+	}
+	return choices, d.err
+}
+
+// Usage returns the usage of the final chunk, sent if StreamOptions.IncludeUsage was set.
+func (d *ChoiceDemux) Usage() *Usage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.usage
+}
@@ -0,0 +1,104 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const demuxStreamBody = `data: {"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}},` +
+	`{"index":1,"delta":{"role":"assistant","content":"Bon"}}]}
+
+data: {"id":"1","choices":[{"index":1,"delta":{"content":"jour"}}]}
+
+data: {"id":"1","choices":[{"index":0,"delta":{"content":"lo"}}]}
+
+data: {"id":"1","choices":[{"index":2,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1",` +
+	`"type":"function","function":{"name":"greet","arguments":"{\"na"}}]}}]}
+
+data: {"id":"1","choices":[{"index":2,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"me\":1}"}}]}}]}
+
+data: {"id":"1","choices":[{"index":0,"delta":{},"finish_reason":"stop"},` +
+	`{"index":1,"delta":{},"finish_reason":"stop"},{"index":2,"delta":{},"finish_reason":"tool_calls"}]}
+
+data: {"id":"1","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":9,"total_tokens":12}}
+
+data: [DONE]
+
+`
+
+func newDemuxStream(t *testing.T) *openai.ChoiceDemux {
+	t.Helper()
+	client, server, teardown := setupOpenAITestServer()
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, demuxStreamBody)
+	})
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		N:        3,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	t.Cleanup(func() { stream.Close() })
+	return openai.NewChoiceDemux(stream)
+}
+
+func TestChoiceDemuxAccumulate(t *testing.T) {
+	demux := newDemuxStream(t)
+	choices, err := demux.Accumulate()
+	checks.NoError(t, err, "Accumulate error")
+	if len(choices) != 3 {
+		t.Fatalf("expected 3 choices, got %d", len(choices))
+	}
+	if choices[0].Content != "Hello" || choices[1].Content != "Bonjour" || choices[1].FinishReason != "stop" {
+		t.Errorf("unexpected choices %+v %+v", choices[0], choices[1])
+	}
+
+	message := choices[2].Message()
+	if message.Role != openai.ChatMessageRoleAssistant || len(message.ToolCalls) != 1 ||
+		message.ToolCalls[0].Index != nil || message.ToolCalls[0].ID != "call_1" ||
+		message.ToolCalls[0].Function.Arguments != `{"name":1}` {
+		t.Errorf("unexpected message %+v", message)
+	}
+	if usage := demux.Usage(); usage == nil || usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestChoiceDemuxSubStreams(t *testing.T) {
+	demux := newDemuxStream(t)
+	streams := []*openai.ChoiceStream{demux.Choice(0), demux.Choice(1)}
+
+	contents := make([]string, len(streams))
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, stream *openai.ChoiceStream) {
+			defer wg.Done()
+			for {
+				choice, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				checks.NoError(t, err, "Recv error")
+				if choice.Index != i {
+					t.Errorf("expected index %d, got %d", i, choice.Index)
+				}
+				contents[i] += choice.Delta.Content
+			}
+		}(i, stream)
+	}
+	wg.Wait()
+	if contents[0] != "Hello" || contents[1] != "Bonjour" {
+		t.Errorf("unexpected contents %q", contents)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/sashabaranov/go-openai/internal/test/checks"
@@ -337,6 +338,35 @@ func TestCreateChatCompletionStreamRateLimitError(t *testing.T) {
 	t.Logf("%+v\n", apiErr)
 }
 
+func TestCreateChatCompletionStreamNonJSONBody(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	page := "<html>\n<body>\n<h1>Service Unavailable</h1>\n</body>\n</html>\n"
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		// A misconfigured proxy answering with an error page and a success status.
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write([]byte(page))
+		checks.NoError(t, err, "Write error")
+	})
+	_, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT3Dot5Turbo,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected RequestError, got %v", err)
+	}
+	if !errors.Is(err, openai.ErrNonJSONResponse) {
+		t.Errorf("expected ErrNonJSONResponse, got %v", reqErr.Err)
+	}
+	if string(reqErr.Body) != page {
+		t.Errorf("expected body %q, got %q", page, reqErr.Body)
+	}
+	if reqErr.ContentType != "text/html" || reqErr.HTTPStatusCode != http.StatusOK {
+		t.Errorf("unexpected content type %q or status %d", reqErr.ContentType, reqErr.HTTPStatusCode)
+	}
+}
+
 func TestCreateChatCompletionStreamWithRefusal(t *testing.T) {
 	client, server, teardown := setupOpenAITestServer()
 	defer teardown()
@@ -1021,3 +1051,230 @@ func compareChatStreamResponseChoices(c1, c2 openai.ChatCompletionStreamChoice)
 	}
 	return true
 }
+
+func TestChatCompletionStreamExtraFields(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"id":"1","model":"qwen","choices":[{"index":0,"delta":{"content":"hi"}}],` +
+			`"matched_stop":"</s>","metrics":{"ttft_ms":12}}` + "\n\n" +
+			`data: {"id":"2","choices":[]}` + "\n\n" + "data: [DONE]\n\n"))
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.Model != "qwen" || len(chunk.ExtraFields) != 2 {
+		t.Fatalf("unexpected chunk: %+v", chunk)
+	}
+	if string(chunk.ExtraFields["matched_stop"]) != `"</s>"` ||
+		string(chunk.ExtraFields["metrics"]) != `{"ttft_ms":12}` {
+		t.Errorf("unexpected extra fields: %s", chunk.ExtraFields)
+	}
+
+	data, err := json.Marshal(chunk)
+	checks.NoError(t, err, "Marshal error")
+	var roundTrip map[string]any
+	checks.NoError(t, json.Unmarshal(data, &roundTrip), "Unmarshal error")
+	if roundTrip["matched_stop"] != "</s>" || roundTrip["model"] != "qwen" {
+		t.Errorf("extra fields lost in round trip: %s", data)
+	}
+
+	chunk, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.ExtraFields != nil {
+		t.Errorf("expected no extra fields, got %s", chunk.ExtraFields)
+	}
+}
+
+func TestCreateChatCompletionStreamReasoningContent(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","model":"deepseek-reasoner","choices":[{"index":0,`+
+			`"delta":{"role":"assistant","reasoning_content":"Thinking","content":null}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","model":"deepseek-reasoner","choices":[{"index":0,`+
+			`"delta":{"reasoning_content":null,"content":"Answer"}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.DeepSeekReasoner,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		Stream:   true,
+	})
+	checks.NoError(t, err, "CreateCompletionStream returned error")
+	defer stream.Close()
+
+	var reasoning, content string
+	for {
+		response, streamErr := stream.Recv()
+		if errors.Is(streamErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, streamErr, "stream.Recv() failed")
+		reasoning += response.Choices[0].Delta.ReasoningContent
+		content += response.Choices[0].Delta.Content
+	}
+	if reasoning != "Thinking" || content != "Answer" {
+		t.Errorf("unexpected reasoning %q and content %q", reasoning, content)
+	}
+}
+
+func TestChatCompletionStreamStats(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunks := []string{
+			`{"id":"1","choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"content":" world"}}]}`,
+			`{"id":"1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":10,"total_tokens":15}}`,
+		}
+		for _, chunk := range chunks {
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         openai.GPT4oMini,
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	var size int64
+	for {
+		var raw []byte
+		raw, err = stream.RecvRaw()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "RecvRaw error")
+		size += int64(len(raw))
+	}
+
+	stats := stream.Stats()
+	if stats.Chunks != 3 || stats.Bytes != size {
+		t.Errorf("unexpected chunks %d and bytes %d, expected 3 and %d", stats.Chunks, stats.Bytes, size)
+	}
+	if stats.TimeToFirstToken < 20*time.Millisecond || stats.Duration < stats.TimeToFirstToken+40*time.Millisecond {
+		t.Errorf("unexpected time to first token %v and duration %v", stats.TimeToFirstToken, stats.Duration)
+	}
+	// RecvRaw does not decode the usage.
+	if stats.CompletionTokens != 0 || stats.TokensPerSecond != 0 {
+		t.Errorf("unexpected usage %+v", stats)
+	}
+}
+
+func TestChatCompletionStreamStatsUsage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n")
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `data: {"id":"1","choices":[],"usage":{"completion_tokens":10}}`+"\n\ndata: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	for {
+		if _, err = stream.Recv(); errors.Is(err, io.EOF) {
+			break
+		}
+		checks.NoError(t, err, "Recv error")
+	}
+
+	stats := stream.Stats()
+	if stats.CompletionTokens != 10 {
+		t.Errorf("unexpected completion tokens %d", stats.CompletionTokens)
+	}
+	if stats.TokensPerSecond <= 0 || stats.TokensPerSecond > 200 {
+		t.Errorf("unexpected tokens per second %v", stats.TokensPerSecond)
+	}
+}
+
+func TestChatCompletionStreamIncludeUsage(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.StreamIncludeUsage = true
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if request.StreamOptions == nil || !request.StreamOptions.IncludeUsage {
+			t.Errorf("expected include_usage to be set, got %+v", request.StreamOptions)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,`+
+			`"total_tokens":7}}`+"\n\ndata: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	if stream.Usage() != nil {
+		t.Error("expected no usage before the final chunk")
+	}
+
+	collected, err := stream.CollectMessage()
+	checks.NoError(t, err, "CollectMessage error")
+	if collected.Message.Role != openai.ChatMessageRoleAssistant || collected.Message.Content != "Hello" ||
+		collected.FinishReason != openai.FinishReasonStop {
+		t.Errorf("unexpected message %+v", collected)
+	}
+	if collected.Usage == nil || collected.Usage.TotalTokens != 7 || stream.Usage().PromptTokens != 5 {
+		t.Errorf("unexpected usage %+v", collected.Usage)
+	}
+}
+
+func TestChatCompletionStreamIncludeUsageKeepsStreamOptions(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.StreamIncludeUsage = true
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if request.StreamOptions == nil || request.StreamOptions.IncludeUsage {
+			t.Errorf("expected the stream options of the request, got %+v", request.StreamOptions)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\ndata: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:         openai.GPT4oMini,
+		Messages:      []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		StreamOptions: &openai.StreamOptions{},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	collected, err := stream.CollectMessage()
+	checks.NoError(t, err, "CollectMessage error")
+	if collected.Message.Content != "Hi" || collected.Usage != nil {
+		t.Errorf("unexpected message %+v", collected)
+	}
+}
@@ -666,17 +666,8 @@ func TestMultipartChatCompletions(t *testing.T) {
 			{
 				Role: openai.ChatMessageRoleUser,
 				MultiContent: []openai.ChatMessagePart{
-					{
-						Type: openai.ChatMessagePartTypeText,
-						Text: "Hello!",
-					},
-					{
-						Type: openai.ChatMessagePartTypeImageURL,
-						ImageURL: &openai.ChatMessageImageURL{
-							URL:    "URL",
-							Detail: openai.ImageURLDetailLow,
-						},
-					},
+					openai.TextPart{Text: "Hello!"},
+					openai.ImagePart{URL: "URL", Detail: openai.ImageURLDetailLow},
 				},
 			},
 		},
@@ -704,10 +695,10 @@ func TestMultipartChatMessageSerialization(t *testing.T) {
 		t.Errorf("invalid user message")
 	}
 	parts := msgs[1].MultiContent
-	if parts[0].Type != "text" || parts[0].Text != "nice-text" {
+	if parts[0] != (openai.TextPart{Text: "nice-text"}) {
 		t.Errorf("invalid text part: %v", parts[0])
 	}
-	if parts[1].Type != "image_url" || parts[1].ImageURL.URL != "URL" || parts[1].ImageURL.Detail != "high" {
+	if parts[1] != (openai.ImagePart{URL: "URL", Detail: openai.ImageURLDetailHigh}) {
 		t.Errorf("invalid image_url part")
 	}
 
@@ -725,10 +716,7 @@ func TestMultipartChatMessageSerialization(t *testing.T) {
 			Role:    "user",
 			Content: "some-text",
 			MultiContent: []openai.ChatMessagePart{
-				{
-					Type: "text",
-					Text: "nice-text",
-				},
+				openai.TextPart{Text: "nice-text"},
 			},
 		},
 	}
@@ -946,3 +934,54 @@ func TestFinishReason(t *testing.T) {
 		}
 	}
 }
+
+func TestChatCompletionsCitations(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"1","model":"sonar","choices":[{"index":0,"message":{"role":"assistant","content":"Paris[1]"}}],`+
+			`"citations":["https://en.wikipedia.org/wiki/Paris"],`+
+			`"search_results":[{"title":"Paris","url":"https://en.wikipedia.org/wiki/Paris","date":"2024-01-02"}]}`)
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "sonar",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Capital of France?"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if len(resp.Citations) != 1 || resp.Citations[0] != "https://en.wikipedia.org/wiki/Paris" {
+		t.Errorf("unexpected citations: %v", resp.Citations)
+	}
+	want := openai.SearchResult{Title: "Paris", URL: "https://en.wikipedia.org/wiki/Paris", Date: "2024-01-02"}
+	if len(resp.SearchResults) != 1 || resp.SearchResults[0] != want {
+		t.Errorf("unexpected search results: %+v", resp.SearchResults)
+	}
+}
+
+func TestToolChoiceMarshal(t *testing.T) {
+	for _, tc := range []struct {
+		choice *openai.ToolChoice
+		want   string
+	}{
+		{openai.NewToolChoice(openai.ToolChoiceModeAuto), `"auto"`},
+		{openai.NewToolChoice(openai.ToolChoiceModeRequired), `"required"`},
+		{openai.ToolChoiceFunction("lookup"), `{"type":"function","function":{"name":"lookup"}}`},
+	} {
+		b, err := json.Marshal(tc.choice)
+		checks.NoError(t, err)
+		if string(b) != tc.want {
+			t.Errorf("expected %s, got %s", tc.want, b)
+		}
+		var decoded openai.ToolChoice
+		checks.NoError(t, json.Unmarshal(b, &decoded))
+		if decoded != *tc.choice {
+			t.Errorf("unexpected decoded tool choice: %+v", decoded)
+		}
+	}
+
+	b, err := json.Marshal(openai.ChatCompletionRequest{Model: openai.GPT4o})
+	checks.NoError(t, err)
+	if strings.Contains(string(b), "tool_choice") {
+		t.Errorf("unset tool choice should be omitted: %s", b)
+	}
+}
@@ -0,0 +1,170 @@
+package openai
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrMaxTokensConflict      = errors.New("max_tokens and max_completion_tokens are mutually exclusive")
+	ErrMessagesEmpty          = errors.New("messages must not be empty")
+	ErrMessageRoleInvalid     = errors.New("invalid message role")
+	ErrToolMessageOrder       = errors.New("tool messages must answer a tool call of the preceding assistant message")
+	ErrToolCallsUnanswered    = errors.New("tool calls of an assistant message must each be answered by a tool message")
+	ErrStreamToolCallsWithN   = errors.New("streaming tool calls requires n to be 1")
+	ErrToolDefinitionInvalid  = errors.New("invalid tool definition")
+	ErrParameterOutOfRange    = errors.New("parameter out of range")
+	ErrTopLogProbsNotLogProbs = errors.New("top_logprobs requires logprobs")
+)
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// RequestValidationError is a problem with a field of a request found by Validate.
+type RequestValidationError struct {
+	// Field is the JSON path of the field, e.g. "messages[2].role".
+	Field string
+	Err   error
+}
+
+func (e *RequestValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *RequestValidationError) Unwrap() error {
+	return e.Err
+}
+
+// RequestValidationErrors lists all the problems found by Validate. errors.Is matches any of them.
+type RequestValidationErrors []*RequestValidationError
+
+func (e RequestValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func (e RequestValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the request for mistakes the API would reject it for: conflicting fields,
+// parameters unsupported by reasoning models, out of range values, misordered messages and
+// invalid tool definitions. All problems are returned at once as RequestValidationErrors.
+func (r ChatCompletionRequest) Validate() error {
+	var errs RequestValidationErrors
+	add := func(field string, err error) {
+		errs = append(errs, &RequestValidationError{Field: field, Err: err})
+	}
+
+	if r.MaxTokens > 0 && r.MaxCompletionTokens > 0 {
+		add("max_tokens", ErrMaxTokensConflict)
+	}
+	if err := NewReasoningValidator().Validate(r); err != nil {
+		add("model", err)
+	}
+	if r.Temperature < 0 || r.Temperature > 2 {
+		add("temperature", fmt.Errorf("%w: must be between 0 and 2", ErrParameterOutOfRange))
+	}
+	if r.TopP < 0 || r.TopP > 1 {
+		add("top_p", fmt.Errorf("%w: must be between 0 and 1", ErrParameterOutOfRange))
+	}
+	if r.TopLogProbs < 0 || r.TopLogProbs > 20 {
+		add("top_logprobs", fmt.Errorf("%w: must be between 0 and 20", ErrParameterOutOfRange))
+	} else if r.TopLogProbs > 0 && !r.LogProbs {
+		add("top_logprobs", ErrTopLogProbsNotLogProbs)
+	}
+	if r.Thinking != nil && r.Thinking.Type == AnthropicThinkingEnabled {
+		maxTokens := r.MaxTokens + r.MaxCompletionTokens
+		if r.Thinking.BudgetTokens < AnthropicMinThinkingBudget || (maxTokens > 0 && r.Thinking.BudgetTokens >= maxTokens) {
+			add("thinking.budget_tokens", fmt.Errorf("%w: must be at least %d and below max tokens",
+				ErrParameterOutOfRange, AnthropicMinThinkingBudget))
+		}
+	}
+	if r.Stream && r.N > 1 && len(r.Tools) > 0 {
+		add("n", ErrStreamToolCallsWithN)
+	}
+
+	r.validateMessages(add)
+	r.validateTools(add)
+	if err := validateToolSchemas(r); err != nil {
+		add("tools", err)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (r ChatCompletionRequest) validateMessages(add func(field string, err error)) {
+	if len(r.Messages) == 0 {
+		add("messages", ErrMessagesEmpty)
+		return
+	}
+
+	var pendingToolCalls map[string]bool
+	for i, message := range r.Messages {
+		field := fmt.Sprintf("messages[%d]", i)
+		if message.Content != "" && len(message.MultiContent) > 0 {
+			add(field+".content", ErrContentFieldsMisused)
+		}
+
+		if message.Role == ChatMessageRoleTool {
+			if !pendingToolCalls[message.ToolCallID] {
+				add(field+".tool_call_id", ErrToolMessageOrder)
+			}
+			delete(pendingToolCalls, message.ToolCallID)
+			continue
+		}
+		if len(pendingToolCalls) > 0 {
+			add(fmt.Sprintf("messages[%d].tool_calls", i-1), ErrToolCallsUnanswered)
+		}
+		pendingToolCalls = nil
+
+		switch message.Role {
+		case ChatMessageRoleSystem, ChatMessageRoleDeveloper, ChatMessageRoleUser, ChatMessageRoleFunction:
+		case ChatMessageRoleAssistant:
+			if len(message.ToolCalls) > 0 {
+				pendingToolCalls = make(map[string]bool, len(message.ToolCalls))
+				for _, toolCall := range message.ToolCalls {
+					pendingToolCalls[toolCall.ID] = true
+				}
+			}
+		default:
+			add(field+".role", fmt.Errorf("%w: %q", ErrMessageRoleInvalid, message.Role))
+		}
+	}
+	// Unanswered tool calls of the last message are expected to be answered next.
+}
+
+func (r ChatCompletionRequest) validateTools(add func(field string, err error)) {
+	names := make(map[string]bool, len(r.Tools))
+	for i, tool := range r.Tools {
+		field := fmt.Sprintf("tools[%d]", i)
+		if tool.Type != ToolTypeFunction {
+			continue
+		}
+		if tool.Function == nil {
+			add(field+".function", fmt.Errorf("%w: function tools need a function", ErrToolDefinitionInvalid))
+			continue
+		}
+		if !toolNamePattern.MatchString(tool.Function.Name) {
+			add(field+".function.name", fmt.Errorf(
+				"%w: name %q must be 1 to 64 letters, digits, underscores or dashes",
+				ErrToolDefinitionInvalid, tool.Function.Name))
+		}
+		if names[tool.Function.Name] {
+			add(field+".function.name", fmt.Errorf("%w: duplicate name %q", ErrToolDefinitionInvalid, tool.Function.Name))
+		}
+		names[tool.Function.Name] = true
+	}
+}
@@ -0,0 +1,98 @@
+package openai_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestChatCompletionRequestValidate(t *testing.T) {
+	user := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "hi"}
+	toolCall := openai.ChatCompletionMessage{
+		Role:      openai.ChatMessageRoleAssistant,
+		ToolCalls: []openai.ToolCall{{ID: "call_1", Type: openai.ToolTypeFunction}},
+	}
+	toolReply := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleTool, Content: "42", ToolCallID: "call_1"}
+	tool := func(name string) openai.Tool {
+		return openai.Tool{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: name}}
+	}
+
+	tests := []struct {
+		name    string
+		request openai.ChatCompletionRequest
+		want    []error
+	}{
+		{"valid", openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{user, toolCall, toolReply},
+			Tools:    []openai.Tool{tool("get_answer")},
+		}, nil},
+		{"pending tool calls of the last message", openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{user, toolCall},
+		}, nil},
+		{"max tokens conflict", openai.ChatCompletionRequest{
+			Model:               openai.GPT4oMini,
+			Messages:            []openai.ChatCompletionMessage{user},
+			MaxTokens:           10,
+			MaxCompletionTokens: 10,
+		}, []error{openai.ErrMaxTokensConflict}},
+		{"reasoning model temperature", openai.ChatCompletionRequest{
+			Model:       openai.O3Mini,
+			Messages:    []openai.ChatCompletionMessage{user},
+			Temperature: 0.5,
+		}, []error{openai.ErrReasoningModelLimitationsOther}},
+		{"ranges", openai.ChatCompletionRequest{
+			Model:       openai.GPT4oMini,
+			Messages:    []openai.ChatCompletionMessage{user},
+			Temperature: 3,
+			TopLogProbs: 5,
+		}, []error{openai.ErrParameterOutOfRange, openai.ErrTopLogProbsNotLogProbs}},
+		{"streaming tool calls with n", openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{user},
+			Stream:   true,
+			N:        2,
+			Tools:    []openai.Tool{tool("get_answer")},
+		}, []error{openai.ErrStreamToolCallsWithN}},
+		{"messages", openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				toolReply,
+				{Role: "robot", Content: "hi"},
+				toolCall,
+				user,
+			},
+		}, []error{openai.ErrToolMessageOrder, openai.ErrMessageRoleInvalid, openai.ErrToolCallsUnanswered}},
+		{"empty messages", openai.ChatCompletionRequest{Model: openai.GPT4oMini}, []error{openai.ErrMessagesEmpty}},
+		{"tools", openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{user},
+			Tools:    []openai.Tool{tool("get answer"), tool("ok"), tool("ok"), {Type: openai.ToolTypeFunction}},
+		}, []error{openai.ErrToolDefinitionInvalid, openai.ErrToolDefinitionInvalid, openai.ErrToolDefinitionInvalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			var errs openai.RequestValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected RequestValidationErrors, got %v", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("expected %d errors, got %v", len(tt.want), err)
+			}
+			for i, want := range tt.want {
+				if !errors.Is(errs[i], want) {
+					t.Errorf("error %d = %v, want %v", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package openai
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	utils "github.com/sashabaranov/go-openai/internal"
 )
@@ -20,6 +19,7 @@ type Client struct {
 
 	requestBuilder    utils.RequestBuilder
 	createFormBuilder func(io.Writer) utils.FormBuilder
+	connTracker       *connTracker
 }
 
 type Response interface {
@@ -54,12 +54,70 @@ func NewClient(authToken string) *Client {
 
 // NewClientWithConfig creates new OpenAI API client for specified config.
 func NewClientWithConfig(config ClientConfig) *Client {
+	if config.Proxy != nil {
+		config.HTTPClient = withProxy(config.HTTPClient, config.Proxy)
+	}
+	if config.DNS != nil {
+		config.HTTPClient = withDNSCache(config.HTTPClient, config.DNS)
+	}
+	if config.KeepAlive != nil {
+		config.HTTPClient = withKeepAlive(config.HTTPClient, config.KeepAlive)
+	}
+	var tracker *connTracker
+	if config.TrackConnections {
+		tracker = newConnTracker()
+	}
+	if tracker != nil || config.MaxIdleConnsPerHost > 0 || config.MaxConnsPerHost > 0 {
+		var configured bool
+		config.HTTPClient, configured = withConnectionPool(config.HTTPClient, config, tracker)
+		if !configured {
+			tracker = nil
+		}
+	}
+	if config.Timeouts != nil {
+		config.HTTPClient = withTimeouts(config.HTTPClient, config.Timeouts)
+	}
+	if tracker != nil {
+		config.HTTPClient = &trackingDoer{doer: config.HTTPClient, tracker: tracker}
+	}
+	if config.ResponseCompression.enabled() {
+		config.HTTPClient = &compressionDoer{doer: config.HTTPClient, compression: config.ResponseCompression}
+	}
+	if config.HedgePolicy != nil {
+		config.HTTPClient = &hedgedDoer{doer: config.HTTPClient, policy: config.HedgePolicy}
+	}
+	if config.CircuitBreaker != nil || config.RetryPolicy != nil {
+		config.HTTPClient = &resilientDoer{
+			doer:    config.HTTPClient,
+			breaker: config.CircuitBreaker,
+			retry:   config.RetryPolicy,
+		}
+	}
+	if config.MaxConcurrentRequests > 0 {
+		config.HTTPClient = newLimitedDoer(config.HTTPClient, config.MaxConcurrentRequests)
+	}
+	config.HTTPClient = &budgetDoer{doer: config.HTTPClient, budget: config.Budget}
+	if config.CurlLogger != nil {
+		config.HTTPClient = &curlDoer{doer: config.HTTPClient, logger: config.CurlLogger, config: config}
+	}
+	if config.JSONUnmarshaler != nil {
+		config.JSONUnmarshaler = &codecUnmarshaler{next: config.JSONUnmarshaler}
+	}
+	if config.SchemaDrift != nil {
+		config.JSONUnmarshaler = withSchemaDrift(config.JSONUnmarshaler, config.SchemaDrift)
+	}
+	var requestBuilder utils.RequestBuilder = utils.NewRequestBuilder()
+	if config.JSONMarshaler != nil {
+		config.JSONMarshaler = &codecMarshaler{next: config.JSONMarshaler}
+		requestBuilder = utils.NewRequestBuilderWithMarshaller(config.JSONMarshaler)
+	}
 	return &Client{
 		config:         config,
-		requestBuilder: utils.NewRequestBuilder(),
+		requestBuilder: requestBuilder,
 		createFormBuilder: func(body io.Writer) utils.FormBuilder {
 			return utils.NewFormBuilder(body)
 		},
+		connTracker: tracker,
 	}
 }
 
@@ -98,6 +156,11 @@ func withBetaAssistantVersion(version string) requestOption {
 }
 
 func (c *Client) newRequest(ctx context.Context, method, url string, setters ...requestOption) (*http.Request, error) {
+	if c.config.AzureCloud != nil {
+		if err := c.config.AzureCloud.ValidateBaseURL(c.config.BaseURL); err != nil {
+			return nil, err
+		}
+	}
 	// Default Options
 	args := &requestOptions{
 		body:   nil,
@@ -111,6 +174,8 @@ func (c *Client) newRequest(ctx context.Context, method, url string, setters ...
 		return nil, err
 	}
 	c.setCommonHeaders(req)
+	overrideAPIVersion(req)
+	setContextHeaders(req)
 	return req, nil
 }
 
@@ -139,6 +204,9 @@ func (c *Client) sendRequest(req *http.Request, v Response) error {
 		return c.handleErrorResp(res)
 	}
 
+	if c.config.JSONUnmarshaler != nil {
+		return decodeResponseWith(res.Body, v, c.config.JSONUnmarshaler)
+	}
 	return decodeResponse(res.Body, v)
 }
 
@@ -149,6 +217,7 @@ func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err er
 	}
 
 	if isFailureStatusCode(resp) {
+		defer resp.Body.Close()
 		err = c.handleErrorResp(resp)
 		return
 	}
@@ -160,26 +229,56 @@ func (c *Client) sendRequestRaw(req *http.Request) (response RawResponse, err er
 
 func sendRequestStream[T streamable](client *Client, req *http.Request) (*streamReader[T], error) {
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Accept", client.config.StreamFormat.accept())
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
 
+	sentAt := time.Now()
 	resp, err := client.config.HTTPClient.Do(req) //nolint:bodyclose // body is closed in stream.Close()
 	if err != nil {
 		return new(streamReader[T]), err
 	}
 	if isFailureStatusCode(resp) {
+		defer resp.Body.Close()
 		return new(streamReader[T]), client.handleErrorResp(resp)
 	}
+	if client.config.StreamFormat == StreamFormatAuto && isNonStreamContentType(resp) {
+		defer resp.Body.Close()
+		return new(streamReader[T]), client.nonStreamResponseError(resp)
+	}
+	stream := newStreamReader[T](resp, client.config)
+	stream.sentAt = sentAt
+	stream.retrier = newStreamRetrier(client, req)
+	return stream, nil
+}
+
+func newStreamReader[T streamable](resp *http.Response, config ClientConfig) *streamReader[T] {
+	pool := getStreamBufferPool(config.StreamBufferSize)
+	reader, dataBuffer := pool.get(decodeCharset(resp))
+	var unmarshaler utils.Unmarshaler = &utils.JSONUnmarshaler{}
+	if config.JSONUnmarshaler != nil {
+		unmarshaler = config.JSONUnmarshaler
+	}
+	termination := config.streamTermination()
+	ndjson := config.StreamFormat.isNDJSON(resp)
+	if ndjson {
+		// Newline-delimited JSON streams end by closing the connection.
+		termination.EndOnEOF = true
+	}
 	return &streamReader[T]{
-		emptyMessagesLimit: client.config.EmptyMessagesLimit,
-		reader:             bufio.NewReaderSize(resp.Body, 65536), // Increase buffer size to 64KB
+		emptyMessagesLimit: config.EmptyMessagesLimit,
+		strictChunks:       config.StreamChunkMode == StreamChunkModeStrict,
+		ndjson:             ndjson,
+		reader:             reader,
 		response:           resp,
 		errAccumulator:     utils.NewErrorAccumulator(),
-		unmarshaler:        &utils.JSONUnmarshaler{},
-		dataBuffer:         new(bytes.Buffer),
+		unmarshaler:        unmarshaler,
+		dataBuffer:         dataBuffer,
+		bufferPool:         pool,
 		httpHeader:         httpHeader(resp.Header),
-	}, nil
+		sentAt:             time.Now(),
+		termination:        &termination,
+	}
 }
 
 func (c *Client) setCommonHeaders(req *http.Request) {
@@ -187,21 +286,32 @@ func (c *Client) setCommonHeaders(req *http.Request) {
 	switch c.config.APIType {
 	case APITypeAzure, APITypeCloudflareAzure:
 		// Azure API Key authentication
-		req.Header.Set(AzureAPIKeyHeader, c.config.authToken)
+		if c.config.AuthStyle == nil {
+			req.Header.Set(AzureAPIKeyHeader, c.config.authToken)
+		}
 	case APITypeAnthropic:
 		// https://docs.anthropic.com/en/api/versioning
 		req.Header.Set("anthropic-version", c.config.APIVersion)
 	case APITypeOpenAI, APITypeAzureAD:
 		fallthrough
 	default:
-		if c.config.authToken != "" {
+		if c.config.authToken != "" && c.config.AuthStyle == nil {
 			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.authToken))
 		}
 	}
+	if c.config.AuthStyle != nil {
+		c.config.AuthStyle.apply(req, c.config.authToken)
+	}
 
 	if c.config.OrgID != "" {
 		req.Header.Set("OpenAI-Organization", c.config.OrgID)
 	}
+	if c.config.CloudflareGateway != nil {
+		c.config.CloudflareGateway.setHeaders(req.Header)
+	}
+	for _, providerHeaders := range c.config.ProviderHeaders {
+		providerHeaders(req.Header)
+	}
 }
 
 func isFailureStatusCode(resp *http.Response) bool {
@@ -223,6 +333,19 @@ func decodeResponse(body io.Reader, v any) error {
 	}
 }
 
+// decodeResponseWith decodes JSON responses with unmarshaler.
+func decodeResponseWith(body io.Reader, v any, unmarshaler JSONUnmarshaler) error {
+	switch v.(type) {
+	case nil, *string, *audioTextResponse:
+		return decodeResponse(body, v)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return unmarshaler.Unmarshal(data, v)
+}
+
 func decodeString(body io.Reader, output *string) error {
 	b, err := io.ReadAll(body)
 	if err != nil {
@@ -252,6 +375,8 @@ var azureDeploymentsEndpoints = []string{
 	"/audio/translations",
 	"/audio/speech",
 	"/images/generations",
+	"/images/edits",
+	"/images/variations",
 }
 
 // fullURL returns full URL for request.
@@ -266,7 +391,7 @@ func (c *Client) fullURL(suffix string, setters ...fullURLOption) string {
 		baseURL = c.baseURLWithAzureDeployment(baseURL, suffix, args.model)
 	}
 
-	if c.config.APIVersion != "" {
+	if c.config.apiVersionFor(suffix) != "" {
 		suffix = c.suffixWithAPIVersion(suffix)
 	}
 	return fmt.Sprintf("%s%s", baseURL, suffix)
@@ -278,7 +403,7 @@ func (c *Client) suffixWithAPIVersion(suffix string) string {
 		panic("failed to parse url suffix")
 	}
 	query := parsedSuffix.Query()
-	query.Add("api-version", c.config.APIVersion)
+	query.Add("api-version", c.config.apiVersionFor(parsedSuffix.Path))
 	return fmt.Sprintf("%s?%s", parsedSuffix.Path, query.Encode())
 }
 
@@ -299,14 +424,29 @@ func (c *Client) handleErrorResp(resp *http.Response) error {
 	if err != nil {
 		return fmt.Errorf("error, reading response body: %w", err)
 	}
+	retryAfter := parseRetryAfter(resp.Header, resp.StatusCode, time.Now())
+	rateLimitHeaders := newRateLimitHeaders(resp.Header)
 	var errRes ErrorResponse
 	err = json.Unmarshal(body, &errRes)
 	if err != nil || errRes.Error == nil {
+		if gatewayErr := parseCloudflareGatewayError(body); gatewayErr != nil {
+			gatewayErr.HTTPStatus = resp.Status
+			gatewayErr.HTTPStatusCode = resp.StatusCode
+			gatewayErr.RetryAfter = retryAfter
+			gatewayErr.RateLimitHeaders = rateLimitHeaders
+			return gatewayErr
+		}
 		reqErr := &RequestError{
-			HTTPStatus:     resp.Status,
-			HTTPStatusCode: resp.StatusCode,
-			Err:            err,
-			Body:           body,
+			HTTPStatus:       resp.Status,
+			HTTPStatusCode:   resp.StatusCode,
+			Err:              err,
+			Body:             body,
+			ContentType:      resp.Header.Get("Content-Type"),
+			RetryAfter:       retryAfter,
+			RateLimitHeaders: rateLimitHeaders,
+		}
+		if err != nil && !json.Valid(body) {
+			reqErr.Err = ErrNonJSONResponse
 		}
 		if errRes.Error != nil {
 			reqErr.Err = errRes.Error
@@ -316,6 +456,8 @@ func (c *Client) handleErrorResp(resp *http.Response) error {
 
 	errRes.Error.HTTPStatus = resp.Status
 	errRes.Error.HTTPStatusCode = resp.StatusCode
+	errRes.Error.RetryAfter = retryAfter
+	errRes.Error.RateLimitHeaders = rateLimitHeaders
 	return errRes.Error
 }
 
@@ -0,0 +1,233 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownTenant is returned by ClientPool.Client for tenants that were not added.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// Tenant is a customer of a ClientPool, with its own credentials and limits.
+type Tenant struct {
+	APIKey string
+	OrgID  string
+	// MaxConcurrentRequests bounds the in-flight requests of the tenant, if positive.
+	MaxConcurrentRequests int
+	// RequestsPerMinute bounds the rate of the requests of the tenant, if positive, allowing
+	// bursts of as many requests. Requests over the rate wait for their turn or until their
+	// context is done.
+	RequestsPerMinute int
+}
+
+// TenantUsage is the accounting of the requests of a tenant.
+type TenantUsage struct {
+	// Requests is the number of requests sent, retries excluded.
+	Requests         int64
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+	// Models is the usage by model, as reported in the responses.
+	Models map[string]Usage
+}
+
+// ClientPool provides the clients of the tenants of a multi-tenant application, e.g. a SaaS
+// backend calling the API on behalf of its customers with their own keys. The clients share
+// the HTTP client, connection pool and resilience settings of the pool configuration, while
+// each tenant has its own credentials, limits and usage accounting.
+//
+//	pool := openai.NewClientPool(openai.DefaultConfig(""))
+//	pool.Add("acme", openai.Tenant{APIKey: acmeKey, RequestsPerMinute: 60})
+//	client, err := pool.Client("acme")
+type ClientPool struct {
+	base *Client
+
+	mu      sync.RWMutex
+	tenants map[string]*poolTenant
+}
+
+type poolTenant struct {
+	client *Client
+
+	mu    sync.Mutex
+	usage TenantUsage
+}
+
+// NewClientPool returns a pool of clients configured like config, whose API key and
+// organization are replaced by the ones of the tenants.
+func NewClientPool(config ClientConfig) *ClientPool {
+	return &ClientPool{
+		base:    NewClientWithConfig(config),
+		tenants: make(map[string]*poolTenant),
+	}
+}
+
+// Add adds the tenant with id, replacing any tenant with the same id and its usage.
+func (p *ClientPool) Add(id string, tenant Tenant) {
+	t := &poolTenant{usage: TenantUsage{Models: make(map[string]Usage)}}
+	config := p.base.config
+	config.authToken = tenant.APIKey
+	config.OrgID = tenant.OrgID
+
+	var doer HTTPDoer = &usageObserverDoer{doer: config.HTTPClient, observe: t.observe}
+	if tenant.RequestsPerMinute > 0 {
+		doer = &rateLimitedDoer{doer: doer, limiter: newRateLimiter(tenant.RequestsPerMinute, time.Minute)}
+	}
+	if tenant.MaxConcurrentRequests > 0 {
+		doer = newLimitedDoer(doer, tenant.MaxConcurrentRequests)
+	}
+	config.HTTPClient = &countingDoer{doer: doer, count: t.countRequest}
+
+	client := *p.base
+	client.config = config
+	t.client = &client
+
+	p.mu.Lock()
+	p.tenants[id] = t
+	p.mu.Unlock()
+}
+
+// Remove removes the tenant with id. Its clients keep working.
+func (p *ClientPool) Remove(id string) {
+	p.mu.Lock()
+	delete(p.tenants, id)
+	p.mu.Unlock()
+}
+
+// Client returns the client of the tenant with id, or ErrUnknownTenant.
+func (p *ClientPool) Client(id string) (*Client, error) {
+	p.mu.RLock()
+	t, ok := p.tenants[id]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownTenant
+	}
+	return t.client, nil
+}
+
+// Tenants returns the ids of the tenants, sorted.
+func (p *ClientPool) Tenants() []string {
+	p.mu.RLock()
+	ids := make([]string, 0, len(p.tenants))
+	for id := range p.tenants {
+		ids = append(ids, id)
+	}
+	p.mu.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// Usage returns the usage of the tenant with id, and whether it exists.
+func (p *ClientPool) Usage(id string) (TenantUsage, bool) {
+	p.mu.RLock()
+	t, ok := p.tenants[id]
+	p.mu.RUnlock()
+	if !ok {
+		return TenantUsage{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage := t.usage
+	usage.Models = make(map[string]Usage, len(t.usage.Models))
+	for model, modelUsage := range t.usage.Models {
+		usage.Models[model] = modelUsage
+	}
+	return usage, true
+}
+
+func (t *poolTenant) countRequest() {
+	t.mu.Lock()
+	t.usage.Requests++
+	t.mu.Unlock()
+}
+
+func (t *poolTenant) observe(_ *http.Request, model string, usage Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.PromptTokens += int64(usage.PromptTokens)
+	t.usage.CompletionTokens += int64(usage.CompletionTokens)
+	t.usage.TotalTokens += int64(usage.TotalTokens)
+	if model == "" || usage.TotalTokens == 0 {
+		return
+	}
+	modelUsage := t.usage.Models[model]
+	modelUsage.PromptTokens += usage.PromptTokens
+	modelUsage.CompletionTokens += usage.CompletionTokens
+	modelUsage.TotalTokens += usage.TotalTokens
+	t.usage.Models[model] = modelUsage
+}
+
+// countingDoer calls count for each request it sends.
+type countingDoer struct {
+	doer  HTTPDoer
+	count func()
+}
+
+func (d *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.count()
+	return d.doer.Do(req)
+}
+
+// rateLimitedDoer waits for the limiter before sending each request.
+type rateLimitedDoer struct {
+	doer    HTTPDoer
+	limiter *rateLimiter
+}
+
+func (d *rateLimitedDoer) Do(req *http.Request) (*http.Response, error) {
+	if err := d.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return d.doer.Do(req)
+}
+
+// rateLimiter is a token bucket allowing limit events per period, in bursts of up to limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	limit    float64
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+func newRateLimiter(limit int, period time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:    float64(limit),
+		interval: period / time.Duration(limit),
+		tokens:   float64(limit),
+		last:     time.Now(),
+	}
+}
+
+// wait takes a token, waiting until one is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > l.limit {
+		l.tokens = l.limit
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens * float64(l.interval))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
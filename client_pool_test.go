@@ -0,0 +1,138 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupClientPool(t *testing.T) *openai.ClientPool {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !strings.HasPrefix(key, "key-") || r.Header.Get("OpenAI-Organization") != "org-"+key[len("key-"):] {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"model\":\"gpt-4o-mini\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"model\":\"gpt-4o-mini\",\"choices\":[],"+
+				"\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1,\"total_tokens\":4}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}],`+
+			`"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+	}))
+	t.Cleanup(server.Close)
+	config := openai.DefaultConfig("")
+	config.BaseURL = server.URL + "/v1"
+	return openai.NewClientPool(config)
+}
+
+var poolRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4o,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+}
+
+func TestClientPoolTenants(t *testing.T) {
+	pool := setupClientPool(t)
+	pool.Add("acme", openai.Tenant{APIKey: "key-acme", OrgID: "org-acme"})
+	pool.Add("globex", openai.Tenant{APIKey: "key-globex", OrgID: "org-globex"})
+	if tenants := pool.Tenants(); len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "globex" {
+		t.Errorf("unexpected tenants %q", tenants)
+	}
+
+	acme, err := pool.Client("acme")
+	checks.NoError(t, err, "Client error")
+	_, err = acme.CreateChatCompletion(context.Background(), poolRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	stream, err := acme.CreateChatCompletionStream(context.Background(), poolRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("unexpected stream error %v", err)
+	}
+
+	globex, err := pool.Client("globex")
+	checks.NoError(t, err, "Client error")
+	_, err = globex.CreateChatCompletion(context.Background(), poolRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	usage, ok := pool.Usage("acme")
+	if !ok {
+		t.Fatal("expected the usage of acme")
+	}
+	if usage.Requests != 2 || usage.PromptTokens != 13 || usage.CompletionTokens != 6 || usage.TotalTokens != 19 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if usage.Models[openai.GPT4o].TotalTokens != 15 || usage.Models[openai.GPT4oMini].TotalTokens != 4 {
+		t.Errorf("unexpected usage by model %+v", usage.Models)
+	}
+	if usage, _ = pool.Usage("globex"); usage.Requests != 1 || usage.TotalTokens != 15 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+
+	pool.Remove("globex")
+	if _, err = pool.Client("globex"); !errors.Is(err, openai.ErrUnknownTenant) {
+		t.Errorf("expected ErrUnknownTenant, got %v", err)
+	}
+	if _, ok = pool.Usage("globex"); ok {
+		t.Error("expected no usage for a removed tenant")
+	}
+}
+
+func TestClientPoolRateLimit(t *testing.T) {
+	pool := setupClientPool(t)
+	pool.Add("acme", openai.Tenant{APIKey: "key-acme", OrgID: "org-acme", RequestsPerMinute: 2})
+	pool.Add("globex", openai.Tenant{APIKey: "key-globex", OrgID: "org-globex", RequestsPerMinute: 2})
+	acme, _ := pool.Client("acme")
+
+	for i := 0; i < 2; i++ {
+		_, err := acme.CreateChatCompletion(context.Background(), poolRequest)
+		checks.NoError(t, err, "CreateChatCompletion error")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := acme.CreateChatCompletion(ctx, poolRequest)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the third request to wait for the rate limit, got %v", err)
+	}
+
+	globex, _ := pool.Client("globex")
+	_, err = globex.CreateChatCompletion(context.Background(), poolRequest)
+	checks.NoError(t, err, "expected the limit of another tenant to be independent")
+}
+
+func TestClientPoolMaxConcurrentRequests(t *testing.T) {
+	pool := setupClientPool(t)
+	pool.Add("acme", openai.Tenant{APIKey: "key-acme", OrgID: "org-acme", MaxConcurrentRequests: 1})
+	acme, _ := pool.Client("acme")
+
+	stream, err := acme.CreateChatCompletionStream(context.Background(), poolRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = acme.CreateChatCompletion(ctx, poolRequest); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to wait for the open stream, got %v", err)
+	}
+	stream.Close()
+	_, err = acme.CreateChatCompletion(context.Background(), poolRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+}
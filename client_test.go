@@ -226,7 +226,7 @@ func TestHandleErrorResp(t *testing.T) {
 	<hr><center>nginx</center>
 	</body>
 	</html>`)),
-			expected: `error, status code: 413, status: , message: invalid character '<' looking for beginning of value, body: 
+			expected: `error, status code: 413, status: , message: response body is not JSON, body: 
 	<html>
 	<head><title>413 Request Entity Too Large</title></head>
 	<body>
@@ -263,6 +263,30 @@ func TestHandleErrorResp(t *testing.T) {
 	}
 }
 
+func TestHandleErrorRespNonJSON(t *testing.T) {
+	client := NewClient(test.GetTestToken())
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Status:     "502 Bad Gateway",
+		Header:     http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("upstream connect error"))),
+	}
+	err := client.handleErrorResp(resp)
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected RequestError, got %v", err)
+	}
+	if !errors.Is(err, ErrNonJSONResponse) {
+		t.Errorf("expected ErrNonJSONResponse, got %v", reqErr.Err)
+	}
+	if string(reqErr.Body) != "upstream connect error" || reqErr.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected body %q or content type %q", reqErr.Body, reqErr.ContentType)
+	}
+	if reqErr.HTTPStatusCode != http.StatusBadGateway {
+		t.Errorf("unexpected status code %d", reqErr.HTTPStatusCode)
+	}
+}
+
 func TestClientReturnsRequestBuilderErrors(t *testing.T) {
 	config := DefaultConfig(test.GetTestToken())
 	client := NewClientWithConfig(config)
@@ -586,3 +610,54 @@ func TestClient_baseURLWithAzureDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_fullURLAzure(t *testing.T) {
+	config := DefaultAzureConfig("", "https://test.openai.azure.com/")
+	config.APIVersions = map[string]string{
+		"/assistants":          "2024-05-01-preview",
+		"/threads":             "2024-05-01-preview",
+		"/threads/runs/legacy": "2023-01-01",
+	}
+	client := NewClientWithConfig(config)
+	tests := []struct {
+		suffix string
+		model  string
+		want   string
+	}{
+		{
+			"/embeddings", string(AdaEmbeddingV2),
+			"https://test.openai.azure.com/openai/deployments/text-embedding-ada-002/embeddings?api-version=2023-05-15",
+		},
+		{
+			"/images/edits", CreateImageModelDallE2,
+			"https://test.openai.azure.com/openai/deployments/dall-e-2/images/edits?api-version=2023-05-15",
+		},
+		{
+			"/images/variations", CreateImageModelDallE2,
+			"https://test.openai.azure.com/openai/deployments/dall-e-2/images/variations?api-version=2023-05-15",
+		},
+		{
+			"/audio/speech", string(TTSModel1),
+			"https://test.openai.azure.com/openai/deployments/tts-1/audio/speech?api-version=2023-05-15",
+		},
+		{
+			"/assistants?limit=5", "",
+			"https://test.openai.azure.com/openai/assistants?api-version=2024-05-01-preview&limit=5",
+		},
+		{
+			"/threads/thread_1/runs", "",
+			"https://test.openai.azure.com/openai/threads/thread_1/runs?api-version=2024-05-01-preview",
+		},
+		{
+			"/batches", "",
+			"https://test.openai.azure.com/openai/batches?api-version=2023-05-15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			if got := client.fullURL(tt.suffix, withModel(tt.model)); got != tt.want {
+				t.Errorf("fullURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
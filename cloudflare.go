@@ -0,0 +1,91 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const cloudflareGatewayBaseURL = "https://gateway.ai.cloudflare.com/v1"
+
+// CloudflareGateway configures requests sent through Cloudflare AI Gateway,
+// see https://developers.cloudflare.com/ai-gateway/.
+type CloudflareGateway struct {
+	AccountID string
+	GatewayID string
+	// Token authenticates requests to an authenticated gateway (cf-aig-authorization).
+	Token string
+	// CacheTTL, if positive, caches responses for the duration (cf-aig-cache-ttl).
+	CacheTTL time.Duration
+	// SkipCache bypasses the gateway cache (cf-aig-skip-cache).
+	SkipCache bool
+	// CacheKey overrides the cache key of requests (cf-aig-cache-key).
+	CacheKey string
+	// Metadata is attached to the gateway logs of requests (cf-aig-metadata).
+	Metadata map[string]string
+	// RequestTimeout, if positive, makes the gateway fail requests taking longer
+	// (cf-aig-request-timeout).
+	RequestTimeout time.Duration
+}
+
+// URL returns the gateway endpoint of provider, e.g. "openai" or "azure-openai/resource/deployment".
+func (g *CloudflareGateway) URL(provider string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", cloudflareGatewayBaseURL, g.AccountID, g.GatewayID, provider)
+}
+
+func (g *CloudflareGateway) setHeaders(header http.Header) {
+	if g.Token != "" {
+		header.Set("cf-aig-authorization", "Bearer "+g.Token)
+	}
+	if g.CacheTTL > 0 {
+		header.Set("cf-aig-cache-ttl", strconv.FormatInt(int64(g.CacheTTL/time.Second), 10))
+	}
+	if g.SkipCache {
+		header.Set("cf-aig-skip-cache", "true")
+	}
+	if g.CacheKey != "" {
+		header.Set("cf-aig-cache-key", g.CacheKey)
+	}
+	if len(g.Metadata) > 0 {
+		metadata, _ := json.Marshal(g.Metadata) // a map of strings always marshals
+		header.Set("cf-aig-metadata", string(metadata))
+	}
+	if g.RequestTimeout > 0 {
+		header.Set("cf-aig-request-timeout", strconv.FormatInt(g.RequestTimeout.Milliseconds(), 10))
+	}
+}
+
+// DefaultCloudflareGatewayConfig returns a config for the OpenAI API behind the Cloudflare AI Gateway
+// gatewayID of account accountID.
+func DefaultCloudflareGatewayConfig(apiKey, accountID, gatewayID string) ClientConfig {
+	config := DefaultConfig(apiKey)
+	config.CloudflareGateway = &CloudflareGateway{AccountID: accountID, GatewayID: gatewayID}
+	config.BaseURL = config.CloudflareGateway.URL("openai")
+	return config
+}
+
+// cloudflareGatewayErrorResponse is the body of errors raised by the gateway itself rather
+// than the upstream provider.
+type cloudflareGatewayErrorResponse struct {
+	Success *bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// parseCloudflareGatewayError returns the first error of a gateway error body, or nil if body
+// is not one.
+func parseCloudflareGatewayError(body []byte) *APIError {
+	var errRes cloudflareGatewayErrorResponse
+	if json.Unmarshal(body, &errRes) != nil || errRes.Success == nil || len(errRes.Errors) == 0 {
+		return nil
+	}
+	return &APIError{
+		Code:    errRes.Errors[0].Code,
+		Message: errRes.Errors[0].Message,
+		Type:    "cloudflare_gateway_error",
+	}
+}
@@ -0,0 +1,77 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestDefaultCloudflareGatewayConfig(t *testing.T) {
+	config := openai.DefaultCloudflareGatewayConfig("key", "account", "gateway")
+	want := "https://gateway.ai.cloudflare.com/v1/account/gateway/openai"
+	if config.BaseURL != want {
+		t.Errorf("BaseURL = %s, want %s", config.BaseURL, want)
+	}
+	if config.APIType != openai.APITypeOpenAI {
+		t.Errorf("unexpected APIType %s", config.APIType)
+	}
+}
+
+func TestCloudflareGatewayHeaders(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.CloudflareGateway = &openai.CloudflareGateway{
+			Token:          "gateway-token",
+			CacheTTL:       time.Hour,
+			SkipCache:      true,
+			CacheKey:       "key-1",
+			Metadata:       map[string]string{"team": "search"},
+			RequestTimeout: 2 * time.Second,
+		}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		want := map[string]string{
+			"cf-aig-authorization":   "Bearer gateway-token",
+			"cf-aig-cache-ttl":       "3600",
+			"cf-aig-skip-cache":      "true",
+			"cf-aig-cache-key":       "key-1",
+			"cf-aig-metadata":        `{"team":"search"}`,
+			"cf-aig-request-timeout": "2000",
+		}
+		for header, value := range want {
+			if got := r.Header.Get(header); got != value {
+				t.Errorf("%s = %q, want %q", header, got, value)
+			}
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+}
+
+func TestCloudflareGatewayError(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.CloudflareGateway = &openai.CloudflareGateway{}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintln(w, `{"success":false,"result":[],"messages":[],"error":[{"code":2009,"message":"Unauthorized"}]}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Code != 2009 || apiErr.Message != "Unauthorized" || apiErr.HTTPStatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
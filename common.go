@@ -1,5 +1,11 @@
 package openai
 
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
 // common.go defines common types used throughout the OpenAI API.
 
 // Usage Represents the total token usage per request to OpenAI.
@@ -24,3 +30,47 @@ type PromptTokensDetails struct {
 	AudioTokens  int `json:"audio_tokens"`
 	CachedTokens int `json:"cached_tokens"`
 }
+
+// StringArray is a list of strings for fields that accept either a single string or an array
+// of strings, such as stop sequences. It decodes both forms and encodes as an array.
+type StringArray []string
+
+func (a *StringArray) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = nil
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = StringArray{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// jsonFieldNames returns the JSON keys of the exported fields of struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestStringArrayUnmarshal(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want openai.StringArray
+	}{
+		{`"\n"`, openai.StringArray{"\n"}},
+		{`["a","b"]`, openai.StringArray{"a", "b"}},
+		{`null`, nil},
+	} {
+		var got openai.StringArray
+		checks.NoError(t, json.Unmarshal([]byte(tc.data), &got))
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected %q, got %q", tc.data, tc.want, got)
+		}
+	}
+
+	var got openai.StringArray
+	checks.HasError(t, json.Unmarshal([]byte(`{"a":1}`), &got))
+}
+
+func TestStringArrayEchoedRequests(t *testing.T) {
+	var chatRequest openai.ChatCompletionRequest
+	checks.NoError(t, json.Unmarshal([]byte(`{"model":"m","stop":"END"}`), &chatRequest))
+	if len(chatRequest.Stop) != 1 || chatRequest.Stop[0] != "END" {
+		t.Errorf("unexpected stop: %q", chatRequest.Stop)
+	}
+
+	var embeddingRequest openai.EmbeddingRequestStrings
+	checks.NoError(t, json.Unmarshal([]byte(`{"model":"m","input":"hello"}`), &embeddingRequest))
+	if len(embeddingRequest.Input) != 1 || embeddingRequest.Input[0] != "hello" {
+		t.Errorf("unexpected input: %q", embeddingRequest.Input)
+	}
+
+	b, err := json.Marshal(openai.CompletionRequest{Model: "m", Stop: []string{"a"}})
+	checks.NoError(t, err)
+	var body map[string]any
+	checks.NoError(t, json.Unmarshal(b, &body))
+	if !reflect.DeepEqual(body["stop"], []any{"a"}) {
+		t.Errorf("unexpected stop: %v", body["stop"])
+	}
+}
@@ -2,7 +2,16 @@ package openai
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+)
+
+var (
+	ErrCompletionBestOfStream    = errors.New("best_of cannot be used when streaming")
+	ErrCompletionBestOfLessThanN = errors.New("best_of must be greater than or equal to n")
+	ErrLogitBiasTokenInvalid     = errors.New("logit_bias keys must be token IDs")
 )
 
 // GPT3 Defines the models provided by OpenAI to use when generating
@@ -190,9 +199,13 @@ func checkPromptType(prompt any) bool {
 
 // CompletionRequest represents a request structure for completion API.
 type CompletionRequest struct {
-	Model            string  `json:"model"`
-	Prompt           any     `json:"prompt,omitempty"`
-	BestOf           int     `json:"best_of,omitempty"`
+	Model  string `json:"model"`
+	Prompt any    `json:"prompt,omitempty"`
+	// BestOf generates best_of completions on the server and returns the n with the highest log
+	// probability per token. It cannot be streamed.
+	BestOf int `json:"best_of,omitempty"`
+	// Echo returns the prompt in addition to the completion. When streaming, the prompt is sent
+	// in the first chunks of each choice.
 	Echo             bool    `json:"echo,omitempty"`
 	FrequencyPenalty float32 `json:"frequency_penalty,omitempty"`
 	// LogitBias is must be a token id string (specified by their token ID in the tokenizer), not a word string.
@@ -209,22 +222,53 @@ type CompletionRequest struct {
 	N               int               `json:"n,omitempty"`
 	PresencePenalty float32           `json:"presence_penalty,omitempty"`
 	Seed            *int              `json:"seed,omitempty"`
-	Stop            []string          `json:"stop,omitempty"`
+	Stop            StringArray       `json:"stop,omitempty"`
 	Stream          bool              `json:"stream,omitempty"`
-	Suffix          string            `json:"suffix,omitempty"`
-	Temperature     float32           `json:"temperature,omitempty"`
-	TopP            float32           `json:"top_p,omitempty"`
-	User            string            `json:"user,omitempty"`
+	// Suffix is the text that comes after the completion, for inserting text.
+	Suffix      string  `json:"suffix,omitempty"`
+	Temperature float32 `json:"temperature,omitempty"`
+	TopP        float32 `json:"top_p,omitempty"`
+	User        string  `json:"user,omitempty"`
 	// Options for streaming response. Only set this when you set stream: true.
 	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 }
 
+// SetLogitBias sets the bias, from -100 to 100, of the token with the given ID.
+func (r *CompletionRequest) SetLogitBias(tokenID, bias int) {
+	if r.LogitBias == nil {
+		r.LogitBias = make(map[string]int)
+	}
+	r.LogitBias[strconv.Itoa(tokenID)] = bias
+}
+
+// Validate checks best_of against n and streaming, and that logit_bias maps token IDs to biases
+// between -100 and 100.
+func (r CompletionRequest) Validate() error {
+	if r.BestOf > 1 && r.Stream {
+		return ErrCompletionBestOfStream
+	}
+	if r.BestOf > 0 && r.BestOf < r.N {
+		return ErrCompletionBestOfLessThanN
+	}
+	for token, bias := range r.LogitBias {
+		if _, err := strconv.Atoi(token); err != nil {
+			return fmt.Errorf("%w: %q", ErrLogitBiasTokenInvalid, token)
+		}
+		if bias < -100 || bias > 100 {
+			return fmt.Errorf("%w: logit_bias of token %s must be between -100 and 100", ErrParameterOutOfRange, token)
+		}
+	}
+	return nil
+}
+
 // CompletionChoice represents one of possible completions.
 type CompletionChoice struct {
 	Text         string        `json:"text"`
 	Index        int           `json:"index"`
 	FinishReason string        `json:"finish_reason"`
 	LogProbs     LogprobResult `json:"logprobs"`
+	// ContentFilterResults are the results of the Azure OpenAI content filters.
+	ContentFilterResults *ContentFilterResults `json:"content_filter_results,omitempty"`
 }
 
 // LogprobResult represents logprob result of Choice.
@@ -243,10 +287,23 @@ type CompletionResponse struct {
 	Model   string             `json:"model"`
 	Choices []CompletionChoice `json:"choices"`
 	Usage   Usage              `json:"usage"`
+	// SystemFingerprint represents the backend configuration that the model runs with.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	// PromptFilterResults are the results of the Azure OpenAI content filters for the prompt.
+	PromptFilterResults []PromptFilterResult `json:"prompt_filter_results,omitempty"`
 
 	httpHeader
 }
 
+// reset zeroes the chunk for decoding the next one into it, keeping the capacity of Choices.
+func (r *CompletionResponse) reset() {
+	choices := r.Choices[:cap(r.Choices)]
+	for i := range choices {
+		choices[i] = CompletionChoice{}
+	}
+	*r = CompletionResponse{Choices: choices[:0]}
+}
+
 // CreateCompletion — API call to create a completion. This is the main endpoint of the API. Returns new text as well
 // as, if requested, the probabilities over each alternative token at each position.
 //
@@ -256,6 +313,7 @@ func (c *Client) CreateCompletion(
 	ctx context.Context,
 	request CompletionRequest,
 ) (response CompletionResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
 	if request.Stream {
 		err = ErrCompletionStreamNotSupported
 		return
@@ -272,6 +330,10 @@ func (c *Client) CreateCompletion(
 		return
 	}
 
+	if err = request.Validate(); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
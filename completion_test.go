@@ -300,3 +300,29 @@ func TestCompletionWithGPT4oModels(t *testing.T) {
 		})
 	}
 }
+
+func TestCompletionRequestValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		request openai.CompletionRequest
+		err     error
+	}{
+		{"valid", openai.CompletionRequest{BestOf: 3, N: 2, LogitBias: map[string]int{"1639": 6}}, nil},
+		{"best_of streamed", openai.CompletionRequest{BestOf: 2, Stream: true}, openai.ErrCompletionBestOfStream},
+		{"best_of below n", openai.CompletionRequest{BestOf: 1, N: 2}, openai.ErrCompletionBestOfLessThanN},
+		{"word bias", openai.CompletionRequest{LogitBias: map[string]int{"You": 6}}, openai.ErrLogitBiasTokenInvalid},
+		{
+			"bias out of range",
+			openai.CompletionRequest{LogitBias: map[string]int{"1639": 101}},
+			openai.ErrParameterOutOfRange,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if !errors.Is(err, tc.err) || (tc.err == nil && err != nil) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
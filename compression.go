@@ -0,0 +1,147 @@
+package openai
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ResponseDecoder decodes a response body compressed with a Content-Encoding.
+type ResponseDecoder func(body io.Reader) (io.ReadCloser, error)
+
+// ResponseCompression selects the classes of endpoints whose responses the server may compress.
+// Compression is off by default because it delays streamed events; it pays off for large
+// non-streaming responses such as embeddings and file downloads.
+//
+// gzip and deflate are decoded out of the box. Other encodings can be added with Decoders,
+// e.g. zstd using github.com/klauspost/compress/zstd:
+//
+//	config.ResponseCompression.Decoders = map[string]openai.ResponseDecoder{
+//		"zstd": func(body io.Reader) (io.ReadCloser, error) {
+//			decoder, err := zstd.NewReader(body)
+//			if err != nil {
+//				return nil, err
+//			}
+//			return decoder.IOReadCloser(), nil
+//		},
+//	}
+type ResponseCompression struct {
+	// JSON enables compression of regular JSON responses.
+	JSON bool
+	// Files enables compression of raw content downloads, such as file contents and speech.
+	Files bool
+	// Streams enables compression of event streams.
+	Streams bool
+	// Decoders adds decoders keyed by Content-Encoding; they are advertised in Accept-Encoding.
+	Decoders map[string]ResponseDecoder
+}
+
+func (c ResponseCompression) enabled() bool {
+	return c.JSON || c.Files || c.Streams
+}
+
+func (c ResponseCompression) appliesTo(req *http.Request) bool {
+	switch accept := req.Header.Get("Accept"); {
+	case strings.HasPrefix(accept, "text/event-stream"):
+		return c.Streams
+	case strings.HasPrefix(accept, "application/json"):
+		return c.JSON
+	default:
+		return c.Files
+	}
+}
+
+func (c ResponseCompression) decoder(encoding string) ResponseDecoder {
+	if decoder, ok := c.Decoders[encoding]; ok {
+		return decoder
+	}
+	switch encoding {
+	case "gzip", "x-gzip":
+		return func(body io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(body)
+		}
+	case "deflate":
+		return newDeflateReader
+	}
+	return nil
+}
+
+func (c ResponseCompression) acceptEncoding() string {
+	encodings := []string{"gzip", "deflate"}
+	custom := make([]string, 0, len(c.Decoders))
+	for encoding := range c.Decoders {
+		if encoding != "gzip" && encoding != "deflate" {
+			custom = append(custom, encoding)
+		}
+	}
+	sort.Strings(custom)
+	return strings.Join(append(custom, encodings...), ", ")
+}
+
+// newDeflateReader decodes "deflate" bodies, which should be zlib streams but are raw
+// DEFLATE data on some servers.
+func newDeflateReader(body io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err == nil && header[0]&0x0F == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+// compressionDoer negotiates and transparently decodes compressed responses.
+type compressionDoer struct {
+	doer        HTTPDoer
+	compression ResponseCompression
+}
+
+func (d *compressionDoer) Do(req *http.Request) (*http.Response, error) {
+	if !d.compression.appliesTo(req) || req.Header.Get("Accept-Encoding") != "" {
+		return d.doer.Do(req)
+	}
+	req.Header.Set("Accept-Encoding", d.compression.acceptEncoding())
+
+	resp, err := d.doer.Do(req)
+	if err != nil || resp.Uncompressed {
+		return resp, err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return resp, nil
+	}
+	decoder := d.compression.decoder(encoding)
+	if decoder == nil {
+		return resp, nil
+	}
+
+	decoded, err := decoder(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &decodedBody{ReadCloser: decoded, raw: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decodedBody closes both the decoder and the underlying response body.
+type decodedBody struct {
+	io.ReadCloser
+	raw io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if rawErr := b.raw.Close(); err == nil {
+		err = rawErr
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package openai_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const compressedEmbeddingsResponse = `{"object":"list","data":[{"object":"embedding","embedding":[0.5,0.25],"index":0}]}`
+
+func compress(t *testing.T, encoding, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "reverse":
+		// A toy encoding standing in for zstd.
+		runes := []rune(body)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return []byte(string(runes))
+	}
+	_, err := io.WriteString(w, body)
+	checks.NoError(t, err, "compress error")
+	checks.NoError(t, w.Close(), "compress close error")
+	return buf.Bytes()
+}
+
+func TestResponseCompressionDecoding(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate", "reverse"} {
+		t.Run(encoding, func(t *testing.T) {
+			client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+				config.ResponseCompression = openai.ResponseCompression{
+					JSON: true,
+					Decoders: map[string]openai.ResponseDecoder{
+						"reverse": func(body io.Reader) (io.ReadCloser, error) {
+							data, err := io.ReadAll(body)
+							runes := []rune(string(data))
+							for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+								runes[i], runes[j] = runes[j], runes[i]
+							}
+							return io.NopCloser(strings.NewReader(string(runes))), err
+						},
+					},
+				}
+			})
+			defer teardown()
+			server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != "reverse, gzip, deflate" {
+					t.Errorf("unexpected Accept-Encoding %q", got)
+				}
+				contentEncoding := encoding
+				if encoding == "raw-deflate" {
+					contentEncoding = "deflate"
+				}
+				w.Header().Set("Content-Encoding", contentEncoding)
+				_, _ = w.Write(compress(t, encoding, compressedEmbeddingsResponse))
+			})
+
+			resp, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+				Input: []string{"hello"},
+				Model: openai.AdaEmbeddingV2,
+			})
+			checks.NoError(t, err, "CreateEmbeddings error")
+			if len(resp.Data) != 1 || resp.Data[0].Embedding[0] != 0.5 {
+				t.Errorf("unexpected embeddings: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestResponseCompressionPerEndpointClass(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.ResponseCompression = openai.ResponseCompression{Files: true}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "" {
+			t.Errorf("JSON responses should not be compressed, got Accept-Encoding %q", r.Header.Get("Accept-Encoding"))
+		}
+		_, _ = io.WriteString(w, compressedEmbeddingsResponse)
+	})
+	server.RegisterHandler("/v1/files/file-1/content", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("file downloads should accept gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compress(t, "gzip", "file content"))
+	})
+
+	_, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+		Input: []string{"hello"},
+		Model: openai.AdaEmbeddingV2,
+	})
+	checks.NoError(t, err, "CreateEmbeddings error")
+
+	content, err := client.GetFileContent(context.Background(), "file-1")
+	checks.NoError(t, err, "GetFileContent error")
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	checks.NoError(t, err, "read content error")
+	if string(data) != "file content" {
+		t.Errorf("unexpected file content %q", data)
+	}
+}
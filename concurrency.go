@@ -0,0 +1,50 @@
+package openai
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// limitedDoer bounds the number of in-flight requests of a client. A slot is held until the
+// response body is closed, so streaming requests keep their slot until the stream is closed.
+type limitedDoer struct {
+	doer  HTTPDoer
+	slots chan struct{}
+}
+
+func newLimitedDoer(doer HTTPDoer, maxConcurrentRequests int) *limitedDoer {
+	return &limitedDoer{
+		doer:  doer,
+		slots: make(chan struct{}, maxConcurrentRequests),
+	}
+}
+
+func (d *limitedDoer) Do(req *http.Request) (*http.Response, error) {
+	select {
+	case d.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := d.doer.Do(req)
+	if err != nil {
+		<-d.slots
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { <-d.slots }}
+	return resp, nil
+}
+
+// releasingBody calls release exactly once when it is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,46 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestMaxConcurrentRequestsStreamHoldsSlot(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.MaxConcurrentRequests = 1
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.ListModels(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected request to wait for the stream slot, got %v", err)
+	}
+
+	stream.Close()
+	_, err = client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels should run once the stream is closed")
+	_, err = client.ListModels(context.Background())
+	checks.NoError(t, err, "completed requests should release their slot")
+}
@@ -4,6 +4,7 @@ import (
 	"net"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -15,6 +16,10 @@ const (
 	azureDeploymentsPrefix = "deployments"
 
 	AnthropicAPIVersion = "2023-06-01"
+
+	deepSeekAPIURLv1 = "https://api.deepseek.com/v1"
+	xAIAPIURLv1      = "https://api.x.ai/v1"
+	perplexityAPIURL = "https://api.perplexity.ai"
 )
 
 type APIType string
@@ -29,6 +34,18 @@ const (
 
 const AzureAPIKeyHeader = "api-key"
 
+// StreamChunkMode controls how streams handle chunks that cannot be decoded.
+type StreamChunkMode string
+
+const (
+	// StreamChunkModeLenient skips malformed chunks that look like partial completions, as
+	// sent by some servers during structured output streaming. Other malformed chunks are
+	// reported as *MalformedChunkError. This is the default.
+	StreamChunkModeLenient StreamChunkMode = "lenient"
+	// StreamChunkModeStrict reports every malformed chunk as *MalformedChunkError.
+	StreamChunkModeStrict StreamChunkMode = "strict"
+)
+
 const defaultAssistantVersion = "v2" // upgrade to v2 to support vector store
 
 type HTTPDoer interface {
@@ -45,9 +62,110 @@ type ClientConfig struct {
 	APIVersion           string // required when APIType is APITypeAzure or APITypeAzureAD or APITypeAnthropic
 	AssistantVersion     string
 	AzureModelMapperFunc func(model string) string // replace model to azure deployment name func
-	HTTPClient           HTTPDoer
+	// AzureCloud, if set, restricts BaseURL to the endpoints of an Azure cloud such as
+	// AzureGovernmentCloud: requests fail with ErrAzureCloudMismatch otherwise.
+	AzureCloud *AzureCloud
+	// ModelMapperFunc, if set, replaces the model of every request, e.g. to map logical names like
+	// "fast-chat" to the model IDs of the provider. It is applied before AzureModelMapperFunc.
+	ModelMapperFunc func(model string) string
+	// MessageFilter, if set, rewrites the messages of every chat completion request before it is
+	// sent, e.g. NewPIIRedactor().Filter to scrub personal data centrally.
+	MessageFilter MessageFilter
+	// ResponseFilter, if set, inspects, modifies or denies the content of chat completions once
+	// received, streamed or not.
+	ResponseFilter ResponseFilter
+	// AutoModerate runs the content of chat completions through the moderation endpoint, denying
+	// flagged content with a *ContentBlockedError. Streamed choices are moderated once complete,
+	// so the stream ends with the error after their deltas were delivered.
+	AutoModerate bool
+	// AuthStyle, if set, overrides how the API key is sent, e.g. AuthXAPIKeyHeader() or
+	// AuthQueryParam("key") for self-hosted gateways.
+	AuthStyle *AuthStyle
+	// APIVersions overrides APIVersion for the endpoints under a path, e.g. to use a preview
+	// version for "/assistants" on Azure. The longest matching path wins.
+	APIVersions map[string]string
+	HTTPClient  HTTPDoer
+	// CloudflareGateway, if set, adds the Cloudflare AI Gateway headers to requests.
+	CloudflareGateway *CloudflareGateway
+	// ProviderHeaders add the headers of proxies and gateways to every request.
+	ProviderHeaders []ProviderHeaders
+	// KeepAlive, if set, health checks idle connections of the HTTP client.
+	KeepAlive *KeepAlive
+
+	// CircuitBreaker, if set, rejects requests with ErrCircuitOpen while the upstream is failing.
+	CircuitBreaker *CircuitBreaker
+	// RetryPolicy, if set, retries transport errors, 429 and 5xx responses.
+	RetryPolicy *RetryPolicy
+	// MaxConcurrentRequests, if positive, bounds the number of in-flight requests. Callers block
+	// until a slot is free or their context is done. Streams hold their slot until Close.
+	MaxConcurrentRequests int
+	// Budget, if set, limits the estimated spend of the client, see Budget.
+	Budget *Budget
+	// ResponseCompression selects the endpoints whose responses may be compressed.
+	ResponseCompression ResponseCompression
+	// HedgePolicy, if set, sends duplicate requests to cut tail latency when the upstream stalls.
+	HedgePolicy *HedgePolicy
+	// MaxIdleConnsPerHost and MaxConnsPerHost, if positive, override the connection pool settings
+	// of the transport. The default transport keeps only 10 idle connections per host, which
+	// causes connection churn for services making many concurrent calls.
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	// TrackConnections counts the connections of the transport for Client.ConnectionStats.
+	TrackConnections bool
+	// Timeouts, if set, bounds the connect, first byte and total phases of each request attempt.
+	Timeouts *Timeouts
+	// FakeStreaming, if set, synthesizes chat completion streams from regular completions, for
+	// backends without server-sent events support.
+	FakeStreaming *FakeStreaming
+	// StreamTermination, if set, overrides how streams are expected to end, see
+	// DefaultStreamTermination.
+	StreamTermination *StreamTermination
+	// RerankEndpoint, if set, is the path of the rerank endpoint of providers that have one,
+	// relative to BaseURL, e.g. "/rerank" for Cohere or Jina. Client.Rerank ranks with
+	// embeddings otherwise.
+	RerankEndpoint string
+	// DNS, if set, caches the addresses of the API host and selects the IP version of connections.
+	DNS *DNSCache
+	// Proxy, if set, overrides the HTTP proxy of the transport, per host if needed.
+	Proxy *ProxyConfig
+	// CurlLogger, if set, logs every request as an equivalent curl command with its credentials
+	// redacted, see ToCurl. The commands include the bodies of requests, which may hold personal
+	// data.
+	CurlLogger Logger
 
 	EmptyMessagesLimit uint
+	// StreamBufferSize is the size of the read buffer of streams and the initial capacity of
+	// their event buffer. Defaults to 64KB; raise it for large vision or structured output chunks.
+	StreamBufferSize int
+	// StreamChunkMode defaults to StreamChunkModeLenient.
+	StreamChunkMode StreamChunkMode
+	// StreamFormat selects how streams are framed, server-sent events or newline-delimited JSON.
+	// Defaults to StreamFormatAuto, detecting the format from the Content-Type of responses.
+	StreamFormat StreamFormat
+	// StreamIncludeUsage sets stream_options.include_usage on the chat completion streams whose
+	// request has no StreamOptions, so ChatCompletionStream.Usage reports their token counts.
+	StreamIncludeUsage bool
+
+	// JSONMarshaler, if set, encodes the JSON bodies of requests instead of encoding/json.
+	JSONMarshaler JSONMarshaler
+	// JSONUnmarshaler, if set, decodes JSON responses and the chunks of streams instead of
+	// encoding/json, e.g. json-iterator or sonic for heavy streaming workloads.
+	JSONUnmarshaler JSONUnmarshaler
+	// SchemaDrift, if set, reports the fields of responses that the types of this package do not
+	// capture, and optionally rejects them.
+	SchemaDrift *SchemaDrift
+}
+
+// JSONMarshaler encodes values to JSON. It must honor json.Marshaler and the json struct tags
+// like encoding/json does.
+type JSONMarshaler interface {
+	Marshal(v any) ([]byte, error)
+}
+
+// JSONUnmarshaler decodes JSON into values. It must honor json.Unmarshaler and the json struct
+// tags like encoding/json does.
+type JSONUnmarshaler interface {
+	Unmarshal(data []byte, v any) error
 }
 
 // defaultHTTPClient returns a new http.Client with appropriate timeouts and keep-alive settings
@@ -66,7 +184,8 @@ func defaultHTTPClient() *http.Client {
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-			// Disable compression to reduce latency for streaming
+			// Disable compression to reduce latency for streaming,
+			// see ClientConfig.ResponseCompression to enable it per endpoint class
 			DisableCompression: true,
 			// Don't set ResponseHeaderTimeout - let it default to no timeout
 			// This prevents "timeout awaiting response headers" errors with slow providers
@@ -122,10 +241,57 @@ func DefaultAnthropicConfig(apiKey, baseURL string) ClientConfig {
 	}
 }
 
+// DeepSeek models, see https://api-docs.deepseek.com/quick_start/pricing.
+const (
+	DeepSeekChat     = "deepseek-chat"
+	DeepSeekReasoner = "deepseek-reasoner"
+)
+
+// DefaultDeepSeekConfig returns a config for the OpenAI-compatible DeepSeek API. The reasoning
+// of deepseek-reasoner is returned in the ReasoningContent field of messages and stream deltas.
+func DefaultDeepSeekConfig(apiKey string) ClientConfig {
+	config := DefaultConfig(apiKey)
+	config.BaseURL = deepSeekAPIURLv1
+	return config
+}
+
+// DefaultXAIConfig returns a config for the OpenAI-compatible xAI API serving Grok models.
+func DefaultXAIConfig(apiKey string) ClientConfig {
+	config := DefaultConfig(apiKey)
+	config.BaseURL = xAIAPIURLv1
+	return config
+}
+
+// DefaultPerplexityConfig returns a config for the OpenAI-compatible Perplexity API. The
+// sources of answers are returned in the Citations and SearchResults fields of chat responses.
+func DefaultPerplexityConfig(apiKey string) ClientConfig {
+	config := DefaultConfig(apiKey)
+	config.BaseURL = perplexityAPIURL
+	return config
+}
+
 func (ClientConfig) String() string {
 	return "<OpenAI API ClientConfig>"
 }
 
+// apiVersionFor returns the API version for the endpoint at path.
+func (c ClientConfig) apiVersionFor(path string) string {
+	version, matched := c.APIVersion, ""
+	for prefix, prefixVersion := range c.APIVersions {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+			version, matched = prefixVersion, prefix
+		}
+	}
+	return version
+}
+
+func (c ClientConfig) mapModel(model string) string {
+	if c.ModelMapperFunc != nil {
+		return c.ModelMapperFunc(model)
+	}
+	return model
+}
+
 func (c ClientConfig) GetAzureDeploymentByModel(model string) string {
 	if c.AzureModelMapperFunc != nil {
 		return c.AzureModelMapperFunc(model)
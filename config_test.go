@@ -1,9 +1,16 @@
 package openai_test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
 )
 
 func TestGetAzureDeploymentByModel(t *testing.T) {
@@ -121,3 +128,186 @@ func TestGetAzureDeploymentByModel_NoMapper(t *testing.T) {
 		t.Errorf("GetAzureDeploymentByModel(%q) = %q; want %q", model, got, model)
 	}
 }
+
+func TestDefaultDeepSeekConfig(t *testing.T) {
+	config := openai.DefaultDeepSeekConfig("test-key")
+
+	if config.APIType != openai.APITypeOpenAI {
+		t.Errorf("Expected APIType to be %v, got %v", openai.APITypeOpenAI, config.APIType)
+	}
+
+	expectedBaseURL := "https://api.deepseek.com/v1"
+	if config.BaseURL != expectedBaseURL {
+		t.Errorf("Expected BaseURL to be %v, got %v", expectedBaseURL, config.BaseURL)
+	}
+}
+
+func TestDefaultXAIAndPerplexityConfig(t *testing.T) {
+	if config := openai.DefaultXAIConfig("test-key"); config.BaseURL != "https://api.x.ai/v1" {
+		t.Errorf("Expected xAI BaseURL, got %v", config.BaseURL)
+	}
+	if config := openai.DefaultPerplexityConfig("test-key"); config.BaseURL != "https://api.perplexity.ai" {
+		t.Errorf("Expected Perplexity BaseURL, got %v", config.BaseURL)
+	}
+}
+
+func TestModelMapperFunc(t *testing.T) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+
+	var models []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		models = append(models, r.URL.Path+" "+request.Model)
+		fmt.Fprint(w, `{"id":"1","choices":[],"data":[]}`)
+	}
+	server.RegisterHandler("/openai/deployments/gpt-41-mini/chat/completions", handler)
+	server.RegisterHandler("/openai/deployments/text-embedding-3-small/embeddings", handler)
+
+	config := openai.DefaultAzureConfig(test.GetTestToken(), ts.URL)
+	config.ModelMapperFunc = func(model string) string {
+		switch model {
+		case "fast-chat":
+			return "gpt-4.1-mini"
+		case "embedder":
+			return "text-embedding-3-small"
+		}
+		return model
+	}
+	client := openai.NewClientWithConfig(config)
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    "fast-chat",
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	_, err = client.CreateEmbeddings(context.Background(), openai.EmbeddingRequestStrings{
+		Model: "embedder",
+		Input: []string{"Hi"},
+	})
+	checks.NoError(t, err, "CreateEmbeddings error")
+
+	want := []string{
+		"/openai/deployments/gpt-41-mini/chat/completions gpt-4.1-mini",
+		"/openai/deployments/text-embedding-3-small/embeddings text-embedding-3-small",
+	}
+	if !reflect.DeepEqual(models, want) {
+		t.Errorf("unexpected models: %q", models)
+	}
+}
+
+type countingJSONCodec struct {
+	marshals, unmarshals int
+	types                []reflect.Type
+}
+
+func (c *countingJSONCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	c.types = append(c.types, reflect.TypeOf(v))
+	return json.Marshal(v)
+}
+
+func (c *countingJSONCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	c.types = append(c.types, reflect.TypeOf(v))
+	return json.Unmarshal(data, v)
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := &countingJSONCodec{}
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.JSONMarshaler = codec
+	config.JSONUnmarshaler = codec
+	client := openai.NewClientWithConfig(config)
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if !request.Stream {
+			fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"!"}}]}`+"\n\ndata: [DONE]\n\n")
+	})
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	}
+
+	resp, err := client.CreateChatCompletion(context.Background(), request)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.Choices[0].Message.Content != "hi" || codec.marshals != 1 || codec.unmarshals != 1 {
+		t.Errorf("unexpected response %+v with %d marshals and %d unmarshals", resp, codec.marshals, codec.unmarshals)
+	}
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), request)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	for i := 0; i < 2; i++ {
+		_, err = stream.Recv()
+		checks.NoError(t, err, "Recv error")
+	}
+	if codec.marshals != 2 || codec.unmarshals != 3 {
+		t.Errorf("stream used %d marshals and %d unmarshals", codec.marshals, codec.unmarshals)
+	}
+}
+
+func TestJSONCodecExtraFields(t *testing.T) {
+	codec := &countingJSONCodec{}
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	defer ts.Close()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.JSONMarshaler = codec
+	config.JSONUnmarshaler = codec
+	client := openai.NewClientWithConfig(config)
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		if body["top_k"] != float64(5) || body["model"] != openai.GPT4oMini {
+			t.Errorf("unexpected request body %v", body)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"hi"}}],"matched_stop":"</s>"}`+
+			"\n\ndata: [DONE]\n\n")
+	})
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:     openai.GPT4oMini,
+		Messages:  []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		ExtraBody: map[string]any{"top_k": 5},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if string(chunk.ExtraFields["matched_stop"]) != `"</s>"` {
+		t.Errorf("unexpected extra fields %s", chunk.ExtraFields)
+	}
+
+	// Handing the request or the chunk itself to the codec would call their json.Marshaler or
+	// json.Unmarshaler, which encode with encoding/json.
+	for _, typ := range codec.types {
+		if typ == reflect.TypeOf(openai.ChatCompletionRequest{}) ||
+			typ == reflect.TypeOf(&openai.ChatCompletionStreamResponse{}) {
+			t.Errorf("the codec was given a %v", typ)
+		}
+	}
+	if codec.marshals == 0 || codec.unmarshals == 0 {
+		t.Errorf("the codec was not used: %d marshals and %d unmarshals", codec.marshals, codec.unmarshals)
+	}
+}
@@ -0,0 +1,283 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	containersSuffix      = "/containers"
+	containersFilesSuffix = "/files"
+)
+
+// Container is a sandboxed environment used by the code_interpreter tool of the Responses API.
+type Container struct {
+	ID           string                 `json:"id"`
+	Object       string                 `json:"object"`
+	CreatedAt    int64                  `json:"created_at"`
+	Status       string                 `json:"status"`
+	Name         string                 `json:"name"`
+	ExpiresAfter *ContainerExpiresAfter `json:"expires_after,omitempty"`
+	LastActiveAt int64                  `json:"last_active_at,omitempty"`
+
+	httpHeader
+}
+
+// ContainerExpiresAfter controls when an idle container expires.
+type ContainerExpiresAfter struct {
+	// Anchor is the time the expiration is counted from; only "last_active_at" is supported.
+	Anchor  string `json:"anchor"`
+	Minutes int    `json:"minutes"`
+}
+
+// ContainerRequest provides the container creation parameters.
+type ContainerRequest struct {
+	Name         string                 `json:"name"`
+	FileIDs      []string               `json:"file_ids,omitempty"`
+	ExpiresAfter *ContainerExpiresAfter `json:"expires_after,omitempty"`
+}
+
+// ContainersList is a list of containers.
+type ContainersList struct {
+	Containers []Container `json:"data"`
+	FirstID    *string     `json:"first_id"`
+	LastID     *string     `json:"last_id"`
+	HasMore    bool        `json:"has_more"`
+
+	httpHeader
+}
+
+type ContainerDeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+
+	httpHeader
+}
+
+// ContainerFile is a file available inside a container, either uploaded or generated by the model.
+type ContainerFile struct {
+	ID          string `json:"id"`
+	Object      string `json:"object"`
+	CreatedAt   int64  `json:"created_at"`
+	Bytes       int64  `json:"bytes"`
+	ContainerID string `json:"container_id"`
+	Path        string `json:"path"`
+	// Source is "user" for uploaded files and "assistant" for files generated by the model.
+	Source string `json:"source"`
+
+	httpHeader
+}
+
+// ContainerFileRequest adds a file to a container. Either FileID of an existing file
+// or Reader and Name of new content must be set.
+type ContainerFileRequest struct {
+	FileID string
+	Reader io.Reader
+	Name   string
+}
+
+// ContainerFilesList is a list of container files.
+type ContainerFilesList struct {
+	ContainerFiles []ContainerFile `json:"data"`
+	FirstID        *string         `json:"first_id"`
+	LastID         *string         `json:"last_id"`
+	HasMore        bool            `json:"has_more"`
+
+	httpHeader
+}
+
+// CreateContainer creates a container.
+func (c *Client) CreateContainer(ctx context.Context, request ContainerRequest) (response Container, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(containersSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// RetrieveContainer retrieves a container.
+func (c *Client) RetrieveContainer(ctx context.Context, containerID string) (response Container, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(containersSuffix+"/"+containerID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// DeleteContainer deletes a container.
+func (c *Client) DeleteContainer(
+	ctx context.Context,
+	containerID string,
+) (response ContainerDeleteResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(containersSuffix+"/"+containerID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ListContainers lists the containers of the project.
+func (c *Client) ListContainers(ctx context.Context, pagination Pagination) (response ContainersList, err error) {
+	urlSuffix := containersSuffix + paginationQuery(pagination)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// CreateContainerFile uploads new content or copies an existing file into a container.
+func (c *Client) CreateContainerFile(
+	ctx context.Context,
+	containerID string,
+	request ContainerFileRequest,
+) (response ContainerFile, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s%s", containersSuffix, containerID, containersFilesSuffix)
+
+	var req *http.Request
+	if request.Reader == nil {
+		req, err = c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix),
+			withBody(map[string]string{"file_id": request.FileID}))
+		if err != nil {
+			return
+		}
+		err = c.sendRequest(req, &response)
+		return
+	}
+
+	var b bytes.Buffer
+	builder := c.createFormBuilder(&b)
+	err = builder.CreateFormFileReader("file", request.Reader, request.Name)
+	if err != nil {
+		return
+	}
+	err = builder.Close()
+	if err != nil {
+		return
+	}
+
+	req, err = c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix),
+		withBody(&b), withContentType(builder.FormDataContentType()))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// RetrieveContainerFile retrieves a container file.
+func (c *Client) RetrieveContainerFile(
+	ctx context.Context,
+	containerID string,
+	fileID string,
+) (response ContainerFile, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s%s/%s", containersSuffix, containerID, containersFilesSuffix, fileID)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// DeleteContainerFile deletes a container file.
+func (c *Client) DeleteContainerFile(
+	ctx context.Context,
+	containerID string,
+	fileID string,
+) (response ContainerDeleteResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s%s/%s", containersSuffix, containerID, containersFilesSuffix, fileID)
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ListContainerFiles lists the files of a container.
+func (c *Client) ListContainerFiles(
+	ctx context.Context,
+	containerID string,
+	pagination Pagination,
+) (response ContainerFilesList, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s%s%s", containersSuffix, containerID, containersFilesSuffix,
+		paginationQuery(pagination))
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// GetContainerFileContent returns the raw content of a container file.
+// The caller is responsible for closing the returned response.
+func (c *Client) GetContainerFileContent(
+	ctx context.Context,
+	containerID string,
+	fileID string,
+) (content RawResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s%s/%s/content", containersSuffix, containerID, containersFilesSuffix, fileID)
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	return c.sendRequestRaw(req)
+}
+
+// DownloadContainerFile writes the content of a container file, such as a chart generated
+// by code_interpreter, to w and returns the number of bytes written.
+func (c *Client) DownloadContainerFile(
+	ctx context.Context,
+	containerID string,
+	fileID string,
+	w io.Writer,
+) (int64, error) {
+	content, err := c.GetContainerFileContent(ctx, containerID, fileID)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+
+	return io.Copy(w, content)
+}
+
+func paginationQuery(pagination Pagination) string {
+	urlValues := url.Values{}
+	if pagination.Limit != nil {
+		urlValues.Add("limit", fmt.Sprintf("%d", *pagination.Limit))
+	}
+	if pagination.Order != nil {
+		urlValues.Add("order", *pagination.Order)
+	}
+	if pagination.After != nil {
+		urlValues.Add("after", *pagination.After)
+	}
+	if pagination.Before != nil {
+		urlValues.Add("before", *pagination.Before)
+	}
+
+	if len(urlValues) == 0 {
+		return ""
+	}
+	return "?" + urlValues.Encode()
+}
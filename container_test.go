@@ -0,0 +1,134 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestContainers(t *testing.T) {
+	const containerID = "cntr_abc123"
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/containers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("limit") != "5" {
+				t.Errorf("unexpected list query: %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"object":"list","data":[{"id":%q}],"has_more":false}`, containerID)
+			return
+		}
+		var request openai.ContainerRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		resBytes, _ := json.Marshal(openai.Container{
+			ID:           containerID,
+			Object:       "container",
+			Name:         request.Name,
+			ExpiresAfter: request.ExpiresAfter,
+			Status:       "running",
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+	server.RegisterHandler("/v1/containers/"+containerID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			fmt.Fprintf(w, `{"id":%q,"object":"container.deleted","deleted":true}`, containerID)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%q,"object":"container","status":"running"}`, containerID)
+	})
+
+	ctx := context.Background()
+	container, err := client.CreateContainer(ctx, openai.ContainerRequest{
+		Name:         "sandbox",
+		ExpiresAfter: &openai.ContainerExpiresAfter{Anchor: "last_active_at", Minutes: 20},
+	})
+	checks.NoError(t, err, "CreateContainer error")
+	if container.Name != "sandbox" || container.ExpiresAfter.Minutes != 20 {
+		t.Errorf("unexpected container: %+v", container)
+	}
+
+	_, err = client.RetrieveContainer(ctx, containerID)
+	checks.NoError(t, err, "RetrieveContainer error")
+
+	limit := 5
+	list, err := client.ListContainers(ctx, openai.Pagination{Limit: &limit})
+	checks.NoError(t, err, "ListContainers error")
+	if len(list.Containers) != 1 {
+		t.Errorf("unexpected containers: %+v", list)
+	}
+
+	deleted, err := client.DeleteContainer(ctx, containerID)
+	checks.NoError(t, err, "DeleteContainer error")
+	if !deleted.Deleted {
+		t.Error("expected container to be deleted")
+	}
+}
+
+func TestContainerFiles(t *testing.T) {
+	const (
+		containerID = "cntr_abc123"
+		fileID      = "cfile_abc123"
+	)
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/containers/"+containerID+"/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"object":"list","data":[{"id":%q,"source":"assistant"}]}`, fileID)
+			return
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			file, header, err := r.FormFile("file")
+			checks.NoError(t, err, "read form file")
+			content, _ := io.ReadAll(file)
+			fmt.Fprintf(w, `{"id":%q,"path":"/mnt/data/%s","bytes":%d,"source":"user"}`,
+				fileID, header.Filename, len(content))
+			return
+		}
+		var request map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		fmt.Fprintf(w, `{"id":%q,"source":"user"}`, request["file_id"])
+	})
+	server.RegisterHandler("/v1/containers/"+containerID+"/files/"+fileID+"/content",
+		func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, "chart-bytes")
+		})
+
+	ctx := context.Background()
+	file, err := client.CreateContainerFile(ctx, containerID, openai.ContainerFileRequest{
+		Reader: strings.NewReader("a,b\n1,2\n"),
+		Name:   "data.csv",
+	})
+	checks.NoError(t, err, "CreateContainerFile upload error")
+	if file.Path != "/mnt/data/data.csv" || file.Bytes != 8 {
+		t.Errorf("unexpected uploaded file: %+v", file)
+	}
+
+	file, err = client.CreateContainerFile(ctx, containerID, openai.ContainerFileRequest{FileID: "file-xyz"})
+	checks.NoError(t, err, "CreateContainerFile by id error")
+	if file.ID != "file-xyz" {
+		t.Errorf("unexpected copied file: %+v", file)
+	}
+
+	files, err := client.ListContainerFiles(ctx, containerID, openai.Pagination{})
+	checks.NoError(t, err, "ListContainerFiles error")
+	if len(files.ContainerFiles) != 1 || files.ContainerFiles[0].Source != "assistant" {
+		t.Errorf("unexpected files: %+v", files)
+	}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadContainerFile(ctx, containerID, fileID, &buf)
+	checks.NoError(t, err, "DownloadContainerFile error")
+	if n != int64(len("chart-bytes")) || buf.String() != "chart-bytes" {
+		t.Errorf("unexpected download: %d %q", n, buf.String())
+	}
+}
@@ -0,0 +1,108 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const azureFilteredCompletion = `{"id":"1","object":"chat.completion","model":"gpt-4o",
+"prompt_filter_results":[{"prompt_index":0,"content_filter_results":{
+	"hate":{"filtered":false,"severity":"safe"},
+	"jailbreak":{"filtered":true,"detected":true},
+	"indirect_attack":{"filtered":false,"detected":false},
+	"custom_blocklists":{"filtered":true,"details":[{"id":"banned-words","filtered":true}]}}}],
+"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"def f(): pass"},
+	"content_filter_results":{
+		"violence":{"filtered":false,"severity":"low"},
+		"protected_material_text":{"filtered":false,"detected":false},
+		"protected_material_code":{"filtered":false,"detected":true,
+			"citation":{"URL":"https://github.com/octo/repo","license":"MIT"}}}}]}`
+
+func setupAzureContentFilterServer(t *testing.T) *openai.Client {
+	t.Helper()
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	t.Cleanup(ts.Close)
+	server.RegisterHandler("/openai/deployments/gpt-4o/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n")
+			fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"finish_reason":null,`+
+				`"content_filter_results":{"sexual":{"filtered":true,"severity":"high"},`+
+				`"error":{"code":503,"message":"unavailable"}},`+
+				`"content_filter_offsets":{"check_offset":0,"start_offset":0,"end_offset":2}}]}`+"\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, azureFilteredCompletion)
+	})
+	return openai.NewClientWithConfig(openai.DefaultAzureConfig(test.GetTestToken(), ts.URL))
+}
+
+var azureContentFilterRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4o,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+}
+
+func TestAzureContentFilterResults(t *testing.T) {
+	client := setupAzureContentFilterServer(t)
+	resp, err := client.CreateChatCompletion(context.Background(), azureContentFilterRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	prompt := resp.PromptFilterResults[0].ContentFilterResults
+	if !prompt.JailBreak.Detected || prompt.Hate.Severity != openai.ContentFilterSeveritySafe {
+		t.Errorf("unexpected prompt filter results %+v", prompt)
+	}
+	if prompt.IndirectAttack == nil || prompt.IndirectAttack.Detected {
+		t.Errorf("unexpected indirect attack result %+v", prompt.IndirectAttack)
+	}
+	if prompt.CustomBlocklists == nil || prompt.CustomBlocklists.Details[0].ID != "banned-words" {
+		t.Errorf("unexpected custom blocklists %+v", prompt.CustomBlocklists)
+	}
+	if !prompt.Filtered() {
+		t.Error("expected the prompt to be reported as filtered")
+	}
+
+	choice := resp.Choices[0].ContentFilterResults
+	if choice.Violence.Severity != openai.ContentFilterSeverityLow || choice.ProtectedMaterialText == nil {
+		t.Errorf("unexpected choice filter results %+v", choice)
+	}
+	code := choice.ProtectedMaterialCode
+	if code == nil || !code.Detected || code.Citation == nil || code.Citation.License != "MIT" {
+		t.Errorf("unexpected protected material code result %+v", code)
+	}
+	if choice.IndirectAttack != nil || choice.Filtered() {
+		t.Errorf("expected no indirect attack result and no filtering, got %+v", choice)
+	}
+}
+
+func TestAzureContentFilterResultsStream(t *testing.T) {
+	client := setupAzureContentFilterServer(t)
+	stream, err := client.CreateChatCompletionStream(context.Background(), azureContentFilterRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+
+	choice := chunk.Choices[0]
+	if !choice.ContentFilterResults.Sexual.Filtered || choice.ContentFilterResults.Sexual.Severity != "high" {
+		t.Errorf("unexpected filter results %+v", choice.ContentFilterResults)
+	}
+	if choice.ContentFilterResults.Error == nil || choice.ContentFilterResults.Error.Code != 503 {
+		t.Errorf("unexpected filter error %+v", choice.ContentFilterResults.Error)
+	}
+	if choice.ContentFilterOffsets == nil || choice.ContentFilterOffsets.EndOffset != 2 {
+		t.Errorf("unexpected filter offsets %+v", choice.ContentFilterOffsets)
+	}
+}
@@ -0,0 +1,167 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrContentBlocked is wrapped by the errors of the content denied by a ResponseFilter, such as
+// *ContentBlockedError.
+var ErrContentBlocked = errors.New("content blocked")
+
+// ContentBlockedError is returned for the content of a choice flagged by AutoModerate.
+type ContentBlockedError struct {
+	// Index is the index of the choice.
+	Index          int
+	Categories     ResultCategories
+	CategoryScores ResultCategoryScores
+}
+
+func (e *ContentBlockedError) Error() string {
+	return fmt.Sprintf("%s: choice %d was flagged by moderation", ErrContentBlocked, e.Index)
+}
+
+func (e *ContentBlockedError) Unwrap() error {
+	return ErrContentBlocked
+}
+
+// FilteredContent is content of a chat completion received from the API, passed to a
+// ResponseFilter.
+type FilteredContent struct {
+	// Index is the index of the choice.
+	Index int
+	// Content is the content of the message of the choice, or the content of a delta of a
+	// stream.
+	Content string
+	Stream  bool
+	// Done reports that Content is the complete content of a streamed choice, passed once the
+	// choice finished after all its deltas. The content returned for it is ignored since the
+	// deltas were already delivered, but an error ends the stream.
+	Done bool
+}
+
+// ResponseFilter inspects the content of chat completions once received, before it is
+// delivered. It returns the content to deliver, possibly modified, or an error to deny it,
+// preferably wrapping ErrContentBlocked.
+type ResponseFilter func(ctx context.Context, content FilteredContent) (string, error)
+
+// responseFilters returns the ResponseFilter of the client followed by its moderation, if any.
+func (c *Client) responseFilters() []ResponseFilter {
+	var filters []ResponseFilter
+	if c.config.ResponseFilter != nil {
+		filters = append(filters, c.config.ResponseFilter)
+	}
+	if c.config.AutoModerate {
+		filters = append(filters, c.moderateContent)
+	}
+	return filters
+}
+
+// moderateContent denies the content flagged by the moderation endpoint. Streamed choices are
+// moderated once complete.
+func (c *Client) moderateContent(ctx context.Context, content FilteredContent) (string, error) {
+	if (content.Stream && !content.Done) || strings.TrimSpace(content.Content) == "" {
+		return content.Content, nil
+	}
+	resp, err := c.Moderations(ctx, ModerationRequest{Input: content.Content, Model: ModerationOmniLatest})
+	if err != nil {
+		return "", fmt.Errorf("moderating content: %w", err)
+	}
+	for _, result := range resp.Results {
+		if result.Flagged {
+			return "", &ContentBlockedError{
+				Index:          content.Index,
+				Categories:     result.Categories,
+				CategoryScores: result.CategoryScores,
+			}
+		}
+	}
+	return content.Content, nil
+}
+
+func applyResponseFilters(ctx context.Context, filters []ResponseFilter, content FilteredContent) (string, error) {
+	for _, filter := range filters {
+		filtered, err := filter(ctx, content)
+		if err != nil {
+			return "", err
+		}
+		content.Content = filtered
+	}
+	return content.Content, nil
+}
+
+// filterResponse applies the response filters of the client to the choices of response. The
+// content of a denied choice is removed.
+func (c *Client) filterResponse(ctx context.Context, response *ChatCompletionResponse) error {
+	filters := c.responseFilters()
+	if len(filters) == 0 {
+		return nil
+	}
+	for i := range response.Choices {
+		choice := &response.Choices[i]
+		content, err := applyResponseFilters(ctx, filters, FilteredContent{
+			Index:   choice.Index,
+			Content: choice.Message.Content,
+		})
+		if err != nil {
+			choice.Message.Content = ""
+			return err
+		}
+		choice.Message.Content = content
+	}
+	return nil
+}
+
+// streamResponseFilter applies response filters to the deltas of a stream, and to the
+// accumulated content of each choice once it finished.
+type streamResponseFilter struct {
+	ctx     context.Context
+	filters []ResponseFilter
+	content map[int]*strings.Builder
+}
+
+func (c *Client) newStreamResponseFilter(ctx context.Context) *streamResponseFilter {
+	filters := c.responseFilters()
+	if len(filters) == 0 {
+		return nil
+	}
+	return &streamResponseFilter{ctx: ctx, filters: filters, content: make(map[int]*strings.Builder)}
+}
+
+func (f *streamResponseFilter) apply(response *ChatCompletionStreamResponse) error {
+	for i := range response.Choices {
+		choice := &response.Choices[i]
+		accumulated, ok := f.content[choice.Index]
+		if !ok {
+			accumulated = &strings.Builder{}
+			f.content[choice.Index] = accumulated
+		}
+		if choice.Delta.Content != "" {
+			content, err := applyResponseFilters(f.ctx, f.filters, FilteredContent{
+				Index:   choice.Index,
+				Content: choice.Delta.Content,
+				Stream:  true,
+			})
+			if err != nil {
+				choice.Delta.Content = ""
+				return err
+			}
+			choice.Delta.Content = content
+			accumulated.WriteString(content)
+		}
+		if choice.FinishReason != "" {
+			_, err := applyResponseFilters(f.ctx, f.filters, FilteredContent{
+				Index:   choice.Index,
+				Content: accumulated.String(),
+				Stream:  true,
+				Done:    true,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// setupContentSafetyServer answers chat completions with content and flags the moderation of
+// content containing "attack".
+func setupContentSafetyServer(t *testing.T, content string, configure func(*openai.ClientConfig)) *openai.Client {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(configure)
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, word := range strings.SplitAfter(content, " ") {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", word)
+			}
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+			return
+		}
+		response := openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+		}}}
+		checks.NoError(t, json.NewEncoder(w).Encode(response), "Encode error")
+	})
+	server.RegisterHandler("/v1/moderations", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ModerationRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		flagged := strings.Contains(request.Input, "attack")
+		result := openai.Result{Flagged: flagged}
+		if flagged {
+			result.Categories.Violence = true
+			result.CategoryScores.Violence = 0.9
+		}
+		checks.NoError(t, json.NewEncoder(w).Encode(openai.ModerationResponse{Results: []openai.Result{result}}),
+			"Encode error")
+	})
+	return client
+}
+
+var contentSafetyRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4o,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+}
+
+func TestResponseFilterModifiesContent(t *testing.T) {
+	var filtered []openai.FilteredContent
+	client := setupContentSafetyServer(t, "the secret plan", func(config *openai.ClientConfig) {
+		config.ResponseFilter = func(_ context.Context, content openai.FilteredContent) (string, error) {
+			filtered = append(filtered, content)
+			return strings.ReplaceAll(content.Content, "secret", "******"), nil
+		}
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), contentSafetyRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.Choices[0].Message.Content != "the ****** plan" {
+		t.Errorf("unexpected content %q", resp.Choices[0].Message.Content)
+	}
+
+	filtered = nil
+	stream, err := client.CreateChatCompletionStream(context.Background(), contentSafetyRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	var content strings.Builder
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	if content.String() != "the ****** plan" {
+		t.Errorf("unexpected streamed content %q", content.String())
+	}
+	last := filtered[len(filtered)-1]
+	if len(filtered) != 4 || !last.Done || !last.Stream || last.Content != "the ****** plan" {
+		t.Errorf("unexpected filtered content %+v", filtered)
+	}
+}
+
+func TestAutoModerate(t *testing.T) {
+	client := setupContentSafetyServer(t, "plan the attack", func(config *openai.ClientConfig) {
+		config.AutoModerate = true
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), contentSafetyRequest)
+	var blocked *openai.ContentBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, openai.ErrContentBlocked) {
+		t.Fatalf("expected ContentBlockedError, got %v", err)
+	}
+	if !blocked.Categories.Violence || blocked.CategoryScores.Violence != 0.9 {
+		t.Errorf("unexpected categories %+v", blocked)
+	}
+	if resp.Choices[0].Message.Content != "" {
+		t.Error("expected the blocked content to be removed")
+	}
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), contentSafetyRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, openai.ErrContentBlocked) {
+		t.Errorf("expected the stream to end with ErrContentBlocked, got %v", err)
+	}
+}
+
+func TestAutoModerateAllowsSafeContent(t *testing.T) {
+	client := setupContentSafetyServer(t, "have a nice day", func(config *openai.ClientConfig) {
+		config.AutoModerate = true
+	})
+	resp, err := client.CreateChatCompletion(context.Background(), contentSafetyRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.Choices[0].Message.Content != "have a nice day" {
+		t.Errorf("unexpected content %q", resp.Choices[0].Message.Content)
+	}
+}
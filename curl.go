@@ -0,0 +1,142 @@
+package openai
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// curlRedacted replaces the credentials of the commands returned by ToCurl.
+const curlRedacted = "REDACTED"
+
+// curlSecretHeaders are the headers carrying credentials, in canonical form.
+var curlSecretHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Api-Key":              true,
+	"X-Api-Key":            true,
+	"Cf-Aig-Authorization": true,
+	// Set by WithHelicone and WithLangfuse.
+	"Helicone-Auth":         true,
+	"X-Langfuse-Secret-Key": true,
+}
+
+// ToCurl returns a curl command sending req, to reproduce a request outside Go or share it in
+// an issue. The API key and other credentials are replaced with REDACTED, including those sent
+// as configured by config.AuthStyle; the authentication scheme, such as "Bearer", is kept.
+// The body of req is read with GetBody, so req can still be sent. Binary bodies, such as file
+// uploads, and bodies without GetBody, such as streamed uploads, are not inlined: the command
+// reads them from standard input instead.
+func ToCurl(req *http.Request, config ClientConfig) (string, error) {
+	body, replayable, err := peekRequestBody(req)
+	if err != nil {
+		return "", err
+	}
+
+	secretHeaders := curlSecretHeaders
+	var secretParam string
+	if config.AuthStyle != nil {
+		secretParam = config.AuthStyle.QueryParam
+		if config.AuthStyle.Header != "" {
+			secretHeaders = map[string]bool{http.CanonicalHeaderKey(config.AuthStyle.Header): true}
+			for header := range curlSecretHeaders {
+				secretHeaders[header] = true
+			}
+		}
+	}
+
+	u := *req.URL
+	if secretParam != "" {
+		query := u.Query()
+		if query.Has(secretParam) {
+			query.Set(secretParam, curlRedacted)
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	var command strings.Builder
+	command.WriteString("curl")
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet || len(body) > 0 || !replayable {
+		command.WriteString(" -X " + method)
+	}
+	command.WriteString(" " + shellQuote(u.Redacted()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if secretHeaders[http.CanonicalHeaderKey(name)] {
+				value = redactCredential(value)
+			}
+			command.WriteString(" \\\n  -H " + shellQuote(name+": "+value))
+		}
+	}
+
+	if len(body) > 0 || !replayable {
+		if replayable && utf8.Valid(body) && !bytes.ContainsRune(body, 0) {
+			command.WriteString(" \\\n  --data-raw " + shellQuote(string(body)))
+		} else {
+			command.WriteString(" \\\n  --data-binary @-")
+		}
+	}
+	return command.String(), nil
+}
+
+// peekRequestBody returns the body of req without consuming it. Bodies that cannot be
+// replayed with GetBody are left unread, so that streamed uploads stay streamed, and
+// replayable is false.
+func peekRequestBody(req *http.Request) (body []byte, replayable bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if req.GetBody == nil {
+		return nil, false, nil
+	}
+	replay, err := req.GetBody()
+	if err != nil {
+		return nil, false, err
+	}
+	defer replay.Close()
+	body, err = io.ReadAll(replay)
+	return body, true, err
+}
+
+// redactCredential replaces a credential, keeping its authentication scheme.
+func redactCredential(value string) string {
+	if scheme, _, found := strings.Cut(value, " "); found {
+		return scheme + " " + curlRedacted
+	}
+	return curlRedacted
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// curlDoer logs the curl command of every request before sending it.
+type curlDoer struct {
+	doer   HTTPDoer
+	logger Logger
+	config ClientConfig
+}
+
+func (d *curlDoer) Do(req *http.Request) (*http.Response, error) {
+	command, err := ToCurl(req, d.config)
+	if err != nil {
+		d.logger.Printf("openai: cannot export %s %s as curl: %v", req.Method, req.URL.Redacted(), err)
+	} else {
+		d.logger.Printf("%s", command)
+	}
+	return d.doer.Do(req)
+}
@@ -0,0 +1,162 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestToCurl(t *testing.T) {
+	body := `{"model":"gpt-4o","messages":[{"role":"user","content":"It's me"}]}`
+	req, err := http.NewRequest(http.MethodPost, "https://api.openai.com/v1/chat/completions", strings.NewReader(body))
+	checks.NoError(t, err, "NewRequest error")
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("Content-Type", "application/json")
+
+	command, err := openai.ToCurl(req, openai.DefaultConfig("sk-secret"))
+	checks.NoError(t, err, "ToCurl error")
+	want := "curl -X POST 'https://api.openai.com/v1/chat/completions' \\\n" +
+		"  -H 'Authorization: Bearer REDACTED' \\\n" +
+		"  -H 'Content-Type: application/json' \\\n" +
+		`  --data-raw '{"model":"gpt-4o","messages":[{"role":"user","content":"It'\''s me"}]}'`
+	if command != want {
+		t.Errorf("unexpected command:\n%s\nwant:\n%s", command, want)
+	}
+
+	// The body is still there to be sent.
+	sent, err := io.ReadAll(req.Body)
+	checks.NoError(t, err, "ReadAll error")
+	if string(sent) != body {
+		t.Errorf("expected the body to be preserved, got %q", sent)
+	}
+}
+
+func TestToCurlAuthStyle(t *testing.T) {
+	config := openai.DefaultConfig("secret")
+	config.AuthStyle = openai.AuthQueryParam("key")
+	req, err := http.NewRequest(http.MethodGet, "https://gateway.example.com/v1/models?key=secret&limit=2", nil)
+	checks.NoError(t, err, "NewRequest error")
+	req.Header.Set("X-Api-Key", "other-secret")
+
+	command, err := openai.ToCurl(req, config)
+	checks.NoError(t, err, "ToCurl error")
+	if strings.Contains(command, "secret") || strings.Contains(command, "-X") ||
+		!strings.Contains(command, "key=REDACTED") || !strings.Contains(command, "limit=2") ||
+		!strings.Contains(command, "'X-Api-Key: REDACTED'") {
+		t.Errorf("unexpected command %s", command)
+	}
+
+	config.AuthStyle = &openai.AuthStyle{Header: "X-Gateway-Token"}
+	req.Header.Set("X-Gateway-Token", "gateway-secret")
+	command, err = openai.ToCurl(req, config)
+	checks.NoError(t, err, "ToCurl error")
+	if strings.Contains(command, "gateway-secret") || !strings.Contains(command, "'X-Gateway-Token: REDACTED'") {
+		t.Errorf("unexpected command %s", command)
+	}
+}
+
+func TestToCurlBinaryBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://api.openai.com/v1/files", bytes.NewReader([]byte{0xff, 0x00}))
+	checks.NoError(t, err, "NewRequest error")
+	command, err := openai.ToCurl(req, openai.DefaultConfig(""))
+	checks.NoError(t, err, "ToCurl error")
+	if !strings.HasSuffix(command, "--data-binary @-") {
+		t.Errorf("expected the binary body to be read from stdin, got %s", command)
+	}
+}
+
+func TestCurlLogger(t *testing.T) {
+	var curlLog bytes.Buffer
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.CurlLogger = log.New(&curlLog, "", 0)
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(payload), "hello") {
+			t.Errorf("unexpected body %s", payload)
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"object":"embedding","embedding":[1],"index":0}]}`)
+	})
+
+	_, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+		Input: []string{"hello"},
+		Model: openai.SmallEmbedding3,
+	})
+	checks.NoError(t, err, "CreateEmbeddings error")
+
+	logged := curlLog.String()
+	if !strings.HasPrefix(logged, "curl -X POST '") || !strings.Contains(logged, "/v1/embeddings'") ||
+		!strings.Contains(logged, "'Authorization: Bearer REDACTED'") || !strings.Contains(logged, `"hello"`) {
+		t.Errorf("unexpected log %s", logged)
+	}
+}
+
+func TestToCurlStreamedBody(t *testing.T) {
+	body := &countingReader{Reader: strings.NewReader(`{"file":"large"}`)}
+	req, err := http.NewRequest(http.MethodPost, "https://api.openai.com/v1/files", io.NopCloser(body))
+	checks.NoError(t, err, "NewRequest error")
+	if req.GetBody != nil {
+		t.Fatal("expected a request body that cannot be replayed")
+	}
+
+	command, err := openai.ToCurl(req, openai.DefaultConfig(""))
+	checks.NoError(t, err, "ToCurl error")
+	if !strings.HasPrefix(command, "curl -X POST ") || !strings.HasSuffix(command, "--data-binary @-") {
+		t.Errorf("expected the streamed body to be read from stdin, got %s", command)
+	}
+	if body.read != 0 {
+		t.Errorf("expected the streamed body to be left unread, %d bytes were read", body.read)
+	}
+}
+
+// countingReader counts the bytes read from Reader.
+type countingReader struct {
+	io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestCurlLoggerProviderHeaders(t *testing.T) {
+	var curlLog bytes.Buffer
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.CurlLogger = log.New(&curlLog, "", 0)
+		config.ProviderHeaders = []openai.ProviderHeaders{
+			openai.WithHelicone("helicone-secret", nil),
+			openai.WithLangfuse("langfuse-public", "langfuse-secret", openai.LangfuseTrace{}),
+		}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","data":[{"object":"embedding","embedding":[1],"index":0}]}`)
+	})
+
+	_, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequest{
+		Input: []string{"hello"},
+		Model: openai.SmallEmbedding3,
+	})
+	checks.NoError(t, err, "CreateEmbeddings error")
+
+	logged := curlLog.String()
+	if strings.Contains(logged, "helicone-secret") || strings.Contains(logged, "langfuse-secret") {
+		t.Errorf("expected the gateway secrets to be redacted, got %s", logged)
+	}
+	if !strings.Contains(logged, "'Helicone-Auth: Bearer REDACTED'") ||
+		!strings.Contains(logged, "'X-Langfuse-Secret-Key: REDACTED'") ||
+		!strings.Contains(logged, "'X-Langfuse-Public-Key: langfuse-public'") {
+		t.Errorf("unexpected log %s", logged)
+	}
+}
@@ -0,0 +1,89 @@
+package openai
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Logger is the logging hook used by helpers to report diagnostics.
+// *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// DeterminismChecker tracks system fingerprints returned for seeded requests and
+// reports when the backend configuration changes between calls with the same
+// model and seed, which means results are no longer reproducible.
+// It is safe for concurrent use.
+type DeterminismChecker struct {
+	logger Logger
+
+	mu           sync.Mutex
+	fingerprints map[string]string
+}
+
+// NewDeterminismChecker creates a checker that warns via logger when fingerprints change.
+// The logger may be nil, in which case changes are only reported through return values.
+func NewDeterminismChecker(logger Logger) *DeterminismChecker {
+	return &DeterminismChecker{
+		logger:       logger,
+		fingerprints: make(map[string]string),
+	}
+}
+
+// Observe records the fingerprint returned for model and seed.
+// It returns true if a different fingerprint was previously observed for the same pair.
+// Empty fingerprints are ignored.
+func (d *DeterminismChecker) Observe(model string, seed int, fingerprint string) bool {
+	if fingerprint == "" {
+		return false
+	}
+
+	key := fmt.Sprintf("%s/%d", model, seed)
+
+	d.mu.Lock()
+	previous, ok := d.fingerprints[key]
+	d.fingerprints[key] = fingerprint
+	d.mu.Unlock()
+
+	if !ok || previous == fingerprint {
+		return false
+	}
+	if d.logger != nil {
+		d.logger.Printf(
+			"openai: system_fingerprint changed for model %q with seed %d: %s -> %s; outputs may differ",
+			model, seed, previous, fingerprint,
+		)
+	}
+	return true
+}
+
+// CheckChatCompletion observes the fingerprint of a chat completion response.
+// Requests without a seed are ignored.
+func (d *DeterminismChecker) CheckChatCompletion(request ChatCompletionRequest, response ChatCompletionResponse) bool {
+	if request.Seed == nil {
+		return false
+	}
+	return d.Observe(request.Model, *request.Seed, response.SystemFingerprint)
+}
+
+// CheckChatCompletionStream observes the fingerprint of a chat completion stream chunk.
+// Requests without a seed are ignored.
+func (d *DeterminismChecker) CheckChatCompletionStream(
+	request ChatCompletionRequest,
+	chunk ChatCompletionStreamResponse,
+) bool {
+	if request.Seed == nil {
+		return false
+	}
+	return d.Observe(request.Model, *request.Seed, chunk.SystemFingerprint)
+}
+
+// CheckCompletion observes the fingerprint of a legacy completion response.
+// Requests without a seed are ignored.
+func (d *DeterminismChecker) CheckCompletion(request CompletionRequest, response CompletionResponse) bool {
+	if request.Seed == nil {
+		return false
+	}
+	return d.Observe(request.Model, *request.Seed, response.SystemFingerprint)
+}
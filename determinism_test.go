@@ -0,0 +1,81 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestDeterminismChecker(t *testing.T) {
+	var buf bytes.Buffer
+	checker := openai.NewDeterminismChecker(log.New(&buf, "", 0))
+	seed := 42
+	req := openai.ChatCompletionRequest{Model: openai.GPT4o, Seed: &seed}
+
+	if checker.CheckChatCompletion(req, openai.ChatCompletionResponse{SystemFingerprint: "fp_1"}) {
+		t.Error("first observation should not be reported as a change")
+	}
+	if checker.CheckChatCompletion(req, openai.ChatCompletionResponse{SystemFingerprint: "fp_1"}) {
+		t.Error("same fingerprint should not be reported as a change")
+	}
+	if !checker.CheckChatCompletionStream(req, openai.ChatCompletionStreamResponse{SystemFingerprint: "fp_2"}) {
+		t.Error("changed fingerprint should be reported")
+	}
+	if !strings.Contains(buf.String(), "fp_1 -> fp_2") {
+		t.Errorf("expected warning to be logged, got %q", buf.String())
+	}
+
+	otherSeed := 7
+	if checker.CheckChatCompletion(
+		openai.ChatCompletionRequest{Model: openai.GPT4o, Seed: &otherSeed},
+		openai.ChatCompletionResponse{SystemFingerprint: "fp_3"},
+	) {
+		t.Error("different seed should be tracked separately")
+	}
+	if checker.CheckChatCompletion(
+		openai.ChatCompletionRequest{Model: openai.GPT4o},
+		openai.ChatCompletionResponse{SystemFingerprint: "fp_4"},
+	) {
+		t.Error("requests without seed should be ignored")
+	}
+	if checker.CheckCompletion(
+		openai.CompletionRequest{Model: openai.GPT4o, Seed: &seed},
+		openai.CompletionResponse{},
+	) {
+		t.Error("empty fingerprints should be ignored")
+	}
+}
+
+func TestChatCompletionStreamSystemFingerprint(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		//nolint:lll
+		fmt.Fprint(w, `data: {"id":"1","object":"chat.completion.chunk","model":"gpt-4o","system_fingerprint":"fp_abc","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream returned error")
+	defer stream.Close()
+
+	if stream.SystemFingerprint() != "" {
+		t.Error("fingerprint should be empty before receiving chunks")
+	}
+	_, err = stream.Recv()
+	checks.NoError(t, err, "Recv returned error")
+	if stream.SystemFingerprint() != "fp_abc" {
+		t.Errorf("unexpected fingerprint: %q", stream.SystemFingerprint())
+	}
+}
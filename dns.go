@@ -0,0 +1,283 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrNoAddresses = errors.New("no addresses of the allowed IP versions")
+
+const (
+	defaultDNSCacheTTL       = time.Minute
+	defaultDNSFallbackDelay  = 300 * time.Millisecond
+	dnsCacheCleanupThreshold = 1024
+)
+
+// Resolver resolves the IP addresses of a host, like *net.Resolver.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// IPPreference selects the IP versions used to connect to the API.
+type IPPreference string
+
+const (
+	// IPPreferenceAny dials the addresses in the order returned by the resolver.
+	IPPreferenceAny  IPPreference = ""
+	IPPreferenceIPv4 IPPreference = "prefer_ipv4"
+	IPPreferenceIPv6 IPPreference = "prefer_ipv6"
+	IPv4Only         IPPreference = "ipv4_only"
+	IPv6Only         IPPreference = "ipv6_only"
+)
+
+// DNSCache caches the addresses of hosts and controls the IP version of connections. It
+// applies when HTTPClient is an *http.Client using an *http.Transport, or the default transport.
+// The transport is cloned, so it can be shared with other clients.
+type DNSCache struct {
+	// TTL is how long addresses are cached, 1 minute by default. Expired addresses are still
+	// used when resolving the host again fails.
+	TTL time.Duration
+	// IPPreference orders or filters the addresses by IP version.
+	IPPreference IPPreference
+	// FallbackDelay is the time to wait for a connection to the preferred IP version before
+	// also dialing the other version ("Happy Eyeballs"), 300ms by default. A negative delay
+	// dials the other version only after all preferred addresses failed.
+	FallbackDelay time.Duration
+	// Resolver resolves hosts, net.DefaultResolver by default.
+	Resolver Resolver
+
+	mu      sync.Mutex
+	entries map[string]dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+func (d *DNSCache) ttl() time.Duration {
+	if d.TTL > 0 {
+		return d.TTL
+	}
+	return defaultDNSCacheTTL
+}
+
+func (d *DNSCache) fallbackDelay() time.Duration {
+	if d.FallbackDelay != 0 {
+		return d.FallbackDelay
+	}
+	return defaultDNSFallbackDelay
+}
+
+// lookup returns the cached addresses of host, resolving them when they expired.
+func (d *DNSCache) lookup(ctx context.Context, host string) ([]net.IP, error) {
+	now := time.Now()
+	d.mu.Lock()
+	entry, cached := d.entries[host]
+	d.mu.Unlock()
+	if cached && now.Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	var resolver Resolver = net.DefaultResolver
+	if d.Resolver != nil {
+		resolver = d.Resolver
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		if cached {
+			return entry.ips, nil
+		}
+		return nil, err
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.entries == nil || len(d.entries) >= dnsCacheCleanupThreshold {
+		d.entries = make(map[string]dnsCacheEntry)
+	}
+	d.entries[host] = dnsCacheEntry{ips: ips, expires: now.Add(d.ttl())}
+	return ips, nil
+}
+
+// partition splits ips into the preferred addresses and the fallback addresses.
+func (d *DNSCache) partition(ips []net.IP) (primaries, fallbacks []net.IP) {
+	for _, ip := range ips {
+		isIPv4 := ip.To4() != nil
+		switch d.IPPreference {
+		case IPPreferenceAny:
+			primaries = append(primaries, ip)
+		case IPPreferenceIPv4, IPv4Only:
+			if isIPv4 {
+				primaries = append(primaries, ip)
+			} else if d.IPPreference == IPPreferenceIPv4 {
+				fallbacks = append(fallbacks, ip)
+			}
+		case IPPreferenceIPv6, IPv6Only:
+			if !isIPv4 {
+				primaries = append(primaries, ip)
+			} else if d.IPPreference == IPPreferenceIPv6 {
+				fallbacks = append(fallbacks, ip)
+			}
+		}
+	}
+	if len(primaries) == 0 {
+		primaries, fallbacks = fallbacks, nil
+	}
+	return primaries, fallbacks
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialer returns a dial function resolving hosts with the cache before dialing their addresses
+// with dial.
+func (d *DNSCache) dialer(dial dialFunc) dialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+		ips, err := d.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		primaries, fallbacks := d.partition(ips)
+		if len(primaries) == 0 {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: ErrNoAddresses}
+		}
+		if len(fallbacks) == 0 || d.fallbackDelay() < 0 {
+			return dialSerial(ctx, dial, network, port, append(primaries, fallbacks...))
+		}
+		return dialParallel(ctx, dial, network, port, primaries, fallbacks, d.fallbackDelay())
+	}
+}
+
+// dialSerial dials ips in order until a connection succeeds.
+func dialSerial(ctx context.Context, dial dialFunc, network, port string, ips []net.IP) (net.Conn, error) {
+	var firstErr error
+	for _, ip := range ips {
+		conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, firstErr
+}
+
+// dialParallel dials primaries and, after delay or once they failed, fallbacks, and returns the
+// first connection established.
+func dialParallel(
+	ctx context.Context,
+	dial dialFunc,
+	network, port string,
+	primaries, fallbacks []net.IP,
+	delay time.Duration,
+) (net.Conn, error) {
+	type dialResult struct {
+		conn    net.Conn
+		err     error
+		primary bool
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan dialResult, 2)
+	start := func(ips []net.IP, primary bool) {
+		go func() {
+			conn, err := dialSerial(ctx, dial, network, port, ips)
+			results <- dialResult{conn: conn, err: err, primary: primary}
+		}()
+	}
+
+	start(primaries, true)
+	fallbackTimer := time.NewTimer(delay)
+	defer fallbackTimer.Stop()
+	var primaryErr error
+	pending, fallbackStarted := 1, false
+	for {
+		select {
+		case <-fallbackTimer.C:
+			if !fallbackStarted {
+				start(fallbacks, false)
+				fallbackStarted = true
+				pending++
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				if pending > 0 {
+					// Close the connection of the dial still pending, if it succeeds.
+					go func() {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}()
+				}
+				return res.conn, nil
+			}
+			if res.primary {
+				primaryErr = res.err
+			}
+			if !fallbackStarted {
+				start(fallbacks, false)
+				fallbackStarted = true
+				pending++
+			}
+			if pending == 0 {
+				if primaryErr != nil {
+					return nil, primaryErr
+				}
+				return nil, res.err
+			}
+		}
+	}
+}
+
+// withDNSCache returns doer with a transport resolving hosts with cache, or doer itself if its
+// transport cannot be configured.
+func withDNSCache(doer HTTPDoer, cache *DNSCache) HTTPDoer {
+	configured, _ := configureTransport(doer, func(transport *http.Transport) {
+		transport.DialContext = cache.dialer(transport.DialContext)
+	})
+	return configured
+}
+
+// configureTransport returns doer with a clone of its transport changed by configure. It reports
+// false and returns doer itself if doer is not an *http.Client using an *http.Transport, or the
+// default transport.
+func configureTransport(doer HTTPDoer, configure func(*http.Transport)) (HTTPDoer, bool) {
+	httpClient, ok := doer.(*http.Client)
+	if !ok {
+		return doer, false
+	}
+	roundTripper := httpClient.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return doer, false
+	}
+
+	transport = transport.Clone()
+	configure(transport)
+	configured := *httpClient
+	configured.Transport = transport
+	return &configured, true
+}
@@ -0,0 +1,121 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// fakeResolver resolves every host to ips, or fails once failing is set.
+type fakeResolver struct {
+	ips     []string
+	lookups int32
+	failing int32
+}
+
+func (r *fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	atomic.AddInt32(&r.lookups, 1)
+	if atomic.LoadInt32(&r.failing) == 1 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	addrs := make([]net.IPAddr, len(r.ips))
+	for i, ip := range r.ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
+
+// setupDNSTestServer starts a client reaching the test server through the host "api.test".
+func setupDNSTestServer(t *testing.T, dns *openai.DNSCache) (*openai.Client, func()) {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		baseURL, err := url.Parse(config.BaseURL)
+		checks.NoError(t, err, "url.Parse error")
+		baseURL.Host = net.JoinHostPort("api.test", baseURL.Port())
+		config.BaseURL = baseURL.String()
+		// Without keep-alives, each request dials and resolves the host.
+		config.HTTPClient = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		config.DNS = dns
+	})
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+	return client, teardown
+}
+
+func TestDNSCache(t *testing.T) {
+	resolver := &fakeResolver{ips: []string{"127.0.0.1"}}
+	client, teardown := setupDNSTestServer(t, &openai.DNSCache{Resolver: resolver})
+	defer teardown()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ListModels(context.Background())
+		checks.NoError(t, err, "ListModels error")
+	}
+	if lookups := atomic.LoadInt32(&resolver.lookups); lookups != 1 {
+		t.Errorf("expected 1 lookup, got %d", lookups)
+	}
+}
+
+func TestDNSCacheExpired(t *testing.T) {
+	resolver := &fakeResolver{ips: []string{"127.0.0.1"}}
+	client, teardown := setupDNSTestServer(t, &openai.DNSCache{Resolver: resolver, TTL: time.Nanosecond})
+	defer teardown()
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+
+	// The expired addresses are used when resolving the host again fails.
+	atomic.StoreInt32(&resolver.failing, 1)
+	_, err = client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error with a failing resolver")
+	if lookups := atomic.LoadInt32(&resolver.lookups); lookups != 2 {
+		t.Errorf("expected 2 lookups, got %d", lookups)
+	}
+}
+
+func TestDNSCacheIPPreference(t *testing.T) {
+	for _, preference := range []openai.IPPreference{
+		openai.IPPreferenceAny,
+		openai.IPPreferenceIPv4,
+		openai.IPPreferenceIPv6,
+		openai.IPv4Only,
+	} {
+		name := string(preference)
+		if name == "" {
+			name = "any"
+		}
+		t.Run(name, func(t *testing.T) {
+			// The test server listens on IPv4 only, so dialing ::1 fails or is not attempted.
+			resolver := &fakeResolver{ips: []string{"::1", "127.0.0.1"}}
+			client, teardown := setupDNSTestServer(t, &openai.DNSCache{
+				Resolver:      resolver,
+				IPPreference:  preference,
+				FallbackDelay: 10 * time.Millisecond,
+			})
+			defer teardown()
+			_, err := client.ListModels(context.Background())
+			checks.NoError(t, err, "ListModels error")
+		})
+	}
+}
+
+func TestDNSCacheNoAllowedAddresses(t *testing.T) {
+	resolver := &fakeResolver{ips: []string{"127.0.0.1"}}
+	client, teardown := setupDNSTestServer(t, &openai.DNSCache{Resolver: resolver, IPPreference: openai.IPv6Only})
+	defer teardown()
+
+	_, err := client.ListModels(context.Background())
+	if !errors.Is(err, openai.ErrNoAddresses) {
+		t.Errorf("expected ErrNoAddresses, got %v", err)
+	}
+}
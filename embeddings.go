@@ -9,7 +9,11 @@ import (
 	"net/http"
 )
 
-var ErrVectorLengthMismatch = errors.New("vector length mismatch")
+var (
+	ErrVectorLengthMismatch            = errors.New("vector length mismatch")
+	ErrEmbeddingDimensionsNotSupported = errors.New("this model does not support the dimensions parameter")
+	ErrEmbeddingDimensionsOutOfRange   = errors.New("dimensions must be between 1 and the output size of the model")
+)
 
 // EmbeddingModel enumerates the models which can be used
 // to generate Embedding vectors.
@@ -39,6 +43,23 @@ const (
 	LargeEmbedding3 EmbeddingModel = "text-embedding-3-large"
 )
 
+// embeddingModelCapabilities lists the output size of embedding models and whether it can be
+// reduced with the dimensions parameter (Matryoshka representation).
+var embeddingModelCapabilities = map[EmbeddingModel]struct {
+	dimensions    int
+	dimensionsSet bool
+}{
+	AdaEmbeddingV2:  {dimensions: 1536},
+	SmallEmbedding3: {dimensions: 1536, dimensionsSet: true},
+	LargeEmbedding3: {dimensions: 3072, dimensionsSet: true},
+}
+
+// Dimensions returns the default output size of the model, or false if the model is unknown.
+func (m EmbeddingModel) Dimensions() (int, bool) {
+	capabilities, ok := embeddingModelCapabilities[m]
+	return capabilities.dimensions, ok
+}
+
 // Embedding is a special format of data representation that can be easily utilized by machine
 // learning models and algorithms. The embedding is an information dense representation of the
 // semantic meaning of a piece of text. Each embedding is a vector of floating point numbers,
@@ -166,6 +187,28 @@ func (r EmbeddingRequest) Convert() EmbeddingRequest {
 	return r
 }
 
+// Validate checks the dimensions parameter against the capabilities of known models. Requests
+// for other models, e.g. of other providers, are left to the API to check.
+func (r EmbeddingRequest) Validate() error {
+	if r.Dimensions == 0 {
+		return nil
+	}
+	if r.Dimensions < 0 {
+		return ErrEmbeddingDimensionsOutOfRange
+	}
+	capabilities, ok := embeddingModelCapabilities[r.Model]
+	if !ok {
+		return nil
+	}
+	if !capabilities.dimensionsSet {
+		return ErrEmbeddingDimensionsNotSupported
+	}
+	if r.Dimensions > capabilities.dimensions {
+		return ErrEmbeddingDimensionsOutOfRange
+	}
+	return nil
+}
+
 // EmbeddingRequestStrings is the input to a create embeddings request with a slice of strings.
 type EmbeddingRequestStrings struct {
 	// Input is a slice of strings for which you want to generate an Embedding vector.
@@ -174,7 +217,7 @@ type EmbeddingRequestStrings struct {
 	// have observed inferior results when newlines are present.
 	// E.g.
 	//	"The food was delicious and the waiter..."
-	Input []string `json:"input"`
+	Input StringArray `json:"input"`
 	// ID of the model to use. You can use the List models API to see all of your available models,
 	// or see our Model overview for descriptions of them.
 	Model EmbeddingModel `json:"model"`
@@ -241,6 +284,11 @@ func (c *Client) CreateEmbeddings(
 	conv EmbeddingRequestConverter,
 ) (res EmbeddingResponse, err error) {
 	baseReq := conv.Convert()
+	baseReq.Model = EmbeddingModel(c.config.mapModel(string(baseReq.Model)))
+	if err = baseReq.Validate(); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"math"
+	"sort"
+)
+
+// VectorDot returns the dot product of two vectors of the same length.
+func VectorDot(a, b []float32) (float32, error) {
+	if len(a) != len(b) {
+		return 0, ErrVectorLengthMismatch
+	}
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot, nil
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors of the same length, or 0
+// if either is a zero vector. For normalized vectors, such as OpenAI embeddings, it equals the
+// dot product.
+func CosineSimilarity(a, b []float32) (float32, error) {
+	if len(a) != len(b) {
+		return 0, ErrVectorLengthMismatch
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB))), nil
+}
+
+// EuclideanDistance returns the distance between two vectors of the same length.
+func EuclideanDistance(a, b []float32) (float32, error) {
+	if len(a) != len(b) {
+		return 0, ErrVectorLengthMismatch
+	}
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(math.Sqrt(sum)), nil
+}
+
+// Normalize returns a copy of v scaled to unit length. A zero vector is returned unchanged.
+// Embeddings shortened by truncation must be normalized again before comparison.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	normalized := make([]float32, len(v))
+	copy(normalized, v)
+	if sum == 0 {
+		return normalized
+	}
+	norm := math.Sqrt(sum)
+	for i := range normalized {
+		normalized[i] = float32(float64(normalized[i]) / norm)
+	}
+	return normalized
+}
+
+// DecodeBase64Embedding decodes an embedding returned with EmbeddingEncodingFormatBase64,
+// which is a base64 string of little-endian float32 values.
+func DecodeBase64Embedding(encoded string) ([]float32, error) {
+	return base64String(encoded).Decode()
+}
+
+// Float32s converts a float64 vector to float32.
+func Float32s(v []float64) []float32 {
+	converted := make([]float32, len(v))
+	for i, x := range v {
+		converted[i] = float32(x)
+	}
+	return converted
+}
+
+// Float64s converts a float32 vector to float64.
+func Float64s(v []float32) []float64 {
+	converted := make([]float64, len(v))
+	for i, x := range v {
+		converted[i] = float64(x)
+	}
+	return converted
+}
+
+// SimilarityFunc scores two vectors; higher scores mean more similar vectors.
+type SimilarityFunc func(a, b []float32) (float32, error)
+
+// NegativeEuclideanDistance is EuclideanDistance as a SimilarityFunc.
+func NegativeEuclideanDistance(a, b []float32) (float32, error) {
+	distance, err := EuclideanDistance(a, b)
+	return -distance, err
+}
+
+// VectorMatch is a candidate vector found by NearestVectors.
+type VectorMatch struct {
+	// Index is the position of the vector in the candidates.
+	Index int
+	Score float32
+}
+
+// NearestVectors returns the k candidates most similar to query according to similarity, most
+// similar first. similarity defaults to CosineSimilarity.
+func NearestVectors(
+	query []float32,
+	candidates [][]float32,
+	k int,
+	similarity SimilarityFunc,
+) ([]VectorMatch, error) {
+	if similarity == nil {
+		similarity = CosineSimilarity
+	}
+	matches := make([]VectorMatch, 0, len(candidates))
+	for i, candidate := range candidates {
+		score, err := similarity(query, candidate)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, VectorMatch{Index: i, Score: score})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if k >= 0 && k < len(matches) {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
@@ -0,0 +1,94 @@
+package openai_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func approxEqual(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-6
+}
+
+func TestVectorSimilarity(t *testing.T) {
+	a := []float32{1, 0, 0}
+	b := []float32{1, 1, 0}
+
+	dot, err := openai.VectorDot(a, b)
+	checks.NoError(t, err, "VectorDot error")
+	if dot != 1 {
+		t.Errorf("unexpected dot product %f", dot)
+	}
+
+	cosine, err := openai.CosineSimilarity(a, b)
+	checks.NoError(t, err, "CosineSimilarity error")
+	if !approxEqual(cosine, float32(1/math.Sqrt2)) {
+		t.Errorf("unexpected cosine similarity %f", cosine)
+	}
+	cosine, err = openai.CosineSimilarity(a, []float32{0, 0, 0})
+	checks.NoError(t, err, "CosineSimilarity error")
+	if cosine != 0 {
+		t.Errorf("expected 0 for a zero vector, got %f", cosine)
+	}
+
+	distance, err := openai.EuclideanDistance(a, b)
+	checks.NoError(t, err, "EuclideanDistance error")
+	if distance != 1 {
+		t.Errorf("unexpected distance %f", distance)
+	}
+
+	for _, f := range []openai.SimilarityFunc{openai.VectorDot, openai.CosineSimilarity, openai.NegativeEuclideanDistance} {
+		_, err = f(a, []float32{1})
+		checks.ErrorIs(t, err, openai.ErrVectorLengthMismatch, "expected a length mismatch")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := []float32{3, 4}
+	normalized := openai.Normalize(v)
+	if !approxEqual(normalized[0], 0.6) || !approxEqual(normalized[1], 0.8) {
+		t.Errorf("unexpected normalized vector %v", normalized)
+	}
+	if v[0] != 3 {
+		t.Error("Normalize should not modify its argument")
+	}
+	if zero := openai.Normalize([]float32{0, 0}); !reflect.DeepEqual(zero, []float32{0, 0}) {
+		t.Errorf("unexpected normalized zero vector %v", zero)
+	}
+}
+
+func TestDecodeBase64Embedding(t *testing.T) {
+	want := []float32{0.5, -1.25}
+	raw := make([]byte, 8)
+	for i, x := range want {
+		binary.LittleEndian.PutUint32(raw[i*4:], math.Float32bits(x))
+	}
+	got, err := openai.DecodeBase64Embedding(base64.StdEncoding.EncodeToString(raw))
+	checks.NoError(t, err, "DecodeBase64Embedding error")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeBase64Embedding() = %v, want %v", got, want)
+	}
+	if got := openai.Float64s(openai.Float32s([]float64{0.5, 2})); !reflect.DeepEqual(got, []float64{0.5, 2}) {
+		t.Errorf("unexpected conversion %v", got)
+	}
+}
+
+func TestNearestVectors(t *testing.T) {
+	candidates := [][]float32{{0, 1}, {1, 0}, {1, 1}, {-1, 0}}
+	matches, err := openai.NearestVectors([]float32{1, 0.1}, candidates, 2, nil)
+	checks.NoError(t, err, "NearestVectors error")
+	if len(matches) != 2 || matches[0].Index != 1 || matches[1].Index != 2 {
+		t.Errorf("unexpected matches %+v", matches)
+	}
+
+	matches, err = openai.NearestVectors([]float32{1, 0}, candidates, -1, openai.NegativeEuclideanDistance)
+	checks.NoError(t, err, "NearestVectors error")
+	if len(matches) != 4 || matches[0].Index != 1 || matches[3].Index != 3 {
+		t.Errorf("unexpected matches %+v", matches)
+	}
+}
@@ -281,3 +281,40 @@ func TestDotProduct(t *testing.T) {
 		t.Errorf("Expected Vector Length Mismatch Error, but got: %v", err)
 	}
 }
+
+func TestEmbeddingRequestValidateDimensions(t *testing.T) {
+	tests := []struct {
+		model      openai.EmbeddingModel
+		dimensions int
+		want       error
+	}{
+		{openai.SmallEmbedding3, 0, nil},
+		{openai.SmallEmbedding3, 256, nil},
+		{openai.LargeEmbedding3, 3072, nil},
+		{openai.SmallEmbedding3, 3072, openai.ErrEmbeddingDimensionsOutOfRange},
+		{openai.LargeEmbedding3, -1, openai.ErrEmbeddingDimensionsOutOfRange},
+		{openai.AdaEmbeddingV2, 512, openai.ErrEmbeddingDimensionsNotSupported},
+		{"custom-embedding-model", 4096, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d", tt.model, tt.dimensions), func(t *testing.T) {
+			err := openai.EmbeddingRequest{Model: tt.model, Dimensions: tt.dimensions}.Validate()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	client, _, teardown := setupOpenAITestServer()
+	defer teardown()
+	_, err := client.CreateEmbeddings(context.Background(), openai.EmbeddingRequestStrings{
+		Input:      []string{"hello"},
+		Model:      openai.AdaEmbeddingV2,
+		Dimensions: 256,
+	})
+	checks.ErrorIs(t, err, openai.ErrEmbeddingDimensionsNotSupported, "CreateEmbeddings should validate dimensions")
+
+	if dimensions, ok := openai.LargeEmbedding3.Dimensions(); !ok || dimensions != 3072 {
+		t.Errorf("unexpected dimensions %d", dimensions)
+	}
+}
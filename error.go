@@ -2,8 +2,10 @@ package openai
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // APIError provides error information returned by the OpenAI API.
@@ -16,6 +18,12 @@ type APIError struct {
 	HTTPStatus     string      `json:"-"`
 	HTTPStatusCode int         `json:"-"`
 	InnerError     *InnerError `json:"innererror,omitempty"`
+	// RetryAfter is how long the server asked to wait before retrying, from the Retry-After or
+	// retry-after-ms header or, for 429 responses, the reset time of the exhausted rate limit.
+	// It is zero if unknown.
+	RetryAfter time.Duration `json:"-"`
+	// RateLimitHeaders are the x-ratelimit-* headers of the response.
+	RateLimitHeaders RateLimitHeaders `json:"-"`
 }
 
 // InnerError Azure Content filtering. Only valid for Azure OpenAI Service.
@@ -24,12 +32,23 @@ type InnerError struct {
 	ContentFilterResults ContentFilterResults `json:"content_filter_result,omitempty"`
 }
 
+// ErrNonJSONResponse is the Err of a RequestError whose body is not JSON, such as the HTML or
+// plain text error pages of proxies and gateways.
+var ErrNonJSONResponse = errors.New("response body is not JSON")
+
 // RequestError provides information about generic request errors.
 type RequestError struct {
 	HTTPStatus     string
 	HTTPStatusCode int
 	Err            error
-	Body           []byte
+	// Body is the raw response body. For streams, it is the part of the body read before the
+	// error was detected.
+	Body []byte
+	// ContentType is the Content-Type header of the response.
+	ContentType string
+	// RetryAfter and RateLimitHeaders are as for APIError.
+	RetryAfter       time.Duration
+	RateLimitHeaders RateLimitHeaders
 }
 
 type ErrorResponse struct {
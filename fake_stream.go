@@ -0,0 +1,220 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FakeStreamChunking selects how a fake stream splits the completion into chunks.
+type FakeStreamChunking string
+
+const (
+	// FakeStreamChunkWords sends a chunk per word, with the whitespace preceding it.
+	FakeStreamChunkWords FakeStreamChunking = "words"
+	// FakeStreamChunkSentences sends a chunk per sentence or line.
+	FakeStreamChunkSentences FakeStreamChunking = "sentences"
+)
+
+// FakeStreaming makes CreateChatCompletionStream request a regular chat completion and
+// synthesize a stream of chunks from it, for backends that do not support server-sent events.
+// Application code can then always use the streaming API.
+type FakeStreaming struct {
+	// Chunking splits the content of the completion, by words by default.
+	Chunking FakeStreamChunking
+	// Interval is the pause between chunks, none by default.
+	Interval time.Duration
+}
+
+// createFakeChatCompletionStream sends request as a regular chat completion and returns a
+// stream of its chunks.
+func (c *Client) createFakeChatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (*ChatCompletionStream, error) {
+	includeUsage := request.StreamOptions != nil && request.StreamOptions.IncludeUsage ||
+		request.StreamOptions == nil && c.config.StreamIncludeUsage
+	request.Stream = false
+	request.StreamOptions = nil
+	response, err := c.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := fakeStreamEvents(response, c.config.FakeStreaming.Chunking, includeUsage)
+	if err != nil {
+		return nil, err
+	}
+	body, writer := io.Pipe()
+	go writeFakeStream(ctx, writer, events, c.config.FakeStreaming.Interval)
+
+	header := response.Header().Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Type", "text/event-stream")
+	resp := &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       body,
+	}
+	config := c.config
+	config.StreamFormat = StreamFormatSSE
+	return &ChatCompletionStream{streamReader: newStreamReader[ChatCompletionStreamResponse](resp, config)}, nil
+}
+
+// writeFakeStream writes events to w as server-sent events, pausing interval between them.
+func writeFakeStream(ctx context.Context, w *io.PipeWriter, events [][]byte, interval time.Duration) {
+	var timer *time.Timer
+	if interval > 0 {
+		timer = time.NewTimer(interval)
+		defer timer.Stop()
+	}
+	for i, event := range events {
+		if i > 0 && timer != nil {
+			select {
+			case <-ctx.Done():
+				w.CloseWithError(ctx.Err())
+				return
+			case <-timer.C:
+				timer.Reset(interval)
+			}
+		}
+		if _, err := w.Write(event); err != nil {
+			return
+		}
+	}
+	w.Close()
+}
+
+// fakeStreamEvents returns the server-sent events of the chunks of response.
+func fakeStreamEvents(
+	response ChatCompletionResponse,
+	chunking FakeStreamChunking,
+	includeUsage bool,
+) ([][]byte, error) {
+	var chunks []ChatCompletionStreamResponse
+	newChunk := func(choice ChatCompletionStreamChoice) ChatCompletionStreamResponse {
+		return ChatCompletionStreamResponse{
+			ID:                response.ID,
+			Object:            "chat.completion.chunk",
+			Created:           response.Created,
+			Model:             response.Model,
+			SystemFingerprint: response.SystemFingerprint,
+			Choices:           []ChatCompletionStreamChoice{choice},
+		}
+	}
+
+	for _, choice := range response.Choices {
+		message := choice.Message
+		chunks = append(chunks, newChunk(ChatCompletionStreamChoice{
+			Index: choice.Index,
+			Delta: ChatCompletionStreamChoiceDelta{Role: message.Role, Refusal: message.Refusal},
+		}))
+		if message.ReasoningContent != "" {
+			chunks = append(chunks, newChunk(ChatCompletionStreamChoice{
+				Index: choice.Index,
+				Delta: ChatCompletionStreamChoiceDelta{ReasoningContent: message.ReasoningContent},
+			}))
+		}
+		for _, piece := range splitFakeStreamContent(message.Content, chunking) {
+			chunks = append(chunks, newChunk(ChatCompletionStreamChoice{
+				Index: choice.Index,
+				Delta: ChatCompletionStreamChoiceDelta{Content: piece},
+			}))
+		}
+		if len(message.ToolCalls) > 0 || message.FunctionCall != nil {
+			toolCalls := make([]ToolCall, len(message.ToolCalls))
+			for i, toolCall := range message.ToolCalls {
+				index := i
+				toolCall.Index = &index
+				toolCalls[i] = toolCall
+			}
+			chunks = append(chunks, newChunk(ChatCompletionStreamChoice{
+				Index: choice.Index,
+				Delta: ChatCompletionStreamChoiceDelta{ToolCalls: toolCalls, FunctionCall: message.FunctionCall},
+			}))
+		}
+		chunks = append(chunks, newChunk(ChatCompletionStreamChoice{
+			Index:                choice.Index,
+			FinishReason:         choice.FinishReason,
+			ContentFilterResults: choice.ContentFilterResults,
+		}))
+	}
+	if includeUsage {
+		usage := response.Usage
+		chunk := newChunk(ChatCompletionStreamChoice{})
+		chunk.Choices = []ChatCompletionStreamChoice{}
+		chunk.Usage = &usage
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) > 0 {
+		chunks[0].PromptFilterResults = response.PromptFilterResults
+		chunks[len(chunks)-1].Citations = response.Citations
+		chunks[len(chunks)-1].SearchResults = response.SearchResults
+	}
+
+	events := make([][]byte, 0, len(chunks)+1)
+	for _, chunk := range chunks {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, err
+		}
+		var event bytes.Buffer
+		event.Grow(len(data) + len("data: \n\n"))
+		event.WriteString("data: ")
+		event.Write(data)
+		event.WriteString("\n\n")
+		events = append(events, event.Bytes())
+	}
+	return append(events, []byte("data: [DONE]\n\n")), nil
+}
+
+// splitFakeStreamContent splits content into words, each with the whitespace preceding it, or
+// into sentences and lines, each with the whitespace following it.
+func splitFakeStreamContent(content string, chunking FakeStreamChunking) []string {
+	var pieces []string
+	start := 0
+	if chunking == FakeStreamChunkSentences {
+		for i, r := range content {
+			end := i + utf8.RuneLen(r)
+			isEnd := r == '\n' || (strings.ContainsRune(".!?", r) &&
+				(end == len(content) || content[end] == ' ' || content[end] == '\n'))
+			if !isEnd {
+				continue
+			}
+			// Keep the spaces following the sentence.
+			for end < len(content) && content[end] == ' ' {
+				end++
+			}
+			if end > start {
+				pieces = append(pieces, content[start:end])
+				start = end
+			}
+		}
+	} else {
+		inWord := false
+		for i, r := range content {
+			if unicode.IsSpace(r) {
+				if inWord {
+					pieces = append(pieces, content[start:i])
+					start = i
+				}
+				inWord = false
+			} else {
+				inWord = true
+			}
+		}
+	}
+	if start < len(content) {
+		pieces = append(pieces, content[start:])
+	}
+	return pieces
+}
@@ -0,0 +1,157 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// setupFakeStreamServer starts a client with fake streaming whose chat completion requests are
+// answered with response, and fails if a streaming request is sent.
+func setupFakeStreamServer(
+	t *testing.T,
+	fake *openai.FakeStreaming,
+	response openai.ChatCompletionResponse,
+) *openai.Client {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.FakeStreaming = fake
+	})
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if request.Stream || request.StreamOptions != nil {
+			t.Error("expected a regular completion request")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		checks.NoError(t, json.NewEncoder(w).Encode(response), "Encode error")
+	})
+	return client
+}
+
+func recvFakeStream(
+	t *testing.T,
+	client *openai.Client,
+	request openai.ChatCompletionRequest,
+) (*openai.ChatCompletionStream, []openai.ChatCompletionStreamResponse) {
+	t.Helper()
+	stream, err := client.CreateChatCompletionStream(context.Background(), request)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	t.Cleanup(func() { stream.Close() })
+	var chunks []openai.ChatCompletionStreamResponse
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			return stream, chunks
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		chunks = append(chunks, chunk)
+	}
+}
+
+func fakeStreamResponse(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		ID:    "chatcmpl-1",
+		Model: openai.GPT4o,
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+			FinishReason: openai.FinishReasonStop,
+		}},
+		Usage: openai.Usage{PromptTokens: 5, CompletionTokens: 7, TotalTokens: 12},
+	}
+}
+
+func contentPieces(chunks []openai.ChatCompletionStreamResponse) []string {
+	var pieces []string
+	for _, chunk := range chunks {
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				pieces = append(pieces, choice.Delta.Content)
+			}
+		}
+	}
+	return pieces
+}
+
+func TestFakeStreamingChunking(t *testing.T) {
+	testCases := []struct {
+		chunking openai.FakeStreamChunking
+		expected []string
+	}{
+		{openai.FakeStreamChunkWords, []string{"Hello", " world.", " How", " are", "\nyou?"}},
+		{openai.FakeStreamChunkSentences, []string{"Hello world. ", "How are\n", "you?"}},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.chunking), func(t *testing.T) {
+			client := setupFakeStreamServer(t, &openai.FakeStreaming{Chunking: tc.chunking},
+				fakeStreamResponse("Hello world. How are\nyou?"))
+			stream, chunks := recvFakeStream(t, client, openai.ChatCompletionRequest{Model: openai.GPT4o})
+
+			pieces := contentPieces(chunks)
+			if len(pieces) != len(tc.expected) {
+				t.Fatalf("expected %q, got %q", tc.expected, pieces)
+			}
+			for i := range pieces {
+				if pieces[i] != tc.expected[i] {
+					t.Errorf("expected %q, got %q", tc.expected, pieces)
+				}
+			}
+			if chunks[0].Choices[0].Delta.Role != openai.ChatMessageRoleAssistant {
+				t.Errorf("expected the role in the first chunk, got %+v", chunks[0])
+			}
+			last := chunks[len(chunks)-1].Choices[0]
+			if last.FinishReason != openai.FinishReasonStop || !stream.IsComplete() {
+				t.Errorf("expected a complete stream ending with stop, got %+v", last)
+			}
+		})
+	}
+}
+
+func TestFakeStreamingToolCallsAndUsage(t *testing.T) {
+	response := fakeStreamResponse("")
+	response.Choices[0].Message.ToolCalls = []openai.ToolCall{{
+		ID:       "call_1",
+		Type:     openai.ToolTypeFunction,
+		Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+	}}
+	response.Choices[0].FinishReason = openai.FinishReasonToolCalls
+	client := setupFakeStreamServer(t, &openai.FakeStreaming{}, response)
+
+	_, chunks := recvFakeStream(t, client, openai.ChatCompletionRequest{
+		Model:         openai.GPT4o,
+		StreamOptions: &openai.StreamOptions{IncludeUsage: true},
+	})
+	var toolCalls []openai.ToolCall
+	for _, chunk := range chunks {
+		for _, choice := range chunk.Choices {
+			toolCalls = append(toolCalls, choice.Delta.ToolCalls...)
+		}
+	}
+	if len(toolCalls) != 1 || toolCalls[0].Index == nil || *toolCalls[0].Index != 0 ||
+		toolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool calls %+v", toolCalls)
+	}
+	usage := chunks[len(chunks)-1].Usage
+	if usage == nil || usage.TotalTokens != 12 || len(chunks[len(chunks)-1].Choices) != 0 {
+		t.Errorf("expected a final usage chunk, got %+v", chunks[len(chunks)-1])
+	}
+}
+
+func TestFakeStreamingInterval(t *testing.T) {
+	client := setupFakeStreamServer(t, &openai.FakeStreaming{Interval: 5 * time.Millisecond},
+		fakeStreamResponse("one two three"))
+	start := time.Now()
+	_, chunks := recvFakeStream(t, client, openai.ChatCompletionRequest{Model: openai.GPT4o})
+	// The role, three words and the finish reason are paced, as well as the done marker.
+	if elapsed := time.Since(start); elapsed < time.Duration(len(chunks))*5*time.Millisecond {
+		t.Errorf("expected paced chunks, got %d chunks in %v", len(chunks), elapsed)
+	}
+}
@@ -0,0 +1,112 @@
+package openai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+const defaultDownloadRetries = 3
+
+// DownloadProgressFunc is called as file content is received, with the number of bytes received
+// so far and the size of the file, or -1 if the size is unknown.
+type DownloadProgressFunc func(received, total int64)
+
+// DownloadOptions configures DownloadFileContent.
+type DownloadOptions struct {
+	// Progress, if set, is called as the content is received.
+	Progress DownloadProgressFunc
+	// SHA256, if set, is the expected hex encoded SHA-256 digest of the content.
+	SHA256 string
+	// MaxRetries is the number of times an interrupted download is resumed with a Range request,
+	// 3 by default. Set it to -1 to disable retries.
+	MaxRetries int
+}
+
+func (o DownloadOptions) maxRetries() int {
+	switch {
+	case o.MaxRetries < 0:
+		return 0
+	case o.MaxRetries == 0:
+		return defaultDownloadRetries
+	}
+	return o.MaxRetries
+}
+
+// DownloadFileContent writes the content of a file, e.g. the output of a batch or the results of
+// a fine-tuning job, to w. Interrupted downloads are resumed from the last byte received, and the
+// content is checked against options.SHA256 when it is set. It returns the number of bytes
+// written.
+func (c *Client) DownloadFileContent(
+	ctx context.Context,
+	fileID string,
+	w io.Writer,
+	options DownloadOptions,
+) (written int64, err error) {
+	var digest hash.Hash
+	if options.SHA256 != "" {
+		digest = sha256.New()
+		w = io.MultiWriter(w, digest)
+	}
+
+	for attempt := 0; ; attempt++ {
+		var n int64
+		n, err = c.downloadFileContentFrom(ctx, fileID, w, written, options.Progress)
+		written += n
+		if err == nil {
+			break
+		}
+		// Only interrupted transfers are resumed; API errors are returned as is.
+		var apiErr *APIError
+		var reqErr *RequestError
+		if ctx.Err() != nil || errors.As(err, &apiErr) || errors.As(err, &reqErr) ||
+			attempt >= options.maxRetries() {
+			return written, err
+		}
+	}
+
+	if digest != nil {
+		sum := hex.EncodeToString(digest.Sum(nil))
+		if !strings.EqualFold(sum, options.SHA256) {
+			return written, fmt.Errorf("%w: got sha256 %s, expected %s", ErrChecksumMismatch, sum, options.SHA256)
+		}
+	}
+	return written, nil
+}
+
+// downloadFileContentFrom copies the content of a file from offset to w.
+func (c *Client) downloadFileContentFrom(
+	ctx context.Context,
+	fileID string,
+	w io.Writer,
+	offset int64,
+	progress DownloadProgressFunc,
+) (int64, error) {
+	stream, err := c.GetFileContentStreamFrom(ctx, fileID, offset)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	var content io.Reader = stream
+	if progress != nil {
+		content = &progressReader{
+			r:        stream,
+			sent:     offset,
+			total:    stream.Size,
+			progress: UploadProgressFunc(progress),
+		}
+	}
+	n, err := io.Copy(w, content)
+	if err == nil && stream.ContentLength >= 0 && n < stream.ContentLength {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
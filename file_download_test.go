@@ -0,0 +1,70 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestDownloadFileContent(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+	sum := sha256.Sum256([]byte(content))
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var requests int
+	server.RegisterHandler("/v1/files/output/content", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Range") == "" {
+			// Interrupt the first download halfway.
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write([]byte(content[:len(content)/2]))
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+	})
+	server.RegisterHandler("/v1/files/missing/content", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"message":"No such File object"}}`))
+	})
+
+	var buf bytes.Buffer
+	var received, total int64
+	n, err := client.DownloadFileContent(context.Background(), "output", &buf, openai.DownloadOptions{
+		SHA256:   hex.EncodeToString(sum[:]),
+		Progress: func(r, t int64) { received, total = r, t },
+	})
+	checks.NoError(t, err, "DownloadFileContent error")
+	if buf.String() != content || n != int64(len(content)) || requests != 2 {
+		t.Errorf("unexpected download of %d bytes in %d requests", n, requests)
+	}
+	if received != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("unexpected progress %d of %d", received, total)
+	}
+
+	buf.Reset()
+	_, err = client.DownloadFileContent(context.Background(), "output", &buf, openai.DownloadOptions{
+		SHA256: strings.Repeat("0", 64),
+	})
+	checks.ErrorIs(t, err, openai.ErrChecksumMismatch, "wrong checksum should fail")
+
+	buf.Reset()
+	_, err = client.DownloadFileContent(context.Background(), "output", &buf, openai.DownloadOptions{MaxRetries: -1})
+	checks.HasError(t, err, "interrupted download should fail without retries")
+
+	requests = 0
+	_, err = client.DownloadFileContent(context.Background(), "missing", &buf, openai.DownloadOptions{})
+	checks.HasError(t, err, "missing file should fail")
+	if requests != 1 {
+		t.Errorf("API errors should not be retried, got %d requests", requests)
+	}
+}
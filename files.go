@@ -3,9 +3,13 @@ package openai
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type FileRequest struct {
@@ -23,8 +27,34 @@ const (
 	PurposeAssistants       PurposeType = "assistants"
 	PurposeAssistantsOutput PurposeType = "assistants_output"
 	PurposeBatch            PurposeType = "batch"
+	PurposeBatchOutput      PurposeType = "batch_output"
+	PurposeVision           PurposeType = "vision"
+	PurposeUserData         PurposeType = "user_data"
+	PurposeEvals            PurposeType = "evals"
 )
 
+var ErrInvalidFilePurpose = errors.New("invalid file purpose")
+
+// uploadPurposes are the purposes that files can be uploaded with; the others are only set
+// on files created by the API.
+var uploadPurposes = map[PurposeType]bool{
+	PurposeFineTune:   true,
+	PurposeAssistants: true,
+	PurposeBatch:      true,
+	PurposeVision:     true,
+	PurposeUserData:   true,
+	PurposeEvals:      true,
+}
+
+// Validate reports ErrInvalidFilePurpose if files cannot be uploaded with the purpose. An
+// empty purpose is left to the API to reject.
+func (p PurposeType) Validate() error {
+	if p != "" && !uploadPurposes[p] {
+		return fmt.Errorf("%w: %q", ErrInvalidFilePurpose, p)
+	}
+	return nil
+}
+
 // FileBytesRequest represents a file upload request.
 type FileBytesRequest struct {
 	// the name of the uploaded file in OpenAI
@@ -58,6 +88,10 @@ type FilesList struct {
 
 // CreateFileBytes uploads bytes directly to OpenAI without requiring a local file.
 func (c *Client) CreateFileBytes(ctx context.Context, request FileBytesRequest) (file File, err error) {
+	if err = request.Purpose.Validate(); err != nil {
+		return
+	}
+
 	var b bytes.Buffer
 	reader := bytes.NewReader(request.Bytes)
 	builder := c.createFormBuilder(&b)
@@ -90,6 +124,10 @@ func (c *Client) CreateFileBytes(ctx context.Context, request FileBytesRequest)
 // CreateFile uploads a jsonl file to GPT3
 // FilePath must be a local file path.
 func (c *Client) CreateFile(ctx context.Context, request FileRequest) (file File, err error) {
+	if err = PurposeType(request.Purpose).Validate(); err != nil {
+		return
+	}
+
 	var b bytes.Buffer
 	builder := c.createFormBuilder(&b)
 
@@ -169,3 +207,94 @@ func (c *Client) GetFileContent(ctx context.Context, fileID string) (content Raw
 
 	return c.sendRequestRaw(req)
 }
+
+// FileContentStream is the content of a file, streamed from Offset.
+type FileContentStream struct {
+	io.ReadCloser
+	// Offset is the position in the file of the first byte of the stream.
+	Offset int64
+	// ContentLength is the number of bytes of the stream, or -1 if unknown.
+	ContentLength int64
+	// Size is the size of the whole file, or -1 if unknown.
+	Size int64
+
+	httpHeader
+}
+
+// GetFileContentStream streams the content of a file without buffering it.
+func (c *Client) GetFileContentStream(ctx context.Context, fileID string) (*FileContentStream, error) {
+	return c.GetFileContentStreamFrom(ctx, fileID, 0)
+}
+
+// GetFileContentStreamFrom streams the content of a file from offset, e.g. to resume an
+// interrupted download. A Range request is sent; if the server ignores it, the bytes before
+// offset are skipped.
+func (c *Client) GetFileContentStreamFrom(
+	ctx context.Context,
+	fileID string,
+	offset int64,
+) (stream *FileContentStream, err error) {
+	urlSuffix := fmt.Sprintf("/files/%s/content", fileID)
+	setters := []requestOption{}
+	if offset > 0 {
+		setters = append(setters, withRange(offset))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix), setters...)
+	if err != nil {
+		return
+	}
+
+	content, err := c.sendRequestRaw(req)
+	if err != nil {
+		return
+	}
+
+	stream = &FileContentStream{
+		ReadCloser:    content.ReadCloser,
+		Offset:        offset,
+		ContentLength: parseContentLength(content.Header().Get("Content-Length")),
+		Size:          -1,
+		httpHeader:    content.httpHeader,
+	}
+	contentRange := content.Header().Get("Content-Range")
+	switch {
+	case contentRange != "":
+		stream.Size = parseContentRangeSize(contentRange)
+	case offset > 0:
+		// The server sent the whole file.
+		stream.Size = stream.ContentLength
+		if _, err = io.CopyN(io.Discard, stream, offset); err != nil {
+			stream.Close()
+			return nil, err
+		}
+		if stream.ContentLength >= 0 {
+			stream.ContentLength -= offset
+		}
+	default:
+		stream.Size = stream.ContentLength
+	}
+	return stream, nil
+}
+
+func withRange(offset int64) requestOption {
+	return func(args *requestOptions) {
+		args.header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+}
+
+func parseContentLength(value string) int64 {
+	length, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return length
+}
+
+// parseContentRangeSize returns the complete length of a "bytes first-last/size" Content-Range.
+func parseContentRangeSize(contentRange string) int64 {
+	i := strings.LastIndexByte(contentRange, '/')
+	if i < 0 {
+		return -1
+	}
+	return parseContentLength(contentRange[i+1:])
+}
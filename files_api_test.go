@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -194,3 +195,53 @@ func TestGetFileContentReturnTimeoutError(t *testing.T) {
 		t.Fatal("Did not return timeout error")
 	}
 }
+
+func TestFileUploadInvalidPurpose(t *testing.T) {
+	client, _, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	_, err := client.CreateFileBytes(context.Background(), openai.FileBytesRequest{
+		Name:    "foo",
+		Bytes:   []byte("foo"),
+		Purpose: openai.PurposeBatchOutput,
+	})
+	checks.ErrorIs(t, err, openai.ErrInvalidFilePurpose, "CreateFileBytes should reject output purposes")
+
+	_, err = client.CreateFile(context.Background(), openai.FileRequest{FilePath: "foo.jsonl", Purpose: "fine_tune"})
+	checks.ErrorIs(t, err, openai.ErrInvalidFilePurpose, "CreateFile should reject unknown purposes")
+}
+
+func TestGetFileContentStream(t *testing.T) {
+	const content = "0123456789"
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/files/deadbeef/content", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(content))
+	})
+	server.RegisterHandler("/v1/files/norange/content", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		fmt.Fprint(w, content)
+	})
+
+	for _, fileID := range []string{"deadbeef", "norange"} {
+		t.Run(fileID, func(t *testing.T) {
+			stream, err := client.GetFileContentStream(context.Background(), fileID)
+			checks.NoError(t, err, "GetFileContentStream error")
+			data, err := io.ReadAll(stream)
+			checks.NoError(t, err, "read error")
+			stream.Close()
+			if string(data) != content || stream.ContentLength != 10 || stream.Size != 10 {
+				t.Errorf("unexpected stream %q, length %d, size %d", data, stream.ContentLength, stream.Size)
+			}
+
+			stream, err = client.GetFileContentStreamFrom(context.Background(), fileID, 4)
+			checks.NoError(t, err, "GetFileContentStreamFrom error")
+			defer stream.Close()
+			data, err = io.ReadAll(stream)
+			checks.NoError(t, err, "read error")
+			if string(data) != content[4:] || stream.ContentLength != 6 || stream.Size != 10 || stream.Offset != 4 {
+				t.Errorf("unexpected resumed stream %q, length %d, size %d", data, stream.ContentLength, stream.Size)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// providerFinishReasons maps the finish reasons of other providers and inference servers,
+// lowercased, to their OpenAI equivalent.
+var providerFinishReasons = map[string]FinishReason{
+	// Anthropic and Bedrock.
+	"end_turn":             FinishReasonStop,
+	"stop_sequence":        FinishReasonStop,
+	"pause_turn":           FinishReasonStop,
+	"max_tokens":           FinishReasonLength,
+	"tool_use":             FinishReasonToolCalls,
+	"refusal":              FinishReasonContentFilter,
+	"guardrail_intervened": FinishReasonContentFilter,
+	"content_filtered":     FinishReasonContentFilter,
+	// Gemini.
+	"safety":             FinishReasonContentFilter,
+	"recitation":         FinishReasonContentFilter,
+	"blocklist":          FinishReasonContentFilter,
+	"prohibited_content": FinishReasonContentFilter,
+	"spii":               FinishReasonContentFilter,
+	// Cohere.
+	"complete":    FinishReasonStop,
+	"tool_call":   FinishReasonToolCalls,
+	"error_toxic": FinishReasonContentFilter,
+	// SGLang, TGI and Mistral.
+	"matched_stop": FinishReasonStop,
+	"eos_token":    FinishReasonStop,
+	"eos":          FinishReasonStop,
+	"model_length": FinishReasonLength,
+}
+
+// Normalized returns the OpenAI finish reason equivalent to the finish reason of another
+// provider, so a switch on FinishReasonStop, FinishReasonLength, FinishReasonToolCalls,
+// FinishReasonFunctionCall and FinishReasonContentFilter works whatever the provider: e.g.
+// Anthropic's end_turn is FinishReasonStop and its max_tokens is FinishReasonLength. The
+// finish reason itself keeps the raw value of the provider.
+//
+// FinishReasonNull is normalized to "". Unknown finish reasons are returned unchanged.
+func (r FinishReason) Normalized() FinishReason {
+	lower := FinishReason(strings.ToLower(string(r)))
+	switch lower {
+	case FinishReasonStop, FinishReasonLength, FinishReasonFunctionCall, FinishReasonToolCalls,
+		FinishReasonContentFilter:
+		return lower
+	case FinishReasonNull:
+		return ""
+	}
+	if normalized, ok := providerFinishReasons[string(lower)]; ok {
+		return normalized
+	}
+	return r
+}
+
+// UnmarshalJSON decodes a finish reason string, or the type of a finish reason object such as
+// the {"type":"stop","matched":...} of SGLang.
+func (r *FinishReason) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		var reason *string
+		if err := json.Unmarshal(data, &reason); err != nil {
+			return err
+		}
+		*r = ""
+		if reason != nil {
+			*r = FinishReason(*reason)
+		}
+		return nil
+	}
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*r = FinishReason(object.Type)
+	return nil
+}
@@ -0,0 +1,57 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestFinishReasonNormalized(t *testing.T) {
+	testCases := []struct {
+		raw  openai.FinishReason
+		want openai.FinishReason
+	}{
+		{"stop", openai.FinishReasonStop},
+		{"tool_calls", openai.FinishReasonToolCalls},
+		{"end_turn", openai.FinishReasonStop},
+		{"max_tokens", openai.FinishReasonLength},
+		{"tool_use", openai.FinishReasonToolCalls},
+		{"refusal", openai.FinishReasonContentFilter},
+		{"matched_stop", openai.FinishReasonStop},
+		{"MAX_TOKENS", openai.FinishReasonLength},
+		{"SAFETY", openai.FinishReasonContentFilter},
+		{"null", ""},
+		{"", ""},
+		{"abort", "abort"},
+	}
+	for _, tc := range testCases {
+		if got := tc.raw.Normalized(); got != tc.want {
+			t.Errorf("%q.Normalized() = %q, want %q", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestFinishReasonUnmarshal(t *testing.T) {
+	var choices []openai.ChatCompletionChoice
+	err := json.Unmarshal([]byte(`[
+		{"finish_reason":"end_turn"},
+		{"finish_reason":null},
+		{"finish_reason":{"type":"stop","matched":151645}}
+	]`), &choices)
+	checks.NoError(t, err, "Unmarshal error")
+
+	if choices[0].FinishReason != "end_turn" || choices[0].FinishReason.Normalized() != openai.FinishReasonStop {
+		t.Errorf("expected the raw finish reason to be kept, got %q", choices[0].FinishReason)
+	}
+	if choices[1].FinishReason != "" {
+		t.Errorf("expected an empty finish reason for null, got %q", choices[1].FinishReason)
+	}
+	if choices[2].FinishReason != openai.FinishReasonStop {
+		t.Errorf("expected the type of the finish reason object, got %q", choices[2].FinishReason)
+	}
+
+	var reason openai.FinishReason
+	checks.HasError(t, json.Unmarshal([]byte(`42`), &reason), "expected an error for a number")
+}
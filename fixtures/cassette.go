@@ -0,0 +1,332 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrNoInteraction is returned by a replaying cassette for a request it has not recorded.
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// CassetteModeEnv is the environment variable overriding the mode of cassettes, e.g.
+// OPENAI_CASSETTE=record go test ./... to record them again.
+const CassetteModeEnv = "OPENAI_CASSETTE"
+
+// CassetteMode selects whether a cassette records or replays interactions.
+type CassetteMode string
+
+const (
+	// CassetteAuto replays the cassette file if it exists and records it otherwise.
+	CassetteAuto CassetteMode = "auto"
+	// CassetteRecord sends the requests and records the interactions.
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay replays recorded interactions without sending any request.
+	CassetteReplay CassetteMode = "replay"
+)
+
+// CassetteOptions configures a cassette.
+type CassetteOptions struct {
+	// Mode is CassetteAuto by default. The CassetteModeEnv environment variable overrides it.
+	Mode CassetteMode
+	// Sanitizer strips the secrets of the recorded interactions, DefaultSanitizer by default.
+	Sanitizer *Sanitizer
+	// RealTime replays streams with the recorded delays between their events, instead of
+	// delivering them at once.
+	RealTime bool
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	// RequestBody is the sanitized request body, which replayed requests must match.
+	RequestBody json.RawMessage `json:"request_body,omitempty"`
+	// Response is the recorded response. The body of streams is in Events instead.
+	Response Fixture `json:"response"`
+	// Events are the server-sent events of a streamed response.
+	Events []StreamEvent `json:"events,omitempty"`
+}
+
+// StreamEvent is a server-sent event of a recorded stream.
+type StreamEvent struct {
+	// Delay is the time since the previous event or, for the first one, since the request.
+	Delay time.Duration `json:"delay"`
+	// Data is the raw event, including its field names and the blank line ending it.
+	Data string `json:"data"`
+}
+
+// Cassette is an openai.HTTPDoer recording interactions with the API to a file and replaying
+// them in later runs, for tests that exercise real responses, streams included, without network
+// access. Call Save once the recorded responses have been read and closed:
+//
+//	cassette, err := fixtures.NewCassette("testdata/chat.json", http.DefaultClient, fixtures.CassetteOptions{})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	t.Cleanup(func() { _ = cassette.Save() })
+//	config.HTTPClient = cassette
+type Cassette struct {
+	path      string
+	mode      CassetteMode
+	doer      openai.HTTPDoer
+	sanitizer *Sanitizer
+	realTime  bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewCassette returns a cassette stored at path, sending requests with doer when recording.
+func NewCassette(path string, doer openai.HTTPDoer, opts CassetteOptions) (*Cassette, error) {
+	c := &Cassette{path: path, mode: opts.Mode, doer: doer, sanitizer: opts.Sanitizer, realTime: opts.RealTime}
+	if mode := os.Getenv(CassetteModeEnv); mode != "" {
+		c.mode = CassetteMode(mode)
+	}
+	if c.sanitizer == nil {
+		c.sanitizer = DefaultSanitizer()
+	}
+
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	err := ReadGolden(path, &file)
+	switch {
+	case c.mode == "" || c.mode == CassetteAuto:
+		c.mode = CassetteReplay
+		if errors.Is(err, os.ErrNotExist) {
+			c.mode, err = CassetteRecord, nil
+		}
+	case c.mode == CassetteRecord:
+		err = nil
+	case c.mode != CassetteReplay:
+		return nil, fmt.Errorf("unknown cassette mode %q", c.mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.mode == CassetteReplay {
+		// The bodies are indented in the file and compacted in requests.
+		for i, interaction := range file.Interactions {
+			if len(interaction.RequestBody) > 0 {
+				file.Interactions[i].RequestBody = rawJSON(interaction.RequestBody)
+			}
+		}
+		c.interactions = file.Interactions
+		c.replayed = make([]bool, len(file.Interactions))
+	}
+	return c, nil
+}
+
+// Mode returns whether the cassette records or replays.
+func (c *Cassette) Mode() CassetteMode {
+	return c.mode
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return WriteGolden(c.path, struct {
+		Interactions []Interaction `json:"interactions"`
+	}{c.interactions})
+}
+
+func (c *Cassette) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery}
+	if len(body) > 0 {
+		interaction.RequestBody = rawJSON(c.sanitizer.SanitizeBody(body))
+	}
+	if c.mode == CassetteReplay {
+		return c.replay(req, interaction)
+	}
+	return c.record(req, interaction)
+}
+
+func (c *Cassette) record(req *http.Request, interaction Interaction) (*http.Response, error) {
+	sentAt := time.Now()
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	if !isEventStream(resp.Header) {
+		if interaction.Response, err = NewFixture(resp, c.sanitizer); err != nil {
+			return nil, err
+		}
+		c.add(interaction)
+		return resp, nil
+	}
+
+	interaction.Response = Fixture{
+		Method: interaction.Method,
+		Path:   interaction.Path,
+		Status: resp.StatusCode,
+		Header: c.sanitizer.SanitizeHeader(resp.Header),
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, last: sentAt, done: func(events []StreamEvent) {
+		for i := range events {
+			events[i].Data = string(c.sanitizer.SanitizeBody([]byte(events[i].Data)))
+		}
+		interaction.Events = events
+		c.add(interaction)
+	}}
+	return resp, nil
+}
+
+func (c *Cassette) add(interaction Interaction) {
+	c.mu.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.mu.Unlock()
+}
+
+// replay returns the response of the first interaction not yet replayed that matches the
+// request.
+func (c *Cassette) replay(req *http.Request, request Interaction) (*http.Response, error) {
+	c.mu.Lock()
+	var interaction *Interaction
+	for i := range c.interactions {
+		if !c.replayed[i] && matches(c.interactions[i], request) {
+			c.replayed[i] = true
+			interaction = &c.interactions[i]
+			break
+		}
+	}
+	c.mu.Unlock()
+	if interaction == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, request.Method, request.Path)
+	}
+
+	resp := &http.Response{
+		StatusCode: interaction.Response.Status,
+		Status:     fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		Header:     interaction.Response.Header.Clone(),
+		Request:    req,
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	if interaction.Events == nil {
+		resp.Body = io.NopCloser(bytes.NewReader(interaction.Response.BodyBytes()))
+		return resp, nil
+	}
+	body, writer := io.Pipe()
+	go c.replayEvents(req, writer, interaction.Events)
+	resp.Body = body
+	return resp, nil
+}
+
+func (c *Cassette) replayEvents(req *http.Request, w *io.PipeWriter, events []StreamEvent) {
+	for _, event := range events {
+		if c.realTime && event.Delay > 0 {
+			timer := time.NewTimer(event.Delay)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				w.CloseWithError(req.Context().Err())
+				return
+			case <-timer.C:
+			}
+		}
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return
+		}
+	}
+	w.Close()
+}
+
+func matches(recorded, request Interaction) bool {
+	return recorded.Method == request.Method && recorded.Path == request.Path &&
+		recorded.Query == request.Query && bytes.Equal(recorded.RequestBody, request.RequestBody)
+}
+
+// readRequestBody returns the body of req and replaces it so that it can be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// rawJSON returns body compacted if it is JSON, and as a JSON string otherwise.
+func rawJSON(body []byte) json.RawMessage {
+	var compacted bytes.Buffer
+	if json.Compact(&compacted, body) == nil {
+		return compacted.Bytes()
+	}
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+
+func isEventStream(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+// recordingBody splits the stream read through it into events with their delays, and passes
+// them to done once the stream ended or was closed.
+type recordingBody struct {
+	io.ReadCloser
+	last    time.Time
+	pending []byte
+	events  []StreamEvent
+	done    func([]StreamEvent)
+	once    sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		now := time.Now()
+		b.pending = append(b.pending, p[:n]...)
+		for {
+			end := bytes.Index(b.pending, []byte("\n\n"))
+			if end < 0 {
+				break
+			}
+			b.events = append(b.events, StreamEvent{Delay: now.Sub(b.last), Data: string(b.pending[:end+2])})
+			b.pending = b.pending[end+2:]
+			b.last = now
+		}
+	}
+	if err != nil {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) finish() {
+	b.once.Do(func() {
+		if len(b.pending) > 0 {
+			b.events = append(b.events, StreamEvent{Delay: time.Since(b.last), Data: string(b.pending)})
+		}
+		b.done(b.events)
+	})
+}
@@ -0,0 +1,145 @@
+package fixtures_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/fixtures"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const cassetteStreamEventDelay = 20 * time.Millisecond
+
+// newCassetteUpstream starts a server answering model requests and streaming chat completions.
+func newCassetteUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/models" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"gpt-4o"}],"api_key":"`+secretKey+`"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, content := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", content)
+			w.(http.Flusher).Flush()
+			time.Sleep(cassetteStreamEventDelay)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func cassetteClient(baseURL string, cassette *fixtures.Cassette) *openai.Client {
+	config := openai.DefaultConfig(secretKey)
+	config.BaseURL = baseURL + "/v1"
+	config.HTTPClient = cassette
+	return openai.NewClientWithConfig(config)
+}
+
+// exerciseCassette lists the models and streams a chat completion, returning the streamed content.
+func exerciseCassette(t *testing.T, client *openai.Client) string {
+	t.Helper()
+	models, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+	if len(models.Models) != 1 || models.Models[0].ID != "gpt-4o" {
+		t.Errorf("unexpected models %+v", models.Models)
+	}
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream error: %v", err)
+	}
+	defer stream.Close()
+	var content strings.Builder
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			return content.String()
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+}
+
+// failingDoer fails the test if a replaying cassette sends a request.
+type failingDoer struct{ t *testing.T }
+
+func (d failingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.t.Errorf("unexpected request to %s", req.URL)
+	return nil, errors.New("network disabled")
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	upstream := newCassetteUpstream(t)
+
+	recorder, err := fixtures.NewCassette(path, http.DefaultClient, fixtures.CassetteOptions{})
+	checks.NoError(t, err, "NewCassette error")
+	if recorder.Mode() != fixtures.CassetteRecord {
+		t.Fatalf("expected a missing cassette to record, got %s", recorder.Mode())
+	}
+	if content := exerciseCassette(t, cassetteClient(upstream.URL, recorder)); content != "Hello" {
+		t.Errorf("unexpected recorded content %q", content)
+	}
+	checks.NoError(t, recorder.Save(), "Save error")
+
+	data, err := os.ReadFile(path)
+	checks.NoError(t, err, "ReadFile error")
+	if strings.Contains(string(data), secretKey) {
+		t.Error("expected the cassette to be redacted")
+	}
+
+	player, err := fixtures.NewCassette(path, failingDoer{t}, fixtures.CassetteOptions{RealTime: true})
+	checks.NoError(t, err, "NewCassette error")
+	if player.Mode() != fixtures.CassetteReplay {
+		t.Fatalf("expected an existing cassette to replay, got %s", player.Mode())
+	}
+	start := time.Now()
+	if content := exerciseCassette(t, cassetteClient("http://cassette.invalid", player)); content != "Hello" {
+		t.Errorf("unexpected replayed content %q", content)
+	}
+	if elapsed := time.Since(start); elapsed < cassetteStreamEventDelay {
+		t.Errorf("expected the stream to be replayed in real time, took %v", elapsed)
+	}
+}
+
+func TestCassetteReplayUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	checks.NoError(t, fixtures.WriteGolden(path, map[string]any{"interactions": []any{}}), "WriteGolden error")
+
+	player, err := fixtures.NewCassette(path, failingDoer{t}, fixtures.CassetteOptions{Mode: fixtures.CassetteReplay})
+	checks.NoError(t, err, "NewCassette error")
+	_, err = cassetteClient("http://cassette.invalid", player).ListModels(context.Background())
+	if !errors.Is(err, fixtures.ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestCassetteModeEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv(fixtures.CassetteModeEnv, string(fixtures.CassetteReplay))
+	_, err := fixtures.NewCassette(path, http.DefaultClient, fixtures.CassetteOptions{Mode: fixtures.CassetteRecord})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the environment to force replaying the missing cassette, got %v", err)
+	}
+
+	t.Setenv(fixtures.CassetteModeEnv, "rewind")
+	if _, err = fixtures.NewCassette(path, http.DefaultClient, fixtures.CassetteOptions{}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,258 @@
+// Package fixtures records API responses into golden files with their secrets stripped and
+// generates stream fixtures from complete responses, for reproducible tests of applications
+// using go-openai without calling the API.
+//
+// Record real responses once by wrapping the HTTP client of a Client:
+//
+//	recorder := fixtures.NewRecorder(http.DefaultClient, fixtures.DefaultSanitizer())
+//	config := openai.DefaultConfig(token)
+//	config.HTTPClient = recorder
+//	// ... make calls, then
+//	err := fixtures.WriteGolden("testdata/chat.json", recorder.Fixtures())
+//
+// and serve them in tests with Handler:
+//
+//	var recorded []fixtures.Fixture
+//	err := fixtures.ReadGolden("testdata/chat.json", &recorded)
+//	server := httptest.NewServer(fixtures.Handler(recorded...))
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Redacted replaces the secrets stripped by a Sanitizer.
+const Redacted = "REDACTED"
+
+// Fixture is a recorded response to a request.
+type Fixture struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	// Body is the response body. JSON bodies are kept as JSON in golden files, other bodies such
+	// as streams as strings.
+	Body json.RawMessage `json:"body"`
+}
+
+// BodyBytes returns the response body as sent by the server.
+func (f Fixture) BodyBytes() []byte {
+	var text string
+	if len(f.Body) > 0 && f.Body[0] == '"' && json.Unmarshal(f.Body, &text) == nil {
+		return []byte(text)
+	}
+	return f.Body
+}
+
+// Sanitizer strips secrets and volatile values from recorded responses.
+type Sanitizer struct {
+	// Headers are removed from the responses. The names are case-insensitive.
+	Headers []string
+	// JSONKeys are the keys of JSON objects whose values are replaced by Redacted.
+	JSONKeys []string
+	// Patterns are replaced by Redacted in the bodies.
+	Patterns []*regexp.Regexp
+}
+
+// DefaultSanitizer removes cookies, organization, project and request identifiers headers, and
+// redacts API keys and the values of secret JSON keys.
+func DefaultSanitizer() *Sanitizer {
+	return &Sanitizer{
+		Headers: []string{
+			"Authorization", "Api-Key", "Set-Cookie", "Cookie", "Openai-Organization", "Openai-Project",
+			"X-Request-Id", "Cf-Ray", "Date",
+		},
+		JSONKeys: []string{"api_key", "client_secret", "secret", "password", "access_token", "refresh_token"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`sk-[A-Za-z0-9_-]{16,}`)},
+	}
+}
+
+// SanitizeHeader returns a copy of header without the headers of the sanitizer.
+func (s *Sanitizer) SanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	for _, name := range s.Headers {
+		sanitized.Del(name)
+	}
+	return sanitized
+}
+
+// SanitizeBody returns body with its secrets redacted. The values of JSONKeys are redacted in
+// JSON bodies, and in each data line of server-sent event streams.
+func (s *Sanitizer) SanitizeBody(body []byte) []byte {
+	if json.Valid(body) {
+		body = s.sanitizeJSON(body)
+	} else if bytes.Contains(body, []byte("data:")) {
+		lines := bytes.Split(body, []byte("\n"))
+		for i, line := range lines {
+			if data := bytes.TrimPrefix(line, []byte("data: ")); len(data) < len(line) && json.Valid(data) {
+				lines[i] = append([]byte("data: "), s.sanitizeJSON(data)...)
+			}
+		}
+		body = bytes.Join(lines, []byte("\n"))
+	}
+	for _, pattern := range s.Patterns {
+		body = pattern.ReplaceAll(body, []byte(Redacted))
+	}
+	return body
+}
+
+func (s *Sanitizer) sanitizeJSON(data []byte) []byte {
+	if len(s.JSONKeys) == 0 {
+		return data
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if decoder.Decode(&value) != nil || !s.redactKeys(value) {
+		return data
+	}
+	sanitized, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return sanitized
+}
+
+// redactKeys redacts the values of JSONKeys in value and reports whether it changed.
+func (s *Sanitizer) redactKeys(value any) bool {
+	changed := false
+	switch value := value.(type) {
+	case map[string]any:
+		for key, member := range value {
+			if s.isSecretKey(key) {
+				if member != Redacted {
+					value[key] = Redacted
+					changed = true
+				}
+				continue
+			}
+			changed = s.redactKeys(member) || changed
+		}
+	case []any:
+		for _, element := range value {
+			changed = s.redactKeys(element) || changed
+		}
+	}
+	return changed
+}
+
+func (s *Sanitizer) isSecretKey(key string) bool {
+	for _, secret := range s.JSONKeys {
+		if strings.EqualFold(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewFixture reads resp into a fixture sanitized by sanitizer, which may be nil. The body of
+// resp is replaced so that it can still be read.
+func NewFixture(resp *http.Response, sanitizer *Sanitizer) (Fixture, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return Fixture{}, err
+	}
+
+	fixture := Fixture{Status: resp.StatusCode, Header: resp.Header.Clone()}
+	if resp.Request != nil {
+		fixture.Method = resp.Request.Method
+		fixture.Path = resp.Request.URL.Path
+	}
+	if sanitizer != nil {
+		fixture.Header = sanitizer.SanitizeHeader(resp.Header)
+		body = sanitizer.SanitizeBody(body)
+	}
+	fixture.Header.Del("Content-Length")
+	if json.Valid(body) {
+		fixture.Body = append(json.RawMessage(nil), body...)
+	} else if fixture.Body, err = json.Marshal(string(body)); err != nil {
+		return Fixture{}, err
+	}
+	return fixture, nil
+}
+
+// Recorder is an openai.HTTPDoer recording the responses of another one as fixtures. It reads
+// each response body entirely before returning it, so streams are only delivered once complete.
+type Recorder struct {
+	doer      openai.HTTPDoer
+	sanitizer *Sanitizer
+
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns a recorder of the responses of doer, sanitized by sanitizer.
+func NewRecorder(doer openai.HTTPDoer, sanitizer *Sanitizer) *Recorder {
+	return &Recorder{doer: doer, sanitizer: sanitizer}
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	fixture, err := NewFixture(resp, r.sanitizer)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.fixtures = append(r.fixtures, fixture)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Fixtures returns the fixtures recorded so far, in the order of the responses.
+func (r *Recorder) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Fixture(nil), r.fixtures...)
+}
+
+// Handler serves fixtures by method and path. Requests matching several fixtures get them in
+// turn, the last one being repeated, and requests matching none get a 404 API error.
+func Handler(fixtures ...Fixture) http.Handler {
+	var mu sync.Mutex
+	served := make(map[string]int)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var matching []Fixture
+		for _, fixture := range fixtures {
+			if fixture.Method == r.Method && fixture.Path == r.URL.Path {
+				matching = append(matching, fixture)
+			}
+		}
+		if len(matching) == 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":{"message":"no fixture for `+r.Method+` `+r.URL.Path+
+				`","type":"invalid_request_error"}}`)
+			return
+		}
+
+		key := r.Method + " " + r.URL.Path
+		mu.Lock()
+		fixture := matching[served[key]]
+		if served[key] < len(matching)-1 {
+			served[key]++
+		}
+		mu.Unlock()
+		for name, values := range fixture.Header {
+			w.Header()[name] = values
+		}
+		status := fixture.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(fixture.BodyBytes())
+	})
+}
@@ -0,0 +1,93 @@
+package fixtures_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/fixtures"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const secretKey = "sk-proj-abcdefghijklmnopqrstuvwxyz"
+
+func TestRecorderSanitizes(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Openai-Organization", "org-secret")
+		w.Header().Set("Set-Cookie", "session=1")
+		_, _ = io.WriteString(w, `{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":"Your key is `+secretKey+`"},`+
+			`"finish_reason":"stop"}],"client_secret":{"value":"ek_1"},"usage":{"total_tokens":3}}`)
+	}))
+	defer upstream.Close()
+
+	recorder := fixtures.NewRecorder(http.DefaultClient, fixtures.DefaultSanitizer())
+	config := openai.DefaultConfig(secretKey)
+	config.BaseURL = upstream.URL + "/v1"
+	config.HTTPClient = recorder
+	client := openai.NewClientWithConfig(config)
+	response, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if !strings.Contains(response.Choices[0].Message.Content, secretKey) {
+		t.Error("expected the client to receive the unsanitized response")
+	}
+
+	recorded := recorder.Fixtures()
+	if len(recorded) != 1 {
+		t.Fatalf("expected 1 fixture, got %d", len(recorded))
+	}
+	fixture := recorded[0]
+	if fixture.Method != http.MethodPost || fixture.Path != "/v1/chat/completions" || fixture.Status != http.StatusOK {
+		t.Errorf("unexpected fixture %s %s %d", fixture.Method, fixture.Path, fixture.Status)
+	}
+	if fixture.Header.Get("Openai-Organization") != "" || fixture.Header.Get("Set-Cookie") != "" {
+		t.Errorf("expected sensitive headers to be removed, got %v", fixture.Header)
+	}
+	body := string(fixture.BodyBytes())
+	if strings.Contains(body, secretKey) || strings.Contains(body, "ek_1") {
+		t.Errorf("expected secrets to be redacted, got %s", body)
+	}
+	if !strings.Contains(body, `"total_tokens":3`) {
+		t.Errorf("expected token counts to be kept, got %s", body)
+	}
+}
+
+func TestSanitizeStreamBody(t *testing.T) {
+	body := "data: {\"api_key\":\"abc\",\"n\":1}\n\ndata: [DONE]\n\n"
+	sanitized := string(fixtures.DefaultSanitizer().SanitizeBody([]byte(body)))
+	if sanitized != "data: {\"api_key\":\"REDACTED\",\"n\":1}\n\ndata: [DONE]\n\n" {
+		t.Errorf("unexpected sanitized body %q", sanitized)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(fixtures.Handler(
+		fixtures.Fixture{Method: http.MethodGet, Path: "/v1/models", Status: http.StatusOK,
+			Body: []byte(`{"object":"list","data":[{"id":"gpt-4o"}]}`)},
+		fixtures.Fixture{Method: http.MethodGet, Path: "/v1/models", Status: http.StatusOK,
+			Body: []byte(`{"object":"list","data":[{"id":"o3"}]}`)},
+	))
+	defer server.Close()
+	config := openai.DefaultConfig("token")
+	config.BaseURL = server.URL + "/v1"
+	client := openai.NewClientWithConfig(config)
+
+	for _, expected := range []string{"gpt-4o", "o3", "o3"} {
+		models, err := client.ListModels(context.Background())
+		checks.NoError(t, err, "ListModels error")
+		if len(models.Models) != 1 || models.Models[0].ID != expected {
+			t.Errorf("expected model %s, got %+v", expected, models.Models)
+		}
+	}
+
+	_, err := client.GetModel(context.Background(), "missing")
+	checks.HasError(t, err, "expected an error without fixture")
+}
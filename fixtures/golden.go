@@ -0,0 +1,72 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateEnv is the environment variable making AssertGolden rewrite the golden files instead
+// of comparing them, e.g. UPDATE_GOLDEN=1 go test ./...
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// MarshalGolden encodes v as indented JSON with a final line break, so that golden files are
+// stable and readable in diffs.
+func MarshalGolden(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteGolden writes v to the golden file path, creating its directory.
+func WriteGolden(path string, v any) error {
+	data, err := MarshalGolden(v)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ReadGolden decodes the golden file path into v.
+func ReadGolden(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// AssertGolden fails tb if got differs from the golden file path. If the UpdateEnv environment
+// variable is set, it writes got to the file instead.
+func AssertGolden(tb testing.TB, path string, got any) {
+	tb.Helper()
+	data, err := MarshalGolden(got)
+	if err != nil {
+		tb.Fatalf("encoding golden value: %v", err)
+	}
+	if os.Getenv(UpdateEnv) != "" {
+		if err = WriteGolden(path, got); err != nil {
+			tb.Fatalf("updating golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("reading golden file, set %s=1 to create it: %v", UpdateEnv, err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(want), bytes.TrimSpace(data)) {
+		tb.Errorf("%s differs from the golden file, set %s=1 to update it\ngot:\n%s\nwant:\n%s",
+			path, UpdateEnv, data, want)
+	}
+}
@@ -0,0 +1,36 @@
+package fixtures_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/fixtures"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestGoldenRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "usage.json")
+	usage := openai.Usage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3}
+	checks.NoError(t, fixtures.WriteGolden(path, usage), "WriteGolden error")
+
+	var read openai.Usage
+	checks.NoError(t, fixtures.ReadGolden(path, &read), "ReadGolden error")
+	if read != usage {
+		t.Errorf("expected %+v, got %+v", usage, read)
+	}
+	fixtures.AssertGolden(t, path, usage)
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.json")
+	t.Setenv(fixtures.UpdateEnv, "1")
+	fixtures.AssertGolden(t, path, []string{"gpt-4o"})
+
+	data, err := os.ReadFile(path)
+	checks.NoError(t, err, "ReadFile error")
+	if string(data) != "[\n  \"gpt-4o\"\n]\n" {
+		t.Errorf("unexpected golden file %q", data)
+	}
+}
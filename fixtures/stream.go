@@ -0,0 +1,190 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultMinChunkLen = 1
+	defaultMaxChunkLen = 6
+)
+
+// StreamOptions configures the chunks generated from a response.
+type StreamOptions struct {
+	// Seed makes the chunking reproducible: the same response and seed always give the same
+	// chunks.
+	Seed int64
+	// MinChunkLen and MaxChunkLen bound the number of characters of the word pieces of a chunk,
+	// 1 and 6 by default, close to the tokens of the API.
+	MinChunkLen int
+	MaxChunkLen int
+	// IncludeUsage adds a final chunk with the usage of the response, as sent when
+	// StreamOptions.IncludeUsage is set in the request.
+	IncludeUsage bool
+}
+
+// ChatStream returns the chunks the API would stream for response: a first chunk with the
+// role, the content and the tool call arguments split in pieces of a few characters, and a
+// last chunk with the finish reason of each choice.
+func ChatStream(response openai.ChatCompletionResponse, opts StreamOptions) []openai.ChatCompletionStreamResponse {
+	chunker := newChunker(opts)
+	var chunks []openai.ChatCompletionStreamResponse
+	newChunk := func(choice openai.ChatCompletionStreamChoice) openai.ChatCompletionStreamResponse {
+		return openai.ChatCompletionStreamResponse{
+			ID:                response.ID,
+			Object:            "chat.completion.chunk",
+			Created:           response.Created,
+			Model:             response.Model,
+			SystemFingerprint: response.SystemFingerprint,
+			Choices:           []openai.ChatCompletionStreamChoice{choice},
+		}
+	}
+
+	for _, choice := range response.Choices {
+		message := choice.Message
+		chunks = append(chunks, newChunk(openai.ChatCompletionStreamChoice{
+			Index: choice.Index,
+			Delta: openai.ChatCompletionStreamChoiceDelta{Role: message.Role, Refusal: message.Refusal},
+		}))
+		for _, piece := range chunker.split(message.Content) {
+			chunks = append(chunks, newChunk(openai.ChatCompletionStreamChoice{
+				Index: choice.Index,
+				Delta: openai.ChatCompletionStreamChoiceDelta{Content: piece},
+			}))
+		}
+		for i, toolCall := range message.ToolCalls {
+			index := i
+			chunks = append(chunks, newChunk(openai.ChatCompletionStreamChoice{
+				Index: choice.Index,
+				Delta: openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{{
+					Index:    &index,
+					ID:       toolCall.ID,
+					Type:     toolCall.Type,
+					Function: openai.FunctionCall{Name: toolCall.Function.Name},
+				}}},
+			}))
+			for _, piece := range chunker.split(toolCall.Function.Arguments) {
+				chunks = append(chunks, newChunk(openai.ChatCompletionStreamChoice{
+					Index: choice.Index,
+					Delta: openai.ChatCompletionStreamChoiceDelta{ToolCalls: []openai.ToolCall{{
+						Index:    &index,
+						Function: openai.FunctionCall{Arguments: piece},
+					}}},
+				}))
+			}
+		}
+		chunks = append(chunks, newChunk(openai.ChatCompletionStreamChoice{
+			Index:        choice.Index,
+			FinishReason: choice.FinishReason,
+		}))
+	}
+	if opts.IncludeUsage {
+		usage := response.Usage
+		chunk := newChunk(openai.ChatCompletionStreamChoice{})
+		chunk.Choices = []openai.ChatCompletionStreamChoice{}
+		chunk.Usage = &usage
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// ChatStreamBody returns the server-sent events body streaming response, ending with
+// "data: [DONE]".
+func ChatStreamBody(response openai.ChatCompletionResponse, opts StreamOptions) ([]byte, error) {
+	var body bytes.Buffer
+	for _, chunk := range ChatStream(response, opts) {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString("data: ")
+		body.Write(data)
+		body.WriteString("\n\n")
+	}
+	body.WriteString("data: [DONE]\n\n")
+	return body.Bytes(), nil
+}
+
+// ChatStreamFixture returns a fixture of the stream of response for POST /v1/chat/completions.
+func ChatStreamFixture(response openai.ChatCompletionResponse, opts StreamOptions) (Fixture, error) {
+	body, err := ChatStreamBody(response, opts)
+	if err != nil {
+		return Fixture{}, err
+	}
+	encoded, err := json.Marshal(string(body))
+	if err != nil {
+		return Fixture{}, err
+	}
+	return Fixture{
+		Method: http.MethodPost,
+		Path:   "/v1/chat/completions",
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"text/event-stream"}},
+		Body:   encoded,
+	}, nil
+}
+
+// chunker splits text into pieces of a few characters, like the tokens of a model: words are
+// cut into pieces with the preceding space attached to the first one, and punctuation and line
+// breaks are pieces of their own.
+type chunker struct {
+	rand     *rand.Rand
+	min, max int
+}
+
+func newChunker(opts StreamOptions) *chunker {
+	c := &chunker{
+		rand: rand.New(rand.NewSource(opts.Seed)), //nolint:gosec // fixtures need no secure randomness
+		min:  opts.MinChunkLen,
+		max:  opts.MaxChunkLen,
+	}
+	if c.min <= 0 {
+		c.min = defaultMinChunkLen
+	}
+	if c.max < c.min {
+		c.max = defaultMaxChunkLen
+		if c.max < c.min {
+			c.max = c.min
+		}
+	}
+	return c
+}
+
+func (c *chunker) split(text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		n := c.pieceLen(text)
+		pieces = append(pieces, text[:n])
+		text = text[n:]
+	}
+	return pieces
+}
+
+// pieceLen returns the length in bytes of the next piece of text.
+func (c *chunker) pieceLen(text string) int {
+	first, size := utf8.DecodeRuneInString(text)
+	if !unicode.IsLetter(first) && !unicode.IsDigit(first) && first != ' ' {
+		return size
+	}
+	limit := c.min + c.rand.Intn(c.max-c.min+1)
+	n, count := 0, 0
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || (i == 0 && r == ' ')
+		if !isWordRune || count == limit {
+			break
+		}
+		n = i + utf8.RuneLen(r)
+		count++
+	}
+	if n == 0 {
+		return size
+	}
+	return n
+}
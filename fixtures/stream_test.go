@@ -0,0 +1,84 @@
+package fixtures_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/fixtures"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func streamedResponse() openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		ID:    "chatcmpl-1",
+		Model: openai.GPT4o,
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: "Hello, wonderful world!\nHow are you?",
+				ToolCalls: []openai.ToolCall{{
+					ID:       "call_1",
+					Type:     openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: "greet", Arguments: `{"name":"Ada Lovelace"}`},
+				}},
+			},
+			FinishReason: openai.FinishReasonToolCalls,
+		}},
+		Usage: openai.Usage{PromptTokens: 4, CompletionTokens: 12, TotalTokens: 16},
+	}
+}
+
+func TestChatStreamDeterministic(t *testing.T) {
+	opts := fixtures.StreamOptions{Seed: 42, IncludeUsage: true}
+	first := fixtures.ChatStream(streamedResponse(), opts)
+	if !reflect.DeepEqual(first, fixtures.ChatStream(streamedResponse(), opts)) {
+		t.Error("expected the same chunks for the same seed")
+	}
+	for _, chunk := range first {
+		for _, choice := range chunk.Choices {
+			if n := len([]rune(choice.Delta.Content)); n > 6 {
+				t.Errorf("piece %q exceeds the maximum length", choice.Delta.Content)
+			}
+		}
+	}
+}
+
+func TestChatStreamFixtureReassembles(t *testing.T) {
+	response := streamedResponse()
+	fixture, err := fixtures.ChatStreamFixture(response, fixtures.StreamOptions{Seed: 7, IncludeUsage: true})
+	checks.NoError(t, err, "ChatStreamFixture error")
+	server := httptest.NewServer(fixtures.Handler(fixture))
+	defer server.Close()
+	config := openai.DefaultConfig("token")
+	config.BaseURL = server.URL + "/v1"
+	client := openai.NewClientWithConfig(config)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	choices, err := openai.NewChoiceDemux(stream).Accumulate()
+	checks.NoError(t, err, "Accumulate error")
+	if !stream.IsComplete() {
+		t.Error("expected a complete stream")
+	}
+
+	if len(choices) != 1 || choices[0].FinishReason != openai.FinishReasonToolCalls {
+		t.Fatalf("unexpected choices %+v", choices)
+	}
+	message := choices[0].Message()
+	expected := response.Choices[0].Message
+	if message.Content != expected.Content || !reflect.DeepEqual(message.ToolCalls, expected.ToolCalls) {
+		t.Errorf("expected %+v, got %+v", expected, message)
+	}
+	if _, err = stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HedgePolicy sends duplicate requests when the upstream is slow to answer and returns
+// whichever response arrives first, canceling the others. Only enable it for requests that
+// are safe to send more than once: every hedge is billed.
+type HedgePolicy struct {
+	// Delay is how long to wait for a response before sending the next hedge.
+	Delay time.Duration
+	// MaxHedges is the number of duplicates sent in addition to the original request. Defaults to 1.
+	MaxHedges int
+	// Endpoints are the URL path suffixes of POST requests that may be hedged.
+	// Defaults to "/completions" and "/embeddings", which covers chat completions.
+	Endpoints []string
+}
+
+var defaultHedgeEndpoints = []string{"/completions", "/embeddings"}
+
+func (p *HedgePolicy) applies(req *http.Request) bool {
+	if p.Delay <= 0 || req.Method != http.MethodPost || req.GetBody == nil {
+		return false
+	}
+	endpoints := p.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = defaultHedgeEndpoints
+	}
+	for _, endpoint := range endpoints {
+		if strings.HasSuffix(req.URL.Path, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+type hedgeResult struct {
+	attempt int
+	resp    *http.Response
+	err     error
+}
+
+// hedgedDoer wraps an HTTPDoer with a HedgePolicy.
+type hedgedDoer struct {
+	doer   HTTPDoer
+	policy *HedgePolicy
+}
+
+func (d *hedgedDoer) Do(req *http.Request) (*http.Response, error) {
+	if !d.policy.applies(req) {
+		return d.doer.Do(req)
+	}
+	maxAttempts := 1 + d.policy.MaxHedges
+	if d.policy.MaxHedges <= 0 {
+		maxAttempts = 2
+	}
+
+	results := make(chan hedgeResult, maxAttempts)
+	cancels := make([]context.CancelFunc, 0, maxAttempts)
+	launch := func() error {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := req.Clone(ctx)
+		attempt.Body = body
+		cancels = append(cancels, cancel)
+
+		go func(i int) {
+			resp, err := d.doer.Do(attempt) //nolint:bodyclose // closed by the caller or discardHedges
+			results <- hedgeResult{attempt: i, resp: resp, err: err}
+		}(len(cancels) - 1)
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(d.policy.Delay)
+	defer timer.Stop()
+
+	pending := 1
+	var last hedgeResult
+	for {
+		select {
+		case <-timer.C:
+			if len(cancels) < maxAttempts && launch() == nil {
+				pending++
+				timer.Reset(d.policy.Delay)
+			}
+		case res := <-results:
+			pending--
+			if res.err == nil && res.resp.StatusCode < http.StatusInternalServerError {
+				if last.resp != nil {
+					last.resp.Body.Close()
+				}
+				go discardHedges(results, pending)
+				return keepHedge(res, cancels)
+			}
+			if last.resp != nil {
+				last.resp.Body.Close()
+			}
+			last = res
+			if pending == 0 {
+				return keepHedge(last, cancels)
+			}
+		}
+	}
+}
+
+// keepHedge cancels every attempt but res and ties the context of res to its response body.
+func keepHedge(res hedgeResult, cancels []context.CancelFunc) (*http.Response, error) {
+	for i, cancel := range cancels {
+		if i != res.attempt {
+			cancel()
+		}
+	}
+	if res.err != nil {
+		cancels[res.attempt]()
+		return nil, res.err
+	}
+	res.resp.Body = &cancelingBody{ReadCloser: res.resp.Body, cancel: cancels[res.attempt]}
+	return res.resp, nil
+}
+
+// discardHedges closes the responses of the losing hedges as they arrive.
+func discardHedges(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		res := <-results
+		if res.resp != nil {
+			res.resp.Body.Close()
+		}
+	}
+}
+
+// cancelingBody releases the context of a request when its response body is closed.
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
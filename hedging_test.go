@@ -0,0 +1,112 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestHedgePolicyReturnsFastestResponse(t *testing.T) {
+	var calls int32
+	canceled := make(chan struct{})
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.HedgePolicy = &openai.HedgePolicy{Delay: 10 * time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The original request stalls until the hedge wins and cancels it.
+			select {
+			case <-r.Context().Done():
+				close(canceled)
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		fmt.Fprintln(w, `{"id":"hedge","choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`)
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.ID != "hedge" {
+		t.Errorf("expected the hedged response, got %+v", resp)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("stalled request was not canceled")
+	}
+}
+
+func TestHedgePolicySkipsOtherEndpoints(t *testing.T) {
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.HedgePolicy = &openai.HedgePolicy{Delay: time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/moderations", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintln(w, `{"id":"modr-1","results":[]}`)
+	})
+
+	_, err := client.Moderations(context.Background(), openai.ModerationRequest{Input: "hello"})
+	checks.NoError(t, err, "Moderations error")
+	if calls != 1 {
+		t.Errorf("expected a single request, got %d", calls)
+	}
+}
+
+// closeTrackingBody records whether a response body was closed.
+type closeTrackingBody struct {
+	io.Reader
+	closed int32
+}
+
+func (b *closeTrackingBody) Close() error {
+	atomic.StoreInt32(&b.closed, 1)
+	return nil
+}
+
+func TestHedgePolicyClosesFailedResponse(t *testing.T) {
+	var calls int32
+	failed := &closeTrackingBody{Reader: strings.NewReader(`{"error":{"message":"boom"}}`)}
+	config := openai.DefaultConfig("token")
+	config.HedgePolicy = &openai.HedgePolicy{Delay: 10 * time.Millisecond}
+	config.HTTPClient = doerFunc(func(*http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The original request fails while the hedge is pending.
+			time.Sleep(20 * time.Millisecond)
+			return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: failed}, nil
+		}
+		time.Sleep(40 * time.Millisecond)
+		body := `{"id":"hedge","choices":[{"index":0,"message":{"role":"assistant","content":"hi"}}]}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+	client := openai.NewClientWithConfig(config)
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.ID != "hedge" {
+		t.Errorf("expected the hedged response, got %+v", resp)
+	}
+	if atomic.LoadInt32(&failed.closed) != 1 {
+		t.Error("expected the body of the failed response to be closed")
+	}
+}
@@ -0,0 +1,176 @@
+package openai
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoHypotheticalDocument is returned by HypotheticalDocumentEmbedding when the model
+// generated no document to embed.
+var ErrNoHypotheticalDocument = errors.New("no hypothetical document was generated")
+
+// DefaultHyDEInstructions ask the model for a passage answering the query.
+const DefaultHyDEInstructions = "Write a short passage that answers the question, in the style of " +
+	"the documents it would be found in. Do not mention that the passage is hypothetical."
+
+// HyDERequest describes a hypothetical document embedding (HyDE): the embedding of documents
+// generated to answer a query, which is often closer to the embeddings of the relevant documents
+// than the embedding of the query itself.
+type HyDERequest struct {
+	Query string
+	// ChatModel generates the documents, GPT4oMini by default.
+	ChatModel string
+	// EmbeddingModel embeds the documents, SmallEmbedding3 by default.
+	EmbeddingModel EmbeddingModel
+	Dimensions     int
+	// Documents is the number of documents generated, 1 by default. Their embeddings are
+	// averaged.
+	Documents int
+	// IncludeQuery also averages the embedding of the query, embedded while the documents are
+	// generated.
+	IncludeQuery bool
+	// Instructions is the system message of the generation, DefaultHyDEInstructions by default.
+	Instructions string
+	Temperature  float32
+	// MaxTokens bounds the length of each document, 256 by default.
+	MaxTokens int
+}
+
+// HyDEResponse is the result of HypotheticalDocumentEmbedding.
+type HyDEResponse struct {
+	// Embedding is the normalized mean of the embeddings of the documents, and of the query if
+	// IncludeQuery was set.
+	Embedding []float32
+	Documents []string
+	// Usage adds up the usage of the chat completion and of the embeddings.
+	Usage Usage
+}
+
+// HypotheticalDocumentEmbedding generates documents answering request.Query with a chat
+// completion and returns their mean embedding, to search a vector index with it:
+//
+//	hyde, err := client.HypotheticalDocumentEmbedding(ctx, openai.HyDERequest{Query: question})
+//	nearest, err := openai.NearestVectors(hyde.Embedding, vectors, 5, openai.CosineSimilarity)
+//
+// With IncludeQuery, the query is embedded in parallel with the generation. Both requests share
+// ctx, and the first failure cancels the other.
+func (c *Client) HypotheticalDocumentEmbedding(ctx context.Context, request HyDERequest) (HyDEResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type queryEmbedding struct {
+		response EmbeddingResponse
+		err      error
+	}
+	var queryResult chan queryEmbedding
+	if request.IncludeQuery {
+		queryResult = make(chan queryEmbedding, 1)
+		go func() {
+			response, err := c.CreateEmbeddings(ctx, request.embeddingRequest([]string{request.Query}))
+			if err != nil {
+				cancel()
+			}
+			queryResult <- queryEmbedding{response, err}
+		}()
+	}
+
+	var response HyDEResponse
+	completion, err := c.CreateChatCompletion(ctx, request.chatRequest())
+	if err != nil {
+		cancel()
+		if queryResult != nil {
+			if query := <-queryResult; query.err != nil && !errors.Is(query.err, context.Canceled) {
+				return response, query.err
+			}
+		}
+		return response, err
+	}
+	response.Usage = completion.Usage
+	for _, choice := range completion.Choices {
+		if choice.Message.Content != "" {
+			response.Documents = append(response.Documents, choice.Message.Content)
+		}
+	}
+	if len(response.Documents) == 0 {
+		return response, ErrNoHypotheticalDocument
+	}
+
+	embeddings, err := c.CreateEmbeddings(ctx, request.embeddingRequest(response.Documents))
+	if err != nil {
+		return response, err
+	}
+	addEmbeddingUsage(&response.Usage, embeddings.Usage)
+	vectors := make([][]float32, 0, len(embeddings.Data)+1)
+	for _, embedding := range embeddings.Data {
+		vectors = append(vectors, embedding.Embedding)
+	}
+	if queryResult != nil {
+		query := <-queryResult
+		if query.err != nil {
+			return response, query.err
+		}
+		addEmbeddingUsage(&response.Usage, query.response.Usage)
+		for _, embedding := range query.response.Data {
+			vectors = append(vectors, embedding.Embedding)
+		}
+	}
+
+	response.Embedding, err = meanVector(vectors)
+	return response, err
+}
+
+func (r HyDERequest) chatRequest() ChatCompletionRequest {
+	model, instructions, maxTokens := r.ChatModel, r.Instructions, r.MaxTokens
+	if model == "" {
+		model = GPT4oMini
+	}
+	if instructions == "" {
+		instructions = DefaultHyDEInstructions
+	}
+	if maxTokens <= 0 {
+		maxTokens = 256
+	}
+	request := ChatCompletionRequest{
+		Model: model,
+		Messages: []ChatCompletionMessage{
+			{Role: ChatMessageRoleSystem, Content: instructions},
+			{Role: ChatMessageRoleUser, Content: r.Query},
+		},
+		Temperature: r.Temperature,
+		MaxTokens:   maxTokens,
+	}
+	if r.Documents > 1 {
+		request.N = r.Documents
+	}
+	return request
+}
+
+func (r HyDERequest) embeddingRequest(input []string) EmbeddingRequest {
+	model := r.EmbeddingModel
+	if model == "" {
+		model = SmallEmbedding3
+	}
+	return EmbeddingRequest{Input: input, Model: model, Dimensions: r.Dimensions}
+}
+
+func addEmbeddingUsage(usage *Usage, embedding Usage) {
+	usage.PromptTokens += embedding.PromptTokens
+	usage.TotalTokens += embedding.TotalTokens
+}
+
+// meanVector returns the normalized mean of vectors.
+func meanVector(vectors [][]float32) ([]float32, error) {
+	if len(vectors) == 0 {
+		return nil, ErrNoHypotheticalDocument
+	}
+	mean := make([]float32, len(vectors[0]))
+	for _, vector := range vectors {
+		if len(vector) != len(mean) {
+			return nil, ErrVectorLengthMismatch
+		}
+		for i, value := range vector {
+			mean[i] += value / float32(len(vectors))
+		}
+	}
+	return Normalize(mean), nil
+}
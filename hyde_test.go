@@ -0,0 +1,89 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestHypotheticalDocumentEmbedding(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var chatRequest openai.ChatCompletionRequest
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&chatRequest), "Decode error")
+		fmt.Fprint(w, `{"id":"1","choices":[`+
+			`{"index":0,"message":{"role":"assistant","content":"Cats sleep 16 hours."}},`+
+			`{"index":1,"message":{"role":"assistant","content":"Most cats sleep all day."}}],`+
+			`"usage":{"prompt_tokens":20,"completion_tokens":12,"total_tokens":32}}`)
+	})
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input []string `json:"input"`
+		}
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		response := openai.EmbeddingResponse{Usage: openai.Usage{PromptTokens: 5, TotalTokens: 5}}
+		for i, input := range request.Input {
+			vector := []float32{1, 0}
+			if input == "How long do cats sleep?" {
+				vector = []float32{0, 1}
+			}
+			response.Data = append(response.Data, openai.Embedding{Index: i, Embedding: vector})
+		}
+		checks.NoError(t, json.NewEncoder(w).Encode(response), "Encode error")
+	})
+
+	hyde, err := client.HypotheticalDocumentEmbedding(context.Background(), openai.HyDERequest{
+		Query:        "How long do cats sleep?",
+		Documents:    2,
+		IncludeQuery: true,
+	})
+	checks.NoError(t, err, "HypotheticalDocumentEmbedding error")
+
+	if chatRequest.N != 2 || chatRequest.Model != openai.GPT4oMini ||
+		chatRequest.Messages[0].Content != openai.DefaultHyDEInstructions {
+		t.Errorf("unexpected chat request %+v", chatRequest)
+	}
+	if len(hyde.Documents) != 2 || hyde.Documents[1] != "Most cats sleep all day." {
+		t.Errorf("unexpected documents %q", hyde.Documents)
+	}
+	// The mean of (1, 0), (1, 0) and (0, 1), normalized.
+	want := []float64{2 / math.Sqrt(5), 1 / math.Sqrt(5)}
+	for i, value := range hyde.Embedding {
+		if math.Abs(float64(value)-want[i]) > 1e-6 {
+			t.Errorf("unexpected embedding %v, want %v", hyde.Embedding, want)
+			break
+		}
+	}
+	if hyde.Usage.PromptTokens != 30 || hyde.Usage.CompletionTokens != 12 || hyde.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected usage %+v", hyde.Usage)
+	}
+}
+
+func TestHypotheticalDocumentEmbeddingError(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"bad model","type":"invalid_request_error"}}`)
+	})
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"data":[{"index":0,"embedding":[0,1]}]}`)
+	})
+
+	_, err := client.HypotheticalDocumentEmbedding(context.Background(), openai.HyDERequest{
+		Query:        "How long do cats sleep?",
+		IncludeQuery: true,
+	})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "bad model" {
+		t.Errorf("expected the chat completion error, got %v", err)
+	}
+}
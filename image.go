@@ -24,8 +24,9 @@ const (
 )
 
 const (
-	CreateImageModelDallE2 = "dall-e-2"
-	CreateImageModelDallE3 = "dall-e-3"
+	CreateImageModelDallE2    = "dall-e-2"
+	CreateImageModelDallE3    = "dall-e-3"
+	CreateImageModelGptImage1 = "gpt-image-1"
 )
 
 const (
@@ -67,6 +68,7 @@ type ImageResponseDataInner struct {
 
 // CreateImage - API call to create an image. This is the main endpoint of the DALL-E API.
 func (c *Client) CreateImage(ctx context.Context, request ImageRequest) (response ImageResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
 	urlSuffix := "/images/generations"
 	req, err := c.newRequest(
 		ctx,
@@ -84,29 +86,58 @@ func (c *Client) CreateImage(ctx context.Context, request ImageRequest) (respons
 
 // ImageEditRequest represents the request structure for the image API.
 type ImageEditRequest struct {
-	Image          *os.File `json:"image,omitempty"`
-	Mask           *os.File `json:"mask,omitempty"`
-	Prompt         string   `json:"prompt,omitempty"`
-	Model          string   `json:"model,omitempty"`
-	N              int      `json:"n,omitempty"`
-	Size           string   `json:"size,omitempty"`
-	ResponseFormat string   `json:"response_format,omitempty"`
+	Image *os.File `json:"image,omitempty"`
+	Mask  *os.File `json:"mask,omitempty"`
+	// Images, if set, are uploaded instead of Image. Only gpt-image-1 accepts several images.
+	Images []ImageInput `json:"-"`
+	// MaskInput, if set, is uploaded instead of Mask.
+	MaskInput      *ImageInput `json:"-"`
+	Prompt         string      `json:"prompt,omitempty"`
+	Model          string      `json:"model,omitempty"`
+	N              int         `json:"n,omitempty"`
+	Size           string      `json:"size,omitempty"`
+	ResponseFormat string      `json:"response_format,omitempty"`
 }
 
 // CreateEditImage - API call to create an image. This is the main endpoint of the DALL-E API.
 func (c *Client) CreateEditImage(ctx context.Context, request ImageEditRequest) (response ImageResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
+	images, mask, err := encodeImageInputs(request.Model, request.Images, request.MaskInput)
+	if err != nil {
+		return
+	}
 	body := &bytes.Buffer{}
 	builder := c.createFormBuilder(body)
 
 	// image
-	err = builder.CreateFormFile("image", request.Image)
+	switch {
+	case len(images) > 1:
+		for _, input := range images {
+			if err = input.write(builder, "image[]"); err != nil {
+				return
+			}
+		}
+	case len(images) == 1:
+		err = images[0].write(builder, "image")
+	default:
+		err = builder.CreateFormFile("image", request.Image)
+	}
 	if err != nil {
 		return
 	}
 
 	// mask, it is optional
-	if request.Mask != nil {
+	if mask != nil {
+		err = mask.write(builder, "mask")
+	} else if request.Mask != nil {
 		err = builder.CreateFormFile("mask", request.Mask)
+	}
+	if err != nil {
+		return
+	}
+
+	if request.Model != "" {
+		err = builder.WriteField("model", request.Model)
 		if err != nil {
 			return
 		}
@@ -154,25 +185,47 @@ func (c *Client) CreateEditImage(ctx context.Context, request ImageEditRequest)
 
 // ImageVariRequest represents the request structure for the image API.
 type ImageVariRequest struct {
-	Image          *os.File `json:"image,omitempty"`
-	Model          string   `json:"model,omitempty"`
-	N              int      `json:"n,omitempty"`
-	Size           string   `json:"size,omitempty"`
-	ResponseFormat string   `json:"response_format,omitempty"`
+	Image *os.File `json:"image,omitempty"`
+	// ImageInput, if set, is uploaded instead of Image.
+	ImageInput     *ImageInput `json:"-"`
+	Model          string      `json:"model,omitempty"`
+	N              int         `json:"n,omitempty"`
+	Size           string      `json:"size,omitempty"`
+	ResponseFormat string      `json:"response_format,omitempty"`
 }
 
 // CreateVariImage - API call to create an image variation. This is the main endpoint of the DALL-E API.
 // Use abbreviations(vari for variation) because ci-lint has a single-line length limit ...
 func (c *Client) CreateVariImage(ctx context.Context, request ImageVariRequest) (response ImageResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
+	var inputs []ImageInput
+	if request.ImageInput != nil {
+		inputs = append(inputs, *request.ImageInput)
+	}
+	images, _, err := encodeImageInputs(request.Model, inputs, nil)
+	if err != nil {
+		return
+	}
 	body := &bytes.Buffer{}
 	builder := c.createFormBuilder(body)
 
 	// image
-	err = builder.CreateFormFile("image", request.Image)
+	if len(images) > 0 {
+		err = images[0].write(builder, "image")
+	} else {
+		err = builder.CreateFormFile("image", request.Image)
+	}
 	if err != nil {
 		return
 	}
 
+	if request.Model != "" {
+		err = builder.WriteField("model", request.Model)
+		if err != nil {
+			return
+		}
+	}
+
 	err = builder.WriteField("n", strconv.Itoa(request.N))
 	if err != nil {
 		return
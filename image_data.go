@@ -0,0 +1,94 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register the GIF decoder for Decode
+	_ "image/jpeg" // register the JPEG decoder for Decode
+	_ "image/png"  // register the PNG decoder for Decode
+	"io"
+	"net/http"
+	"os"
+)
+
+var ErrImageDataMissing = errors.New("image data has neither url nor b64_json")
+
+// Open returns the content of the image, decoding the b64_json payload or downloading the
+// url, whichever the response format of the request was. The url is downloaded with
+// http.DefaultClient; use Client.OpenImage to go through the proxy, timeouts and other
+// transport settings of a client.
+func (d ImageResponseDataInner) Open(ctx context.Context) (io.ReadCloser, error) {
+	return d.open(ctx, http.DefaultClient)
+}
+
+// OpenImage returns the content of the image like ImageResponseDataInner.Open, downloading
+// its url with the HTTP client configured for c.
+func (c *Client) OpenImage(ctx context.Context, data ImageResponseDataInner) (io.ReadCloser, error) {
+	return data.open(ctx, c.config.HTTPClient)
+}
+
+func (d ImageResponseDataInner) open(ctx context.Context, doer HTTPDoer) (io.ReadCloser, error) {
+	switch {
+	case d.B64JSON != "":
+		data, err := base64.StdEncoding.DecodeString(d.B64JSON)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	case d.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := doer.Do(req) //nolint:bodyclose // closed by the caller
+		if err != nil {
+			return nil, err
+		}
+		if isFailureStatusCode(resp) {
+			resp.Body.Close()
+			return nil, fmt.Errorf("error, downloading image: status %s", resp.Status)
+		}
+		return resp.Body, nil
+	default:
+		return nil, ErrImageDataMissing
+	}
+}
+
+// Write writes the content of the image to w.
+func (d ImageResponseDataInner) Write(ctx context.Context, w io.Writer) (int64, error) {
+	content, err := d.Open(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+	return io.Copy(w, content)
+}
+
+// Save writes the content of the image to the file at path.
+func (d ImageResponseDataInner) Save(ctx context.Context, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = d.Write(ctx, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Decode decodes the image. PNG, JPEG and GIF images are supported; other formats such as
+// WebP need their decoder to be registered with the image package.
+func (d ImageResponseDataInner) Decode(ctx context.Context) (image.Image, error) {
+	content, err := d.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	img, _, err := image.Decode(content)
+	return img, err
+}
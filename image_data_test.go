@@ -0,0 +1,106 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 3))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	checks.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestImageResponseDataDecode(t *testing.T) {
+	pngBytes := testPNG(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/img.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(pngBytes)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	for _, data := range []openai.ImageResponseDataInner{
+		{B64JSON: base64.StdEncoding.EncodeToString(pngBytes)},
+		{URL: ts.URL + "/img.png"},
+	} {
+		img, err := data.Decode(ctx)
+		checks.NoError(t, err, "Decode error")
+		if img.Bounds() != image.Rect(0, 0, 2, 3) {
+			t.Errorf("unexpected bounds: %v", img.Bounds())
+		}
+		if r, _, _, _ := img.At(1, 1).RGBA(); r != 0xffff {
+			t.Errorf("unexpected pixel: %v", img.At(1, 1))
+		}
+
+		var buf bytes.Buffer
+		n, err := data.Write(ctx, &buf)
+		checks.NoError(t, err, "Write error")
+		if n != int64(len(pngBytes)) || !bytes.Equal(buf.Bytes(), pngBytes) {
+			t.Errorf("unexpected content of %d bytes", n)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "out.png")
+	checks.NoError(t, openai.ImageResponseDataInner{URL: ts.URL + "/img.png"}.Save(ctx, path))
+	saved, err := os.ReadFile(path)
+	checks.NoError(t, err)
+	if !bytes.Equal(saved, pngBytes) {
+		t.Error("unexpected saved content")
+	}
+
+	_, err = openai.ImageResponseDataInner{URL: ts.URL + "/missing.png"}.Decode(ctx)
+	checks.HasError(t, err)
+	_, err = openai.ImageResponseDataInner{}.Decode(ctx)
+	if !errors.Is(err, openai.ErrImageDataMissing) {
+		t.Errorf("expected ErrImageDataMissing, got %v", err)
+	}
+}
+
+func TestClientOpenImage(t *testing.T) {
+	pngBytes := testPNG(t)
+	var downloads int
+	config := openai.DefaultConfig("")
+	config.HTTPClient = doerFunc(func(req *http.Request) (*http.Response, error) {
+		downloads++
+		if req.URL.String() != "https://images.example.com/img.png" {
+			t.Errorf("unexpected url %s", req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(bytes.NewReader(pngBytes)),
+		}, nil
+	})
+	client := openai.NewClientWithConfig(config)
+
+	content, err := client.OpenImage(context.Background(),
+		openai.ImageResponseDataInner{URL: "https://images.example.com/img.png"})
+	checks.NoError(t, err, "OpenImage error")
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	checks.NoError(t, err, "ReadAll error")
+	if !bytes.Equal(data, pngBytes) || downloads != 1 {
+		t.Errorf("expected the image to be downloaded once with the client doer, got %d downloads", downloads)
+	}
+}
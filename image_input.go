@@ -0,0 +1,123 @@
+package openai
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	utils "github.com/sashabaranov/go-openai/internal"
+)
+
+var (
+	ErrImageTooLarge         = errors.New("image is too large")
+	ErrImageNotSquare        = errors.New("image must be square")
+	ErrImageMaskSizeMismatch = errors.New("mask must have the dimensions of the image")
+	ErrTooManyImages         = errors.New("too many images for the model")
+)
+
+const (
+	dallE2MaxImageBytes     = 4 << 20
+	gptImage1MaxImageBytes  = 50 << 20
+	gptImage1MaxInputImages = 16
+)
+
+// ImageInput is an image to upload, read from Reader or encoded to PNG from Image.
+type ImageInput struct {
+	Reader io.Reader
+	// Name is the file name of the image, which tells its format. Defaults to "image.png".
+	Name  string
+	Image image.Image
+}
+
+// NewImageInput returns the input for an image, uploaded as PNG.
+func NewImageInput(img image.Image) ImageInput {
+	return ImageInput{Image: img}
+}
+
+// NewImageInputReader returns the input for an encoded image named name, e.g. "photo.jpg".
+func NewImageInputReader(r io.Reader, name string) ImageInput {
+	return ImageInput{Reader: r, Name: name}
+}
+
+// encodedImage is the content of an ImageInput. config is only set for formats known to the
+// image package.
+type encodedImage struct {
+	data   []byte
+	name   string
+	config *image.Config
+}
+
+func (in ImageInput) encode() (encoded encodedImage, err error) {
+	encoded.name = in.Name
+	if encoded.name == "" {
+		encoded.name = "image.png"
+	}
+	if in.Image != nil {
+		var buf bytes.Buffer
+		if err = png.Encode(&buf, in.Image); err != nil {
+			return
+		}
+		encoded.data = buf.Bytes()
+	} else if in.Reader != nil {
+		if encoded.data, err = io.ReadAll(in.Reader); err != nil {
+			return
+		}
+	}
+	if config, _, configErr := image.DecodeConfig(bytes.NewReader(encoded.data)); configErr == nil {
+		encoded.config = &config
+	}
+	return
+}
+
+func (e encodedImage) write(builder utils.FormBuilder, fieldname string) error {
+	return builder.CreateFormFileReader(fieldname, bytes.NewReader(e.data), e.name)
+}
+
+// encodeImageInputs encodes images and mask, and checks them against the limits of model.
+func encodeImageInputs(model string, inputs []ImageInput, maskInput *ImageInput) (
+	images []encodedImage,
+	mask *encodedImage,
+	err error,
+) {
+	maxBytes, maxImages := dallE2MaxImageBytes, 1
+	if model == CreateImageModelGptImage1 {
+		maxBytes, maxImages = gptImage1MaxImageBytes, gptImage1MaxInputImages
+	}
+	if len(inputs) > maxImages {
+		return nil, nil, fmt.Errorf("%w: %d images, at most %d", ErrTooManyImages, len(inputs), maxImages)
+	}
+
+	for _, input := range inputs {
+		var encoded encodedImage
+		if encoded, err = input.encode(); err != nil {
+			return
+		}
+		images = append(images, encoded)
+	}
+	if maskInput != nil {
+		var encoded encodedImage
+		if encoded, err = maskInput.encode(); err != nil {
+			return
+		}
+		mask = &encoded
+	}
+
+	for _, encoded := range images {
+		if len(encoded.data) > maxBytes {
+			return nil, nil, fmt.Errorf("%w: %s is %d bytes, at most %d",
+				ErrImageTooLarge, encoded.name, len(encoded.data), maxBytes)
+		}
+		config := encoded.config
+		if model != CreateImageModelGptImage1 && config != nil && config.Width != config.Height {
+			return nil, nil, fmt.Errorf("%w: %s is %dx%d", ErrImageNotSquare, encoded.name, config.Width, config.Height)
+		}
+	}
+	if mask != nil && len(images) > 0 && mask.config != nil && images[0].config != nil &&
+		(mask.config.Width != images[0].config.Width || mask.config.Height != images[0].config.Height) {
+		return nil, nil, ErrImageMaskSizeMismatch
+	}
+	return images, mask, nil
+}
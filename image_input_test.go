@@ -0,0 +1,116 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateEditImageInputs(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/images/edits", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, r.ParseMultipartForm(1<<20))
+		images := r.MultipartForm.File["image[]"]
+		if len(images) != 2 || images[0].Filename != "image.png" || images[1].Filename != "photo.png" {
+			t.Errorf("unexpected images: %v", images)
+		}
+		if len(r.MultipartForm.File["mask"]) != 1 || r.FormValue("model") != openai.CreateImageModelGptImage1 {
+			t.Errorf("unexpected form: %v", r.MultipartForm.Value)
+		}
+		file, _ := images[0].Open()
+		defer file.Close()
+		img, format, err := image.Decode(file)
+		if err != nil || format != "png" || img.Bounds().Dx() != 2 {
+			t.Errorf("unexpected image: %v, %s", err, format)
+		}
+		fmt.Fprint(w, `{"data":[{"b64_json":"aW1n"}]}`)
+	})
+
+	_, err := client.CreateEditImage(context.Background(), openai.ImageEditRequest{
+		Model:  openai.CreateImageModelGptImage1,
+		Prompt: "Combine them",
+		Images: []openai.ImageInput{
+			openai.NewImageInput(image.NewRGBA(image.Rect(0, 0, 2, 3))),
+			openai.NewImageInputReader(bytes.NewReader(testPNG(t)), "photo.png"),
+		},
+		MaskInput: &openai.ImageInput{Image: image.NewRGBA(image.Rect(0, 0, 2, 3))},
+	})
+	checks.NoError(t, err, "CreateEditImage error")
+}
+
+func TestCreateVariImageInput(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/images/variations", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, r.ParseMultipartForm(1<<20))
+		file, _, err := r.FormFile("image")
+		checks.NoError(t, err)
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if config, _, configErr := image.DecodeConfig(bytes.NewReader(data)); configErr != nil || config.Width != 4 {
+			t.Errorf("unexpected image: %v", configErr)
+		}
+		fmt.Fprint(w, `{"data":[{"url":"https://example.com/a.png"}]}`)
+	})
+
+	_, err := client.CreateVariImage(context.Background(), openai.ImageVariRequest{
+		ImageInput: &openai.ImageInput{Image: image.NewRGBA(image.Rect(0, 0, 4, 4))},
+	})
+	checks.NoError(t, err, "CreateVariImage error")
+}
+
+func TestImageInputValidation(t *testing.T) {
+	client := openai.NewClient("key")
+	ctx := context.Background()
+	square := openai.NewImageInput(image.NewRGBA(image.Rect(0, 0, 4, 4)))
+
+	for _, tc := range []struct {
+		name    string
+		request openai.ImageEditRequest
+		want    error
+	}{
+		{
+			"not square",
+			openai.ImageEditRequest{Images: []openai.ImageInput{openai.NewImageInput(image.NewRGBA(image.Rect(0, 0, 4, 2)))}},
+			openai.ErrImageNotSquare,
+		},
+		{
+			"too many images",
+			openai.ImageEditRequest{Model: openai.CreateImageModelDallE2, Images: []openai.ImageInput{square, square}},
+			openai.ErrTooManyImages,
+		},
+		{
+			"too large",
+			openai.ImageEditRequest{Images: []openai.ImageInput{
+				openai.NewImageInputReader(bytes.NewReader(make([]byte, 4<<20+1)), "big.png"),
+			}},
+			openai.ErrImageTooLarge,
+		},
+		{
+			"mask mismatch",
+			openai.ImageEditRequest{
+				Images:    []openai.ImageInput{square},
+				MaskInput: &openai.ImageInput{Image: image.NewRGBA(image.Rect(0, 0, 8, 8))},
+			},
+			openai.ErrImageMaskSizeMismatch,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.CreateEditImage(ctx, tc.request)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package openai
+
+// codecMarshalable is implemented by types whose MarshalJSON encodes with encoding/json, so
+// that a configured JSONMarshaler can encode them itself.
+type codecMarshalable interface {
+	marshalWith(marshaler JSONMarshaler) ([]byte, error)
+}
+
+// codecUnmarshalable is implemented by types whose UnmarshalJSON decodes with encoding/json,
+// so that a configured JSONUnmarshaler can decode them itself.
+type codecUnmarshalable interface {
+	unmarshalWith(unmarshaler JSONUnmarshaler, data []byte) error
+}
+
+// codecMarshaler encodes with a configured JSONMarshaler. Calling MarshalJSON on the types
+// implementing codecMarshalable would hand their encoding back to encoding/json.
+type codecMarshaler struct {
+	next JSONMarshaler
+}
+
+func (m *codecMarshaler) Marshal(v any) ([]byte, error) {
+	if marshalable, ok := v.(codecMarshalable); ok {
+		return marshalable.marshalWith(m.next)
+	}
+	return m.next.Marshal(v)
+}
+
+// codecUnmarshaler decodes with a configured JSONUnmarshaler. Calling UnmarshalJSON on the
+// types implementing codecUnmarshalable would hand their decoding back to encoding/json.
+type codecUnmarshaler struct {
+	next JSONUnmarshaler
+}
+
+func (u *codecUnmarshaler) Unmarshal(data []byte, v any) error {
+	if unmarshalable, ok := v.(codecUnmarshalable); ok {
+		return unmarshalable.unmarshalWith(u.next, data)
+	}
+	return u.next.Unmarshal(data, v)
+}
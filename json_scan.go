@@ -0,0 +1,109 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// scanJSONObject calls fn with the key and raw value of each member of the JSON object data,
+// without decoding the values or allocating. data must be valid JSON, e.g. already decoded
+// successfully; it is ignored if it is not an object. The slices passed to fn alias data.
+func scanJSONObject(data []byte, fn func(key, value []byte)) {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return
+	}
+	i++
+	for {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return
+		}
+		keyEnd := skipJSONString(data, i)
+		rawKey := data[i:keyEnd]
+		i = skipJSONSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return
+		}
+		i = skipJSONSpace(data, i+1)
+		valueStart := i
+		i = skipJSONValue(data, i)
+
+		if bytes.IndexByte(rawKey, '\\') >= 0 {
+			var key string
+			if json.Unmarshal(rawKey, &key) == nil {
+				fn([]byte(key), data[valueStart:i])
+			}
+		} else {
+			fn(rawKey[1:len(rawKey)-1], data[valueStart:i])
+		}
+
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != ',' {
+			return
+		}
+		i++
+	}
+}
+
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipJSONString returns the index after the string starting with the quote at i.
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipJSONValue returns the index after the value starting at i.
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return skipJSONString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipJSONString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	}
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			return i
+		}
+		i++
+	}
+	return i
+}
@@ -0,0 +1,154 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+var (
+	ErrMissingCustomID   = errors.New("batch request has no custom_id")
+	ErrDuplicateCustomID = errors.New("duplicate custom_id")
+	ErrInvalidMethod     = errors.New("batch request method must be POST")
+	ErrInvalidEndpoint   = errors.New("invalid batch endpoint")
+	ErrMixedEndpoints    = errors.New("all requests of a batch must use the same endpoint")
+	ErrMissingModel      = errors.New("batch request body has no model")
+	ErrTooManyRequests   = errors.New("batch file exceeds the request limit")
+)
+
+// MaxBatchRequests is the number of requests a batch file may contain.
+const MaxBatchRequests = 50000
+
+// BatchRequest is a line of a batch input file with a body of type T.
+type BatchRequest[T any] struct {
+	CustomID string               `json:"custom_id"`
+	Method   string               `json:"method"`
+	URL      openai.BatchEndpoint `json:"url"`
+	Body     T                    `json:"body"`
+}
+
+// NewChatBatchRequest returns a batch line for a chat completion request.
+func NewChatBatchRequest(
+	customID string,
+	request openai.ChatCompletionRequest,
+) BatchRequest[openai.ChatCompletionRequest] {
+	return BatchRequest[openai.ChatCompletionRequest]{
+		CustomID: customID,
+		Method:   http.MethodPost,
+		URL:      openai.BatchEndpointChatCompletions,
+		Body:     request,
+	}
+}
+
+// NewEmbeddingBatchRequest returns a batch line for an embedding request.
+func NewEmbeddingBatchRequest(
+	customID string,
+	request openai.EmbeddingRequest,
+) BatchRequest[openai.EmbeddingRequest] {
+	return BatchRequest[openai.EmbeddingRequest]{
+		CustomID: customID,
+		Method:   http.MethodPost,
+		URL:      openai.BatchEndpointEmbeddings,
+		Body:     request,
+	}
+}
+
+// NewCompletionBatchRequest returns a batch line for a completion request.
+func NewCompletionBatchRequest(
+	customID string,
+	request openai.CompletionRequest,
+) BatchRequest[openai.CompletionRequest] {
+	return BatchRequest[openai.CompletionRequest]{
+		CustomID: customID,
+		Method:   http.MethodPost,
+		URL:      openai.BatchEndpointCompletions,
+		Body:     request,
+	}
+}
+
+var batchEndpoints = map[openai.BatchEndpoint]bool{
+	openai.BatchEndpointChatCompletions: true,
+	openai.BatchEndpointCompletions:     true,
+	openai.BatchEndpointEmbeddings:      true,
+}
+
+// ValidateBatchFile checks a batch input file as the batch API does: every line must be a POST
+// request with a unique custom_id and a model, and all lines must target the same endpoint.
+// It returns the errors of all invalid lines as *LineError values joined in a ValidationErrors.
+func ValidateBatchFile(r io.Reader) error {
+	var (
+		errs      ValidationErrors
+		endpoint  openai.BatchEndpoint
+		customIDs = make(map[string]bool)
+		requests  int
+	)
+	decoder := NewDecoder[BatchRequest[json.RawMessage]](r)
+	for {
+		request, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *LineError
+		if errors.As(err, &lineErr) {
+			errs = append(errs, lineErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		requests++
+		if err = validateBatchRequest(request, endpoint, customIDs); err != nil {
+			errs = append(errs, &LineError{Line: decoder.Line(), Err: err})
+		}
+		if endpoint == "" && batchEndpoints[request.URL] {
+			endpoint = request.URL
+		}
+		customIDs[request.CustomID] = true
+	}
+	if requests > MaxBatchRequests {
+		errs = append(errs, &LineError{
+			Line: decoder.Line(),
+			Err:  fmt.Errorf("%w: %d requests, limit %d", ErrTooManyRequests, requests, MaxBatchRequests),
+		})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateBatchRequest(
+	request BatchRequest[json.RawMessage],
+	endpoint openai.BatchEndpoint,
+	customIDs map[string]bool,
+) error {
+	switch {
+	case request.CustomID == "":
+		return ErrMissingCustomID
+	case customIDs[request.CustomID]:
+		return fmt.Errorf("%w: %q", ErrDuplicateCustomID, request.CustomID)
+	case request.Method != http.MethodPost:
+		return ErrInvalidMethod
+	case !batchEndpoints[request.URL]:
+		return fmt.Errorf("%w: %q", ErrInvalidEndpoint, request.URL)
+	case endpoint != "" && request.URL != endpoint:
+		return fmt.Errorf("%w: %q and %q", ErrMixedEndpoints, endpoint, request.URL)
+	}
+	var body struct {
+		Model string `json:"model"`
+	}
+	if len(request.Body) > 0 {
+		if err := json.Unmarshal(request.Body, &body); err != nil {
+			return err
+		}
+	}
+	if body.Model == "" {
+		return ErrMissingModel
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonl"
+)
+
+func TestBatchRequestEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := jsonl.NewEncoder[jsonl.BatchRequest[openai.ChatCompletionRequest]](&buf)
+	checks.NoError(t, encoder.Encode(jsonl.NewChatBatchRequest("req-1", openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+	})), "Encode error")
+
+	want := `{"custom_id":"req-1","method":"POST","url":"/v1/chat/completions",` +
+		`"body":{"model":"gpt-4o-mini","messages":[{"role":"user","content":"hi"}]}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("unexpected line %s", buf.String())
+	}
+	checks.NoError(t, jsonl.ValidateBatchFile(&buf), "ValidateBatchFile error")
+}
+
+func TestValidateBatchFile(t *testing.T) {
+	file := strings.Join([]string{
+		`{"custom_id":"1","method":"POST","url":"/v1/embeddings","body":{"model":"m","input":"a"}}`,
+		`{"custom_id":"1","method":"POST","url":"/v1/embeddings","body":{"model":"m","input":"a"}}`,
+		`{"custom_id":"2","method":"GET","url":"/v1/embeddings","body":{"model":"m"}}`,
+		`{"custom_id":"3","method":"POST","url":"/v1/chat/completions","body":{"model":"m"}}`,
+		`{"custom_id":"4","method":"POST","url":"/v1/embeddings","body":{"input":"a"}}`,
+		`{"method":"POST","url":"/v1/embeddings","body":{"model":"m"}}`,
+	}, "\n")
+
+	err := jsonl.ValidateBatchFile(strings.NewReader(file))
+	var errs jsonl.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := []error{
+		jsonl.ErrDuplicateCustomID,
+		jsonl.ErrInvalidMethod,
+		jsonl.ErrMixedEndpoints,
+		jsonl.ErrMissingModel,
+		jsonl.ErrMissingCustomID,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, target := range want {
+		if !errors.Is(errs[i], target) || errs[i].Line != i+2 {
+			t.Errorf("error %d = %v, want %v on line %d", i, errs[i], target, i+2)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+var (
+	ErrNoMessages          = errors.New("example has no messages")
+	ErrInvalidRole         = errors.New("invalid message role")
+	ErrSystemMessageOrder  = errors.New("system message must be the first message")
+	ErrMissingContent      = errors.New("message has no content")
+	ErrNoAssistantMessage  = errors.New("example has no assistant message")
+	ErrInvalidWeight       = errors.New("weight must be 0 or 1 and is only allowed on assistant messages")
+	ErrUnexpectedToolReply = errors.New("tool message does not answer a tool call of the previous assistant message")
+	ErrTooManyTokens       = errors.New("example exceeds the token limit")
+)
+
+// DefaultMaxExampleTokens is the token limit of a fine-tuning example used when
+// ValidationOptions.MaxTokens is not set. Limits depend on the model being fine-tuned.
+const DefaultMaxExampleTokens = 65536
+
+// ChatMessage is a message of a fine-tuning example.
+type ChatMessage struct {
+	openai.ChatCompletionMessage
+	// Weight set to 0 excludes an assistant message from training.
+	Weight *int `json:"weight,omitempty"`
+}
+
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	message, err := json.Marshal(m.ChatCompletionMessage)
+	if err != nil || m.Weight == nil {
+		return message, err
+	}
+	return append(message[:len(message)-1], fmt.Sprintf(`,"weight":%d}`, *m.Weight)...), nil
+}
+
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.ChatCompletionMessage); err != nil {
+		return err
+	}
+	var weight struct {
+		Weight *int `json:"weight"`
+	}
+	if err := json.Unmarshal(data, &weight); err != nil {
+		return err
+	}
+	m.Weight = weight.Weight
+	return nil
+}
+
+// ChatExample is a line of a chat fine-tuning file.
+type ChatExample struct {
+	Messages          []ChatMessage `json:"messages"`
+	Tools             []openai.Tool `json:"tools,omitempty"`
+	ParallelToolCalls *bool         `json:"parallel_tool_calls,omitempty"`
+}
+
+// ValidationOptions configures the validation of fine-tuning examples.
+type ValidationOptions struct {
+	// MaxTokens is the token limit of an example. Defaults to DefaultMaxExampleTokens.
+	MaxTokens int
+	// CountTokens counts the tokens of a text. Defaults to an estimate of 4 bytes per token;
+	// use a tokenizer of the model for exact counts.
+	CountTokens func(text string) int
+}
+
+func (o ValidationOptions) maxTokens() int {
+	if o.MaxTokens > 0 {
+		return o.MaxTokens
+	}
+	return DefaultMaxExampleTokens
+}
+
+func (o ValidationOptions) countTokens(text string) int {
+	if o.CountTokens != nil {
+		return o.CountTokens(text)
+	}
+	return (len(text) + 3) / 4
+}
+
+// tokensPerMessage is the overhead of the role and separators of a message.
+const tokensPerMessage = 3
+
+// ValidateChatExample checks an example as the fine-tuning API does when validating files.
+//
+//nolint:gocognit // one check per rule of the API
+func ValidateChatExample(example ChatExample, options ValidationOptions) error {
+	if len(example.Messages) == 0 {
+		return ErrNoMessages
+	}
+
+	var (
+		tokens         int
+		hasAssistant   bool
+		pendingToolIDs map[string]bool
+	)
+	for i, message := range example.Messages {
+		switch message.Role {
+		case openai.ChatMessageRoleSystem, openai.ChatMessageRoleDeveloper:
+			if i != 0 {
+				return fmt.Errorf("message %d: %w", i, ErrSystemMessageOrder)
+			}
+		case openai.ChatMessageRoleUser, openai.ChatMessageRoleFunction:
+		case openai.ChatMessageRoleAssistant:
+			hasAssistant = true
+		case openai.ChatMessageRoleTool:
+			if !pendingToolIDs[message.ToolCallID] {
+				return fmt.Errorf("message %d: %w", i, ErrUnexpectedToolReply)
+			}
+			delete(pendingToolIDs, message.ToolCallID)
+		default:
+			return fmt.Errorf("message %d: %w: %q", i, ErrInvalidRole, message.Role)
+		}
+
+		if message.Role != openai.ChatMessageRoleTool {
+			pendingToolIDs = nil
+		}
+		if message.Role == openai.ChatMessageRoleAssistant && len(message.ToolCalls) > 0 {
+			pendingToolIDs = make(map[string]bool, len(message.ToolCalls))
+			for _, toolCall := range message.ToolCalls {
+				pendingToolIDs[toolCall.ID] = true
+			}
+		}
+
+		if message.Weight != nil &&
+			(message.Role != openai.ChatMessageRoleAssistant || (*message.Weight != 0 && *message.Weight != 1)) {
+			return fmt.Errorf("message %d: %w", i, ErrInvalidWeight)
+		}
+
+		hasContent := message.Content != "" || len(message.MultiContent) > 0
+		if message.Role == openai.ChatMessageRoleAssistant {
+			hasContent = hasContent || len(message.ToolCalls) > 0 || message.FunctionCall != nil
+		}
+		if !hasContent {
+			return fmt.Errorf("message %d: %w", i, ErrMissingContent)
+		}
+
+		tokens += tokensPerMessage + options.countTokens(message.Content)
+		for _, part := range message.MultiContent {
+			if text, ok := part.(openai.TextPart); ok {
+				tokens += options.countTokens(text.Text)
+			}
+		}
+		for _, toolCall := range message.ToolCalls {
+			tokens += options.countTokens(toolCall.Function.Name) + options.countTokens(toolCall.Function.Arguments)
+		}
+	}
+	if !hasAssistant {
+		return ErrNoAssistantMessage
+	}
+	if tokens > options.maxTokens() {
+		return fmt.Errorf("%w: about %d tokens, limit %d", ErrTooManyTokens, tokens, options.maxTokens())
+	}
+	return nil
+}
+
+// ValidateChatFile validates every example of a chat fine-tuning file. It returns the errors
+// of all invalid lines as *LineError values joined in a ValidationErrors.
+func ValidateChatFile(r io.Reader, options ValidationOptions) error {
+	var errs ValidationErrors
+	decoder := NewDecoder[ChatExample](r)
+	for {
+		example, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *LineError
+		if errors.As(err, &lineErr) {
+			errs = append(errs, lineErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = ValidateChatExample(example, options); err != nil {
+			errs = append(errs, &LineError{Line: decoder.Line(), Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidationErrors lists the invalid lines of a file.
+type ValidationErrors []*LineError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more errors)", e[0], len(e)-1)
+}
+
+// Is reports whether any line failed with target.
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonl"
+)
+
+func message(role, content string) jsonl.ChatMessage {
+	return jsonl.ChatMessage{ChatCompletionMessage: openai.ChatCompletionMessage{Role: role, Content: content}}
+}
+
+func TestChatMessageWeight(t *testing.T) {
+	weight := 0
+	msg := message(openai.ChatMessageRoleAssistant, "hi")
+	msg.Weight = &weight
+	data, err := json.Marshal(msg)
+	checks.NoError(t, err, "Marshal error")
+	if string(data) != `{"role":"assistant","content":"hi","weight":0}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var decoded jsonl.ChatMessage
+	checks.NoError(t, json.Unmarshal(data, &decoded), "Unmarshal error")
+	if decoded.Content != "hi" || decoded.Weight == nil || *decoded.Weight != 0 {
+		t.Errorf("unexpected message %+v", decoded)
+	}
+}
+
+func TestValidateChatExample(t *testing.T) {
+	weight := 2
+	weighted := message(openai.ChatMessageRoleAssistant, "hi")
+	weighted.Weight = &weight
+	toolCall := jsonl.ChatMessage{ChatCompletionMessage: openai.ChatCompletionMessage{
+		Role:      openai.ChatMessageRoleAssistant,
+		ToolCalls: []openai.ToolCall{{ID: "call_1", Type: openai.ToolTypeFunction}},
+	}}
+	toolReply := func(id string) jsonl.ChatMessage {
+		msg := message(openai.ChatMessageRoleTool, "42")
+		msg.ToolCallID = id
+		return msg
+	}
+
+	tests := []struct {
+		name     string
+		messages []jsonl.ChatMessage
+		options  jsonl.ValidationOptions
+		want     error
+	}{
+		{"valid", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleSystem, "be nice"),
+			message(openai.ChatMessageRoleUser, "hello"),
+			toolCall, toolReply("call_1"),
+			message(openai.ChatMessageRoleAssistant, "hi"),
+		}, jsonl.ValidationOptions{}, nil},
+		{"empty", nil, jsonl.ValidationOptions{}, jsonl.ErrNoMessages},
+		{"no assistant", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, "hello"),
+		}, jsonl.ValidationOptions{}, jsonl.ErrNoAssistantMessage},
+		{"late system", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, "hello"),
+			message(openai.ChatMessageRoleSystem, "be nice"),
+		}, jsonl.ValidationOptions{}, jsonl.ErrSystemMessageOrder},
+		{"bad role", []jsonl.ChatMessage{
+			message("bot", "hello"),
+		}, jsonl.ValidationOptions{}, jsonl.ErrInvalidRole},
+		{"no content", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, ""),
+		}, jsonl.ValidationOptions{}, jsonl.ErrMissingContent},
+		{"bad weight", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, "hello"), weighted,
+		}, jsonl.ValidationOptions{}, jsonl.ErrInvalidWeight},
+		{"orphan tool reply", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, "hello"), toolCall, toolReply("call_2"),
+		}, jsonl.ValidationOptions{}, jsonl.ErrUnexpectedToolReply},
+		{"too long", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, strings.Repeat("a", 100)),
+			message(openai.ChatMessageRoleAssistant, "hi"),
+		}, jsonl.ValidationOptions{MaxTokens: 20}, jsonl.ErrTooManyTokens},
+		{"custom counter", []jsonl.ChatMessage{
+			message(openai.ChatMessageRoleUser, strings.Repeat("a", 100)),
+			message(openai.ChatMessageRoleAssistant, "hi"),
+		}, jsonl.ValidationOptions{MaxTokens: 20, CountTokens: func(string) int { return 1 }}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jsonl.ValidateChatExample(jsonl.ChatExample{Messages: tt.messages}, tt.options)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("ValidateChatExample() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateChatFile(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := jsonl.NewEncoder[jsonl.ChatExample](&buf)
+	checks.NoError(t, encoder.Encode(jsonl.ChatExample{Messages: []jsonl.ChatMessage{
+		message(openai.ChatMessageRoleUser, "hello"),
+		message(openai.ChatMessageRoleAssistant, "hi"),
+	}}), "Encode error")
+	checks.NoError(t, encoder.Encode(jsonl.ChatExample{Messages: []jsonl.ChatMessage{
+		message(openai.ChatMessageRoleUser, "hello"),
+	}}), "Encode error")
+	buf.WriteString("not json\n")
+
+	err := jsonl.ValidateChatFile(&buf, jsonl.ValidationOptions{})
+	var errs jsonl.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 line errors, got %v", err)
+	}
+	if errs[0].Line != 2 || errs[1].Line != 3 {
+		t.Errorf("unexpected lines %d and %d", errs[0].Line, errs[1].Line)
+	}
+	checks.ErrorIs(t, err, jsonl.ErrNoAssistantMessage, "expected a missing assistant message")
+}
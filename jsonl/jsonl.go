@@ -0,0 +1,112 @@
+// Package jsonl reads and writes the JSON Lines files used by the batch and fine-tuning APIs,
+// and validates them the way the API does before they are uploaded.
+//
+//	var buf bytes.Buffer
+//	encoder := jsonl.NewEncoder[jsonl.ChatExample](&buf)
+//	for _, example := range examples {
+//		if err := jsonl.ValidateChatExample(example, jsonl.ValidationOptions{}); err != nil {
+//			return err
+//		}
+//		if err := encoder.Encode(example); err != nil {
+//			return err
+//		}
+//	}
+//	file, err := client.CreateFileBytes(ctx, openai.FileBytesRequest{
+//		Name:    "train.jsonl",
+//		Bytes:   buf.Bytes(),
+//		Purpose: openai.PurposeFineTune,
+//	})
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize bounds the size of a line read by a Decoder. Lines of fine-tuning examples with
+// images can be large, but the API rejects files with lines over this size anyway.
+const maxLineSize = 64 << 20
+
+// LineError reports an error on a line of a file. Lines are numbered from 1.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Encoder writes values of type T as JSON lines.
+type Encoder[T any] struct {
+	w     io.Writer
+	lines int
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder[T any](w io.Writer) *Encoder[T] {
+	return &Encoder[T]{w: w}
+}
+
+// Encode writes v on its own line.
+func (e *Encoder[T]) Encode(v T) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return &LineError{Line: e.lines + 1, Err: err}
+	}
+	if _, err = e.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	e.lines++
+	return nil
+}
+
+// Lines returns the number of lines written.
+func (e *Encoder[T]) Lines() int {
+	return e.lines
+}
+
+// Decoder reads values of type T from JSON lines. Blank lines are skipped.
+type Decoder[T any] struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder[T any](r io.Reader) *Decoder[T] {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	return &Decoder[T]{scanner: scanner}
+}
+
+// Decode returns the value of the next line, or io.EOF at the end of the input. Malformed lines
+// are reported as *LineError; decoding can continue with the next line.
+func (d *Decoder[T]) Decode() (v T, err error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err = json.Unmarshal(line, &v); err != nil {
+			return v, &LineError{Line: d.line, Err: err}
+		}
+		return v, nil
+	}
+	if err = d.scanner.Err(); err != nil {
+		return v, err
+	}
+	return v, io.EOF
+}
+
+// Line returns the number of the line last decoded.
+func (d *Decoder[T]) Line() int {
+	return d.line
+}
@@ -0,0 +1,53 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonl"
+)
+
+type line struct {
+	Text string `json:"text"`
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := jsonl.NewEncoder[line](&buf)
+	for _, text := range []string{"a", "multi\nline"} {
+		checks.NoError(t, encoder.Encode(line{Text: text}), "Encode error")
+	}
+	if encoder.Lines() != 2 {
+		t.Errorf("expected 2 lines, got %d", encoder.Lines())
+	}
+	if want := "{\"text\":\"a\"}\n{\"text\":\"multi\\nline\"}\n"; buf.String() != want {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	decoder := jsonl.NewDecoder[line](strings.NewReader(buf.String() + "\n{broken\n" + `{"text":"b"}`))
+	var texts []string
+	var lineErrs []int
+	for {
+		v, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var lineErr *jsonl.LineError
+		if errors.As(err, &lineErr) {
+			lineErrs = append(lineErrs, lineErr.Line)
+			continue
+		}
+		checks.NoError(t, err, "Decode error")
+		texts = append(texts, v.Text)
+	}
+	if strings.Join(texts, "|") != "a|multi\nline|b" {
+		t.Errorf("unexpected values %q", texts)
+	}
+	if len(lineErrs) != 1 || lineErrs[0] != 4 {
+		t.Errorf("expected an error on line 4, got %v", lineErrs)
+	}
+}
@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+const defaultPingTimeout = 15 * time.Second
+
+// KeepAlive enables health checks of idle connections, so that connections silently dropped
+// by NATs or load balancers are detected instead of failing the next stream with an
+// unexpected EOF.
+//
+// HTTP/2 connections are sent a PING frame after ReadIdleTimeout without any frame received
+// and are closed if no response arrives within PingTimeout. This requires Go 1.24 or newer;
+// older toolchains fall back to TCP keep-alive probes every ReadIdleTimeout, which also cover
+// HTTP/1.1 connections.
+//
+// KeepAlive applies when HTTPClient is an *http.Client using an *http.Transport, or the
+// default transport. The transport is cloned, so it can be shared with other clients.
+type KeepAlive struct {
+	// ReadIdleTimeout is the time without received frames after which a connection is checked.
+	ReadIdleTimeout time.Duration
+	// PingTimeout is the time to wait for the PING response. Defaults to 15 seconds.
+	PingTimeout time.Duration
+}
+
+func (k *KeepAlive) pingTimeout() time.Duration {
+	if k.PingTimeout > 0 {
+		return k.PingTimeout
+	}
+	return defaultPingTimeout
+}
+
+// withKeepAlive returns doer with a transport configured for k, or doer itself if its
+// transport cannot be configured.
+func withKeepAlive(doer HTTPDoer, k *KeepAlive) HTTPDoer {
+	if k.ReadIdleTimeout <= 0 {
+		return doer
+	}
+	configured, _ := configureTransport(doer, func(transport *http.Transport) {
+		transport.DialContext = keepAliveDialer(transport.DialContext, k.ReadIdleTimeout)
+		configureHTTP2Pings(transport, k)
+	})
+	return configured
+}
+
+// keepAliveDialer enables TCP keep-alive probes every period on the connections dialed by dial.
+func keepAliveDialer(
+	dial func(ctx context.Context, network, addr string) (net.Conn, error),
+	period time.Duration,
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.SetKeepAlive(true)
+			_ = tcpConn.SetKeepAlivePeriod(period)
+		}
+		return conn, nil
+	}
+}
@@ -0,0 +1,15 @@
+//go:build go1.24
+
+package openai
+
+import "net/http"
+
+func configureHTTP2Pings(transport *http.Transport, k *KeepAlive) {
+	http2 := http.HTTP2Config{}
+	if transport.HTTP2 != nil {
+		http2 = *transport.HTTP2
+	}
+	http2.SendPingTimeout = k.ReadIdleTimeout
+	http2.PingTimeout = k.pingTimeout()
+	transport.HTTP2 = &http2
+}
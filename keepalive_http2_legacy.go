@@ -0,0 +1,9 @@
+//go:build !go1.24
+
+package openai
+
+import "net/http"
+
+// configureHTTP2Pings is a no-op before Go 1.24, which has no HTTP/2 settings in
+// net/http; connections are only checked by TCP keep-alive probes.
+func configureHTTP2Pings(*http.Transport, *KeepAlive) {}
@@ -0,0 +1,77 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestKeepAliveHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("expected HTTP/2 request, got %s", r.Proto)
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	httpClient := ts.Client()
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", httpClient.Transport)
+	}
+	var dials int
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		return dialer.DialContext(ctx, network, addr)
+	}
+	config := openai.DefaultConfig("")
+	config.BaseURL = ts.URL + "/v1"
+	config.HTTPClient = httpClient
+	config.KeepAlive = &openai.KeepAlive{ReadIdleTimeout: time.Second, PingTimeout: time.Second}
+	client := openai.NewClientWithConfig(config)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.ListModels(context.Background())
+		checks.NoError(t, err, "ListModels error")
+	}
+	if dials != 1 {
+		t.Errorf("expected one reused connection from the configured dialer, got %d dials", dials)
+	}
+	if httpClient.Transport != transport {
+		t.Error("KeepAlive should not modify the configured client")
+	}
+}
+
+type countingDoer struct {
+	calls int
+}
+
+func (d *countingDoer) Do(*http.Request) (*http.Response, error) {
+	d.calls++
+	return nil, fmt.Errorf("no network")
+}
+
+func TestKeepAliveIgnoresCustomDoer(t *testing.T) {
+	doer := &countingDoer{}
+	config := openai.DefaultConfig("")
+	config.HTTPClient = doer
+	config.KeepAlive = &openai.KeepAlive{ReadIdleTimeout: time.Second}
+	client := openai.NewClientWithConfig(config)
+
+	_, err := client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	if doer.calls != 1 {
+		t.Errorf("expected the custom doer to be used, got %d calls", doer.calls)
+	}
+}
@@ -0,0 +1,88 @@
+package openai
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Tokenizer encodes text into the token IDs of a model. Token IDs differ between the encodings
+// of models, e.g. cl100k_base for gpt-4 and o200k_base for gpt-4o, so the tokenizer must match
+// the model of the request. Plug in a tiktoken implementation or the tokenizer of a self-hosted
+// model.
+type Tokenizer interface {
+	Encode(text string) ([]int, error)
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface.
+type TokenizerFunc func(text string) ([]int, error)
+
+func (f TokenizerFunc) Encode(text string) ([]int, error) {
+	return f(text)
+}
+
+// LogitBiasBuilder builds the logit_bias map of a request from texts rather than token IDs:
+//
+//	bias, err := openai.NewLogitBias(tokenizer).
+//		Ban("Paris").
+//		Word("London", 5).
+//		Build()
+//
+// The first error of the tokenizer or of a bias out of range is returned by Build.
+type LogitBiasBuilder struct {
+	tokenizer Tokenizer
+	bias      map[string]int
+	err       error
+}
+
+// NewLogitBias starts building a logit bias with the tokenizer of the model of the request.
+func NewLogitBias(tokenizer Tokenizer) *LogitBiasBuilder {
+	return &LogitBiasBuilder{tokenizer: tokenizer, bias: make(map[string]int)}
+}
+
+// Text sets the bias, from -100 to 100, of every token of text.
+func (b *LogitBiasBuilder) Text(text string, bias int) *LogitBiasBuilder {
+	if b.err != nil {
+		return b
+	}
+	if bias < -100 || bias > 100 {
+		b.err = fmt.Errorf("%w: logit_bias of %q must be between -100 and 100", ErrParameterOutOfRange, text)
+		return b
+	}
+	tokens, err := b.tokenizer.Encode(text)
+	if err != nil {
+		b.err = fmt.Errorf("encoding %q: %w", text, err)
+		return b
+	}
+	if len(tokens) == 0 {
+		b.err = fmt.Errorf("%w: %q has no tokens", ErrLogitBiasTokenInvalid, text)
+		return b
+	}
+	for _, token := range tokens {
+		b.bias[strconv.Itoa(token)] = bias
+	}
+	return b
+}
+
+// Word sets the bias of the tokens of word both at the start of a text and after a space, which
+// are different tokens in most encodings.
+func (b *LogitBiasBuilder) Word(word string, bias int) *LogitBiasBuilder {
+	return b.Text(word, bias).Text(" "+word, bias)
+}
+
+// Ban prevents the tokens of word from being generated, at the start of a text and after a space.
+func (b *LogitBiasBuilder) Ban(word string) *LogitBiasBuilder {
+	return b.Word(word, -100)
+}
+
+// Build returns the logit_bias map, keyed by token ID, for ChatCompletionRequest.LogitBias or
+// CompletionRequest.LogitBias.
+func (b *LogitBiasBuilder) Build() (map[string]int, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	bias := make(map[string]int, len(b.bias))
+	for token, value := range b.bias {
+		bias[token] = value
+	}
+	return bias, nil
+}
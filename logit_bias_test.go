@@ -0,0 +1,53 @@
+package openai_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// testTokenizer encodes each byte of a text as a token whose ID is the byte value, with a space
+// and the following letter merged into one token offset by 1000.
+var testTokenizer = openai.TokenizerFunc(func(text string) ([]int, error) {
+	if strings.Contains(text, "\x00") {
+		return nil, errors.New("invalid text")
+	}
+	var tokens []int
+	for i := 0; i < len(text); i++ {
+		if text[i] == ' ' && i+1 < len(text) {
+			i++
+			tokens = append(tokens, 1000+int(text[i]))
+			continue
+		}
+		tokens = append(tokens, int(text[i]))
+	}
+	return tokens, nil
+})
+
+func TestLogitBiasBuilder(t *testing.T) {
+	bias, err := openai.NewLogitBias(testTokenizer).
+		Ban("a").
+		Text("b", 5).
+		Build()
+	checks.NoError(t, err, "Build error")
+	expected := map[string]int{"97": -100, "1097": -100, "98": 5}
+	if !reflect.DeepEqual(bias, expected) {
+		t.Errorf("expected %v, got %v", expected, bias)
+	}
+
+	_, err = openai.NewLogitBias(testTokenizer).Word("a", 101).Build()
+	checks.ErrorIs(t, err, openai.ErrParameterOutOfRange, "bias out of range should fail")
+
+	_, err = openai.NewLogitBias(testTokenizer).Text("", 1).Build()
+	checks.ErrorIs(t, err, openai.ErrLogitBiasTokenInvalid, "text without tokens should fail")
+
+	_, err = openai.NewLogitBias(testTokenizer).Text("\x00", 1).Ban("a").Build()
+	checks.HasError(t, err, "tokenizer error should be returned")
+
+	request := openai.CompletionRequest{LogitBias: bias}
+	checks.NoError(t, request.Validate(), "built logit bias should be valid")
+}
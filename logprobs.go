@@ -0,0 +1,114 @@
+package openai
+
+import "math"
+
+// Probability returns the linear probability of the token.
+func (l LogProb) Probability() float64 {
+	return math.Exp(l.LogProb)
+}
+
+// Probability returns the linear probability of the alternative token.
+func (t TopLogProbs) Probability() float64 {
+	return math.Exp(t.LogProb)
+}
+
+// SequenceLogProb returns the sum of the log probabilities of all content tokens,
+// i.e. the log probability of the whole generated sequence.
+func (l *LogProbs) SequenceLogProb() float64 {
+	if l == nil {
+		return 0
+	}
+	var sum float64
+	for _, token := range l.Content {
+		sum += token.LogProb
+	}
+	return sum
+}
+
+// SequenceProbability returns the joint probability of the generated sequence.
+func (l *LogProbs) SequenceProbability() float64 {
+	return math.Exp(l.SequenceLogProb())
+}
+
+// Perplexity returns exp of the negative mean token log probability.
+// It returns 0 when there are no tokens.
+func (l *LogProbs) Perplexity() float64 {
+	if l == nil || len(l.Content) == 0 {
+		return 0
+	}
+	return math.Exp(-l.SequenceLogProb() / float64(len(l.Content)))
+}
+
+// TopLogProbs returns the most likely alternatives for every content token position.
+// The outer slice is indexed by token position.
+func (l *LogProbs) TopLogProbs() [][]TopLogProbs {
+	if l == nil {
+		return nil
+	}
+	top := make([][]TopLogProbs, len(l.Content))
+	for i, token := range l.Content {
+		top[i] = token.TopLogProbs
+	}
+	return top
+}
+
+// Append accumulates the logprobs of a streaming delta, so a stream can be
+// analyzed with the same helpers as a non-streaming response.
+func (l *LogProbs) Append(delta *ChatCompletionStreamChoiceLogprobs) {
+	if delta == nil {
+		return
+	}
+	l.Content = append(l.Content, delta.ToLogProbs().Content...)
+}
+
+// ToLogProbs converts streaming logprobs into the non-streaming representation.
+func (l *ChatCompletionStreamChoiceLogprobs) ToLogProbs() LogProbs {
+	if l == nil {
+		return LogProbs{}
+	}
+	content := make([]LogProb, len(l.Content))
+	for i, token := range l.Content {
+		top := make([]TopLogProbs, len(token.TopLogprobs))
+		for j, alt := range token.TopLogprobs {
+			top[j] = TopLogProbs{
+				Token:   alt.Token,
+				LogProb: alt.Logprob,
+				Bytes:   int64sToBytes(alt.Bytes),
+			}
+		}
+		content[i] = LogProb{
+			Token:       token.Token,
+			LogProb:     token.Logprob,
+			Bytes:       int64sToBytes(token.Bytes),
+			TopLogProbs: top,
+		}
+	}
+	return LogProbs{Content: content}
+}
+
+// LogProbs returns the logprobs of the choice with the given index,
+// or nil if the choice does not exist or logprobs were not requested.
+func (r ChatCompletionResponse) LogProbs(choiceIndex int) *LogProbs {
+	for _, choice := range r.Choices {
+		if choice.Index == choiceIndex {
+			return choice.LogProbs
+		}
+	}
+	return nil
+}
+
+// TopLogProbs returns the top alternatives per token position for the choice with the given index.
+func (r ChatCompletionResponse) TopLogProbs(choiceIndex int) [][]TopLogProbs {
+	return r.LogProbs(choiceIndex).TopLogProbs()
+}
+
+func int64sToBytes(in []int64) []byte {
+	if in == nil {
+		return nil
+	}
+	out := make([]byte, len(in))
+	for i, b := range in {
+		out[i] = byte(b)
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestLogProbsHelpers(t *testing.T) {
+	logProbs := &openai.LogProbs{
+		Content: []openai.LogProb{
+			{
+				Token:   "Hello",
+				LogProb: math.Log(0.5),
+				TopLogProbs: []openai.TopLogProbs{
+					{Token: "Hello", LogProb: math.Log(0.5)},
+					{Token: "Hi", LogProb: math.Log(0.25)},
+				},
+			},
+			{
+				Token:   "!",
+				LogProb: math.Log(0.25),
+			},
+		},
+	}
+
+	if got := logProbs.SequenceProbability(); !almostEqual(got, 0.125) {
+		t.Errorf("unexpected sequence probability: %v", got)
+	}
+	if got := logProbs.Perplexity(); !almostEqual(got, math.Sqrt(8)) {
+		t.Errorf("unexpected perplexity: %v", got)
+	}
+	if got := logProbs.Content[0].TopLogProbs[1].Probability(); !almostEqual(got, 0.25) {
+		t.Errorf("unexpected top logprob probability: %v", got)
+	}
+
+	top := logProbs.TopLogProbs()
+	if len(top) != 2 || len(top[0]) != 2 || top[0][1].Token != "Hi" {
+		t.Errorf("unexpected top logprobs: %+v", top)
+	}
+
+	var empty *openai.LogProbs
+	if empty.Perplexity() != 0 || empty.SequenceLogProb() != 0 || empty.TopLogProbs() != nil {
+		t.Error("nil logprobs should return zero values")
+	}
+}
+
+func TestChatCompletionResponseLogProbs(t *testing.T) {
+	resp := openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{
+			{Index: 0},
+			{Index: 1, LogProbs: &openai.LogProbs{Content: []openai.LogProb{{Token: "a"}}}},
+		},
+	}
+	if resp.LogProbs(0) != nil {
+		t.Error("expected nil logprobs for choice without logprobs")
+	}
+	if got := resp.TopLogProbs(1); len(got) != 1 {
+		t.Errorf("unexpected top logprobs: %+v", got)
+	}
+	if resp.LogProbs(5) != nil {
+		t.Error("expected nil logprobs for unknown choice")
+	}
+}
+
+func TestLogProbsAppendStreamDelta(t *testing.T) {
+	var accumulated openai.LogProbs
+	deltas := []string{
+		`{"content":[{"token":"Hel","logprob":-0.1,"bytes":[72,101,108],"top_logprobs":[{"token":"Hel","logprob":-0.1,"bytes":[72,101,108]}]}]}`,
+		`{"content":[{"token":"lo","logprob":-0.2,"bytes":[108,111],"top_logprobs":[]}]}`,
+	}
+	for _, raw := range deltas {
+		var delta openai.ChatCompletionStreamChoiceLogprobs
+		checks.NoError(t, json.Unmarshal([]byte(raw), &delta), "unmarshal delta")
+		accumulated.Append(&delta)
+	}
+	accumulated.Append(nil)
+
+	if len(accumulated.Content) != 2 {
+		t.Fatalf("unexpected token count: %d", len(accumulated.Content))
+	}
+	if string(accumulated.Content[0].Bytes) != "Hel" || string(accumulated.Content[0].TopLogProbs[0].Bytes) != "Hel" {
+		t.Errorf("unexpected bytes: %v", accumulated.Content[0])
+	}
+	if got := accumulated.SequenceLogProb(); !almostEqual(got, -0.3) {
+		t.Errorf("unexpected sequence logprob: %v", got)
+	}
+}
+
+func TestChatCompletionRequestSendsLogProbsAndExtraBody(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		checks.NoError(t, err, "read body")
+		var fields map[string]any
+		checks.NoError(t, json.Unmarshal(body, &fields), "unmarshal body")
+		if fields["logprobs"] != true || fields["top_logprobs"] != float64(2) {
+			t.Errorf("logprobs fields not sent: %s", body)
+		}
+		if fields["custom_param"] != "value" {
+			t.Errorf("extra body not sent: %s", body)
+		}
+		resBytes, _ := json.Marshal(openai.ChatCompletionResponse{})
+		_, _ = w.Write(resBytes)
+	})
+
+	_, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		Model:       openai.GPT4o,
+		Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		LogProbs:    true,
+		TopLogProbs: 2,
+		ExtraBody:   map[string]any{"custom_param": "value"},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+}
+
+func TestChatCompletionRequestExtraBodyKeepsLargeIntegers(t *testing.T) {
+	seed := 1<<60 + 1
+	data, err := json.Marshal(openai.ChatCompletionRequest{
+		Model:     openai.GPT4o,
+		Seed:      &seed,
+		ExtraBody: map[string]any{"custom_param": "value", "big": int64(1<<62 + 1)},
+	})
+	checks.NoError(t, err, "Marshal error")
+	for _, want := range []string{`"seed":1152921504606846977`, `"big":4611686018427387905`, `"custom_param":"value"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const sessionIDHeader = "Mcp-Session-Id"
+
+// httpTransport implements the streamable HTTP transport: every message is POSTed to the
+// endpoint, and responses come back as JSON or as an event stream.
+type httpTransport struct {
+	endpoint   string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+func (t *httpTransport) post(ctx context.Context, message rpcRequest) (*http.Response, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set(sessionIDHeader, t.sessionID)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("mcp: %s %s: %s", message.Method, resp.Status, bytes.TrimSpace(data))
+	}
+	if sessionID := resp.Header.Get(sessionIDHeader); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) call(ctx context.Context, request rpcRequest) (*rpcResponse, error) {
+	resp, err := t.post(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var response rpcResponse
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+	return readEventStreamResponse(resp.Body, *request.ID)
+}
+
+// readEventStreamResponse returns the response with the given id from an event stream,
+// skipping the notifications and requests the server sends before it.
+func readEventStreamResponse(r io.Reader, id int64) (*rpcResponse, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxMessageSize)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		case line != "":
+			continue
+		}
+		var message struct {
+			rpcResponse
+			Method string `json:"method"`
+		}
+		if json.Unmarshal([]byte(data.String()), &message) == nil &&
+			message.Method == "" && message.ID != nil && *message.ID == id {
+			return &message.rpcResponse, nil
+		}
+		data.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrClosed
+}
+
+func (t *httpTransport) notify(ctx context.Context, notification rpcRequest) error {
+	resp, err := t.post(ctx, notification)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// close ends the session, if the server assigned one.
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(sessionIDHeader, sessionID)
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// NewHTTPClient connects to the server at endpoint with the streamable HTTP transport.
+// httpClient defaults to http.DefaultClient.
+func NewHTTPClient(ctx context.Context, endpoint string, httpClient *http.Client) (*Client, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return connect(ctx, &httpTransport{endpoint: endpoint, httpClient: httpClient})
+}
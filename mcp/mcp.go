@@ -0,0 +1,221 @@
+// Package mcp connects to Model Context Protocol servers and exposes their tools to chat
+// completions. It implements the client side of the stdio and streamable HTTP transports,
+// see https://modelcontextprotocol.io/specification.
+//
+//	server, err := mcp.NewStdioClient(ctx, exec.Command("npx", "-y", "@modelcontextprotocol/server-everything"))
+//	if err != nil {
+//		return err
+//	}
+//	defer server.Close()
+//	registry := openai.NewToolRegistry()
+//	if err := server.RegisterTools(ctx, registry); err != nil {
+//		return err
+//	}
+//	agent := &openai.Agent{Client: client, Tools: registry, Request: request}
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ProtocolVersion is the protocol version requested by clients.
+const ProtocolVersion = "2025-03-26"
+
+var (
+	ErrClosed    = errors.New("mcp: connection closed")
+	ErrToolError = errors.New("mcp: tool returned an error")
+)
+
+// RPCError is an error response of the server.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp: error %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	ID     *int64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// transport sends JSON-RPC messages to a server. call returns the response to a request;
+// notify sends a message that has no response.
+type transport interface {
+	call(ctx context.Context, request rpcRequest) (*rpcResponse, error)
+	notify(ctx context.Context, notification rpcRequest) error
+	close() error
+}
+
+// Implementation identifies a client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool is a tool offered by a server.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// FunctionDefinition converts the tool for use in chat completion requests.
+func (t Tool) FunctionDefinition() openai.FunctionDefinition {
+	parameters := t.InputSchema
+	if len(parameters) == 0 {
+		parameters = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+	return openai.FunctionDefinition{Name: t.Name, Description: t.Description, Parameters: parameters}
+}
+
+// Content is an item of the result of a tool call. Text is set for text content; other
+// content types are kept in Data and MIMEType.
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MIMEType string `json:"mimeType,omitempty"`
+}
+
+// CallToolResult is the result of a tool call.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Text returns the text content of the result.
+func (r *CallToolResult) Text() string {
+	texts := make([]string, 0, len(r.Content))
+	for _, content := range r.Content {
+		if content.Type == "text" {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Client is a connection to an MCP server.
+type Client struct {
+	transport transport
+	nextID    int64
+	// ServerInfo identifies the server, as reported during initialization.
+	ServerInfo Implementation
+}
+
+func connect(ctx context.Context, t transport) (*Client, error) {
+	c := &Client{transport: t}
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+	}
+	err := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      Implementation{Name: "go-openai", Version: "1"},
+	}, &result)
+	if err == nil {
+		err = t.notify(ctx, rpcRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+	}
+	if err != nil {
+		t.close()
+		return nil, err
+	}
+	c.ServerInfo = result.ServerInfo
+	return c, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	response, err := c.transport.call(ctx, rpcRequest{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// ListTools returns all the tools of the server.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	params := map[string]any{}
+	for {
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		params = map[string]any{"cursor": page.NextCursor}
+	}
+}
+
+// CallTool calls a tool with its JSON arguments.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	var result CallToolResult
+	err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RegisterTools registers all the tools of the server in registry. Calls of the tools are
+// dispatched to the server, and their text content is returned to the model; results flagged
+// as errors are reported as ErrToolError.
+func (c *Client) RegisterTools(ctx context.Context, registry *openai.ToolRegistry) error {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tool := range tools {
+		name := tool.Name
+		err = registry.Register(tool.FunctionDefinition(), func(ctx context.Context, arguments string) (string, error) {
+			result, callErr := c.CallTool(ctx, name, json.RawMessage(arguments))
+			if callErr != nil {
+				return "", callErr
+			}
+			if result.IsError {
+				return "", fmt.Errorf("%w: %s", ErrToolError, result.Text())
+			}
+			return result.Text(), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection, and stops the server process of stdio clients.
+func (c *Client) Close() error {
+	return c.transport.close()
+}
@@ -0,0 +1,182 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/mcp"
+)
+
+type fakeMessage struct {
+	ID     *int64          `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// fakeServer answers the requests of the tests, or returns nil for notifications.
+func fakeServer(t *testing.T, message fakeMessage) any {
+	if message.ID == nil {
+		return nil
+	}
+	var result any
+	switch message.Method {
+	case "initialize":
+		result = map[string]any{
+			"protocolVersion": mcp.ProtocolVersion,
+			"serverInfo":      map[string]string{"name": "fake", "version": "0.1"},
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+		}
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(message.Params, &params)
+		if params.Cursor == "" {
+			result = map[string]any{"tools": []map[string]any{{
+				"name":        "add",
+				"description": "Add two numbers",
+				"inputSchema": map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "number"}}},
+			}}, "nextCursor": "page2"}
+		} else {
+			result = map[string]any{"tools": []map[string]any{{"name": "fail"}}}
+		}
+	case "tools/call":
+		var params struct {
+			Name      string `json:"name"`
+			Arguments struct{ A, B int }
+		}
+		if err := json.Unmarshal(message.Params, &params); err != nil {
+			t.Error(err)
+		}
+		if params.Name == "fail" {
+			result = map[string]any{"content": []map[string]string{{"type": "text", "text": "boom"}}, "isError": true}
+		} else {
+			result = map[string]any{"content": []map[string]string{
+				{"type": "text", "text": fmt.Sprint(params.Arguments.A + params.Arguments.B)},
+			}}
+		}
+	default:
+		return map[string]any{"jsonrpc": "2.0", "id": message.ID, "error": map[string]any{"code": -32601, "message": "not found"}}
+	}
+	return map[string]any{"jsonrpc": "2.0", "id": message.ID, "result": result}
+}
+
+func checkTools(t *testing.T, client *mcp.Client) {
+	t.Helper()
+	if client.ServerInfo.Name != "fake" {
+		t.Errorf("unexpected server info %+v", client.ServerInfo)
+	}
+
+	registry := openai.NewToolRegistry()
+	checks.NoError(t, client.RegisterTools(context.Background(), registry), "RegisterTools error")
+	tools := registry.Tools()
+	if len(tools) != 2 || tools[0].Function.Name != "add" || tools[0].Function.Description != "Add two numbers" {
+		t.Fatalf("unexpected tools %+v", tools)
+	}
+	schema, _ := json.Marshal(tools[1].Function.Parameters)
+	if string(schema) != `{"type":"object","properties":{}}` {
+		t.Errorf("unexpected default schema %s", schema)
+	}
+
+	output, err := registry.Call(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "add", Arguments: `{"a":1,"b":2}`},
+	})
+	checks.NoError(t, err, "Call error")
+	if output != "3" {
+		t.Errorf("unexpected output %q", output)
+	}
+	_, err = registry.Call(context.Background(), openai.ToolCall{Function: openai.FunctionCall{Name: "fail"}})
+	checks.ErrorIs(t, err, mcp.ErrToolError, "expected a tool error")
+
+	_, err = client.CallTool(context.Background(), "", nil)
+	checks.NoError(t, err, "CallTool error")
+}
+
+func TestStreamClient(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	go func() {
+		defer serverWriter.Close()
+		scanner := bufio.NewScanner(serverReader)
+		encoder := json.NewEncoder(serverWriter)
+		pinged := false
+		for scanner.Scan() {
+			var message fakeMessage
+			if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+				t.Error(err)
+				return
+			}
+			if message.Method == "" {
+				continue // the answer to our ping
+			}
+			if !pinged && message.Method == "tools/call" {
+				// Servers may send requests to the client before answering.
+				pinged = true
+				_ = encoder.Encode(map[string]any{"jsonrpc": "2.0", "id": 99, "method": "ping"})
+			}
+			if response := fakeServer(t, message); response != nil {
+				_ = encoder.Encode(response)
+			}
+		}
+	}()
+
+	client, err := mcp.NewStreamClient(context.Background(), clientReader, clientWriter)
+	checks.NoError(t, err, "NewStreamClient error")
+	checkTools(t, client)
+	checks.NoError(t, client.Close(), "Close error")
+
+	_, err = client.ListTools(context.Background())
+	if !errors.Is(err, mcp.ErrClosed) && !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected a closed connection, got %v", err)
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	var deleted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = r.Header.Get("Mcp-Session-Id") == "session-1"
+			return
+		}
+		var message fakeMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Error(err)
+			return
+		}
+		if message.Method != "initialize" && r.Header.Get("Mcp-Session-Id") != "session-1" {
+			t.Errorf("missing session for %s", message.Method)
+		}
+		response := fakeServer(t, message)
+		switch {
+		case response == nil:
+			w.WriteHeader(http.StatusAccepted)
+		case message.Method == "tools/call":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			data, _ := json.Marshal(response)
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			_ = json.NewEncoder(w).Encode(response)
+		}
+	}))
+	defer server.Close()
+
+	client, err := mcp.NewHTTPClient(context.Background(), server.URL, nil)
+	checks.NoError(t, err, "NewHTTPClient error")
+	checkTools(t, client)
+	checks.NoError(t, client.Close(), "Close error")
+	if !deleted {
+		t.Error("Close should end the session")
+	}
+}
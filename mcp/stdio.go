@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	maxMessageSize   = 16 << 20
+	stdioStopTimeout = 5 * time.Second
+)
+
+// stdioTransport exchanges newline-delimited JSON-RPC messages over a pair of streams.
+type stdioTransport struct {
+	w       io.WriteCloser
+	writeMu sync.Mutex
+	stop    func() error
+
+	mu      sync.Mutex
+	pending map[int64]chan *rpcResponse
+	done    chan struct{}
+}
+
+func newStdioTransport(r io.Reader, w io.WriteCloser, stop func() error) *stdioTransport {
+	t := &stdioTransport{
+		w:       w,
+		stop:    stop,
+		pending: make(map[int64]chan *rpcResponse),
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(r)
+	return t
+}
+
+func (t *stdioTransport) readLoop(r io.Reader) {
+	defer close(t.done)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxMessageSize)
+	for scanner.Scan() {
+		var message struct {
+			rpcResponse
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &message) != nil || message.ID == nil {
+			continue
+		}
+		if message.Method != "" {
+			go t.answerServerRequest(*message.ID, message.Method)
+			continue
+		}
+		t.mu.Lock()
+		ch, ok := t.pending[*message.ID]
+		delete(t.pending, *message.ID)
+		t.mu.Unlock()
+		if ok {
+			response := message.rpcResponse
+			ch <- &response
+		}
+	}
+}
+
+// answerServerRequest answers the requests of the server: pings succeed, other requests are
+// not supported by this client.
+func (t *stdioTransport) answerServerRequest(id int64, method string) {
+	response := map[string]any{"jsonrpc": "2.0", "id": id, "result": map[string]any{}}
+	if method != "ping" {
+		delete(response, "result")
+		response["error"] = RPCError{Code: -32601, Message: "method not found"}
+	}
+	_ = t.write(response)
+}
+
+func (t *stdioTransport) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.w.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) call(ctx context.Context, request rpcRequest) (*rpcResponse, error) {
+	ch := make(chan *rpcResponse, 1)
+	t.mu.Lock()
+	t.pending[*request.ID] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, *request.ID)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(request); err != nil {
+		return nil, err
+	}
+	select {
+	case response := <-ch:
+		return response, nil
+	case <-t.done:
+		return nil, ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(_ context.Context, notification rpcRequest) error {
+	return t.write(notification)
+}
+
+func (t *stdioTransport) close() error {
+	err := t.w.Close()
+	if t.stop != nil {
+		if stopErr := t.stop(); err == nil {
+			err = stopErr
+		}
+	}
+	return err
+}
+
+// NewStreamClient connects to a server reading its messages from r and writing to w, e.g. the
+// pipes of a process started by the caller. Close closes w.
+func NewStreamClient(ctx context.Context, r io.Reader, w io.WriteCloser) (*Client, error) {
+	return connect(ctx, newStdioTransport(r, w, nil))
+}
+
+// NewStdioClient starts the server process cmd and connects to it over its standard input and
+// output. Close closes the input of the process and waits for it to exit, killing it if it
+// does not exit within 5 seconds.
+func NewStdioClient(ctx context.Context, cmd *exec.Cmd) (*Client, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	t := newStdioTransport(stdout, stdin, nil)
+	t.stop = func() error {
+		// The output of the process ends when it exits; pipes must be read before Wait.
+		select {
+		case <-t.done:
+		case <-time.After(stdioStopTimeout):
+			_ = cmd.Process.Kill()
+		}
+		return cmd.Wait()
+	}
+	return connect(ctx, t)
+}
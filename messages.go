@@ -90,6 +90,9 @@ type MessageDeletionStatus struct {
 
 // CreateMessage creates a new message.
 func (c *Client) CreateMessage(ctx context.Context, threadID string, request MessageRequest) (msg Message, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	urlSuffix := fmt.Sprintf("/threads/%s/%s", threadID, messagesSuffix)
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
@@ -164,6 +167,9 @@ func (c *Client) ModifyMessage(
 	threadID, messageID string,
 	metadata map[string]string,
 ) (msg Message, err error) {
+	if err = Metadata(metadata).Validate(); err != nil {
+		return
+	}
 	urlSuffix := fmt.Sprintf("/threads/%s/%s/%s", threadID, messagesSuffix, messageID)
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix),
 		withBody(map[string]any{"metadata": metadata}), withBetaAssistantVersion(c.config.AssistantVersion))
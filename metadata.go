@@ -0,0 +1,134 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"unicode/utf8"
+)
+
+// Limits of the metadata of assistants, threads, messages, runs and vector stores.
+const (
+	MaxMetadataPairs       = 16
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 512
+)
+
+// ErrInvalidMetadata is wrapped by the errors of metadata exceeding the limits of the API.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// Metadata is a typed form of the metadata maps of assistants, threads, messages and runs, whose
+// values are strings.
+type Metadata map[string]string
+
+// Map returns the metadata as the map of the metadata fields of requests.
+func (m Metadata) Map() map[string]any {
+	if m == nil {
+		return nil
+	}
+	metadata := make(map[string]any, len(m))
+	for key, value := range m {
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// Validate checks the limits of the API: at most 16 pairs, keys of at most 64 characters and
+// values of at most 512 characters.
+func (m Metadata) Validate() error {
+	return ValidateMetadata(m.Map())
+}
+
+// ParseMetadata returns the metadata of a response as Metadata. It fails if a value is not a
+// string.
+func ParseMetadata(metadata map[string]any) (Metadata, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	parsed := make(Metadata, len(metadata))
+	for _, key := range sortedMetadataKeys(metadata) {
+		value, ok := metadata[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: the value of %q is a %T, not a string", ErrInvalidMetadata, key, metadata[key])
+		}
+		parsed[key] = value
+	}
+	return parsed, nil
+}
+
+// ValidateMetadata checks metadata against the limits of the API, see Metadata.Validate. The
+// values must be strings.
+func ValidateMetadata(metadata map[string]any) error {
+	if len(metadata) > MaxMetadataPairs {
+		return fmt.Errorf("%w: %d pairs, the maximum is %d", ErrInvalidMetadata, len(metadata), MaxMetadataPairs)
+	}
+	for _, key := range sortedMetadataKeys(metadata) {
+		if utf8.RuneCountInString(key) > MaxMetadataKeyLength {
+			return fmt.Errorf("%w: key %q is longer than %d characters", ErrInvalidMetadata, key, MaxMetadataKeyLength)
+		}
+		value, ok := metadata[key].(string)
+		if !ok {
+			return fmt.Errorf("%w: the value of %q is a %T, not a string", ErrInvalidMetadata, key, metadata[key])
+		}
+		if utf8.RuneCountInString(value) > MaxMetadataValueLength {
+			return fmt.Errorf("%w: the value of %q is longer than %d characters",
+				ErrInvalidMetadata, key, MaxMetadataValueLength)
+		}
+	}
+	return nil
+}
+
+func sortedMetadataKeys(metadata map[string]any) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MetadataMatches reports whether metadata has every key of query with the same value.
+func MetadataMatches(metadata map[string]any, query Metadata) bool {
+	for key, want := range query {
+		if value, ok := metadata[key].(string); !ok || value != want {
+			return false
+		}
+	}
+	return true
+}
+
+// FindThreadsByMetadata retrieves the threads of threadIDs and returns those whose metadata
+// matches query, see MetadataMatches. The API cannot list threads, so their IDs come from the
+// store of the application. Deleted threads are skipped.
+func (c *Client) FindThreadsByMetadata(ctx context.Context, threadIDs []string, query Metadata) ([]Thread, error) {
+	var threads []Thread
+	for _, id := range threadIDs {
+		thread, err := c.RetrieveThread(ctx, id)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.HTTPStatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return threads, err
+		}
+		if MetadataMatches(thread.Metadata, query) {
+			threads = append(threads, thread)
+		}
+	}
+	return threads, nil
+}
+
+// validate checks the metadata of the thread and of its messages.
+func (r ThreadRequest) validate() error {
+	if err := ValidateMetadata(r.Metadata); err != nil {
+		return err
+	}
+	for i, message := range r.Messages {
+		if err := ValidateMetadata(message.Metadata); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+	}
+	return nil
+}
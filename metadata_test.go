@@ -0,0 +1,111 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestMetadataValidate(t *testing.T) {
+	tooMany := openai.Metadata{}
+	for i := 0; i < 17; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	for name, metadata := range map[string]openai.Metadata{
+		"too many pairs": tooMany,
+		"long key":       {strings.Repeat("k", 65): "value"},
+		"long value":     {"key": strings.Repeat("é", 513)},
+	} {
+		if err := metadata.Validate(); !errors.Is(err, openai.ErrInvalidMetadata) {
+			t.Errorf("%s: expected ErrInvalidMetadata, got %v", name, err)
+		}
+	}
+
+	valid := openai.Metadata{strings.Repeat("k", 64): strings.Repeat("é", 512), "user": "42"}
+	checks.NoError(t, valid.Validate(), "Validate error")
+	checks.NoError(t, openai.Metadata(nil).Validate(), "Validate error")
+
+	err := openai.ValidateMetadata(map[string]any{"count": 3})
+	if !errors.Is(err, openai.ErrInvalidMetadata) {
+		t.Errorf("expected ErrInvalidMetadata for a non-string value, got %v", err)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := openai.ParseMetadata(map[string]any{"user": "42", "topic": "billing"})
+	checks.NoError(t, err, "ParseMetadata error")
+	if metadata["user"] != "42" || metadata["topic"] != "billing" {
+		t.Errorf("unexpected metadata %v", metadata)
+	}
+	if back := metadata.Map(); back["user"] != "42" {
+		t.Errorf("unexpected map %v", back)
+	}
+	if _, err = openai.ParseMetadata(map[string]any{"user": 42.0}); !errors.Is(err, openai.ErrInvalidMetadata) {
+		t.Errorf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func TestCreateThreadInvalidMetadata(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads", func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("the request should not be sent")
+	})
+
+	_, err := client.CreateThread(context.Background(), openai.ThreadRequest{
+		Messages: []openai.ThreadMessage{{
+			Role:     openai.ThreadMessageRoleUser,
+			Content:  "Hi",
+			Metadata: map[string]any{"note": strings.Repeat("x", 600)},
+		}},
+	})
+	if !errors.Is(err, openai.ErrInvalidMetadata) || !strings.Contains(err.Error(), "message 0") {
+		t.Errorf("expected ErrInvalidMetadata for the message, got %v", err)
+	}
+	_, err = client.ModifyMessage(context.Background(), "thread_1", "msg_1",
+		map[string]string{strings.Repeat("k", 100): "v"})
+	if !errors.Is(err, openai.ErrInvalidMetadata) {
+		t.Errorf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func TestFindThreadsByMetadata(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	threads := map[string]string{
+		"thread_1": `{"id":"thread_1","object":"thread","metadata":{"user":"42","topic":"billing"}}`,
+		"thread_2": `{"id":"thread_2","object":"thread","metadata":{"user":"42","topic":"support"}}`,
+		"thread_3": `{"id":"thread_3","object":"thread","metadata":{"user":"7","topic":"billing"}}`,
+	}
+	for id, body := range threads {
+		body := body
+		server.RegisterHandler("/v1/threads/"+id, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+	server.RegisterHandler("/v1/threads/thread_deleted", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"No thread found","type":"invalid_request_error"}}`)
+	})
+
+	found, err := client.FindThreadsByMetadata(context.Background(),
+		[]string{"thread_1", "thread_deleted", "thread_2", "thread_3"},
+		openai.Metadata{"user": "42", "topic": "billing"})
+	checks.NoError(t, err, "FindThreadsByMetadata error")
+	if len(found) != 1 || found[0].ID != "thread_1" {
+		t.Errorf("unexpected threads %+v", found)
+	}
+
+	found, err = client.FindThreadsByMetadata(context.Background(),
+		[]string{"thread_1", "thread_2", "thread_3"}, openai.Metadata{"user": "42"})
+	checks.NoError(t, err, "FindThreadsByMetadata error")
+	if len(found) != 2 {
+		t.Errorf("expected the two threads of the user, got %+v", found)
+	}
+}
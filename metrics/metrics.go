@@ -0,0 +1,320 @@
+// Package metrics instruments the HTTP client of a go-openai Client and exposes request counts,
+// error counts by status, latency histograms, token usage counters and in-flight gauges keyed by
+// model and endpoint in the Prometheus text exposition format.
+//
+// The package does not depend on the Prometheus client library. Serve a Collector as the /metrics
+// handler of your application, or write it into an existing exposition with WriteTo:
+//
+//	collector := metrics.NewCollector()
+//	config := openai.DefaultConfig(token)
+//	config.HTTPClient = collector.Wrap(config.HTTPClient)
+//	client := openai.NewClientWithConfig(config)
+//	http.Handle("/metrics", collector)
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const defaultNamespace = "openai"
+
+// DefaultBuckets are the latency histogram buckets in seconds.
+var DefaultBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Option configures a Collector.
+type Option func(*Collector)
+
+// WithNamespace sets the prefix of all metric names. Defaults to "openai".
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the latency histogram buckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *Collector) {
+		c.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(c.buckets)
+	}
+}
+
+type seriesKey struct {
+	endpoint string
+	model    string
+}
+
+type series struct {
+	requests         uint64
+	inFlight         int64
+	errors           map[int]uint64
+	durationBuckets  []uint64
+	durationSum      float64
+	durationCount    uint64
+	promptTokens     uint64
+	completionTokens uint64
+}
+
+// Collector records metrics of the requests sent through the HTTPDoers it wraps.
+// It is safe for concurrent use.
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+// NewCollector creates a Collector.
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		namespace: defaultNamespace,
+		buckets:   DefaultBuckets,
+		series:    make(map[seriesKey]*series),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Wrap returns an HTTPDoer that records metrics for every request sent through doer.
+// Latency is measured until the response headers arrive, so for streams it is the time
+// to first byte. Requests stay in flight until their response body is closed.
+func (c *Collector) Wrap(doer openai.HTTPDoer) openai.HTTPDoer {
+	return &instrumentedDoer{collector: c, doer: doer}
+}
+
+func (c *Collector) get(key seriesKey) *series {
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{
+			errors:          make(map[int]uint64),
+			durationBuckets: make([]uint64, len(c.buckets)),
+		}
+		c.series[key] = s
+	}
+	return s
+}
+
+func (c *Collector) begin(key seriesKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.get(key)
+	s.requests++
+	s.inFlight++
+}
+
+func (c *Collector) observe(key seriesKey, duration time.Duration, status int, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.get(key)
+	seconds := duration.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			s.durationBuckets[i]++
+		}
+	}
+	s.durationSum += seconds
+	s.durationCount++
+	if failed {
+		s.errors[status]++
+	}
+}
+
+func (c *Collector) end(key seriesKey, usage *openai.Usage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.get(key)
+	s.inFlight--
+	if usage != nil {
+		s.promptTokens += uint64(usage.PromptTokens)
+		s.completionTokens += uint64(usage.CompletionTokens)
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to w.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	keys := make([]seriesKey, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	var b bytes.Buffer
+	name := func(metric string) string { return c.namespace + "_" + metric }
+
+	writeHeader(&b, name("requests_total"), "counter", "Total number of requests sent to the API.")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s%s %d\n", name("requests_total"), formatLabels(key), c.series[key].requests)
+	}
+
+	writeHeader(&b, name("request_errors_total"), "counter",
+		"Total number of failed requests by HTTP status, 0 for transport errors.")
+	for _, key := range keys {
+		s := c.series[key]
+		statuses := make([]int, 0, len(s.errors))
+		for status := range s.errors {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(&b, "%s%s %d\n", name("request_errors_total"),
+				formatLabels(key, "status", fmt.Sprint(status)), s.errors[status])
+		}
+	}
+
+	writeHeader(&b, name("request_duration_seconds"), "histogram",
+		"Time until the response headers were received.")
+	for _, key := range keys {
+		s := c.series[key]
+		for i, bound := range c.buckets {
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name("request_duration_seconds"),
+				formatLabels(key, "le", formatFloat(bound)), s.durationBuckets[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", name("request_duration_seconds"),
+			formatLabels(key, "le", "+Inf"), s.durationCount)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", name("request_duration_seconds"), formatLabels(key),
+			formatFloat(s.durationSum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", name("request_duration_seconds"), formatLabels(key), s.durationCount)
+	}
+
+	writeHeader(&b, name("tokens_total"), "counter", "Total number of tokens reported in response usage.")
+	for _, key := range keys {
+		s := c.series[key]
+		fmt.Fprintf(&b, "%s%s %d\n", name("tokens_total"), formatLabels(key, "type", "prompt"), s.promptTokens)
+		fmt.Fprintf(&b, "%s%s %d\n", name("tokens_total"), formatLabels(key, "type", "completion"),
+			s.completionTokens)
+	}
+
+	writeHeader(&b, name("requests_in_flight"), "gauge", "Number of requests whose response is still open.")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s%s %d\n", name("requests_in_flight"), formatLabels(key), c.series[key].inFlight)
+	}
+	c.mu.Unlock()
+
+	return b.WriteTo(w)
+}
+
+func writeHeader(b *bytes.Buffer, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(key seriesKey, extra ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `{endpoint="%s",model="%s"`, labelEscaper.Replace(key.endpoint), labelEscaper.Replace(key.model))
+	for i := 0; i+1 < len(extra); i += 2 {
+		fmt.Fprintf(&b, `,%s="%s"`, extra[i], labelEscaper.Replace(extra[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%g", f)
+}
+
+type instrumentedDoer struct {
+	collector *Collector
+	doer      openai.HTTPDoer
+}
+
+func (d *instrumentedDoer) Do(req *http.Request) (*http.Response, error) {
+	key := seriesKey{endpoint: normalizeEndpoint(req.URL.Path), model: requestModel(req)}
+	d.collector.begin(key)
+
+	var usage *openai.Usage
+	doer := openai.ObserveUsage(d.doer, func(_ *http.Request, _ string, observed openai.Usage) {
+		usage = &observed
+	})
+	start := time.Now()
+	resp, err := doer.Do(req)
+	if err != nil {
+		d.collector.observe(key, time.Since(start), 0, true)
+		d.collector.end(key, nil)
+		return resp, err
+	}
+	failed := resp.StatusCode >= http.StatusBadRequest
+	d.collector.observe(key, time.Since(start), resp.StatusCode, failed)
+
+	resp.Body = &instrumentedBody{ReadCloser: resp.Body, end: func() {
+		d.collector.end(key, usage)
+	}}
+	return resp, nil
+}
+
+// instrumentedBody marks the request as finished when closed, after the token usage of the
+// response has been observed.
+type instrumentedBody struct {
+	io.ReadCloser
+	end  func()
+	once sync.Once
+}
+
+func (b *instrumentedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.end)
+	return err
+}
+
+// requestModel reads the model from a replayable JSON request body.
+func requestModel(req *http.Request) string {
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	var request struct {
+		Model string `json:"model"`
+	}
+	_ = json.NewDecoder(bufio.NewReader(body)).Decode(&request)
+	return request.Model
+}
+
+var (
+	staticSegment  = regexp.MustCompile(`^[a-z_.]+$`)
+	versionSegment = regexp.MustCompile(`^v\d+$`)
+)
+
+// normalizeEndpoint replaces resource ids in a path with "{id}" to bound label cardinality,
+// e.g. "/v1/threads/thread_abc123/runs" becomes "/v1/threads/{id}/runs".
+func normalizeEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" || staticSegment.MatchString(segment) || versionSegment.MatchString(segment) {
+			continue
+		}
+		segments[i] = "{id}"
+	}
+	return strings.Join(segments, "/")
+}
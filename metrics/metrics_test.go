@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/metrics"
+)
+
+func setupInstrumentedClient(collector *metrics.Collector) (*openai.Client, *test.ServerTest, func()) {
+	server := test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	config.HTTPClient = collector.Wrap(config.HTTPClient)
+	return openai.NewClientWithConfig(config), server, ts.Close
+}
+
+func scrape(t *testing.T, collector *metrics.Collector) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	return rec.Body.String()
+}
+
+func expectLines(t *testing.T, exposition string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(exposition, line+"\n") {
+			t.Errorf("missing %q in exposition:\n%s", line, exposition)
+		}
+	}
+}
+
+func TestCollectorChatCompletion(t *testing.T) {
+	collector := metrics.NewCollector(metrics.WithBuckets([]float64{60}))
+	client, server, teardown := setupInstrumentedClient(collector)
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"id":"1","usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`)
+	})
+
+	request := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	}
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateChatCompletion(context.Background(), request)
+		checks.NoError(t, err, "CreateChatCompletion error")
+	}
+
+	labels := `endpoint="/v1/chat/completions",model="gpt-4o"`
+	expectLines(t, scrape(t, collector),
+		`# TYPE openai_requests_total counter`,
+		`openai_requests_total{`+labels+`} 2`,
+		`openai_request_duration_seconds_bucket{`+labels+`,le="60"} 2`,
+		`openai_request_duration_seconds_bucket{`+labels+`,le="+Inf"} 2`,
+		`openai_request_duration_seconds_count{`+labels+`} 2`,
+		`openai_tokens_total{`+labels+`,type="prompt"} 24`,
+		`openai_tokens_total{`+labels+`,type="completion"} 10`,
+		`openai_requests_in_flight{`+labels+`} 0`,
+	)
+}
+
+func TestCollectorStreamAndErrors(t *testing.T) {
+	collector := metrics.NewCollector(metrics.WithNamespace("llm"))
+	client, server, teardown := setupInstrumentedClient(collector)
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[],\"usage\":{\"prompt_tokens\":3,\"completion_tokens\":1}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	server.RegisterHandler("/v1/files/.*", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":{"message":"not found"}}`)
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+
+	labels := `endpoint="/v1/chat/completions",model="gpt-4o-mini"`
+	expectLines(t, scrape(t, collector), `llm_requests_in_flight{`+labels+`} 1`)
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	stream.Close()
+
+	_, err = client.GetFile(context.Background(), "file-abc123")
+	checks.HasError(t, err, "GetFile should fail")
+
+	expectLines(t, scrape(t, collector),
+		`llm_requests_in_flight{`+labels+`} 0`,
+		`llm_tokens_total{`+labels+`,type="prompt"} 3`,
+		`llm_tokens_total{`+labels+`,type="completion"} 1`,
+		`llm_request_errors_total{endpoint="/v1/files/{id}",model="",status="404"} 1`,
+	)
+}
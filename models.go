@@ -2,10 +2,15 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
+// ErrNotFineTunedModel is returned for model IDs that are not the ID of a fine-tuned model.
+var ErrNotFineTunedModel = errors.New("not a fine-tuned model")
+
 // Model struct represents an OpenAPI model.
 type Model struct {
 	CreatedAt  int64        `json:"created"`
@@ -51,15 +56,74 @@ type ModelsList struct {
 	httpHeader
 }
 
+// ModelFilter selects the models returned by ListModels.
+type ModelFilter func(model Model) bool
+
+// ModelsOwnedBy selects the models owned by one of owners, e.g. "openai", "system" or an
+// organization.
+func ModelsOwnedBy(owners ...string) ModelFilter {
+	return func(model Model) bool {
+		for _, owner := range owners {
+			if model.OwnedBy == owner {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ModelsWithPrefix selects the models whose ID starts with one of prefixes, e.g. "gpt-4o".
+func ModelsWithPrefix(prefixes ...string) ModelFilter {
+	return func(model Model) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(model.ID, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FineTunedModels selects the fine-tuned models.
+func FineTunedModels() ModelFilter {
+	return func(model Model) bool {
+		return isFineTunedModelID(model.ID)
+	}
+}
+
+// Filter returns the models of the list selected by all filters.
+func (l ModelsList) Filter(filters ...ModelFilter) ModelsList {
+	filtered := l
+	filtered.Models = nil
+	for _, model := range l.Models {
+		selected := true
+		for _, filter := range filters {
+			if !filter(model) {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			filtered.Models = append(filtered.Models, model)
+		}
+	}
+	return filtered
+}
+
 // ListModels Lists the currently available models,
 // and provides basic information about each model such as the model id and parent.
-func (c *Client) ListModels(ctx context.Context) (models ModelsList, err error) {
+// The filters, if any, select the models returned, e.g. ModelsOwnedBy("openai"). The API
+// cannot filter models, they are filtered once listed.
+func (c *Client) ListModels(ctx context.Context, filters ...ModelFilter) (models ModelsList, err error) {
 	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/models"))
 	if err != nil {
 		return
 	}
 
 	err = c.sendRequest(req, &models)
+	if err == nil && len(filters) > 0 {
+		models = models.Filter(filters...)
+	}
 	return
 }
 
@@ -88,3 +152,37 @@ func (c *Client) DeleteFineTuneModel(ctx context.Context, modelID string) (
 	err = c.sendRequest(req, &response)
 	return
 }
+
+// FineTunedModelID is the ID of a fine-tuned model, as returned by ParseFineTunedModelID and
+// Model.FineTunedModelID, so that cleanup code cannot delete a base model by mistake.
+type FineTunedModelID string
+
+// ParseFineTunedModelID returns id as a FineTunedModelID, or ErrNotFineTunedModel if it is not
+// the ID of a fine-tuned model, such as "ft:gpt-4o-mini-2024-07-18:org::abc123" or the legacy
+// "davinci:ft-org-2023-01-01-00-00-00".
+func ParseFineTunedModelID(id string) (FineTunedModelID, error) {
+	if !isFineTunedModelID(id) {
+		return "", fmt.Errorf("%w: %q", ErrNotFineTunedModel, id)
+	}
+	return FineTunedModelID(id), nil
+}
+
+// FineTunedModelID returns the ID of the model if it is a fine-tuned model.
+func (m Model) FineTunedModelID() (FineTunedModelID, bool) {
+	id, err := ParseFineTunedModelID(m.ID)
+	return id, err == nil
+}
+
+func isFineTunedModelID(id string) bool {
+	return strings.HasPrefix(id, "ft:") || strings.Contains(id, ":ft-")
+}
+
+// DeleteFineTunedModel deletes a fine-tuned model, like DeleteFineTuneModel but refusing with
+// ErrNotFineTunedModel the IDs that are not the ID of a fine-tuned model.
+func (c *Client) DeleteFineTunedModel(ctx context.Context, modelID FineTunedModelID) (
+	response FineTuneModelDeleteResponse, err error) {
+	if _, err = ParseFineTunedModelID(string(modelID)); err != nil {
+		return
+	}
+	return c.DeleteFineTuneModel(ctx, string(modelID))
+}
@@ -3,6 +3,7 @@ package openai_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -110,3 +111,62 @@ func handleDeleteFineTuneModelEndpoint(w http.ResponseWriter, _ *http.Request) {
 	resBytes, _ := json.Marshal(openai.FineTuneModelDeleteResponse{})
 	fmt.Fprintln(w, string(resBytes))
 }
+
+func TestListModelsFilters(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"list","data":[`+
+			`{"id":"gpt-4o","owned_by":"system"},`+
+			`{"id":"gpt-4o-mini","owned_by":"system"},`+
+			`{"id":"ft:gpt-4o-mini-2024-07-18:acme::abc123","owned_by":"acme"},`+
+			`{"id":"text-embedding-3-small","owned_by":"system"}]}`)
+	})
+
+	models, err := client.ListModels(context.Background(), openai.ModelsOwnedBy("system"),
+		openai.ModelsWithPrefix("gpt-", "o1"))
+	checks.NoError(t, err, "ListModels error")
+	if len(models.Models) != 2 || models.Models[0].ID != "gpt-4o" || models.Models[1].ID != "gpt-4o-mini" {
+		t.Errorf("unexpected models %+v", models.Models)
+	}
+
+	models, err = client.ListModels(context.Background(), openai.FineTunedModels())
+	checks.NoError(t, err, "ListModels error")
+	if len(models.Models) != 1 || models.Models[0].OwnedBy != "acme" {
+		t.Fatalf("unexpected fine-tuned models %+v", models.Models)
+	}
+	if id, ok := models.Models[0].FineTunedModelID(); !ok || id != "ft:gpt-4o-mini-2024-07-18:acme::abc123" {
+		t.Errorf("unexpected fine-tuned model ID %q", id)
+	}
+}
+
+func TestDeleteFineTunedModel(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	const modelID = "ft:gpt-4o-mini-2024-07-18:acme::abc123"
+	server.RegisterHandler("/v1/models/"+modelID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected %s request", r.Method)
+		}
+		fmt.Fprintf(w, `{"id":%q,"object":"model","deleted":true}`, modelID)
+	})
+
+	id, err := openai.ParseFineTunedModelID(modelID)
+	checks.NoError(t, err, "ParseFineTunedModelID error")
+	response, err := client.DeleteFineTunedModel(context.Background(), id)
+	checks.NoError(t, err, "DeleteFineTunedModel error")
+	if !response.Deleted || response.ID != modelID {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	if _, err = openai.ParseFineTunedModelID("gpt-4o"); !errors.Is(err, openai.ErrNotFineTunedModel) {
+		t.Errorf("expected ErrNotFineTunedModel, got %v", err)
+	}
+	_, err = client.DeleteFineTunedModel(context.Background(), openai.FineTunedModelID("gpt-4o"))
+	if !errors.Is(err, openai.ErrNotFineTunedModel) {
+		t.Errorf("expected ErrNotFineTunedModel for a base model, got %v", err)
+	}
+	if _, err = openai.ParseFineTunedModelID("davinci:ft-acme-2023-01-01-00-00-00"); err != nil {
+		t.Errorf("expected a legacy fine-tuned model ID to be accepted, got %v", err)
+	}
+}
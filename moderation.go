@@ -88,6 +88,7 @@ type ModerationResponse struct {
 // Moderations — perform a moderation api call over a string.
 // Input can be an array or slice but a string will reduce the complexity.
 func (c *Client) Moderations(ctx context.Context, request ModerationRequest) (response ModerationResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
 	if _, ok := validModerationModel[request.Model]; len(request.Model) > 0 && !ok {
 		err = ErrModerationInvalidModel
 		return
@@ -0,0 +1,489 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrMalformedPartialJSON is returned by a StreamingJSONParser for JSON that cannot be
+// completed into a valid document.
+var ErrMalformedPartialJSON = errors.New("malformed partial JSON")
+
+// StreamingJSONParser incrementally parses JSON that arrives as a sequence of deltas, such as
+// the content of a structured output streamed by CreateChatCompletionStream. After each delta
+// it returns the best-effort value of the JSON received so far: unterminated strings, numbers
+// and literals are completed, dangling keys are dropped, and open objects and arrays are closed.
+// Only the new delta is scanned, so a stream is parsed in linear time.
+//
+//	parser := openai.NewStreamingJSONParser()
+//	for {
+//		chunk, err := stream.Recv()
+//		...
+//		partial, err := parser.Append(chunk.Choices[0].Delta.Content)
+//		render(partial)
+//	}
+type StreamingJSONParser struct {
+	buf bytes.Buffer
+
+	// root is the value parsed so far, and stack its open objects and arrays.
+	root    any
+	hasRoot bool
+	stack   []partialContainer
+	state   partialState
+	// allowClose is set right after '{' or '[', which may be closed at once.
+	allowClose bool
+	// token holds the raw bytes of the string or scalar being read, without quotes.
+	token       []byte
+	stringIsKey bool
+	escaped     bool
+	unicodeLeft int // Hex digits left in a \u escape
+	escapeStart int // Offset in token of the backslash of an incomplete escape
+	// reserved is set once the partial string or scalar being read has a slot in its container.
+	reserved bool
+	err      error
+}
+
+// partialContainer is an open object or array of a StreamingJSONParser.
+type partialContainer struct {
+	object bool
+	obj    map[string]any
+	arr    []any
+	key    string // Key of the value being read in obj
+}
+
+type partialState int
+
+const (
+	partialValue partialState = iota
+	partialKey
+	partialColon
+	partialAfterValue
+	partialString
+	partialScalar
+)
+
+// NewStreamingJSONParser creates an empty StreamingJSONParser.
+func NewStreamingJSONParser() *StreamingJSONParser {
+	return &StreamingJSONParser{}
+}
+
+// Append adds a delta to the accumulated JSON and returns the best-effort partial value.
+// The value is nil until the first token has started to arrive. The objects and arrays of the
+// value are updated in place by later calls to Append; copy them to keep a snapshot.
+func (p *StreamingJSONParser) Append(delta string) (any, error) {
+	p.buf.WriteString(delta)
+	if p.err == nil {
+		p.err = p.scan(delta)
+	}
+	return p.Value()
+}
+
+// Value returns the best-effort partial value of the accumulated JSON.
+func (p *StreamingJSONParser) Value() (any, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.err = p.setPartial(); p.err != nil {
+		return nil, p.err
+	}
+	return p.root, nil
+}
+
+// Unmarshal decodes the best-effort partial value of the accumulated JSON into v.
+// It does nothing while no token has been received yet. Unlike Value, it decodes the whole
+// accumulated JSON.
+func (p *StreamingJSONParser) Unmarshal(v any) error {
+	completed := CompletePartialJSON(p.buf.Bytes())
+	if len(completed) == 0 {
+		return nil
+	}
+	return json.Unmarshal(completed, v)
+}
+
+// Raw returns the JSON accumulated so far.
+func (p *StreamingJSONParser) Raw() string {
+	return p.buf.String()
+}
+
+// Reset discards the accumulated JSON.
+func (p *StreamingJSONParser) Reset() {
+	p.buf.Reset()
+	*p = StreamingJSONParser{buf: p.buf, token: p.token[:0], stack: p.stack[:0]}
+}
+
+// scan advances the parser over delta.
+//
+//nolint:gocognit,gocyclo // a state machine reads best as a single switch
+func (p *StreamingJSONParser) scan(delta string) error {
+	for i := 0; i < len(delta); i++ {
+		c := delta[i]
+		switch p.state {
+		case partialString:
+			switch {
+			case p.unicodeLeft > 0:
+				if !isHexDigit(c) {
+					return p.syntaxError()
+				}
+				p.unicodeLeft--
+			case p.escaped:
+				p.escaped = false
+				if c == 'u' {
+					p.unicodeLeft = 4
+				}
+			case c == '\\':
+				p.escaped = true
+				p.escapeStart = len(p.token)
+			case c == '"':
+				if err := p.endString(); err != nil {
+					return err
+				}
+				continue
+			}
+			p.token = append(p.token, c)
+			continue
+		case partialScalar:
+			if isPartialScalarByte(c) {
+				p.token = append(p.token, c)
+				continue
+			}
+			value, err := decodePartialScalar(p.token)
+			if err != nil {
+				return err
+			}
+			p.setValue(value)
+			p.state = partialAfterValue
+		}
+
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			continue
+		}
+		var top *partialContainer
+		if len(p.stack) > 0 {
+			top = &p.stack[len(p.stack)-1]
+		}
+		switch p.state {
+		case partialValue:
+			switch {
+			case c == '{':
+				obj := map[string]any{}
+				p.setValue(obj)
+				p.stack = append(p.stack, partialContainer{object: true, obj: obj})
+				p.state, p.allowClose = partialKey, true
+			case c == '[':
+				arr := []any{}
+				p.setValue(arr)
+				p.stack = append(p.stack, partialContainer{arr: arr})
+				p.state, p.allowClose = partialValue, true
+			case c == '"':
+				p.state, p.stringIsKey, p.token = partialString, false, p.token[:0]
+			case c == ']' && p.allowClose && top != nil && !top.object:
+				p.closeContainer()
+			case isPartialScalarByte(c):
+				p.state, p.token = partialScalar, append(p.token[:0], c)
+			default:
+				return p.syntaxError()
+			}
+		case partialKey:
+			switch {
+			case c == '"':
+				p.state, p.stringIsKey, p.token = partialString, true, p.token[:0]
+			case c == '}' && p.allowClose:
+				p.closeContainer()
+			default:
+				return p.syntaxError()
+			}
+		case partialColon:
+			if c != ':' {
+				return p.syntaxError()
+			}
+			p.state, p.allowClose = partialValue, false
+		case partialAfterValue:
+			switch {
+			case top == nil:
+				return p.syntaxError()
+			case c == ',':
+				p.state, p.allowClose = partialValue, false
+				if top.object {
+					p.state = partialKey
+				}
+			case (c == '}' && top.object) || (c == ']' && !top.object):
+				p.closeContainer()
+			default:
+				return p.syntaxError()
+			}
+		}
+	}
+	return nil
+}
+
+// endString completes the string being read as a key or a value.
+func (p *StreamingJSONParser) endString() error {
+	s, err := decodePartialString(p.token)
+	if err != nil {
+		return err
+	}
+	if p.stringIsKey {
+		p.stack[len(p.stack)-1].key = s
+		p.state = partialColon
+		return nil
+	}
+	p.setValue(s)
+	p.state = partialAfterValue
+	return nil
+}
+
+// setPartial sets the string or scalar being read, as far as it can be completed, in its slot.
+func (p *StreamingJSONParser) setPartial() error {
+	var value any
+	switch {
+	case p.state == partialString && !p.stringIsKey:
+		token := p.token
+		if p.escaped || p.unicodeLeft > 0 {
+			token = token[:p.escapeStart]
+		}
+		s, err := decodePartialString(token)
+		if err != nil {
+			return err
+		}
+		value = s
+	case p.state == partialScalar:
+		token := completePartialScalar(p.token)
+		if token == nil {
+			return nil
+		}
+		var err error
+		if value, err = decodePartialScalar(token); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	p.put(value, !p.reserved)
+	p.reserved = true
+	return nil
+}
+
+// setValue sets a complete value in its slot.
+func (p *StreamingJSONParser) setValue(value any) {
+	p.put(value, !p.reserved)
+	p.reserved = false
+}
+
+// put sets value as the root, the value of the current key of the open object, or the last
+// element of the open array, appended if add is set.
+func (p *StreamingJSONParser) put(value any, add bool) {
+	if len(p.stack) == 0 {
+		p.root, p.hasRoot = value, true
+		return
+	}
+	top := &p.stack[len(p.stack)-1]
+	switch {
+	case top.object:
+		top.obj[top.key] = value
+	case add:
+		top.arr = append(top.arr, value)
+		p.updateArray(len(p.stack) - 1)
+	default:
+		top.arr[len(top.arr)-1] = value
+	}
+}
+
+// updateArray stores the array of the open container at index in its parent after it grew.
+func (p *StreamingJSONParser) updateArray(index int) {
+	arr := p.stack[index].arr
+	if index == 0 {
+		p.root = arr
+		return
+	}
+	parent := &p.stack[index-1]
+	if parent.object {
+		parent.obj[parent.key] = arr
+	} else {
+		parent.arr[len(parent.arr)-1] = arr
+	}
+}
+
+func (p *StreamingJSONParser) closeContainer() {
+	p.stack = p.stack[:len(p.stack)-1]
+	p.state, p.allowClose = partialAfterValue, false
+}
+
+// syntaxError returns the error of decoding the malformed JSON accumulated so far.
+func (p *StreamingJSONParser) syntaxError() error {
+	var v any
+	if err := json.Unmarshal(CompletePartialJSON(p.buf.Bytes()), &v); err != nil {
+		return err
+	}
+	return ErrMalformedPartialJSON
+}
+
+func decodePartialString(raw []byte) (string, error) {
+	quoted := make([]byte, 0, len(raw)+2)
+	quoted = append(append(append(quoted, '"'), raw...), '"')
+	var s string
+	err := json.Unmarshal(quoted, &s)
+	return s, err
+}
+
+func decodePartialScalar(token []byte) (any, error) {
+	var value any
+	err := json.Unmarshal(token, &value)
+	return value, err
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// ParsePartialJSON parses a possibly truncated JSON document, see StreamingJSONParser.
+func ParsePartialJSON(data []byte) (any, error) {
+	completed := CompletePartialJSON(data)
+	if len(completed) == 0 {
+		return nil, nil
+	}
+	var v any
+	if err := json.Unmarshal(completed, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type partialFrame struct {
+	object bool
+	// expectKey is set while an object waits for its next key.
+	expectKey bool
+}
+
+// CompletePartialJSON turns a truncated JSON document into the longest valid document it
+// can be completed to. Malformed input is returned mostly as is, so that decoding it reports
+// the syntax error.
+func CompletePartialJSON(data []byte) []byte {
+	var stack []partialFrame
+	// safe is the length of the longest prefix that becomes valid once the open containers
+	// in safeStack are closed.
+	safe := 0
+	var safeStack []partialFrame
+	markSafe := func(n int) {
+		safe = n
+		safeStack = append(safeStack[:0], stack...)
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '{' || c == '[':
+			stack = append(stack, partialFrame{object: c == '{', expectKey: c == '{'})
+			markSafe(i + 1)
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			markSafe(i + 1)
+		case c == ',':
+			if len(stack) > 0 && stack[len(stack)-1].object {
+				stack[len(stack)-1].expectKey = true
+			}
+		case c == ':':
+		case c == '"':
+			end, complete := scanPartialString(data, i)
+			isKey := len(stack) > 0 && stack[len(stack)-1].expectKey
+			if complete {
+				if isKey {
+					stack[len(stack)-1].expectKey = false
+				} else {
+					markSafe(end)
+				}
+				i = end - 1
+				continue
+			}
+			if isKey {
+				return closePartialJSON(data[:safe], safeStack)
+			}
+			out := append([]byte(nil), data[:end]...)
+			out = append(out, '"')
+			return closePartialJSON(out, stack)
+		case !isPartialScalarByte(c):
+			return data
+		default:
+			start := i
+			for i < len(data) && isPartialScalarByte(data[i]) {
+				i++
+			}
+			if i < len(data) {
+				markSafe(i)
+				i--
+				continue
+			}
+			token := completePartialScalar(data[start:])
+			if token == nil {
+				return closePartialJSON(data[:safe], safeStack)
+			}
+			out := append(append([]byte(nil), data[:start]...), token...)
+			return closePartialJSON(out, stack)
+		}
+	}
+
+	if len(stack) == 0 {
+		return bytes.TrimSpace(data)
+	}
+	return closePartialJSON(data[:safe], safeStack)
+}
+
+// scanPartialString returns the end of the string starting at data[start]. If the string is
+// not terminated, end is the length of the prefix that can be closed with a quote.
+func scanPartialString(data []byte, start int) (end int, complete bool) {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			return i + 1, true
+		case '\\':
+			if i+1 >= len(data) {
+				return i, false
+			}
+			if data[i+1] == 'u' {
+				if i+6 > len(data) {
+					return i, false
+				}
+				i += 5
+				continue
+			}
+			i++
+		}
+	}
+	return len(data), false
+}
+
+func isPartialScalarByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		c == '-' || c == '+' || c == '.'
+}
+
+// completePartialScalar completes a truncated number or literal, or returns nil if nothing
+// valid can be made of it.
+func completePartialScalar(token []byte) []byte {
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(literal, string(token)) {
+			return []byte(literal)
+		}
+	}
+	token = bytes.TrimRight(token, ".eE+-")
+	if len(token) == 0 {
+		return nil
+	}
+	return token
+}
+
+func closePartialJSON(prefix []byte, stack []partialFrame) []byte {
+	out := append([]byte(nil), bytes.TrimSpace(prefix)...)
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].object {
+			out = append(out, '}')
+		} else {
+			out = append(out, ']')
+		}
+	}
+	return out
+}
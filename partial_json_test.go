@@ -0,0 +1,153 @@
+package openai_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCompletePartialJSON(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{``, ``},
+		{`  `, ``},
+		{`{`, `{}`},
+		{`{"na`, `{}`},
+		{`{"name"`, `{}`},
+		{`{"name":`, `{}`},
+		{`{"name": "Al`, `{"name": "Al"}`},
+		{`{"name": "Al\`, `{"name": "Al"}`},
+		{`{"name": "Al\u00`, `{"name": "Al"}`},
+		{`{"name": "Alice", `, `{"name": "Alice"}`},
+		{`{"name": "Alice", "age": 3`, `{"name": "Alice", "age": 3}`},
+		{`{"score": -`, `{}`},
+		{`{"score": 1.`, `{"score": 1}`},
+		{`{"ok": tr`, `{"ok": true}`},
+		{`{"tags": ["a", "b`, `{"tags": ["a", "b"]}`},
+		{`{"tags": ["a",`, `{"tags": ["a"]}`},
+		{`[{"a": 1}, {"b": [`, `[{"a": 1}, {"b": []}]`},
+		{`{"a": {"b": null}, "c": fa`, `{"a": {"b": null}, "c": false}`},
+		{`{"done": true}`, `{"done": true}`},
+		{`"str`, `"str"`},
+		{`12`, `12`},
+	}
+	for _, tc := range cases {
+		if got := string(openai.CompletePartialJSON([]byte(tc.input))); got != tc.want {
+			t.Errorf("CompletePartialJSON(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParsePartialJSONMalformed(t *testing.T) {
+	_, err := openai.ParsePartialJSON([]byte(`{"a": #`))
+	checks.HasError(t, err, "malformed JSON should fail to parse")
+}
+
+func TestStreamingJSONParser(t *testing.T) {
+	type person struct {
+		Name    string   `json:"name"`
+		Age     int      `json:"age"`
+		Hobbies []string `json:"hobbies"`
+	}
+
+	deltas := []string{`{"na`, `me": "Ali`, `ce", "age"`, `: 30, "hob`, `bies": ["chess", "g`, `o"]}`}
+	want := []any{
+		map[string]any{},
+		map[string]any{"name": "Ali"},
+		map[string]any{"name": "Alice"},
+		map[string]any{"name": "Alice", "age": float64(30)},
+		map[string]any{"name": "Alice", "age": float64(30), "hobbies": []any{"chess", "g"}},
+		map[string]any{"name": "Alice", "age": float64(30), "hobbies": []any{"chess", "go"}},
+	}
+
+	parser := openai.NewStreamingJSONParser()
+	for i, delta := range deltas {
+		got, err := parser.Append(delta)
+		checks.NoError(t, err, "Append error")
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("after delta %d got %#v, want %#v", i, got, want[i])
+		}
+	}
+
+	var p person
+	checks.NoError(t, parser.Unmarshal(&p), "Unmarshal error")
+	if p.Name != "Alice" || p.Age != 30 || len(p.Hobbies) != 2 {
+		t.Errorf("unexpected person: %+v", p)
+	}
+	if parser.Raw() != `{"name": "Alice", "age": 30, "hobbies": ["chess", "go"]}` {
+		t.Errorf("unexpected raw JSON: %s", parser.Raw())
+	}
+}
+
+func TestStreamingJSONParserMatchesParsePartialJSON(t *testing.T) {
+	documents := []string{
+		`{"name": "Alé \"the\" \\ great", "age": -12.5e+3, "ok": true, "none": null,` +
+			` "tags": ["a", [], [1, [false, {}]], {"x": "y"}], "nested": {"deep": {"list": [0.5]}}}`,
+		`[1, "two", {"three": 3}, [4, [5]], null, fals`,
+		`"just a string with \n escapes 😀"`,
+		` 42 `,
+	}
+	for _, document := range documents {
+		parser := openai.NewStreamingJSONParser()
+		for i := 0; i < len(document); i++ {
+			got, err := parser.Append(document[i : i+1])
+			checks.NoError(t, err, "Append error")
+			want, err := openai.ParsePartialJSON([]byte(document[:i+1]))
+			checks.NoError(t, err, "ParsePartialJSON error")
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("after %q got %#v, want %#v", document[:i+1], got, want)
+			}
+		}
+	}
+}
+
+func TestStreamingJSONParserMalformed(t *testing.T) {
+	parser := openai.NewStreamingJSONParser()
+	_, err := parser.Append(`{"a": 1`)
+	checks.NoError(t, err, "Append error")
+	_, err = parser.Append(` #}`)
+	checks.HasError(t, err, "malformed JSON should fail to parse")
+	_, err = parser.Append(`}`)
+	checks.HasError(t, err, "the parser should keep failing after malformed JSON")
+
+	parser.Reset()
+	got, err := parser.Append(`[1, 2`)
+	checks.NoError(t, err, "Append error after Reset")
+	if !reflect.DeepEqual(got, []any{float64(1), float64(2)}) {
+		t.Errorf("unexpected value after Reset %#v", got)
+	}
+	_, err = parser.Append(`,]`)
+	checks.HasError(t, err, "a trailing comma should fail to parse")
+}
+
+func BenchmarkStreamingJSONParser(b *testing.B) {
+	var document strings.Builder
+	document.WriteString(`{"items": [`)
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			document.WriteString(", ")
+		}
+		fmt.Fprintf(&document, `{"id": %d, "text": "item number %d"}`, i, i)
+	}
+	document.WriteString(`]}`)
+	data := document.String()
+
+	for n := 0; n < b.N; n++ {
+		parser := openai.NewStreamingJSONParser()
+		for i := 0; i < len(data); i += 8 {
+			end := i + 8
+			if end > len(data) {
+				end = len(data)
+			}
+			if _, err := parser.Append(data[i:end]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package openai
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PingDiagnosis is the likely cause of a failed Ping.
+type PingDiagnosis string
+
+const (
+	PingDiagnosisOK PingDiagnosis = "ok"
+	// PingDiagnosisDNS means the API host could not be resolved.
+	PingDiagnosisDNS PingDiagnosis = "dns_failure"
+	// PingDiagnosisConnection means the API host could not be reached.
+	PingDiagnosisConnection PingDiagnosis = "connection_error"
+	// PingDiagnosisTimeout means the API did not answer in time.
+	PingDiagnosisTimeout PingDiagnosis = "timeout"
+	// PingDiagnosisTLS means the TLS handshake failed, e.g. on an untrusted certificate.
+	PingDiagnosisTLS PingDiagnosis = "tls_error"
+	// PingDiagnosisProxy means the proxy could not be reached or refused the request.
+	PingDiagnosisProxy PingDiagnosis = "proxy_error"
+	// PingDiagnosisUnauthorized means the API key was rejected (401).
+	PingDiagnosisUnauthorized PingDiagnosis = "unauthorized"
+	// PingDiagnosisForbidden means the API key lacks permissions, e.g. on the organization (403).
+	PingDiagnosisForbidden PingDiagnosis = "forbidden"
+	// PingDiagnosisHTTPError means the API answered with another error status.
+	PingDiagnosisHTTPError PingDiagnosis = "http_error"
+	// PingDiagnosisUnknown means the error could not be classified.
+	PingDiagnosisUnknown PingDiagnosis = "unknown"
+)
+
+// PingResult is the outcome of a Ping.
+type PingResult struct {
+	// Latency is the round-trip time of the call, including connection setup.
+	Latency   time.Duration
+	Diagnosis PingDiagnosis
+	// StatusCode is the HTTP status of the response, or 0 if none was received.
+	StatusCode int
+}
+
+// Ping performs a cheap authenticated call to the API, listing a single model, to check
+// connectivity and credentials at startup or when troubleshooting. On failure, the result
+// holds a diagnosis of the error.
+func (c *Client) Ping(ctx context.Context) (result PingResult, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL("/models?limit=1"))
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	var models ModelsList
+	err = c.sendRequest(req, &models)
+	result.Latency = time.Since(start)
+	result.Diagnosis, result.StatusCode = diagnosePingError(err)
+	return
+}
+
+//nolint:gocyclo // one case per diagnosis
+func diagnosePingError(err error) (PingDiagnosis, int) {
+	if err == nil {
+		return PingDiagnosisOK, http.StatusOK
+	}
+
+	statusCode := 0
+	var apiErr *APIError
+	var reqErr *RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		statusCode = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		statusCode = reqErr.HTTPStatusCode
+	}
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return PingDiagnosisUnauthorized, statusCode
+	case statusCode == http.StatusForbidden:
+		return PingDiagnosisForbidden, statusCode
+	case statusCode == http.StatusProxyAuthRequired:
+		return PingDiagnosisProxy, statusCode
+	case statusCode != 0:
+		return PingDiagnosisHTTPError, statusCode
+	}
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		return PingDiagnosisProxy, 0
+	case errors.As(err, &dnsErr):
+		return PingDiagnosisDNS, 0
+	case errors.As(err, &unknownAuthorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &certificateErr), errors.As(err, &recordHeaderErr),
+		strings.Contains(err.Error(), "tls: "), strings.Contains(err.Error(), "x509: "):
+		return PingDiagnosisTLS, 0
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return PingDiagnosisTimeout, 0
+	case opErr != nil:
+		return PingDiagnosisConnection, 0
+	}
+	return PingDiagnosisUnknown, 0
+}
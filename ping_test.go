@@ -0,0 +1,91 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestPing(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"gpt-4o"}]}`)
+	})
+
+	result, err := client.Ping(context.Background())
+	checks.NoError(t, err, "Ping error")
+	if result.Diagnosis != openai.PingDiagnosisOK || result.StatusCode != http.StatusOK || result.Latency <= 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestPingUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`)
+	}))
+	defer ts.Close()
+	config := openai.DefaultConfig("bad-key")
+	config.BaseURL = ts.URL
+	client := openai.NewClientWithConfig(config)
+
+	result, err := client.Ping(context.Background())
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if result.Diagnosis != openai.PingDiagnosisUnauthorized || result.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestPingNetworkDiagnosis(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	checks.NoError(t, err)
+	closedURL := "http://" + listener.Addr().String()
+	listener.Close()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+
+	proxyURL, _ := url.Parse(closedURL)
+	for _, tc := range []struct {
+		name    string
+		baseURL string
+		client  *http.Client
+		want    openai.PingDiagnosis
+	}{
+		{"connection refused", closedURL, &http.Client{}, openai.PingDiagnosisConnection},
+		{"untrusted certificate", tlsServer.URL, &http.Client{}, openai.PingDiagnosisTLS},
+		{
+			"proxy down",
+			"http://api.example.com/v1",
+			&http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}},
+			openai.PingDiagnosisProxy,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := openai.DefaultConfig("key")
+			config.BaseURL = tc.baseURL
+			config.HTTPClient = tc.client
+			result, pingErr := openai.NewClientWithConfig(config).Ping(context.Background())
+			checks.HasError(t, pingErr)
+			if result.Diagnosis != tc.want {
+				t.Errorf("expected %s, got %s (%v)", tc.want, result.Diagnosis, pingErr)
+			}
+		})
+	}
+}
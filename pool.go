@@ -0,0 +1,163 @@
+package openai
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+)
+
+// ConnectionStats are the connection counts of a client, see ClientConfig.TrackConnections.
+type ConnectionStats struct {
+	// Open is the number of connections dialed and not yet closed.
+	Open int
+	// Active is the number of open connections serving at least one request.
+	Active int
+	// Idle is the number of open connections serving no request.
+	Idle int
+	// InFlight is the number of requests sent and whose response body is not yet closed.
+	// It exceeds Active when HTTP/2 connections serve several requests at once.
+	InFlight int
+}
+
+// connTracker counts the connections dialed by a transport and the requests using them.
+type connTracker struct {
+	mu       sync.Mutex
+	open     map[net.Conn]int // requests per connection
+	inFlight int
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{open: make(map[net.Conn]int)}
+}
+
+func (t *connTracker) stats() ConnectionStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := ConnectionStats{Open: len(t.open), InFlight: t.inFlight}
+	for _, requests := range t.open {
+		if requests > 0 {
+			stats.Active++
+		}
+	}
+	stats.Idle = stats.Open - stats.Active
+	return stats
+}
+
+func (t *connTracker) dialer(
+	dial func(ctx context.Context, network, addr string) (net.Conn, error),
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tracked := &trackedConn{Conn: conn, tracker: t}
+		t.mu.Lock()
+		t.open[tracked] = 0
+		t.mu.Unlock()
+		return tracked, nil
+	}
+}
+
+// use adds delta to the requests of conn, if it was dialed by the tracker.
+func (t *connTracker) use(conn net.Conn, delta int) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if requests, ok := t.open[conn]; ok {
+		t.open[conn] = requests + delta
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	tracker *connTracker
+	once    sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.tracker.mu.Lock()
+		delete(c.tracker.open, c)
+		c.tracker.mu.Unlock()
+	})
+	return c.Conn.Close()
+}
+
+// trackingDoer counts the requests of a client and the connections serving them.
+type trackingDoer struct {
+	doer    HTTPDoer
+	tracker *connTracker
+}
+
+func (d *trackingDoer) Do(req *http.Request) (*http.Response, error) {
+	var mu sync.Mutex
+	var conns []net.Conn
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			conns = append(conns, info.Conn)
+			mu.Unlock()
+			d.tracker.use(info.Conn, 1)
+		},
+	}
+	d.tracker.mu.Lock()
+	d.tracker.inFlight++
+	d.tracker.mu.Unlock()
+	release := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			d.tracker.use(conn, -1)
+		}
+		d.tracker.mu.Lock()
+		d.tracker.inFlight--
+		d.tracker.mu.Unlock()
+	}
+
+	resp, err := d.doer.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	if err != nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// withConnectionPool returns doer with a transport configured by the pool settings of config
+// and, if tracker is not nil, whose connections are counted by tracker. It reports false if
+// doer is not an *http.Client using an *http.Transport, or the default transport.
+func withConnectionPool(doer HTTPDoer, config ClientConfig, tracker *connTracker) (HTTPDoer, bool) {
+	return configureTransport(doer, func(transport *http.Transport) {
+		if config.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+			if transport.MaxIdleConns > 0 && transport.MaxIdleConns < config.MaxIdleConnsPerHost {
+				transport.MaxIdleConns = config.MaxIdleConnsPerHost
+			}
+		}
+		if config.MaxConnsPerHost > 0 {
+			transport.MaxConnsPerHost = config.MaxConnsPerHost
+		}
+		if tracker != nil {
+			transport.DialContext = tracker.dialer(transport.DialContext)
+		}
+	})
+}
+
+// ConnectionStats returns the connection counts of the client. They are zero unless
+// ClientConfig.TrackConnections is set and HTTPClient is an *http.Client using an
+// *http.Transport, or the default transport.
+func (c *Client) ConnectionStats() ConnectionStats {
+	if c.connTracker == nil {
+		return ConnectionStats{}
+	}
+	return c.connTracker.stats()
+}
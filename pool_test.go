@@ -0,0 +1,111 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// blockingModelsServer starts a client whose list models requests block until release is
+// closed, and returns a channel receiving a value for each request reaching the server.
+func blockingModelsServer(
+	t *testing.T,
+	configure func(*openai.ClientConfig),
+) (client *openai.Client, arrived <-chan struct{}, release chan struct{}, teardown func()) {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(configure)
+	requests := make(chan struct{}, 10)
+	release = make(chan struct{})
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		requests <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+	return client, requests, release, teardown
+}
+
+func waitForStats(t *testing.T, client *openai.Client, expected openai.ConnectionStats) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for client.ConnectionStats() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %+v, got %+v", expected, client.ConnectionStats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConnectionStats(t *testing.T) {
+	client, arrived, release, teardown := blockingModelsServer(t, func(config *openai.ClientConfig) {
+		config.TrackConnections = true
+		config.MaxIdleConnsPerHost = 5
+	})
+	defer teardown()
+	if stats := client.ConnectionStats(); stats != (openai.ConnectionStats{}) {
+		t.Errorf("unexpected stats before the first request %+v", stats)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListModels(context.Background())
+			checks.NoError(t, err, "ListModels error")
+		}()
+	}
+	<-arrived
+	<-arrived
+	waitForStats(t, client, openai.ConnectionStats{Open: 2, Active: 2, InFlight: 2})
+
+	close(release)
+	wg.Wait()
+	waitForStats(t, client, openai.ConnectionStats{Open: 2, Idle: 2})
+}
+
+func TestMaxConnsPerHost(t *testing.T) {
+	client, arrived, release, teardown := blockingModelsServer(t, func(config *openai.ClientConfig) {
+		config.TrackConnections = true
+		config.MaxConnsPerHost = 1
+	})
+	defer teardown()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListModels(context.Background())
+			checks.NoError(t, err, "ListModels error")
+		}()
+	}
+	<-arrived
+	// The second request waits for the only connection.
+	waitForStats(t, client, openai.ConnectionStats{Open: 1, Active: 1, InFlight: 2})
+
+	close(release)
+	wg.Wait()
+	waitForStats(t, client, openai.ConnectionStats{Open: 1, Idle: 1})
+}
+
+func TestConnectionStatsCustomDoer(t *testing.T) {
+	config := openai.DefaultConfig("token")
+	config.HTTPClient = doerFunc(http.DefaultClient.Do)
+	config.TrackConnections = true
+	client := openai.NewClientWithConfig(config)
+	if stats := client.ConnectionStats(); stats != (openai.ConnectionStats{}) {
+		t.Errorf("unexpected stats for a custom doer %+v", stats)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
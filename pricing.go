@@ -0,0 +1,71 @@
+package openai
+
+import "strings"
+
+// ModelPrice is the price of a model in USD per million tokens.
+type ModelPrice struct {
+	Input float64
+	// CachedInput is the price of the prompt tokens read from the prompt cache. Zero means the
+	// model has no cache discount.
+	CachedInput float64
+	Output      float64
+}
+
+// DefaultModelPrices are the standard prices of the OpenAI models, as published when this
+// version was released. Prices change: pass your own table to EstimateCost or Budget.Prices
+// for exact accounting. Dated snapshots without an entry use the price of their model.
+var DefaultModelPrices = map[string]ModelPrice{
+	GPT4Dot1:                {Input: 2, CachedInput: 0.5, Output: 8},
+	GPT4Dot1Mini:            {Input: 0.4, CachedInput: 0.1, Output: 1.6},
+	GPT4Dot1Nano:            {Input: 0.1, CachedInput: 0.025, Output: 0.4},
+	GPT4o:                   {Input: 2.5, CachedInput: 1.25, Output: 10},
+	GPT4o20240513:           {Input: 5, Output: 15},
+	GPT4oMini:               {Input: 0.15, CachedInput: 0.075, Output: 0.6},
+	GPT4Turbo:               {Input: 10, Output: 30},
+	GPT4:                    {Input: 30, Output: 60},
+	GPT3Dot5Turbo:           {Input: 0.5, Output: 1.5},
+	O1:                      {Input: 15, CachedInput: 7.5, Output: 60},
+	O1Mini:                  {Input: 1.1, CachedInput: 0.55, Output: 4.4},
+	O3:                      {Input: 2, CachedInput: 0.5, Output: 8},
+	O3Mini:                  {Input: 1.1, CachedInput: 0.55, Output: 4.4},
+	O4Mini:                  {Input: 1.1, CachedInput: 0.275, Output: 4.4},
+	string(SmallEmbedding3): {Input: 0.02},
+	string(LargeEmbedding3): {Input: 0.13},
+	string(AdaEmbeddingV2):  {Input: 0.1},
+}
+
+// LookupModelPrice returns the price of model in prices, falling back to the price of the
+// model a dated snapshot like "gpt-4o-2024-08-06" belongs to.
+func LookupModelPrice(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	if price, ok := prices[model]; ok {
+		return price, true
+	}
+	var price ModelPrice
+	matched := ""
+	for name, candidate := range prices {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(matched) {
+			price, matched = candidate, name
+		}
+	}
+	return price, matched != ""
+}
+
+// EstimateCost returns the cost in USD of usage with model, priced with prices or
+// DefaultModelPrices if nil, and whether the model has a price.
+func EstimateCost(prices map[string]ModelPrice, model string, usage Usage) (float64, bool) {
+	if prices == nil {
+		prices = DefaultModelPrices
+	}
+	price, ok := LookupModelPrice(prices, model)
+	if !ok {
+		return 0, false
+	}
+	cached := 0
+	if usage.PromptTokensDetails != nil && price.CachedInput > 0 {
+		cached = usage.PromptTokensDetails.CachedTokens
+	}
+	cost := float64(usage.PromptTokens-cached)*price.Input +
+		float64(cached)*price.CachedInput +
+		float64(usage.CompletionTokens)*price.Output
+	return cost / 1e6, true
+}
@@ -0,0 +1,46 @@
+package openai_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestEstimateCost(t *testing.T) {
+	usage := openai.Usage{
+		PromptTokens:        1000,
+		CompletionTokens:    500,
+		PromptTokensDetails: &openai.PromptTokensDetails{CachedTokens: 400},
+	}
+	testCases := []struct {
+		name  string
+		model string
+		want  float64
+		ok    bool
+	}{
+		{"exact model", openai.GPT4oMini, (600*0.15 + 400*0.075 + 500*0.6) / 1e6, true},
+		{"dated snapshot", openai.GPT4oMini20240718, (600*0.15 + 400*0.075 + 500*0.6) / 1e6, true},
+		{"snapshot with its own price", openai.GPT4o20240513, (1000*5 + 500*15) / 1e6, true},
+		{"unknown model", "my-model", 0, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cost, ok := openai.EstimateCost(nil, tc.model, usage)
+			if ok != tc.ok || math.Abs(cost-tc.want) > 1e-12 {
+				t.Errorf("expected %v %v, got %v %v", tc.want, tc.ok, cost, ok)
+			}
+		})
+	}
+}
+
+func TestEstimateCostCustomPrices(t *testing.T) {
+	prices := map[string]openai.ModelPrice{"my-model": {Input: 1, Output: 2}}
+	cost, ok := openai.EstimateCost(prices, "my-model", openai.Usage{PromptTokens: 1e6, CompletionTokens: 1e6})
+	if !ok || cost != 3 {
+		t.Errorf("unexpected cost %v %v", cost, ok)
+	}
+	if _, ok = openai.LookupModelPrice(prices, openai.GPT4o); ok {
+		t.Error("expected custom prices to replace the default ones")
+	}
+}
@@ -0,0 +1,59 @@
+package openai
+
+import "net/http"
+
+// ProviderHeaders adds headers to every request of a client, including streamed ones. It is
+// meant for proxies and gateways that read their settings from request headers.
+type ProviderHeaders func(header http.Header)
+
+// WithHeaders returns ProviderHeaders setting fixed headers.
+func WithHeaders(headers map[string]string) ProviderHeaders {
+	return func(header http.Header) {
+		for name, value := range headers {
+			header.Set(name, value)
+		}
+	}
+}
+
+// WithHelicone returns ProviderHeaders for the Helicone proxy, see https://docs.helicone.ai.
+// Properties are sent as Helicone-Property-<name> custom properties. Point BaseURL at the
+// proxy, e.g. https://oai.helicone.ai/v1.
+func WithHelicone(apiKey string, properties map[string]string) ProviderHeaders {
+	return func(header http.Header) {
+		header.Set("Helicone-Auth", "Bearer "+apiKey)
+		for name, value := range properties {
+			header.Set("Helicone-Property-"+name, value)
+		}
+	}
+}
+
+// LangfuseTrace describes the Langfuse trace that a request belongs to.
+type LangfuseTrace struct {
+	TraceID   string
+	SessionID string
+	UserID    string
+	Tags      []string
+}
+
+// WithLangfuse returns ProviderHeaders for gateways that forward traces to Langfuse, such as
+// the LiteLLM proxy. The keys select the Langfuse project; they are sent as
+// x-langfuse-public-key and x-langfuse-secret-key, and trace attributes as x-langfuse-<attribute>.
+func WithLangfuse(publicKey, secretKey string, trace LangfuseTrace) ProviderHeaders {
+	return func(header http.Header) {
+		header.Set("x-langfuse-public-key", publicKey)
+		header.Set("x-langfuse-secret-key", secretKey)
+		setIfNotEmpty(header, "x-langfuse-trace-id", trace.TraceID)
+		setIfNotEmpty(header, "x-langfuse-session-id", trace.SessionID)
+		setIfNotEmpty(header, "x-langfuse-user-id", trace.UserID)
+		header.Del("x-langfuse-tags")
+		for _, tag := range trace.Tags {
+			header.Add("x-langfuse-tags", tag)
+		}
+	}
+}
+
+func setIfNotEmpty(header http.Header, name, value string) {
+	if value != "" {
+		header.Set(name, value)
+	}
+}
@@ -0,0 +1,69 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestProviderHeaders(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.ProviderHeaders = []openai.ProviderHeaders{
+			openai.WithHelicone("helicone-key", map[string]string{"App": "search"}),
+			openai.WithLangfuse("pk", "sk", openai.LangfuseTrace{SessionID: "session-1", Tags: []string{"a", "b"}}),
+			openai.WithHeaders(map[string]string{"X-Custom": "1"}),
+		}
+	})
+	defer teardown()
+	checkHeaders := func(r *http.Request) {
+		want := map[string]string{
+			"Helicone-Auth":         "Bearer helicone-key",
+			"Helicone-Property-App": "search",
+			"X-Langfuse-Public-Key": "pk",
+			"X-Langfuse-Secret-Key": "sk",
+			"X-Langfuse-Session-Id": "session-1",
+			"X-Custom":              "1",
+		}
+		for header, value := range want {
+			if got := r.Header.Get(header); got != value {
+				t.Errorf("%s = %q, want %q", header, got, value)
+			}
+		}
+		if got := r.Header.Values("X-Langfuse-Tags"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+			t.Errorf("unexpected tags %v", got)
+		}
+		if r.Header.Get("X-Langfuse-Trace-Id") != "" {
+			t.Error("empty trace attributes should not be sent")
+		}
+	}
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		checkHeaders(r)
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		checkHeaders(r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	_, err = stream.Recv()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
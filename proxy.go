@@ -0,0 +1,148 @@
+package openai
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DirectProxy is the proxy of ProxyConfig.Hosts connecting to a host directly.
+const DirectProxy = "direct"
+
+// ProxyConfig selects the HTTP proxy of each request, e.g. to route the OpenAI API through an
+// egress proxy while reaching an internal vLLM host directly:
+//
+//	config.Proxy = &openai.ProxyConfig{
+//		URL:   "http://egress.internal:3128",
+//		Hosts: map[string]string{"vllm.internal": openai.DirectProxy},
+//	}
+//
+// It applies when HTTPClient is an *http.Client using an *http.Transport, or the default
+// transport. The transport is cloned, so it can be shared with other clients.
+type ProxyConfig struct {
+	// URL is the proxy of the hosts without an entry in Hosts or NoProxy. If empty, the proxy is
+	// taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, like
+	// http.ProxyFromEnvironment. Go does not read the system proxy of Windows or macOS, set the
+	// environment variables or URL there.
+	URL string
+	// Hosts maps hosts to the URL of their proxy, or to DirectProxy. A key starting with a dot,
+	// such as ".internal", matches the subdomains of the domain. The exact host wins over the
+	// longest matching domain.
+	Hosts map[string]string
+	// NoProxy lists the hosts connected directly when they have no entry in Hosts, with the
+	// syntax of NO_PROXY: "example.com" matches the domain and its subdomains, ".example.com"
+	// only its subdomains, "example.com:8080" only that port, "10.0.0.0/8" the IP addresses of
+	// a network and "*" every host.
+	NoProxy []string
+}
+
+// Proxy returns the URL of the proxy for req, or nil to connect directly. It can be used as
+// the Proxy of an http.Transport.
+func (p *ProxyConfig) Proxy(req *http.Request) (*url.URL, error) {
+	host, port := req.URL.Hostname(), req.URL.Port()
+	if port == "" {
+		port = defaultPort(req.URL.Scheme)
+	}
+	if proxy, ok := p.hostProxy(host); ok {
+		return parseProxyURL(proxy)
+	}
+	if matchNoProxy(p.NoProxy, host, port) {
+		return nil, nil
+	}
+	if p.URL == "" {
+		return http.ProxyFromEnvironment(req)
+	}
+	return parseProxyURL(p.URL)
+}
+
+// hostProxy returns the proxy of host in Hosts, if any.
+func (p *ProxyConfig) hostProxy(host string) (string, bool) {
+	host = strings.ToLower(host)
+	if proxy, ok := p.Hosts[host]; ok {
+		return proxy, true
+	}
+	var domain, proxy string
+	for key, value := range p.Hosts {
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, ".") && strings.HasSuffix(host, key) && len(key) > len(domain) {
+			domain, proxy = key, value
+		}
+	}
+	return proxy, domain != ""
+}
+
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if proxy == "" || proxy == DirectProxy {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		// Accept "host:port" like the environment variables do.
+		if proxyURL, err = url.Parse("http://" + proxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", proxy, err)
+		}
+	}
+	return proxyURL, nil
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// matchNoProxy reports whether host and port match an entry of noProxy.
+func matchNoProxy(noProxy []string, host, port string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(entryHost, ".") {
+			if strings.HasSuffix(host, entryHost) {
+				return true
+			}
+			continue
+		}
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// withProxy returns doer with a transport using the proxies of proxy, or doer itself if its
+// transport cannot be configured.
+func withProxy(doer HTTPDoer, proxy *ProxyConfig) HTTPDoer {
+	configured, _ := configureTransport(doer, func(transport *http.Transport) {
+		transport.Proxy = proxy.Proxy
+	})
+	return configured
+}
@@ -0,0 +1,97 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestProxyConfigProxy(t *testing.T) {
+	proxy := &openai.ProxyConfig{
+		URL: "http://egress:3128",
+		Hosts: map[string]string{
+			"vllm.internal":    openai.DirectProxy,
+			".corp.example":    "http://corp-proxy:8080",
+			".eu.corp.example": "10.0.0.1:3128",
+		},
+		NoProxy: []string{"localhost", ".svc.cluster.local", "example.org:8443", "192.168.0.0/16", "::1"},
+	}
+	for _, test := range []struct {
+		url   string
+		proxy string
+	}{
+		{"https://api.openai.com/v1/models", "http://egress:3128"},
+		{"http://vllm.internal:8000/v1/models", ""},
+		{"https://VLLM.internal/v1/models", ""},
+		{"https://api.corp.example/v1", "http://corp-proxy:8080"},
+		{"https://api.eu.corp.example/v1", "http://10.0.0.1:3128"},
+		{"https://corp.example/v1", "http://egress:3128"},
+		{"http://localhost:8080/v1", ""},
+		{"http://api.localhost/v1", ""},
+		{"http://model.ns.svc.cluster.local/v1", ""},
+		{"https://svc.cluster.local/v1", "http://egress:3128"},
+		{"https://example.org:8443/v1", ""},
+		{"https://example.org/v1", "http://egress:3128"},
+		{"http://192.168.1.20/v1", ""},
+		{"http://10.1.1.1/v1", "http://egress:3128"},
+		{"http://[::1]:8000/v1", ""},
+	} {
+		req, err := http.NewRequest(http.MethodGet, test.url, nil)
+		checks.NoError(t, err, "NewRequest error")
+		proxyURL, err := proxy.Proxy(req)
+		checks.NoError(t, err, "Proxy error")
+		got := ""
+		if proxyURL != nil {
+			got = proxyURL.String()
+		}
+		if got != test.proxy {
+			t.Errorf("expected proxy %q for %s, got %q", test.proxy, test.url, got)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openai.com", nil)
+	wildcard := &openai.ProxyConfig{URL: "http://egress:3128", NoProxy: []string{"*"}}
+	if proxyURL, _ := wildcard.Proxy(req); proxyURL != nil {
+		t.Errorf("expected no proxy for the * NoProxy entry, got %s", proxyURL)
+	}
+}
+
+func TestClientConfigProxy(t *testing.T) {
+	var proxied []string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Host)
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"gpt-4o"}]}`)
+	}))
+	defer proxyServer.Close()
+	direct := 0
+	directServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		direct++
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"llama"}]}`)
+	}))
+	defer directServer.Close()
+
+	proxy := &openai.ProxyConfig{
+		URL:   proxyServer.URL,
+		Hosts: map[string]string{"127.0.0.1": openai.DirectProxy},
+	}
+	config := openai.DefaultConfig("key")
+	config.BaseURL = "http://api.openai.test/v1"
+	config.Proxy = proxy
+	models, err := openai.NewClientWithConfig(config).ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+	if len(proxied) != 1 || proxied[0] != "api.openai.test" || models.Models[0].ID != "gpt-4o" {
+		t.Errorf("expected the request to go through the proxy, got %v", proxied)
+	}
+
+	config.BaseURL = directServer.URL + "/v1"
+	_, err = openai.NewClientWithConfig(config).ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+	if direct != 1 || len(proxied) != 1 {
+		t.Errorf("expected the request to go directly, got %d direct and %d proxied", direct, len(proxied))
+	}
+}
@@ -0,0 +1,190 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	realtimeSuffix                      = "/realtime"
+	realtimeSessionsSuffix              = "/realtime/sessions"
+	realtimeTranscriptionSessionsSuffix = "/realtime/transcription_sessions"
+)
+
+// RealtimeAudioFormat is the format of input or output audio of a realtime session.
+type RealtimeAudioFormat string
+
+const (
+	RealtimeAudioFormatPCM16    RealtimeAudioFormat = "pcm16"
+	RealtimeAudioFormatG711ULaw RealtimeAudioFormat = "g711_ulaw"
+	RealtimeAudioFormatG711ALaw RealtimeAudioFormat = "g711_alaw"
+)
+
+// RealtimeTurnDetectionType selects how the server detects the end of a user turn.
+type RealtimeTurnDetectionType string
+
+const (
+	RealtimeTurnDetectionTypeServerVAD   RealtimeTurnDetectionType = "server_vad"
+	RealtimeTurnDetectionTypeSemanticVAD RealtimeTurnDetectionType = "semantic_vad"
+)
+
+// RealtimeTurnDetection configures voice activity detection.
+type RealtimeTurnDetection struct {
+	Type              RealtimeTurnDetectionType `json:"type"`
+	Threshold         float64                   `json:"threshold,omitempty"`
+	PrefixPaddingMs   int                       `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int                       `json:"silence_duration_ms,omitempty"`
+	CreateResponse    *bool                     `json:"create_response,omitempty"`
+	InterruptResponse *bool                     `json:"interrupt_response,omitempty"`
+	// Eagerness is only used with semantic_vad: "low", "medium", "high" or "auto".
+	Eagerness string `json:"eagerness,omitempty"`
+}
+
+// RealtimeInputAudioTranscription configures transcription of the input audio.
+type RealtimeInputAudioTranscription struct {
+	Model    string `json:"model,omitempty"`
+	Language string `json:"language,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+}
+
+// RealtimeNoiseReduction configures input audio noise reduction ("near_field" or "far_field").
+type RealtimeNoiseReduction struct {
+	Type string `json:"type"`
+}
+
+// RealtimeClientSecret is an ephemeral key that browser or WebRTC clients use to
+// authenticate against the Realtime API.
+type RealtimeClientSecret struct {
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// ExpiresAtTime returns the expiration of the secret as a time.Time.
+func (s RealtimeClientSecret) ExpiresAtTime() time.Time {
+	return time.Unix(s.ExpiresAt, 0)
+}
+
+// RealtimeSessionRequest configures a realtime session.
+type RealtimeSessionRequest struct {
+	Model                    string                           `json:"model,omitempty"`
+	Modalities               []string                         `json:"modalities,omitempty"`
+	Instructions             string                           `json:"instructions,omitempty"`
+	Voice                    string                           `json:"voice,omitempty"`
+	InputAudioFormat         RealtimeAudioFormat              `json:"input_audio_format,omitempty"`
+	OutputAudioFormat        RealtimeAudioFormat              `json:"output_audio_format,omitempty"`
+	InputAudioTranscription  *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	InputAudioNoiseReduction *RealtimeNoiseReduction          `json:"input_audio_noise_reduction,omitempty"`
+	TurnDetection            *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	Tools                    []Tool                           `json:"tools,omitempty"`
+	ToolChoice               any                              `json:"tool_choice,omitempty"`
+	Temperature              *float32                         `json:"temperature,omitempty"`
+	// MaxResponseOutputTokens is either an integer or "inf".
+	MaxResponseOutputTokens any `json:"max_response_output_tokens,omitempty"`
+}
+
+// RealtimeSession is the configuration of a created realtime session
+// along with its ephemeral client secret.
+type RealtimeSession struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	RealtimeSessionRequest
+	ClientSecret RealtimeClientSecret `json:"client_secret"`
+
+	httpHeader
+}
+
+// RealtimeTranscriptionSessionRequest configures a transcription-only realtime session.
+type RealtimeTranscriptionSessionRequest struct {
+	Modalities               []string                         `json:"modalities,omitempty"`
+	InputAudioFormat         RealtimeAudioFormat              `json:"input_audio_format,omitempty"`
+	InputAudioTranscription  *RealtimeInputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	InputAudioNoiseReduction *RealtimeNoiseReduction          `json:"input_audio_noise_reduction,omitempty"`
+	TurnDetection            *RealtimeTurnDetection           `json:"turn_detection,omitempty"`
+	Include                  []string                         `json:"include,omitempty"`
+}
+
+// RealtimeTranscriptionSession is a created transcription session along with its ephemeral client secret.
+type RealtimeTranscriptionSession struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	RealtimeTranscriptionSessionRequest
+	ClientSecret RealtimeClientSecret `json:"client_secret"`
+
+	httpHeader
+}
+
+// CreateRealtimeSession creates a realtime session and returns an ephemeral client secret
+// that can be handed to browser or WebRTC clients.
+func (c *Client) CreateRealtimeSession(
+	ctx context.Context,
+	request RealtimeSessionRequest,
+) (response RealtimeSession, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(realtimeSessionsSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// CreateTranscriptionSession creates a transcription-only realtime session and returns
+// an ephemeral client secret.
+func (c *Client) CreateTranscriptionSession(
+	ctx context.Context,
+	request RealtimeTranscriptionSessionRequest,
+) (response RealtimeTranscriptionSession, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(realtimeTranscriptionSessionsSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// RealtimeSDPAnswer is the SDP answer to the offer of a WebRTC client.
+type RealtimeSDPAnswer struct {
+	SDP string
+
+	httpHeader
+}
+
+// CreateRealtimeSDPAnswer performs the WebRTC offer/answer exchange of the Realtime API: it
+// posts the SDP offer of a WebRTC client for a session with model and returns the answer to
+// hand back to the client.
+func (c *Client) CreateRealtimeSDPAnswer(
+	ctx context.Context,
+	model string,
+	offer string,
+) (response RealtimeSDPAnswer, err error) {
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(realtimeSuffix+"?model="+url.QueryEscape(model)),
+		withBody(strings.NewReader(offer)),
+		withContentType("application/sdp"),
+	)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "application/sdp")
+
+	raw, err := c.sendRequestRaw(req)
+	if err != nil {
+		return
+	}
+	defer raw.Close()
+
+	answer, err := io.ReadAll(raw)
+	if err != nil {
+		return
+	}
+	response.SDP = string(answer)
+	response.SetHeader(raw.Header())
+	return
+}
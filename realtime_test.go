@@ -0,0 +1,103 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateRealtimeSession(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/realtime/sessions", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		turnDetection, _ := request["turn_detection"].(map[string]any)
+		if request["model"] != "gpt-4o-realtime-preview" || turnDetection["type"] != "semantic_vad" {
+			t.Errorf("unexpected request: %v", request)
+		}
+		fmt.Fprintln(w, `{"id":"sess_001","object":"realtime.session","model":"gpt-4o-realtime-preview",`+
+			`"voice":"alloy","client_secret":{"value":"ek_abc123","expires_at":1234567890}}`)
+	})
+
+	session, err := client.CreateRealtimeSession(context.Background(), openai.RealtimeSessionRequest{
+		Model:         "gpt-4o-realtime-preview",
+		Voice:         "alloy",
+		TurnDetection: &openai.RealtimeTurnDetection{Type: openai.RealtimeTurnDetectionTypeSemanticVAD},
+	})
+	checks.NoError(t, err, "CreateRealtimeSession error")
+	if session.ClientSecret.Value != "ek_abc123" || session.ClientSecret.ExpiresAtTime().Unix() != 1234567890 {
+		t.Errorf("unexpected client secret: %+v", session.ClientSecret)
+	}
+	if session.ID != "sess_001" || session.Voice != "alloy" {
+		t.Errorf("unexpected session: %+v", session)
+	}
+}
+
+func TestCreateTranscriptionSession(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/realtime/transcription_sessions", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, `{"id":"sess_002","object":"realtime.transcription_session","input_audio_format":"pcm16",`+
+			`"input_audio_transcription":{"model":"gpt-4o-transcribe"},`+
+			`"client_secret":{"value":"ek_def456","expires_at":1234567890}}`)
+	})
+
+	session, err := client.CreateTranscriptionSession(context.Background(), openai.RealtimeTranscriptionSessionRequest{
+		InputAudioFormat:        openai.RealtimeAudioFormatPCM16,
+		InputAudioTranscription: &openai.RealtimeInputAudioTranscription{Model: "gpt-4o-transcribe"},
+	})
+	checks.NoError(t, err, "CreateTranscriptionSession error")
+	if session.ClientSecret.Value != "ek_def456" || session.InputAudioTranscription.Model != "gpt-4o-transcribe" {
+		t.Errorf("unexpected session: %+v", session)
+	}
+}
+
+func TestCreateRealtimeSDPAnswer(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	const offer = "v=0\r\no=- 1 2 IN IP4 127.0.0.1\r\n"
+	server.RegisterHandler("/v1/realtime", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != offer ||
+			r.Header.Get("Content-Type") != "application/sdp" ||
+			r.URL.Query().Get("model") != "gpt-4o-realtime-preview" {
+			t.Errorf("unexpected request: %s %s %q", r.Method, r.URL, body)
+		}
+		w.Header().Set("Content-Type", "application/sdp")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, "v=0\r\nanswer\r\n")
+	})
+
+	answer, err := client.CreateRealtimeSDPAnswer(context.Background(), "gpt-4o-realtime-preview", offer)
+	checks.NoError(t, err, "CreateRealtimeSDPAnswer error")
+	if answer.SDP != "v=0\r\nanswer\r\n" || answer.Header().Get("Content-Type") != "application/sdp" {
+		t.Errorf("unexpected answer: %q", answer.SDP)
+	}
+}
+
+func TestCreateRealtimeSDPAnswerError(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/realtime", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"invalid offer","type":"invalid_request_error"}}`)
+	})
+
+	_, err := client.CreateRealtimeSDPAnswer(context.Background(), "gpt-4o-realtime-preview", "bad")
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package openai
+
+import "regexp"
+
+// MessageFilter rewrites the messages of chat completion requests before they are sent, e.g. to
+// scrub personal data. It must not modify the messages it is given, but return new ones.
+type MessageFilter func(messages []ChatCompletionMessage) []ChatCompletionMessage
+
+// RedactionRule replaces the matches of Pattern by Replacement.
+type RedactionRule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultRedactionRules redact API keys and access tokens of common providers, email addresses
+// and phone numbers in international or North American formats.
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{
+			Name: "api_key",
+			Pattern: regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{16,}|AKIA[0-9A-Z]{16}|` +
+				`gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|AIza[0-9A-Za-z_-]{35})`),
+			Replacement: "[API_KEY]",
+		},
+		{
+			Name:        "email",
+			Pattern:     regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+			Replacement: "[EMAIL]",
+		},
+		{
+			Name: "phone",
+			Pattern: regexp.MustCompile(`\+\d{8,15}\b|(?:\+\d{1,3}[\s.-]?)?(?:\(\d{2,4}\)[\s.-]?|\b\d{2,4}[\s.-])` +
+				`\d{3,4}[\s.-]?\d{3,4}\b`),
+			Replacement: "[PHONE]",
+		},
+	}
+}
+
+// PIIRedactor redacts personal data and secrets from the text of messages. Set its Filter as
+// ClientConfig.MessageFilter to scrub every prompt of a client:
+//
+//	config.MessageFilter = openai.NewPIIRedactor().Filter
+type PIIRedactor struct {
+	// Rules are applied in order.
+	Rules []RedactionRule
+}
+
+// NewPIIRedactor returns a redactor with the DefaultRedactionRules and the extra rules.
+func NewPIIRedactor(extra ...RedactionRule) *PIIRedactor {
+	return &PIIRedactor{Rules: append(DefaultRedactionRules(), extra...)}
+}
+
+// Redact returns text with the matches of the rules replaced.
+func (r *PIIRedactor) Redact(text string) string {
+	for _, rule := range r.Rules {
+		text = rule.Pattern.ReplaceAllString(text, rule.Replacement)
+	}
+	return text
+}
+
+// Filter returns copies of messages with their content, text parts and tool call arguments
+// redacted. It is a MessageFilter.
+func (r *PIIRedactor) Filter(messages []ChatCompletionMessage) []ChatCompletionMessage {
+	filtered := make([]ChatCompletionMessage, len(messages))
+	for i, message := range messages {
+		message.Content = r.Redact(message.Content)
+		if message.MultiContent != nil {
+			parts := make([]ChatMessagePart, len(message.MultiContent))
+			for j, part := range message.MultiContent {
+				switch part := part.(type) {
+				case TextPart:
+					parts[j] = TextPart{Text: r.Redact(part.Text)}
+				case *TextPart:
+					parts[j] = &TextPart{Text: r.Redact(part.Text)}
+				default:
+					parts[j] = part
+				}
+			}
+			message.MultiContent = parts
+		}
+		if message.ToolCalls != nil {
+			toolCalls := make([]ToolCall, len(message.ToolCalls))
+			for j, toolCall := range message.ToolCalls {
+				toolCall.Function.Arguments = r.Redact(toolCall.Function.Arguments)
+				toolCalls[j] = toolCall
+			}
+			message.ToolCalls = toolCalls
+		}
+		filtered[i] = message
+	}
+	return filtered
+}
+
+// filterMessages applies the MessageFilter of the client to request.
+func (c ClientConfig) filterMessages(request *ChatCompletionRequest) {
+	if c.MessageFilter != nil {
+		request.Messages = c.MessageFilter(request.Messages)
+	}
+}
@@ -0,0 +1,41 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+)
+
+type requestHeadersContextKey struct{}
+
+// WithRequestHeaders returns a context adding headers to the requests made with it, e.g. the
+// session or tenant headers an LLM gateway routes on to keep a conversation on the same
+// backend and benefit from its prompt cache:
+//
+//	ctx = openai.WithRequestHeaders(ctx, map[string]string{"X-Session-Affinity": conversationID})
+//	resp, err := client.CreateChatCompletion(ctx, request)
+//
+// The headers are merged with those of the parent context and take precedence over the
+// headers set by the client, including its ProviderHeaders.
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string)
+	for name, value := range RequestHeadersFromContext(ctx) {
+		merged[http.CanonicalHeaderKey(name)] = value
+	}
+	for name, value := range headers {
+		merged[http.CanonicalHeaderKey(name)] = value
+	}
+	return context.WithValue(ctx, requestHeadersContextKey{}, merged)
+}
+
+// RequestHeadersFromContext returns the headers set with WithRequestHeaders, if any.
+func RequestHeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(requestHeadersContextKey{}).(map[string]string)
+	return headers
+}
+
+// setContextHeaders sets the headers of the context of req on it.
+func setContextHeaders(req *http.Request) {
+	for name, value := range RequestHeadersFromContext(req.Context()) {
+		req.Header.Set(name, value)
+	}
+}
@@ -0,0 +1,54 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestWithRequestHeaders(t *testing.T) {
+	var header http.Header
+	var body map[string]any
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&body), "Decode error")
+		fmt.Fprint(w, `{"id":"1","choices":[]}`)
+	})
+
+	ctx := openai.WithRequestHeaders(context.Background(), map[string]string{"x-session-affinity": "conv-1"})
+	ctx = openai.WithRequestHeaders(ctx, map[string]string{"X-Tenant": "acme"})
+	_, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:            openai.GPT4o,
+		Messages:         []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+		User:             "user-1",
+		SafetyIdentifier: "5f1d3c",
+		PromptCacheKey:   "support-bot-v2",
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	if header.Get("X-Session-Affinity") != "conv-1" || header.Get("X-Tenant") != "acme" {
+		t.Errorf("routing headers not sent: %v", header)
+	}
+	if body["user"] != "user-1" || body["safety_identifier"] != "5f1d3c" || body["prompt_cache_key"] != "support-bot-v2" {
+		t.Errorf("routing metadata not sent: %v", body)
+	}
+}
+
+func TestRequestHeadersFromContext(t *testing.T) {
+	if headers := openai.RequestHeadersFromContext(context.Background()); len(headers) != 0 {
+		t.Errorf("expected no headers, got %v", headers)
+	}
+	ctx := openai.WithRequestHeaders(context.Background(), map[string]string{"x-route": "a", "X-Keep": "1"})
+	ctx = openai.WithRequestHeaders(ctx, map[string]string{"X-Route": "b"})
+	headers := openai.RequestHeadersFromContext(ctx)
+	if len(headers) != 2 || headers["X-Route"] != "b" || headers["X-Keep"] != "1" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+}
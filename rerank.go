@@ -0,0 +1,118 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"sort"
+)
+
+// RerankRequest ranks documents by their relevance to a query.
+type RerankRequest struct {
+	// Model is the rerank model when ClientConfig.RerankEndpoint is set, and the embedding
+	// model otherwise, SmallEmbedding3 by default.
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	// TopN, if positive, returns only the TopN most relevant documents.
+	TopN int `json:"top_n,omitempty"`
+}
+
+// RerankResult is a document ranked by Rerank.
+type RerankResult struct {
+	// Index is the position of the document in RerankRequest.Documents.
+	Index int `json:"index"`
+	// RelevanceScore is the score of the rerank endpoint, or the cosine similarity of the
+	// embeddings of the document and the query.
+	RelevanceScore float64 `json:"relevance_score"`
+	Document       string  `json:"-"`
+}
+
+// RerankResponse lists the documents from the most to the least relevant.
+type RerankResponse struct {
+	ID      string         `json:"id,omitempty"`
+	Results []RerankResult `json:"results"`
+	// Usage is the usage of the embeddings, or of the rerank endpoint if it reports one.
+	Usage *Usage `json:"usage,omitempty"`
+
+	httpHeader
+}
+
+// Rerank scores the documents of request against its query and returns them sorted by
+// relevance. It calls the rerank endpoint of the provider if ClientConfig.RerankEndpoint is
+// set, and otherwise embeds the query and the documents in one request and ranks the
+// documents by cosine similarity.
+func (c *Client) Rerank(ctx context.Context, request RerankRequest) (response RerankResponse, err error) {
+	if len(request.Documents) == 0 {
+		return
+	}
+	if c.config.RerankEndpoint != "" {
+		response, err = c.rerankWithEndpoint(ctx, request)
+	} else {
+		response, err = c.rerankWithEmbeddings(ctx, request)
+	}
+	if err != nil {
+		return
+	}
+
+	results := response.Results[:0]
+	for _, result := range response.Results {
+		if result.Index >= 0 && result.Index < len(request.Documents) {
+			result.Document = request.Documents[result.Index]
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].RelevanceScore > results[j].RelevanceScore
+	})
+	if request.TopN > 0 && request.TopN < len(results) {
+		results = results[:request.TopN]
+	}
+	response.Results = results
+	return
+}
+
+func (c *Client) rerankWithEndpoint(ctx context.Context, request RerankRequest) (response RerankResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(c.config.RerankEndpoint), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+func (c *Client) rerankWithEmbeddings(ctx context.Context, request RerankRequest) (response RerankResponse, err error) {
+	model := EmbeddingModel(request.Model)
+	if model == "" {
+		model = SmallEmbedding3
+	}
+	embeddings, err := c.CreateEmbeddings(ctx, EmbeddingRequest{
+		Input: append([]string{request.Query}, request.Documents...),
+		Model: model,
+	})
+	if err != nil {
+		return
+	}
+
+	vectors := make([][]float32, len(request.Documents)+1)
+	for _, embedding := range embeddings.Data {
+		if embedding.Index >= 0 && embedding.Index < len(vectors) {
+			vectors[embedding.Index] = embedding.Embedding
+		}
+	}
+	matches, err := NearestVectors(vectors[0], vectors[1:], -1, CosineSimilarity)
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		response.Results = append(response.Results, RerankResult{
+			Index:          match.Index,
+			RelevanceScore: float64(match.Score),
+		})
+	}
+	usage := embeddings.Usage
+	response.Usage = &usage
+	response.SetHeader(embeddings.Header())
+	return
+}
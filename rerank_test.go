@@ -0,0 +1,92 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestRerankWithEmbeddings(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input []string `json:"input"`
+			Model string   `json:"model"`
+		}
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if len(request.Input) != 4 || request.Input[0] != "capital of France" ||
+			request.Model != string(openai.SmallEmbedding3) {
+			t.Errorf("unexpected embeddings request %+v", request)
+		}
+		// The query, then documents pointing away from, close to and exactly at the query.
+		fmt.Fprint(w, `{"object":"list","data":[
+			{"index":0,"embedding":[1,0]},
+			{"index":1,"embedding":[0,1]},
+			{"index":2,"embedding":[0.8,0.6]},
+			{"index":3,"embedding":[1,0]}],
+			"usage":{"prompt_tokens":12,"total_tokens":12}}`)
+	})
+
+	response, err := client.Rerank(context.Background(), openai.RerankRequest{
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany", "Lyon is in France", "Paris is the capital of France"},
+		TopN:      2,
+	})
+	checks.NoError(t, err, "Rerank error")
+	if len(response.Results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", response.Results)
+	}
+	if response.Results[0].Index != 2 || response.Results[0].Document != "Paris is the capital of France" ||
+		response.Results[0].RelevanceScore < 0.99 {
+		t.Errorf("unexpected first result %+v", response.Results[0])
+	}
+	if response.Results[1].Index != 1 || response.Results[1].RelevanceScore < 0.79 ||
+		response.Results[1].RelevanceScore > 0.81 {
+		t.Errorf("unexpected second result %+v", response.Results[1])
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage %+v", response.Usage)
+	}
+}
+
+func TestRerankWithEndpoint(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RerankEndpoint = "/rerank"
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/rerank", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.RerankRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if request.Model != "rerank-v3.5" || request.Query != "capital of France" || request.TopN != 1 {
+			t.Errorf("unexpected rerank request %+v", request)
+		}
+		fmt.Fprint(w, `{"id":"r1","results":[{"index":0,"relevance_score":0.1},{"index":1,"relevance_score":0.9}]}`)
+	})
+
+	response, err := client.Rerank(context.Background(), openai.RerankRequest{
+		Model:     "rerank-v3.5",
+		Query:     "capital of France",
+		Documents: []string{"Berlin is in Germany", "Paris is the capital of France"},
+		TopN:      1,
+	})
+	checks.NoError(t, err, "Rerank error")
+	if len(response.Results) != 1 || response.Results[0].Index != 1 ||
+		response.Results[0].Document != "Paris is the capital of France" {
+		t.Errorf("unexpected results %+v", response.Results)
+	}
+}
+
+func TestRerankNoDocuments(t *testing.T) {
+	client := openai.NewClient("token")
+	response, err := client.Rerank(context.Background(), openai.RerankRequest{Query: "anything"})
+	checks.NoError(t, err, "Rerank error")
+	if len(response.Results) != 0 {
+		t.Errorf("expected no results, got %+v", response.Results)
+	}
+}
@@ -0,0 +1,312 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCircuitOpen = errors.New("circuit breaker is open, request rejected")
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+	defaultRetryMinBackoff         = 500 * time.Millisecond
+	defaultRetryMaxBackoff         = 8 * time.Second
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before letting probes through. Defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent probe requests allowed while half-open. Defaults to 1.
+	HalfOpenProbes int
+}
+
+// CircuitBreaker rejects requests with ErrCircuitOpen after a run of consecutive upstream
+// failures (transport errors and 5xx responses), then lets a limited number of probes
+// through once OpenTimeout has passed. A successful probe closes the circuit again.
+// A CircuitBreaker is safe for concurrent use and may be shared between clients.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+	nowFunc  func() time.Time
+}
+
+// NewCircuitBreaker creates a CircuitBreaker, filling unset config fields with defaults.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultCircuitFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{config: config, nowFunc: time.Now}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.nowFunc().Sub(b.openedAt) >= b.config.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		if b.nowFunc().Sub(b.openedAt) < b.config.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probes = 0
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = CircuitClosed
+		b.failures = 0
+		b.probes = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.nowFunc()
+		b.probes = 0
+	}
+}
+
+// release returns a probe slot without recording an outcome, e.g. when the caller canceled.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// RetryBudget caps retries to a fraction of the requests sent within a time window, so that
+// many goroutines sharing a client cannot amplify load on a degraded upstream.
+// A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	window     time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	requests    int
+	retries     int
+	nowFunc     func() time.Time
+}
+
+// NewRetryBudget allows, within each window, minRetries retries plus ratio retries
+// per request sent. For example NewRetryBudget(0.1, 10, 10*time.Second) allows 10 retries
+// plus one more for every ten requests in a ten second window.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		window:     window,
+		nowFunc:    time.Now,
+	}
+}
+
+func (b *RetryBudget) rotate() {
+	now := b.nowFunc()
+	if now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.requests = 0
+		b.retries = 0
+	}
+}
+
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rotate()
+	b.requests++
+}
+
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rotate()
+	if float64(b.retries) >= float64(b.minRetries)+b.ratio*float64(b.requests) {
+		return false
+	}
+	b.retries++
+	return true
+}
+
+// RetryPolicy retries requests that failed with a transport error, 429 or 5xx status.
+// Streams are also sent again when they fail before delivering their first chunk, because the
+// connection dropped or the provider sent an overloaded or server error in the stream.
+// Requests whose body cannot be replayed are never retried.
+type RetryPolicy struct {
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts.
+	// They default to 500ms and 8s. Each backoff is shortened by a random amount of up to half
+	// of it, so that clients failing together do not retry in lockstep.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRetryAfter bounds the wait asked by the Retry-After header of 429 and 503 responses and
+	// overloaded stream errors, which replaces the backoff. Responses asking for a longer wait
+	// are returned without retry, their error carrying the RetryAfter for the caller to
+	// schedule. Defaults to 1 minute, negative values do not bound the wait.
+	MaxRetryAfter time.Duration
+	// Budget, if set, is consulted before every retry.
+	Budget *RetryBudget
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultRetryMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	d := minBackoff << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	// Equal jitter: half of the backoff is kept, the other half is random.
+	half := d / 2
+	return d - half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec // jitter needs no secure randomness
+}
+
+// resilientDoer wraps an HTTPDoer with the circuit breaker and retry policy of a ClientConfig.
+type resilientDoer struct {
+	doer    HTTPDoer
+	breaker *CircuitBreaker
+	retry   *RetryPolicy
+}
+
+func (d *resilientDoer) Do(req *http.Request) (*http.Response, error) {
+	if d.retry != nil && d.retry.Budget != nil {
+		d.retry.Budget.deposit()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if d.breaker != nil {
+			if err := d.breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := d.doer.Do(req)
+		if d.breaker != nil {
+			if errors.Is(err, context.Canceled) {
+				d.breaker.release()
+			} else {
+				d.breaker.record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+			}
+		}
+
+		var retryAfter time.Duration
+		if err == nil {
+			retryAfter = parseRetryAfter(resp.Header, resp.StatusCode, time.Now())
+		}
+		if !d.shouldRetry(req, resp, err, attempt, retryAfter) {
+			return resp, err
+		}
+		delay, _ := d.retry.delay(attempt, retryAfter)
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (d *resilientDoer) shouldRetry(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+	attempt int,
+	retryAfter time.Duration,
+) bool {
+	if d.retry == nil || attempt >= d.retry.MaxRetries || req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+		return false
+	}
+	if _, ok := d.retry.delay(attempt, retryAfter); !ok {
+		return false
+	}
+	return d.retry.Budget == nil || d.retry.Budget.withdraw()
+}
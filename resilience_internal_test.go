@@ -0,0 +1,24 @@
+package openai //nolint:testpackage // testing private method
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second} {
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 50; i++ {
+			d := policy.backoff(attempt)
+			if d < want/2 || d > want {
+				t.Fatalf("backoff of attempt %d is %v, want between %v and %v", attempt, d, want/2, want)
+			}
+			seen[d] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("expected randomized backoffs for attempt %d, got %v", attempt, seen)
+		}
+	}
+}
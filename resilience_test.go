@@ -0,0 +1,139 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupResilientTestServer(
+	configure func(*openai.ClientConfig),
+) (client *openai.Client, server *test.ServerTest, teardown func()) {
+	server = test.NewTestServer()
+	ts := server.OpenAITestServer()
+	ts.Start()
+	teardown = ts.Close
+	config := openai.DefaultConfig(test.GetTestToken())
+	config.BaseURL = ts.URL + "/v1"
+	configure(&config)
+	client = openai.NewClientWithConfig(config)
+	return
+}
+
+func TestRetryPolicyRetriesServerErrors(t *testing.T) {
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels should succeed after retries")
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryPolicyReplaysBody(t *testing.T) {
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/moderations", func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength == 0 {
+			t.Error("retried request has no body")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `{"id":"modr-1","results":[]}`)
+	})
+
+	_, err := client.Moderations(context.Background(), openai.ModerationRequest{Input: "hello"})
+	checks.NoError(t, err, "Moderations should succeed after retry")
+}
+
+func TestRetryBudgetLimitsRetries(t *testing.T) {
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{
+			MaxRetries: 5,
+			MinBackoff: time.Millisecond,
+			Budget:     openai.NewRetryBudget(0, 2, time.Minute),
+		}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	_, err = client.ListModels(context.Background())
+	checks.HasError(t, err, "ListModels should fail")
+	// 2 initial attempts plus the 2 retries the budget allows.
+	if calls != 4 {
+		t.Errorf("expected 4 attempts, got %d", calls)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy int32
+	breaker := openai.NewCircuitBreaker(openai.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      20 * time.Millisecond,
+	})
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.CircuitBreaker = breaker
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/models", func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, `{"object":"list","data":[]}`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := client.ListModels(ctx)
+		checks.HasError(t, err, "expected upstream error")
+	}
+	if breaker.State() != openai.CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", breaker.State())
+	}
+	_, err := client.ListModels(ctx)
+	if !errors.Is(err, openai.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if breaker.State() != openai.CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", breaker.State())
+	}
+	atomic.StoreInt32(&healthy, 1)
+	_, err = client.ListModels(ctx)
+	checks.NoError(t, err, "probe should succeed")
+	if breaker.State() != openai.CircuitClosed {
+		t.Errorf("expected closed circuit, got %s", breaker.State())
+	}
+}
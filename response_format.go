@@ -0,0 +1,66 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrNoChoices      = errors.New("response has no choices")
+	ErrContentRefused = errors.New("the model refused to answer")
+)
+
+// ResponseFormatJSONObject returns the response format of JSON mode. The messages must ask the
+// model to answer in JSON.
+func ResponseFormatJSONObject() *ChatCompletionResponseFormat {
+	return &ChatCompletionResponseFormat{Type: ChatCompletionResponseFormatTypeJSONObject}
+}
+
+// ResponseFormatJSONSchema returns the response format of structured outputs following schema,
+// e.g. a *jsonschema.Definition from jsonschema.GenerateSchemaForType.
+func ResponseFormatJSONSchema(name string, schema json.Marshaler, strict bool) *ChatCompletionResponseFormat {
+	return &ChatCompletionResponseFormat{
+		Type:       ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &ChatCompletionResponseFormatJSONSchema{Name: name, Schema: schema, Strict: strict},
+	}
+}
+
+// UnmarshalContent unmarshals the JSON answer of the first choice into v. Markdown code fences
+// that some models wrap JSON answers in are stripped. A refusal is reported as ErrContentRefused.
+func (r ChatCompletionResponse) UnmarshalContent(v any) error {
+	if len(r.Choices) == 0 {
+		return ErrNoChoices
+	}
+	message := r.Choices[0].Message
+	if message.Refusal != "" {
+		return fmt.Errorf("%w: %s", ErrContentRefused, message.Refusal)
+	}
+	return json.Unmarshal([]byte(stripCodeFences(message.Content)), v)
+}
+
+// stripCodeFences returns the JSON inside a markdown code block of content, or content itself
+// if it is not fenced.
+func stripCodeFences(content string) string {
+	content = strings.TrimSpace(content)
+	if json.Valid([]byte(content)) {
+		return content
+	}
+	start := strings.Index(content, "```")
+	if start < 0 {
+		return content
+	}
+	fenced := content[start+3:]
+	// Skip the info string, e.g. "json", which single-line fences follow with the value itself.
+	if info := strings.IndexFunc(fenced, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '{' || r == '['
+	}); info >= 0 {
+		fenced = fenced[info:]
+	}
+	if end := strings.Index(fenced, "```"); end >= 0 {
+		fenced = fenced[:end]
+	}
+	return strings.TrimSpace(fenced)
+}
@@ -0,0 +1,56 @@
+package openai_test
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func answer(content string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+	}}}
+}
+
+func TestUnmarshalContent(t *testing.T) {
+	type result struct {
+		City string `json:"city"`
+	}
+	for _, content := range []string{
+		`{"city":"Paris"}`,
+		"  {\"city\":\"Paris\"}\n",
+		"```json\n{\"city\":\"Paris\"}\n```",
+		"```\n{\"city\":\"Paris\"}\n```",
+		"Here you go:\n```json\n{\"city\":\"Paris\"}\n```\nAnything else?",
+		"```json {\"city\":\"Paris\"}```",
+		"```{\"city\":\"Paris\"}```",
+	} {
+		var v result
+		checks.NoError(t, answer(content).UnmarshalContent(&v), "UnmarshalContent error")
+		if v.City != "Paris" {
+			t.Errorf("unexpected result %+v for %q", v, content)
+		}
+	}
+
+	var v result
+	checks.HasError(t, answer("not json").UnmarshalContent(&v), "UnmarshalContent should fail on prose")
+	checks.ErrorIs(t, openai.ChatCompletionResponse{}.UnmarshalContent(&v), openai.ErrNoChoices, "expected no choices")
+
+	refused := answer("")
+	refused.Choices[0].Message.Refusal = "I can't help with that."
+	checks.ErrorIs(t, refused.UnmarshalContent(&v), openai.ErrContentRefused, "expected a refusal")
+}
+
+func TestResponseFormatConstructors(t *testing.T) {
+	if format := openai.ResponseFormatJSONObject(); format.Type != openai.ChatCompletionResponseFormatTypeJSONObject {
+		t.Errorf("unexpected format %+v", format)
+	}
+	schema := &jsonschema.Definition{Type: jsonschema.Object}
+	format := openai.ResponseFormatJSONSchema("answer", schema, true)
+	if format.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || format.JSONSchema.Name != "answer" ||
+		!format.JSONSchema.Strict || format.JSONSchema.Schema != schema {
+		t.Errorf("unexpected format %+v", format)
+	}
+}
@@ -0,0 +1,191 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const responsesSuffix = "/responses"
+
+// ResponseToolType is the type of a tool of the Responses API.
+type ResponseToolType string
+
+const (
+	ResponseToolTypeFunction    ResponseToolType = "function"
+	ResponseToolTypeComputerUse ResponseToolType = "computer_use_preview"
+	ResponseToolTypeFileSearch  ResponseToolType = "file_search"
+)
+
+// ResponseTool is a tool the model may use in the Responses API. The fields of the tool type
+// are set through the embedded struct matching Type.
+type ResponseTool struct {
+	Type ResponseToolType `json:"type"`
+	*ResponseFunctionTool
+	*ComputerUseTool
+	*FileSearchTool
+}
+
+// ResponseFunctionTool defines a function tool of the Responses API.
+type ResponseFunctionTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters"`
+	Strict      bool   `json:"strict"`
+}
+
+// ResponseInputItem is an item of the input of a response: a message or the output of a
+// tool call.
+type ResponseInputItem interface {
+	responseInputItem()
+}
+
+// ResponseInputMessage is a message of the input of a response.
+type ResponseInputMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (ResponseInputMessage) responseInputItem() {}
+
+// ResponseRequest creates a model response.
+type ResponseRequest struct {
+	Model string `json:"model"`
+	// Input is a string or a []ResponseInputItem.
+	Input        any            `json:"input"`
+	Instructions string         `json:"instructions,omitempty"`
+	Tools        []ResponseTool `json:"tools,omitempty"`
+	// Include adds optional data to the output, e.g. ResponseIncludeFileSearchResults.
+	Include []string `json:"include,omitempty"`
+	// PreviousResponseID continues the conversation of a previous response.
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+	// Truncation must be "auto" when using the computer use tool.
+	Truncation      string            `json:"truncation,omitempty"`
+	MaxOutputTokens int               `json:"max_output_tokens,omitempty"`
+	Temperature     *float32          `json:"temperature,omitempty"`
+	Store           *bool             `json:"store,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	User            string            `json:"user,omitempty"`
+	// SafetyIdentifier is a stable, hashed identifier of the end user, used by abuse monitoring.
+	SafetyIdentifier string `json:"safety_identifier,omitempty"`
+	// PromptCacheKey groups the requests sharing a long prompt prefix, improving their cache
+	// hit rate.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
+	// Background runs the response asynchronously. Poll it with RetrieveResponse, or stop it
+	// with CancelResponse.
+	Background bool `json:"background,omitempty"`
+}
+
+// ResponseOutputContent is a content part of an output message.
+type ResponseOutputContent struct {
+	Type    string `json:"type"`
+	Text    string `json:"text,omitempty"`
+	Refusal string `json:"refusal,omitempty"`
+}
+
+// ResponseOutputItem is an item of the output of a response. The fields set depend on Type:
+// "message", "function_call", "computer_call" or "file_search_call".
+type ResponseOutputItem struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+
+	// Role and Content are set for messages.
+	Role    string                  `json:"role,omitempty"`
+	Content []ResponseOutputContent `json:"content,omitempty"`
+
+	// CallID identifies function and computer calls in their outputs.
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+
+	// Action and PendingSafetyChecks are set for computer calls.
+	Action              *ComputerAction       `json:"action,omitempty"`
+	PendingSafetyChecks []ComputerSafetyCheck `json:"pending_safety_checks,omitempty"`
+
+	// Queries and Results are set for file search calls.
+	Queries []string           `json:"queries,omitempty"`
+	Results []FileSearchResult `json:"results,omitempty"`
+}
+
+// ResponseUsage is the token usage of a response.
+type ResponseUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// ResponseError is the error of a failed response.
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ModelResponse is a model response of the Responses API.
+type ModelResponse struct {
+	ID        string               `json:"id"`
+	Object    string               `json:"object"`
+	CreatedAt int64                `json:"created_at"`
+	Status    string               `json:"status"`
+	Model     string               `json:"model"`
+	Output    []ResponseOutputItem `json:"output"`
+	Usage     *ResponseUsage       `json:"usage,omitempty"`
+	Error     *ResponseError       `json:"error,omitempty"`
+
+	httpHeader
+}
+
+// OutputText returns the text of the output messages.
+func (r ModelResponse) OutputText() string {
+	var text strings.Builder
+	for _, item := range r.Output {
+		if item.Type != "message" {
+			continue
+		}
+		for _, content := range item.Content {
+			if content.Type == "output_text" {
+				text.WriteString(content.Text)
+			}
+		}
+	}
+	return text.String()
+}
+
+// CreateResponse creates a model response.
+func (c *Client) CreateResponse(ctx context.Context, request ResponseRequest) (response ModelResponse, err error) {
+	request.Model = c.config.mapModel(request.Model)
+	req, err := c.newRequest(
+		ctx,
+		http.MethodPost,
+		c.fullURL(responsesSuffix, withModel(request.Model)),
+		withBody(request),
+	)
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// RetrieveResponse retrieves a stored response.
+func (c *Client) RetrieveResponse(ctx context.Context, responseID string) (response ModelResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(responsesSuffix+"/"+responseID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// CancelResponse cancels a response created with Background set.
+func (c *Client) CancelResponse(ctx context.Context, responseID string) (response ModelResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(responsesSuffix+"/"+responseID+"/cancel"))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
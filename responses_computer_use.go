@@ -0,0 +1,126 @@
+package openai
+
+// ComputerEnvironment is the environment controlled by the computer use tool.
+type ComputerEnvironment string
+
+const (
+	ComputerEnvironmentBrowser ComputerEnvironment = "browser"
+	ComputerEnvironmentMac     ComputerEnvironment = "mac"
+	ComputerEnvironmentWindows ComputerEnvironment = "windows"
+	ComputerEnvironmentUbuntu  ComputerEnvironment = "ubuntu"
+)
+
+// ComputerUseTool lets the model operate a computer through screenshots and input actions.
+type ComputerUseTool struct {
+	DisplayWidth  int                 `json:"display_width"`
+	DisplayHeight int                 `json:"display_height"`
+	Environment   ComputerEnvironment `json:"environment"`
+}
+
+// NewComputerUseTool returns the computer use tool for a display of the given size.
+func NewComputerUseTool(width, height int, environment ComputerEnvironment) ResponseTool {
+	return ResponseTool{
+		Type:            ResponseToolTypeComputerUse,
+		ComputerUseTool: &ComputerUseTool{DisplayWidth: width, DisplayHeight: height, Environment: environment},
+	}
+}
+
+// ComputerActionType is the type of an action requested by a computer call.
+type ComputerActionType string
+
+const (
+	ComputerActionClick       ComputerActionType = "click"
+	ComputerActionDoubleClick ComputerActionType = "double_click"
+	ComputerActionDrag        ComputerActionType = "drag"
+	ComputerActionKeypress    ComputerActionType = "keypress"
+	ComputerActionMove        ComputerActionType = "move"
+	ComputerActionScreenshot  ComputerActionType = "screenshot"
+	ComputerActionScroll      ComputerActionType = "scroll"
+	ComputerActionTypeText    ComputerActionType = "type"
+	ComputerActionWait        ComputerActionType = "wait"
+)
+
+// ComputerMouseButton is the button of a click action.
+type ComputerMouseButton string
+
+const (
+	ComputerMouseButtonLeft    ComputerMouseButton = "left"
+	ComputerMouseButtonRight   ComputerMouseButton = "right"
+	ComputerMouseButtonWheel   ComputerMouseButton = "wheel"
+	ComputerMouseButtonBack    ComputerMouseButton = "back"
+	ComputerMouseButtonForward ComputerMouseButton = "forward"
+)
+
+// ComputerPoint is a position on the display, in pixels.
+type ComputerPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ComputerAction is an action requested by a computer call. The fields set depend on Type:
+//   - click: Button, X, Y
+//   - double_click, move: X, Y
+//   - drag: Path
+//   - keypress: Keys
+//   - scroll: X, Y, ScrollX, ScrollY
+//   - type: Text
+//   - screenshot, wait: none
+type ComputerAction struct {
+	Type    ComputerActionType  `json:"type"`
+	Button  ComputerMouseButton `json:"button,omitempty"`
+	X       int                 `json:"x,omitempty"`
+	Y       int                 `json:"y,omitempty"`
+	Path    []ComputerPoint     `json:"path,omitempty"`
+	Keys    []string            `json:"keys,omitempty"`
+	ScrollX int                 `json:"scroll_x,omitempty"`
+	ScrollY int                 `json:"scroll_y,omitempty"`
+	Text    string              `json:"text,omitempty"`
+}
+
+// ComputerSafetyCheck is a safety check raised on a computer call, e.g. for a suspected prompt
+// injection. Pending checks must be acknowledged in the call output to proceed.
+type ComputerSafetyCheck struct {
+	ID      string `json:"id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ComputerScreenshot is the screenshot taken after performing a computer action.
+type ComputerScreenshot struct {
+	Type string `json:"type"`
+	// ImageURL is a URL or a base64 data URL of the screenshot.
+	ImageURL string `json:"image_url,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+}
+
+// ComputerCallOutput is the input item answering a computer call with a screenshot.
+type ComputerCallOutput struct {
+	Type                     string                `json:"type"`
+	CallID                   string                `json:"call_id"`
+	Output                   ComputerScreenshot    `json:"output"`
+	AcknowledgedSafetyChecks []ComputerSafetyCheck `json:"acknowledged_safety_checks,omitempty"`
+}
+
+func (ComputerCallOutput) responseInputItem() {}
+
+// NewComputerCallOutput answers the computer call callID with a screenshot, acknowledging the
+// given pending safety checks.
+func NewComputerCallOutput(callID, screenshotURL string, acknowledged []ComputerSafetyCheck) ComputerCallOutput {
+	return ComputerCallOutput{
+		Type:                     "computer_call_output",
+		CallID:                   callID,
+		Output:                   ComputerScreenshot{Type: "computer_screenshot", ImageURL: screenshotURL},
+		AcknowledgedSafetyChecks: acknowledged,
+	}
+}
+
+// ComputerCalls returns the computer calls of the output of a response.
+func (r ModelResponse) ComputerCalls() []ResponseOutputItem {
+	var calls []ResponseOutputItem
+	for _, item := range r.Output {
+		if item.Type == "computer_call" {
+			calls = append(calls, item)
+		}
+	}
+	return calls
+}
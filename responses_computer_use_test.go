@@ -0,0 +1,82 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestComputerUse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	var calls int
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var request struct {
+			Tools              []map[string]any `json:"tools"`
+			Input              json.RawMessage  `json:"input"`
+			PreviousResponseID string           `json:"previous_response_id"`
+			Truncation         string           `json:"truncation"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request.Truncation != "auto" || len(request.Tools) != 1 || request.Tools[0]["environment"] != "browser" {
+			t.Errorf("unexpected request: %+v", request)
+		}
+		if calls == 1 {
+			fmt.Fprint(w, `{"id":"resp_1","object":"response","output":[{"type":"computer_call","id":"cu_1",
+				"call_id":"call_1","status":"completed","action":{"type":"click","button":"left","x":10,"y":20},
+				"pending_safety_checks":[{"id":"sc_1","code":"malicious_instructions","message":"careful"}]}]}`)
+			return
+		}
+		var input []openai.ComputerCallOutput
+		if err := json.Unmarshal(request.Input, &input); err != nil || len(input) != 1 {
+			t.Fatalf("unexpected input: %s", request.Input)
+		}
+		output := input[0]
+		if request.PreviousResponseID != "resp_1" || output.Type != "computer_call_output" ||
+			output.CallID != "call_1" || output.Output.Type != "computer_screenshot" ||
+			output.Output.ImageURL != "data:image/png;base64,AAAA" ||
+			len(output.AcknowledgedSafetyChecks) != 1 || output.AcknowledgedSafetyChecks[0].ID != "sc_1" {
+			t.Errorf("unexpected computer call output: %+v", output)
+		}
+		fmt.Fprint(w, `{"id":"resp_2","object":"response","output":[{"type":"computer_call","id":"cu_2",
+			"call_id":"call_2","action":{"type":"drag","path":[{"x":1,"y":2},{"x":3,"y":4}]}}]}`)
+	})
+
+	ctx := context.Background()
+	request := openai.ResponseRequest{
+		Model:      "computer-use-preview",
+		Input:      "Open the settings",
+		Tools:      []openai.ResponseTool{openai.NewComputerUseTool(1024, 768, openai.ComputerEnvironmentBrowser)},
+		Truncation: "auto",
+	}
+	response, err := client.CreateResponse(ctx, request)
+	checks.NoError(t, err, "CreateResponse error")
+
+	computerCalls := response.ComputerCalls()
+	if len(computerCalls) != 1 {
+		t.Fatalf("expected 1 computer call, got %d", len(computerCalls))
+	}
+	call := computerCalls[0]
+	if call.Action.Type != openai.ComputerActionClick || call.Action.Button != openai.ComputerMouseButtonLeft ||
+		call.Action.X != 10 || call.Action.Y != 20 {
+		t.Errorf("unexpected action: %+v", call.Action)
+	}
+
+	request.PreviousResponseID = response.ID
+	request.Input = []openai.ResponseInputItem{
+		openai.NewComputerCallOutput(call.CallID, "data:image/png;base64,AAAA", call.PendingSafetyChecks),
+	}
+	response, err = client.CreateResponse(ctx, request)
+	checks.NoError(t, err, "CreateResponse error")
+	action := response.ComputerCalls()[0].Action
+	if action.Type != openai.ComputerActionDrag || len(action.Path) != 2 || action.Path[1] != (openai.ComputerPoint{X: 3, Y: 4}) {
+		t.Errorf("unexpected action: %+v", action)
+	}
+}
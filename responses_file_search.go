@@ -0,0 +1,88 @@
+package openai
+
+import "sort"
+
+// ResponseIncludeFileSearchResults includes the results of file_search calls in a response.
+const ResponseIncludeFileSearchResults = "file_search_call.results"
+
+// FileSearchTool searches the files of vector stores.
+type FileSearchTool struct {
+	VectorStoreIDs []string `json:"vector_store_ids"`
+	// MaxNumResults is the maximum number of results, between 1 and 50.
+	MaxNumResults  int                       `json:"max_num_results,omitempty"`
+	RankingOptions *FileSearchRankingOptions `json:"ranking_options,omitempty"`
+	// Filters restricts the search to files whose attributes match.
+	Filters *FileSearchFilter `json:"filters,omitempty"`
+}
+
+// NewFileSearchTool returns the file_search tool for the given vector stores.
+func NewFileSearchTool(vectorStoreIDs ...string) ResponseTool {
+	return ResponseTool{
+		Type:           ResponseToolTypeFileSearch,
+		FileSearchTool: &FileSearchTool{VectorStoreIDs: vectorStoreIDs},
+	}
+}
+
+// FileSearchFilterType is a comparison operator or a logical operator of a file search filter.
+type FileSearchFilterType string
+
+const (
+	FileSearchFilterEq  FileSearchFilterType = "eq"
+	FileSearchFilterNe  FileSearchFilterType = "ne"
+	FileSearchFilterGt  FileSearchFilterType = "gt"
+	FileSearchFilterGte FileSearchFilterType = "gte"
+	FileSearchFilterLt  FileSearchFilterType = "lt"
+	FileSearchFilterLte FileSearchFilterType = "lte"
+	FileSearchFilterAnd FileSearchFilterType = "and"
+	FileSearchFilterOr  FileSearchFilterType = "or"
+)
+
+// FileSearchFilter filters files on their attributes. Comparison filters compare the attribute
+// Key with Value, while "and" and "or" filters combine Filters.
+type FileSearchFilter struct {
+	Type FileSearchFilterType `json:"type"`
+	Key  string               `json:"key,omitempty"`
+	// Value is a string, a number or a boolean.
+	Value   any                `json:"value,omitempty"`
+	Filters []FileSearchFilter `json:"filters,omitempty"`
+}
+
+// FileSearchCompare returns a filter comparing the attribute key with value.
+func FileSearchCompare(key string, operator FileSearchFilterType, value any) FileSearchFilter {
+	return FileSearchFilter{Type: operator, Key: key, Value: value}
+}
+
+// FileSearchAnd returns a filter matching files that match all filters.
+func FileSearchAnd(filters ...FileSearchFilter) FileSearchFilter {
+	return FileSearchFilter{Type: FileSearchFilterAnd, Filters: filters}
+}
+
+// FileSearchOr returns a filter matching files that match any of filters.
+func FileSearchOr(filters ...FileSearchFilter) FileSearchFilter {
+	return FileSearchFilter{Type: FileSearchFilterOr, Filters: filters}
+}
+
+// FileSearchResult is a chunk found by a file_search call. Results are only returned when the
+// request includes ResponseIncludeFileSearchResults.
+type FileSearchResult struct {
+	FileID     string         `json:"file_id"`
+	Filename   string         `json:"filename"`
+	Score      float64        `json:"score"`
+	Text       string         `json:"text"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// FileSearchResults returns the results of the file_search calls of a response, best score
+// first.
+func (r ModelResponse) FileSearchResults() []FileSearchResult {
+	var results []FileSearchResult
+	for _, item := range r.Output {
+		if item.Type == "file_search_call" {
+			results = append(results, item.Results...)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
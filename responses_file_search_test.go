@@ -0,0 +1,71 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestFileSearchToolMarshal(t *testing.T) {
+	tool := openai.NewFileSearchTool("vs_1")
+	tool.MaxNumResults = 5
+	tool.RankingOptions = &openai.FileSearchRankingOptions{Ranker: "auto", ScoreThreshold: 0.5}
+	filters := openai.FileSearchAnd(
+		openai.FileSearchCompare("region", openai.FileSearchFilterEq, "eu"),
+		openai.FileSearchOr(
+			openai.FileSearchCompare("year", openai.FileSearchFilterGte, 2024),
+			openai.FileSearchCompare("draft", openai.FileSearchFilterNe, true),
+		),
+	)
+	tool.Filters = &filters
+
+	b, err := json.Marshal(tool)
+	checks.NoError(t, err)
+	want := `{"type":"file_search","vector_store_ids":["vs_1"],"max_num_results":5,` +
+		`"ranking_options":{"ranker":"auto","score_threshold":0.5},"filters":{"type":"and","filters":[` +
+		`{"type":"eq","key":"region","value":"eu"},{"type":"or","filters":[` +
+		`{"type":"gte","key":"year","value":2024},{"type":"ne","key":"draft","value":true}]}]}}`
+	if string(b) != want {
+		t.Errorf("unexpected tool:\n got %s\nwant %s", b, want)
+	}
+}
+
+func TestFileSearchResults(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ResponseRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if len(request.Include) != 1 || request.Include[0] != openai.ResponseIncludeFileSearchResults {
+			t.Errorf("unexpected include: %v", request.Include)
+		}
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","output":[
+			{"type":"file_search_call","id":"fs_1","status":"completed","queries":["refund policy"],"results":[
+				{"file_id":"file_1","filename":"a.md","score":0.4,"text":"A"},
+				{"file_id":"file_2","filename":"b.md","score":0.9,"text":"B","attributes":{"region":"eu"}}]},
+			{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Done"}]}]}`)
+	})
+
+	response, err := client.CreateResponse(context.Background(), openai.ResponseRequest{
+		Model:   "gpt-4.1",
+		Input:   "What is the refund policy?",
+		Tools:   []openai.ResponseTool{openai.NewFileSearchTool("vs_1")},
+		Include: []string{openai.ResponseIncludeFileSearchResults},
+	})
+	checks.NoError(t, err, "CreateResponse error")
+
+	if queries := response.Output[0].Queries; len(queries) != 1 || queries[0] != "refund policy" {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+	results := response.FileSearchResults()
+	if len(results) != 2 || results[0].FileID != "file_2" || results[0].Attributes["region"] != "eu" ||
+		results[1].Score != 0.4 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
@@ -0,0 +1,71 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request["input"] != "Hello" || request["model"] != "gpt-4.1" {
+			t.Errorf("unexpected request: %v", request)
+		}
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"completed","output":[
+			{"type":"reasoning","id":"rs_1"},
+			{"type":"message","id":"msg_1","role":"assistant","content":[
+				{"type":"output_text","text":"Hi "},{"type":"output_text","text":"there"}]}],
+			"usage":{"input_tokens":3,"output_tokens":2,"total_tokens":5}}`)
+	})
+	server.RegisterHandler("/v1/responses/resp_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"completed"}`)
+	})
+
+	response, err := client.CreateResponse(context.Background(), openai.ResponseRequest{
+		Model: "gpt-4.1",
+		Input: "Hello",
+	})
+	checks.NoError(t, err, "CreateResponse error")
+	if got := response.OutputText(); got != "Hi there" {
+		t.Errorf("unexpected output text: %q", got)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 5 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+
+	response, err = client.RetrieveResponse(context.Background(), "resp_1")
+	checks.NoError(t, err, "RetrieveResponse error")
+	if response.ID != "resp_1" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestResponseToolMarshal(t *testing.T) {
+	tools := []openai.ResponseTool{
+		openai.NewComputerUseTool(1024, 768, openai.ComputerEnvironmentBrowser),
+		{
+			Type:                 openai.ResponseToolTypeFunction,
+			ResponseFunctionTool: &openai.ResponseFunctionTool{Name: "lookup", Parameters: map[string]any{}},
+		},
+	}
+	b, err := json.Marshal(tools)
+	checks.NoError(t, err)
+	want := `[{"type":"computer_use_preview","display_width":1024,"display_height":768,"environment":"browser"},` +
+		`{"type":"function","name":"lookup","parameters":{},"strict":false}]`
+	if string(b) != want {
+		t.Errorf("unexpected tools:\n got %s\nwant %s", b, want)
+	}
+}
@@ -0,0 +1,216 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoRetrievalQuery is returned by Retriever when the request has no user message to
+// retrieve context for.
+var ErrNoRetrievalQuery = errors.New("no user message to retrieve context for")
+
+// DefaultRetrievalInstructions introduce the retrieved sources in the context message.
+const DefaultRetrievalInstructions = "Answer using the following sources when they are relevant. " +
+	"Cite the sources you use with their number in brackets, such as [1]."
+
+// Citation is a chunk retrieved by a Retriever and injected into a chat request.
+type Citation struct {
+	// Number is the marker of the chunk in the context, from 1, cited as [Number].
+	Number     int
+	FileID     string
+	Filename   string
+	Score      float64
+	Text       string
+	Attributes map[string]any
+}
+
+// Retriever adds the chunks of a vector index most relevant to the last user message to chat
+// requests, for retrieval-augmented generation:
+//
+//	retriever := openai.Retriever{Index: index, VectorStoreID: store.ID, MaxContextTokens: 2000}
+//	response, err := retriever.CreateChatCompletion(ctx, client, request)
+//	for _, citation := range response.CitedIn(response.Choices[0].Message.Content) {
+//		fmt.Printf("[%d] %s\n", citation.Number, citation.Filename)
+//	}
+//
+// The Index can be a Client, searching a hosted vector store, or a LocalVectorStore.
+type Retriever struct {
+	Index         VectorIndex
+	VectorStoreID string
+	// TopK is the number of chunks retrieved, 5 by default.
+	TopK           int
+	Filters        *FileSearchFilter
+	ScoreThreshold float64
+	// MaxContextTokens, if positive, bounds the tokens of the injected sources. The chunks that
+	// do not fit are left out, the most relevant ones are kept.
+	MaxContextTokens int
+	// Tokenizer counts the tokens of the sources. Defaults to an estimate of 4 bytes per token.
+	Tokenizer Tokenizer
+	// FormatCitation formats a source of the context, by default as "[1] filename" followed by
+	// the text of the chunk on the next lines.
+	FormatCitation func(citation Citation) string
+	// Instructions precede the sources in the context message. Defaults to
+	// DefaultRetrievalInstructions.
+	Instructions string
+}
+
+// RetrievalResponse is a chat completion with the citations of its context.
+type RetrievalResponse struct {
+	ChatCompletionResponse
+	// Citations are the chunks injected into the context, by number.
+	Citations []Citation
+}
+
+// CitedIn returns the citations whose marker, such as [1], appears in content.
+func (r RetrievalResponse) CitedIn(content string) []Citation {
+	var cited []Citation
+	for _, citation := range r.Citations {
+		if strings.Contains(content, fmt.Sprintf("[%d]", citation.Number)) {
+			cited = append(cited, citation)
+		}
+	}
+	return cited
+}
+
+// CreateChatCompletion augments request with Augment and sends it with client.
+func (r Retriever) CreateChatCompletion(
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) (response RetrievalResponse, err error) {
+	request, response.Citations, err = r.Augment(ctx, request)
+	if err != nil {
+		return
+	}
+	response.ChatCompletionResponse, err = client.CreateChatCompletion(ctx, request)
+	return
+}
+
+// Augment searches the index for the last user message of request and returns a copy of
+// request with a system message listing the retrieved chunks inserted before that user
+// message, along with the citations of the chunks. No message is inserted if nothing relevant
+// was found.
+func (r Retriever) Augment(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (ChatCompletionRequest, []Citation, error) {
+	queryIndex := -1
+	for i := len(request.Messages) - 1; i >= 0; i-- {
+		if request.Messages[i].Role == ChatMessageRoleUser {
+			queryIndex = i
+			break
+		}
+	}
+	if queryIndex < 0 {
+		return request, nil, ErrNoRetrievalQuery
+	}
+	query := messageText(request.Messages[queryIndex])
+	if strings.TrimSpace(query) == "" {
+		return request, nil, ErrNoRetrievalQuery
+	}
+
+	citations, err := r.Retrieve(ctx, query)
+	if err != nil || len(citations) == 0 {
+		return request, citations, err
+	}
+
+	var sources strings.Builder
+	sources.WriteString(r.instructions())
+	for _, citation := range citations {
+		sources.WriteString("\n\n")
+		sources.WriteString(r.format(citation))
+	}
+	messages := make([]ChatCompletionMessage, 0, len(request.Messages)+1)
+	messages = append(messages, request.Messages[:queryIndex]...)
+	messages = append(messages, ChatCompletionMessage{Role: ChatMessageRoleSystem, Content: sources.String()})
+	messages = append(messages, request.Messages[queryIndex:]...)
+	request.Messages = messages
+	return request, citations, nil
+}
+
+// Retrieve returns the chunks most relevant to query that fit in MaxContextTokens, numbered
+// from 1.
+func (r Retriever) Retrieve(ctx context.Context, query string) ([]Citation, error) {
+	topK := r.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+	search := VectorStoreSearchRequest{Query: query, MaxNumResults: topK, Filters: r.Filters}
+	if r.ScoreThreshold > 0 {
+		search.RankingOptions = &FileSearchRankingOptions{ScoreThreshold: r.ScoreThreshold}
+	}
+	results, err := r.Index.SearchVectorStore(ctx, r.VectorStoreID, search)
+	if err != nil {
+		return nil, err
+	}
+
+	var citations []Citation
+	tokens := 0
+	for _, result := range results.Data {
+		var text strings.Builder
+		for _, content := range result.Content {
+			text.WriteString(content.Text)
+		}
+		citation := Citation{
+			Number:     len(citations) + 1,
+			FileID:     result.FileID,
+			Filename:   result.Filename,
+			Score:      result.Score,
+			Text:       text.String(),
+			Attributes: result.Attributes,
+		}
+		if r.MaxContextTokens > 0 {
+			count, countErr := r.countTokens(r.format(citation))
+			if countErr != nil {
+				return nil, countErr
+			}
+			if tokens+count > r.MaxContextTokens {
+				continue
+			}
+			tokens += count
+		}
+		citations = append(citations, citation)
+	}
+	return citations, nil
+}
+
+func (r Retriever) instructions() string {
+	if r.Instructions == "" {
+		return DefaultRetrievalInstructions
+	}
+	return r.Instructions
+}
+
+func (r Retriever) format(citation Citation) string {
+	if r.FormatCitation != nil {
+		return r.FormatCitation(citation)
+	}
+	return fmt.Sprintf("[%d] %s\n%s", citation.Number, citation.Filename, citation.Text)
+}
+
+func (r Retriever) countTokens(text string) (int, error) {
+	if r.Tokenizer == nil {
+		return (len(text) + 3) / 4, nil
+	}
+	tokens, err := r.Tokenizer.Encode(text)
+	return len(tokens), err
+}
+
+// messageText returns the content of a message, or the concatenation of its text parts.
+func messageText(message ChatCompletionMessage) string {
+	if message.Content != "" || len(message.MultiContent) == 0 {
+		return message.Content
+	}
+	var text []string
+	for _, part := range message.MultiContent {
+		switch part := part.(type) {
+		case TextPart:
+			text = append(text, part.Text)
+		case *TextPart:
+			text = append(text, part.Text)
+		}
+	}
+	return strings.Join(text, "\n")
+}
@@ -0,0 +1,130 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func setupRetrievalIndex(t *testing.T) (*openai.LocalVectorStore, string) {
+	t.Helper()
+	ctx := context.Background()
+	index := openai.NewLocalVectorStore(&keywordEmbedder{})
+	store, err := index.CreateVectorStore(ctx, openai.VectorStoreRequest{Name: "pets"})
+	checks.NoError(t, err, "CreateVectorStore error")
+	for filename, text := range map[string]string{
+		"cats.txt": "A cat sleeps sixteen hours a day.",
+		"dogs.txt": "A dog needs a walk, and a dog likes a cat.",
+		"fish.txt": "A fish needs clean water.",
+	} {
+		_, err = index.AddVectorStoreText(ctx, store.ID, filename, text, nil)
+		checks.NoError(t, err, "AddVectorStoreText error")
+	}
+	return index, store.ID
+}
+
+func TestRetrieverCreateChatCompletion(t *testing.T) {
+	index, storeID := setupRetrievalIndex(t)
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	var messages []openai.ChatCompletionMessage
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.ChatCompletionRequest
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		messages = request.Messages
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"message":{"role":"assistant",`+
+			`"content":"Cats sleep a lot [1]."},"finish_reason":"stop"}]}`)
+	})
+
+	retriever := openai.Retriever{Index: index, VectorStoreID: storeID, TopK: 2}
+	response, err := retriever.CreateChatCompletion(context.Background(), client, openai.ChatCompletionRequest{
+		Model: openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "You are a vet."},
+			{Role: openai.ChatMessageRoleUser, Content: "How long does a cat sleep?"},
+		},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	if len(messages) != 3 || messages[0].Content != "You are a vet." || messages[2].Role != openai.ChatMessageRoleUser {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	sources := messages[1].Content
+	if messages[1].Role != openai.ChatMessageRoleSystem ||
+		!strings.HasPrefix(sources, openai.DefaultRetrievalInstructions) ||
+		!strings.Contains(sources, "[1] cats.txt\nA cat sleeps sixteen hours a day.") ||
+		!strings.Contains(sources, "[2] dogs.txt\n") || strings.Contains(sources, "fish.txt") {
+		t.Errorf("unexpected sources message %q", sources)
+	}
+
+	if len(response.Citations) != 2 || response.Citations[0].Filename != "cats.txt" ||
+		response.Citations[1].Number != 2 {
+		t.Errorf("unexpected citations %+v", response.Citations)
+	}
+	cited := response.CitedIn(response.Choices[0].Message.Content)
+	if len(cited) != 1 || cited[0].Filename != "cats.txt" {
+		t.Errorf("unexpected cited sources %+v", cited)
+	}
+}
+
+func TestRetrieverAugmentTokenBudget(t *testing.T) {
+	index, storeID := setupRetrievalIndex(t)
+	// One token per word.
+	tokenizer := openai.TokenizerFunc(func(text string) ([]int, error) {
+		return make([]int, len(strings.Fields(text))), nil
+	})
+	retriever := openai.Retriever{
+		Index:            index,
+		VectorStoreID:    storeID,
+		MaxContextTokens: 12,
+		Tokenizer:        tokenizer,
+		Instructions:     "Sources:",
+		FormatCitation: func(citation openai.Citation) string {
+			return fmt.Sprintf("<source id=%d>%s</source>", citation.Number, citation.Text)
+		},
+	}
+	request, citations, err := retriever.Augment(context.Background(), openai.ChatCompletionRequest{
+		Messages: []openai.ChatCompletionMessage{{
+			Role:         openai.ChatMessageRoleUser,
+			MultiContent: []openai.ChatMessagePart{openai.TextPart{Text: "Tell me about a dog"}},
+		}},
+	})
+	checks.NoError(t, err, "Augment error")
+
+	// The formatted dogs source has 12 words and the cats source 8, so only the first fits.
+	if len(citations) != 1 || citations[0].Filename != "dogs.txt" {
+		t.Errorf("unexpected citations %+v", citations)
+	}
+	want := "Sources:\n\n<source id=1>A dog needs a walk, and a dog likes a cat.</source>"
+	if len(request.Messages) != 2 || request.Messages[0].Content != want {
+		t.Errorf("unexpected messages %+v", request.Messages)
+	}
+}
+
+func TestRetrieverAugmentNoQuery(t *testing.T) {
+	index, storeID := setupRetrievalIndex(t)
+	retriever := openai.Retriever{Index: index, VectorStoreID: storeID}
+	_, _, err := retriever.Augment(context.Background(), openai.ChatCompletionRequest{
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: "Hi"}},
+	})
+	if !errors.Is(err, openai.ErrNoRetrievalQuery) {
+		t.Errorf("expected ErrNoRetrievalQuery, got %v", err)
+	}
+
+	retriever.ScoreThreshold = 0.1
+	request := openai.ChatCompletionRequest{
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "What about birds?"}},
+	}
+	augmented, citations, err := retriever.Augment(context.Background(), request)
+	checks.NoError(t, err, "Augment error")
+	if len(citations) != 0 || len(augmented.Messages) != 1 {
+		t.Errorf("expected no context for an unrelated query, got %+v", augmented.Messages)
+	}
+}
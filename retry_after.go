@@ -0,0 +1,79 @@
+package openai
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMaxRetryAfter = time.Minute
+
+// anthropicRateLimits are the limits of the anthropic-ratelimit-* headers.
+var anthropicRateLimits = []string{"requests", "tokens", "input-tokens", "output-tokens"}
+
+// parseRetryAfter returns how long to wait before retrying a request rejected with a response of
+// status statusCode and header, or zero if unknown. It reads the retry-after-ms header of OpenAI,
+// then the Retry-After header in seconds or as an HTTP date. Without them, a 429 waits for the
+// reset of its exhausted x-ratelimit-* or anthropic-ratelimit-* limits.
+func parseRetryAfter(header http.Header, statusCode int, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			if seconds <= 0 {
+				return 0
+			}
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			if d := date.Sub(now); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	if statusCode != http.StatusTooManyRequests {
+		return 0
+	}
+
+	var wait time.Duration
+	for _, limit := range []string{"requests", "tokens"} {
+		if header.Get("x-ratelimit-remaining-"+limit) != "0" {
+			continue
+		}
+		if d, err := time.ParseDuration(header.Get("x-ratelimit-reset-" + limit)); err == nil && d > wait {
+			wait = d
+		}
+	}
+	for _, limit := range anthropicRateLimits {
+		if header.Get("anthropic-ratelimit-"+limit+"-remaining") != "0" {
+			continue
+		}
+		reset, err := time.Parse(time.RFC3339, header.Get("anthropic-ratelimit-"+limit+"-reset"))
+		if d := reset.Sub(now); err == nil && d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+func (p *RetryPolicy) maxRetryAfter() time.Duration {
+	if p.MaxRetryAfter == 0 {
+		return defaultMaxRetryAfter
+	}
+	return p.MaxRetryAfter
+}
+
+// delay returns the time to wait before the retry following attempt: retryAfter if the server
+// asked for one, the backoff otherwise. It reports false if retryAfter exceeds MaxRetryAfter.
+func (p *RetryPolicy) delay(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if retryAfter <= 0 {
+		return p.backoff(attempt), true
+	}
+	if maxRetryAfter := p.maxRetryAfter(); maxRetryAfter > 0 && retryAfter > maxRetryAfter {
+		return 0, false
+	}
+	return retryAfter, true
+}
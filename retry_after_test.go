@@ -0,0 +1,137 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+var retryAfterRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4oMini,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+}
+
+func TestStreamRateLimitRetryAfter(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "1s")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`)
+	})
+
+	_, err := client.CreateChatCompletionStream(context.Background(), retryAfterRequest)
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 APIError, got %v", err)
+	}
+	if apiErr.RetryAfter != 7*time.Second {
+		t.Errorf("expected the Retry-After header to take precedence, got %s", apiErr.RetryAfter)
+	}
+	if apiErr.RateLimitHeaders.LimitRequests != 500 || apiErr.RateLimitHeaders.ResetRequests != "1s" {
+		t.Errorf("unexpected rate limit headers %+v", apiErr.RateLimitHeaders)
+	}
+}
+
+func TestRetryAfterSources(t *testing.T) {
+	resetAt := time.Now().Add(30 * time.Second).UTC()
+	for _, test := range []struct {
+		name   string
+		status int
+		header map[string]string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"milliseconds", http.StatusTooManyRequests, map[string]string{"retry-after-ms": "1500", "Retry-After": "2"},
+			1500 * time.Millisecond, 1500 * time.Millisecond},
+		{"HTTP date", http.StatusServiceUnavailable, map[string]string{"Retry-After": resetAt.Format(http.TimeFormat)},
+			25 * time.Second, 31 * time.Second},
+		{"exhausted token limit", http.StatusTooManyRequests, map[string]string{
+			"x-ratelimit-remaining-requests": "10", "x-ratelimit-reset-requests": "20s",
+			"x-ratelimit-remaining-tokens": "0", "x-ratelimit-reset-tokens": "6m0s",
+		}, 6 * time.Minute, 6 * time.Minute},
+		{"Anthropic limit", http.StatusTooManyRequests, map[string]string{
+			"anthropic-ratelimit-requests-remaining": "0",
+			"anthropic-ratelimit-requests-reset":     resetAt.Format(time.RFC3339),
+		}, 25 * time.Second, 31 * time.Second},
+		{"server error without header", http.StatusServiceUnavailable, map[string]string{
+			"x-ratelimit-remaining-tokens": "0", "x-ratelimit-reset-tokens": "6s",
+		}, 0, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			client, server, teardown := setupOpenAITestServer()
+			defer teardown()
+			server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+				for key, value := range test.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, `{"error":{"message":"slow down","type":"server_error"}}`)
+			})
+			_, err := client.CreateChatCompletion(context.Background(), retryAfterRequest)
+			var apiErr *openai.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if apiErr.RetryAfter < test.min || apiErr.RetryAfter > test.max {
+				t.Errorf("expected a RetryAfter between %s and %s, got %s", test.min, test.max, apiErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyHonorsRetryAfter(t *testing.T) {
+	var calls int
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 2, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("retry-after-ms", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"Rate limit reached","type":"requests"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.CreateChatCompletion(ctx, retryAfterRequest)
+	if err != nil || calls != 2 {
+		t.Errorf("expected a retry after 10ms instead of the backoff, got %d calls and %v", calls, err)
+	}
+}
+
+func TestRetryPolicyMaxRetryAfter(t *testing.T) {
+	var calls int
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 3, MaxRetryAfter: 30 * time.Second}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"message":"Overloaded","type":"overloaded_error"}}`)
+	})
+
+	_, err := client.CreateChatCompletionStream(context.Background(), retryAfterRequest)
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*time.Minute {
+		t.Fatalf("expected an APIError with the Retry-After, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry for a Retry-After over MaxRetryAfter, got %d calls", calls)
+	}
+}
@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -38,6 +39,11 @@ type Run struct {
 	// ThreadTruncationStrategy defines the truncation strategy to use for the thread.
 	TruncationStrategy *ThreadTruncationStrategy `json:"truncation_strategy,omitempty"`
 
+	TopP              *float32           `json:"top_p,omitempty"`
+	ToolChoice        *RunToolChoice     `json:"tool_choice,omitempty"`
+	ResponseFormat    *RunResponseFormat `json:"response_format,omitempty"`
+	ParallelToolCalls *bool              `json:"parallel_tool_calls,omitempty"`
+
 	httpHeader
 }
 
@@ -107,14 +113,76 @@ type RunRequest struct {
 	// ThreadTruncationStrategy defines the truncation strategy to use for the thread.
 	TruncationStrategy *ThreadTruncationStrategy `json:"truncation_strategy,omitempty"`
 
-	// This can be either a string or a ToolChoice object.
+	// This can be either one of the RunToolChoice* strings or an AssistantToolChoice object.
 	ToolChoice any `json:"tool_choice,omitempty"`
-	// This can be either a string or a ResponseFormat object.
+	// This can be either AssistantResponseFormatAuto or a ChatCompletionResponseFormat object.
 	ResponseFormat any `json:"response_format,omitempty"`
 	// Disable the default behavior of parallel tool calls by setting it: false.
 	ParallelToolCalls any `json:"parallel_tool_calls,omitempty"`
 }
 
+// Tool choice strings accepted by runs.
+const (
+	RunToolChoiceNone     = "none"
+	RunToolChoiceAuto     = "auto"
+	RunToolChoiceRequired = "required"
+)
+
+// AssistantToolChoice forces the run to use a specific tool.
+// Function must be set when Type is AssistantToolTypeFunction.
+type AssistantToolChoice struct {
+	Type     AssistantToolType `json:"type"`
+	Function *ToolFunction     `json:"function,omitempty"`
+}
+
+// RunToolChoice is the tool choice of a run: either Mode, one of the RunToolChoice* strings,
+// or a specific tool.
+type RunToolChoice struct {
+	Mode string `json:"-"`
+	AssistantToolChoice
+}
+
+func (c RunToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Mode != "" {
+		return json.Marshal(c.Mode)
+	}
+	return json.Marshal(c.AssistantToolChoice)
+}
+
+func (c *RunToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = RunToolChoice{Mode: mode}
+		return nil
+	}
+	*c = RunToolChoice{}
+	return json.Unmarshal(data, &c.AssistantToolChoice)
+}
+
+// RunResponseFormat is the response format of a run: either Mode, AssistantResponseFormatAuto,
+// or a ChatCompletionResponseFormat.
+type RunResponseFormat struct {
+	Mode string `json:"-"`
+	ChatCompletionResponseFormat
+}
+
+func (f RunResponseFormat) MarshalJSON() ([]byte, error) {
+	if f.Mode != "" {
+		return json.Marshal(f.Mode)
+	}
+	return json.Marshal(f.ChatCompletionResponseFormat)
+}
+
+func (f *RunResponseFormat) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*f = RunResponseFormat{Mode: mode}
+		return nil
+	}
+	*f = RunResponseFormat{}
+	return json.Unmarshal(data, &f.ChatCompletionResponseFormat)
+}
+
 // ThreadTruncationStrategy defines the truncation strategy to use for the thread.
 // https://platform.openai.com/docs/assistants/how-it-works/truncation-strategy.
 type ThreadTruncationStrategy struct {
@@ -164,6 +232,8 @@ type ToolOutput struct {
 type CreateThreadAndRunRequest struct {
 	RunRequest
 	Thread ThreadRequest `json:"thread"`
+	// ToolResources are made available to the assistant's tools for this run.
+	ToolResources *ToolResourcesRequest `json:"tool_resources,omitempty"`
 }
 
 type RunStep struct {
@@ -206,7 +276,37 @@ const (
 type StepDetails struct {
 	Type            RunStepType                 `json:"type"`
 	MessageCreation *StepDetailsMessageCreation `json:"message_creation,omitempty"`
-	ToolCalls       []ToolCall                  `json:"tool_calls,omitempty"`
+	ToolCalls       []RunStepToolCall           `json:"tool_calls,omitempty"`
+}
+
+// RunStepIncludeFileSearchResultContent includes the content of the results of file_search
+// tool calls in run steps.
+const RunStepIncludeFileSearchResultContent = "step_details.tool_calls[*].file_search.results[*].content"
+
+// RunStepToolCall is a tool call of a run step. FileSearch is set for file_search calls.
+type RunStepToolCall struct {
+	ToolCall
+	FileSearch *RunStepFileSearch `json:"file_search,omitempty"`
+}
+
+// RunStepFileSearch holds the results of a file_search tool call.
+type RunStepFileSearch struct {
+	RankingOptions *FileSearchRankingOptions `json:"ranking_options,omitempty"`
+	// Results are only returned when requested with RunStepIncludeFileSearchResultContent.
+	Results []RunStepFileSearchResult `json:"results,omitempty"`
+}
+
+type RunStepFileSearchResult struct {
+	FileID   string  `json:"file_id"`
+	FileName string  `json:"file_name"`
+	Score    float64 `json:"score"`
+	// Content is the content of the result, only returned when requested.
+	Content []RunStepFileSearchContent `json:"content,omitempty"`
+}
+
+type RunStepFileSearchContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
 }
 
 type StepDetailsMessageCreation struct {
@@ -229,6 +329,33 @@ type Pagination struct {
 	Order  *string
 	After  *string
 	Before *string
+	// Include adds optional fields to listed run steps, e.g. RunStepIncludeFileSearchResultContent.
+	Include []string
+}
+
+// encode returns the query string of the pagination, with its leading "?".
+func (p Pagination) encode() string {
+	urlValues := url.Values{}
+	if p.Limit != nil {
+		urlValues.Add("limit", fmt.Sprintf("%d", *p.Limit))
+	}
+	if p.Order != nil {
+		urlValues.Add("order", *p.Order)
+	}
+	if p.After != nil {
+		urlValues.Add("after", *p.After)
+	}
+	if p.Before != nil {
+		urlValues.Add("before", *p.Before)
+	}
+	for _, include := range p.Include {
+		urlValues.Add("include[]", include)
+	}
+
+	if len(urlValues) == 0 {
+		return ""
+	}
+	return "?" + urlValues.Encode()
 }
 
 // CreateRun creates a new run.
@@ -237,6 +364,9 @@ func (c *Client) CreateRun(
 	threadID string,
 	request RunRequest,
 ) (response Run, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	urlSuffix := fmt.Sprintf("/threads/%s/runs", threadID)
 	req, err := c.newRequest(
 		ctx,
@@ -279,6 +409,9 @@ func (c *Client) ModifyRun(
 	runID string,
 	request RunModifyRequest,
 ) (response Run, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s", threadID, runID)
 	req, err := c.newRequest(
 		ctx,
@@ -300,25 +433,7 @@ func (c *Client) ListRuns(
 	threadID string,
 	pagination Pagination,
 ) (response RunList, err error) {
-	urlValues := url.Values{}
-	if pagination.Limit != nil {
-		urlValues.Add("limit", fmt.Sprintf("%d", *pagination.Limit))
-	}
-	if pagination.Order != nil {
-		urlValues.Add("order", *pagination.Order)
-	}
-	if pagination.After != nil {
-		urlValues.Add("after", *pagination.After)
-	}
-	if pagination.Before != nil {
-		urlValues.Add("before", *pagination.Before)
-	}
-
-	encodedValues := ""
-	if len(urlValues) > 0 {
-		encodedValues = "?" + urlValues.Encode()
-	}
-
+	encodedValues := pagination.encode()
 	urlSuffix := fmt.Sprintf("/threads/%s/runs%s", threadID, encodedValues)
 	req, err := c.newRequest(
 		ctx,
@@ -377,6 +492,12 @@ func (c *Client) CancelRun(
 func (c *Client) CreateThreadAndRun(
 	ctx context.Context,
 	request CreateThreadAndRunRequest) (response Run, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
+	if err = request.Thread.validate(); err != nil {
+		return
+	}
 	urlSuffix := "/threads/runs"
 	req, err := c.newRequest(
 		ctx,
@@ -392,14 +513,17 @@ func (c *Client) CreateThreadAndRun(
 	return
 }
 
-// RetrieveRunStep retrieves a run step.
+// RetrieveRunStep retrieves a run step. include adds optional fields to the step, e.g.
+// RunStepIncludeFileSearchResultContent.
 func (c *Client) RetrieveRunStep(
 	ctx context.Context,
 	threadID string,
 	runID string,
 	stepID string,
+	include ...string,
 ) (response RunStep, err error) {
-	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps/%s", threadID, runID, stepID)
+	query := Pagination{Include: include}.encode()
+	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps/%s%s", threadID, runID, stepID, query)
 	req, err := c.newRequest(
 		ctx,
 		http.MethodGet,
@@ -420,25 +544,7 @@ func (c *Client) ListRunSteps(
 	runID string,
 	pagination Pagination,
 ) (response RunStepList, err error) {
-	urlValues := url.Values{}
-	if pagination.Limit != nil {
-		urlValues.Add("limit", fmt.Sprintf("%d", *pagination.Limit))
-	}
-	if pagination.Order != nil {
-		urlValues.Add("order", *pagination.Order)
-	}
-	if pagination.After != nil {
-		urlValues.Add("after", *pagination.After)
-	}
-	if pagination.Before != nil {
-		urlValues.Add("before", *pagination.Before)
-	}
-
-	encodedValues := ""
-	if len(urlValues) > 0 {
-		encodedValues = "?" + urlValues.Encode()
-	}
-
+	encodedValues := pagination.encode()
 	urlSuffix := fmt.Sprintf("/threads/%s/runs/%s/steps%s", threadID, runID, encodedValues)
 	req, err := c.newRequest(
 		ctx,
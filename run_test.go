@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -235,3 +236,120 @@ func TestRun(t *testing.T) {
 	)
 	checks.NoError(t, err, "ListRunSteps error")
 }
+
+func TestRunV2Fields(t *testing.T) {
+	lastMessages := 5
+	request := openai.CreateThreadAndRunRequest{
+		RunRequest: openai.RunRequest{
+			AssistantID: "asst_abc123",
+			Tools:       []openai.Tool{{Type: openai.ToolType(openai.AssistantToolTypeFileSearch)}},
+			ToolChoice: openai.AssistantToolChoice{
+				Type:     openai.AssistantToolTypeFunction,
+				Function: &openai.ToolFunction{Name: "get_weather"},
+			},
+			ResponseFormat: openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+			TruncationStrategy: &openai.ThreadTruncationStrategy{
+				Type:         openai.TruncationStrategyLastMessages,
+				LastMessages: &lastMessages,
+			},
+		},
+		ToolResources: &openai.ToolResourcesRequest{
+			FileSearch: &openai.FileSearchToolResourcesRequest{VectorStoreIDs: []string{"vs_abc123"}},
+		},
+	}
+	data, err := json.Marshal(request)
+	checks.NoError(t, err, "marshal request")
+
+	var fields map[string]any
+	checks.NoError(t, json.Unmarshal(data, &fields), "unmarshal request")
+	toolChoice, _ := fields["tool_choice"].(map[string]any)
+	if toolChoice["type"] != "function" {
+		t.Errorf("unexpected tool_choice: %s", data)
+	}
+	toolResources, _ := fields["tool_resources"].(map[string]any)
+	if _, ok := toolResources["file_search"]; !ok {
+		t.Errorf("tool_resources not serialized: %s", data)
+	}
+
+	var run openai.Run
+	err = json.Unmarshal([]byte(`{"id":"run_abc123","tool_choice":"required",`+
+		`"response_format":"auto","parallel_tool_calls":false,`+
+		`"truncation_strategy":{"type":"last_messages","last_messages":5}}`), &run)
+	checks.NoError(t, err, "unmarshal run")
+	if run.ToolChoice == nil || run.ToolChoice.Mode != openai.RunToolChoiceRequired ||
+		run.ResponseFormat == nil || run.ResponseFormat.Mode != openai.AssistantResponseFormatAuto {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if run.ParallelToolCalls == nil || *run.ParallelToolCalls {
+		t.Errorf("unexpected parallel_tool_calls: %v", run.ParallelToolCalls)
+	}
+	if run.TruncationStrategy == nil || *run.TruncationStrategy.LastMessages != 5 {
+		t.Errorf("unexpected truncation strategy: %+v", run.TruncationStrategy)
+	}
+
+	run = openai.Run{}
+	err = json.Unmarshal([]byte(`{"id":"run_abc123",`+
+		`"tool_choice":{"type":"function","function":{"name":"get_weather"}},`+
+		`"response_format":{"type":"json_schema",`+
+		`"json_schema":{"name":"answer","schema":{"type":"object"},"strict":true}}}`), &run)
+	checks.NoError(t, err, "unmarshal run")
+	if run.ToolChoice == nil || run.ToolChoice.Mode != "" || run.ToolChoice.Type != openai.AssistantToolTypeFunction ||
+		run.ToolChoice.Function == nil || run.ToolChoice.Function.Name != "get_weather" {
+		t.Errorf("unexpected tool choice: %+v", run.ToolChoice)
+	}
+	if run.ResponseFormat == nil || run.ResponseFormat.Mode != "" ||
+		run.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONSchema ||
+		run.ResponseFormat.JSONSchema == nil || run.ResponseFormat.JSONSchema.Name != "answer" ||
+		run.ResponseFormat.JSONSchema.Schema == nil {
+		t.Errorf("unexpected response format: %+v", run.ResponseFormat)
+	}
+
+	data, err = json.Marshal(run)
+	checks.NoError(t, err, "marshal run")
+	if !strings.Contains(string(data), `"tool_choice":{"type":"function","function":{"name":"get_weather"}}`) ||
+		!strings.Contains(string(data), `"response_format":{"type":"json_schema"`) {
+		t.Errorf("unexpected run JSON: %s", data)
+	}
+}
+
+func TestRunStepFileSearchResults(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	const stepJSON = `{"id":"step_1","object":"thread.run.step","type":"tool_calls","step_details":{` +
+		`"type":"tool_calls","tool_calls":[{"id":"call_1","type":"file_search","file_search":{` +
+		`"ranking_options":{"ranker":"default_2024_08_21","score_threshold":0.2},"results":[` +
+		`{"file_id":"file_1","file_name":"a.md","score":0.8,"content":[{"type":"text","text":"Refunds take 5 days"}]}]}}]}}`
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1/steps/step_1", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include[]") != openai.RunStepIncludeFileSearchResultContent {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, stepJSON)
+	})
+	server.RegisterHandler("/v1/threads/thread_1/runs/run_1/steps", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("include[]") != openai.RunStepIncludeFileSearchResultContent || query.Get("order") != "desc" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"object":"list","data":[%s]}`, stepJSON)
+	})
+
+	ctx := context.Background()
+	step, err := client.RetrieveRunStep(ctx, "thread_1", "run_1", "step_1", openai.RunStepIncludeFileSearchResultContent)
+	checks.NoError(t, err, "RetrieveRunStep error")
+	fileSearch := step.StepDetails.ToolCalls[0].FileSearch
+	if fileSearch == nil || len(fileSearch.Results) != 1 || fileSearch.Results[0].Score != 0.8 ||
+		fileSearch.Results[0].Content[0].Text != "Refunds take 5 days" || fileSearch.RankingOptions.ScoreThreshold != 0.2 {
+		t.Errorf("unexpected file search: %+v", fileSearch)
+	}
+
+	order := "desc"
+	steps, err := client.ListRunSteps(ctx, "thread_1", "run_1", openai.Pagination{
+		Order:   &order,
+		Include: []string{openai.RunStepIncludeFileSearchResultContent},
+	})
+	checks.NoError(t, err, "ListRunSteps error")
+	if len(steps.RunSteps) != 1 || steps.RunSteps[0].StepDetails.ToolCalls[0].ID != "call_1" {
+		t.Errorf("unexpected steps: %+v", steps.RunSteps)
+	}
+}
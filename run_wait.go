@@ -0,0 +1,124 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	runPollMinInterval = 100 * time.Millisecond
+	runPollMaxInterval = 2 * time.Second
+)
+
+var (
+	ErrRunNotCompleted       = errors.New("run did not complete")
+	ErrRunToolHandlerMissing = errors.New("run requires tool outputs but no handler was provided")
+)
+
+// RunToolHandler is called by RunAndWait when a run requires tool outputs.
+// It receives the run and the tool calls to answer, and returns one output per tool call.
+type RunToolHandler func(ctx context.Context, run Run, toolCalls []ToolCall) ([]ToolOutput, error)
+
+// RunAndWait creates a run on the thread and polls it until it reaches a terminal status.
+// Whenever the run requires action, handler is invoked and its outputs are submitted.
+// On completion all the messages created by the run are returned in chronological order,
+// listing as many pages as needed.
+// If the run ends in any other terminal status, the run is returned together with an error
+// wrapping ErrRunNotCompleted.
+func (c *Client) RunAndWait(
+	ctx context.Context,
+	threadID string,
+	request RunRequest,
+	handler RunToolHandler,
+) (run Run, messages MessagesList, err error) {
+	run, err = c.CreateRun(ctx, threadID, request)
+	if err != nil {
+		return
+	}
+
+	run, err = c.waitForRun(ctx, run, handler)
+	if err != nil {
+		return
+	}
+
+	messages, err = c.listRunMessages(ctx, threadID, run.ID)
+	return
+}
+
+// listRunMessages lists all the messages of a run, following the pages of the list.
+func (c *Client) listRunMessages(ctx context.Context, threadID, runID string) (MessagesList, error) {
+	order := "asc"
+	messages, err := c.ListMessage(ctx, threadID, nil, &order, nil, nil, &runID)
+	for err == nil && messages.HasMore && messages.LastID != nil {
+		var page MessagesList
+		page, err = c.ListMessage(ctx, threadID, nil, &order, messages.LastID, nil, &runID)
+		if err != nil {
+			break
+		}
+		messages.Messages = append(messages.Messages, page.Messages...)
+		messages.LastID, messages.HasMore = page.LastID, page.HasMore
+	}
+	return messages, err
+}
+
+func (c *Client) waitForRun(ctx context.Context, run Run, handler RunToolHandler) (Run, error) {
+	interval := runPollMinInterval
+	for {
+		switch run.Status {
+		case RunStatusCompleted:
+			return run, nil
+		case RunStatusFailed, RunStatusCancelled, RunStatusExpired, RunStatusIncomplete:
+			return run, runNotCompletedError(run)
+		case RunStatusRequiresAction:
+			var err error
+			run, err = c.submitRequiredToolOutputs(ctx, run, handler)
+			if err != nil {
+				return run, err
+			}
+			interval = runPollMinInterval
+			continue
+		case RunStatusQueued, RunStatusInProgress, RunStatusCancelling:
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return run, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > runPollMaxInterval {
+			interval = runPollMaxInterval
+		}
+
+		var err error
+		run, err = c.RetrieveRun(ctx, run.ThreadID, run.ID)
+		if err != nil {
+			return run, err
+		}
+	}
+}
+
+func (c *Client) submitRequiredToolOutputs(ctx context.Context, run Run, handler RunToolHandler) (Run, error) {
+	if run.RequiredAction == nil || run.RequiredAction.SubmitToolOutputs == nil {
+		return run, fmt.Errorf("%w: run %s has no tool calls to answer", ErrRunNotCompleted, run.ID)
+	}
+	if handler == nil {
+		return run, ErrRunToolHandlerMissing
+	}
+
+	outputs, err := handler(ctx, run, run.RequiredAction.SubmitToolOutputs.ToolCalls)
+	if err != nil {
+		return run, err
+	}
+	return c.SubmitToolOutputs(ctx, run.ThreadID, run.ID, SubmitToolOutputsRequest{ToolOutputs: outputs})
+}
+
+func runNotCompletedError(run Run) error {
+	if run.LastError != nil {
+		return fmt.Errorf("%w: status %s: %s: %s", ErrRunNotCompleted, run.Status, run.LastError.Code, run.LastError.Message)
+	}
+	return fmt.Errorf("%w: status %s", ErrRunNotCompleted, run.Status)
+}
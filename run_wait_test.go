@@ -0,0 +1,122 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestRunAndWait(t *testing.T) {
+	const (
+		threadID = "thread_abc123"
+		runID    = "run_abc123"
+	)
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	writeRun := func(w http.ResponseWriter, status openai.RunStatus, action *openai.RunRequiredAction) {
+		resBytes, _ := json.Marshal(openai.Run{
+			ID:             runID,
+			ThreadID:       threadID,
+			Status:         status,
+			RequiredAction: action,
+		})
+		fmt.Fprintln(w, string(resBytes))
+	}
+
+	retrievals := 0
+	submitted := false
+	server.RegisterHandler("/v1/threads/"+threadID+"/runs", func(w http.ResponseWriter, _ *http.Request) {
+		writeRun(w, openai.RunStatusQueued, nil)
+	})
+	server.RegisterHandler("/v1/threads/"+threadID+"/runs/"+runID, func(w http.ResponseWriter, _ *http.Request) {
+		retrievals++
+		switch {
+		case !submitted:
+			writeRun(w, openai.RunStatusRequiresAction, &openai.RunRequiredAction{
+				Type: openai.RequiredActionTypeSubmitToolOutputs,
+				SubmitToolOutputs: &openai.SubmitToolOutputs{ToolCalls: []openai.ToolCall{{
+					ID:       "call_1",
+					Type:     openai.ToolTypeFunction,
+					Function: openai.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+				}}},
+			})
+		default:
+			writeRun(w, openai.RunStatusCompleted, nil)
+		}
+	})
+	server.RegisterHandler(
+		"/v1/threads/"+threadID+"/runs/"+runID+"/submit_tool_outputs",
+		func(w http.ResponseWriter, r *http.Request) {
+			var request openai.SubmitToolOutputsRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if len(request.ToolOutputs) != 1 || request.ToolOutputs[0].ToolCallID != "call_1" {
+				t.Errorf("unexpected tool outputs: %+v", request)
+			}
+			submitted = true
+			writeRun(w, openai.RunStatusInProgress, nil)
+		},
+	)
+	server.RegisterHandler("/v1/threads/"+threadID+"/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("run_id") != runID || r.URL.Query().Get("order") != "asc" {
+			t.Errorf("unexpected messages query: %s", r.URL.RawQuery)
+		}
+		page := openai.MessagesList{Messages: []openai.Message{{ID: "msg_2"}}}
+		if r.URL.Query().Get("after") == "" {
+			page = openai.MessagesList{Messages: []openai.Message{{ID: "msg_1"}}, HasMore: true}
+		} else if after := r.URL.Query().Get("after"); after != "msg_1" {
+			t.Errorf("unexpected page after %s", after)
+		}
+		page.LastID = &page.Messages[0].ID
+		resBytes, _ := json.Marshal(page)
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	handled := 0
+	run, messages, err := client.RunAndWait(
+		context.Background(),
+		threadID,
+		openai.RunRequest{AssistantID: "asst_abc123"},
+		func(_ context.Context, _ openai.Run, toolCalls []openai.ToolCall) ([]openai.ToolOutput, error) {
+			handled++
+			return []openai.ToolOutput{{ToolCallID: toolCalls[0].ID, Output: "sunny"}}, nil
+		},
+	)
+	checks.NoError(t, err, "RunAndWait error")
+	if run.Status != openai.RunStatusCompleted || handled != 1 || retrievals != 2 {
+		t.Errorf("unexpected result: status=%s handled=%d retrievals=%d", run.Status, handled, retrievals)
+	}
+	if len(messages.Messages) != 2 || messages.Messages[0].ID != "msg_1" || messages.Messages[1].ID != "msg_2" ||
+		messages.HasMore {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	submitted = false
+	_, _, err = client.RunAndWait(context.Background(), threadID, openai.RunRequest{AssistantID: "asst_abc123"}, nil)
+	checks.ErrorIs(t, err, openai.ErrRunToolHandlerMissing, "missing handler should be reported")
+}
+
+func TestRunAndWaitFailedRun(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/threads/thread_abc123/runs", func(w http.ResponseWriter, _ *http.Request) {
+		resBytes, _ := json.Marshal(openai.Run{
+			ID:        "run_abc123",
+			ThreadID:  "thread_abc123",
+			Status:    openai.RunStatusFailed,
+			LastError: &openai.RunLastError{Code: openai.RunErrorServerError, Message: "boom"},
+		})
+		fmt.Fprintln(w, string(resBytes))
+	})
+
+	run, _, err := client.RunAndWait(context.Background(), "thread_abc123", openai.RunRequest{}, nil)
+	checks.ErrorIs(t, err, openai.ErrRunNotCompleted, "failed run should be reported")
+	if run.Status != openai.RunStatusFailed {
+		t.Errorf("unexpected run status: %s", run.Status)
+	}
+}
@@ -0,0 +1,202 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	utils "github.com/sashabaranov/go-openai/internal"
+)
+
+// UnknownField is a member of a JSON response that no field of the type it is decoded into
+// captures, and that is therefore dropped or only kept raw, as in ExtraFields.
+type UnknownField struct {
+	// Path locates the member in the response, e.g. "choices[0].message.audio".
+	Path  string
+	Value json.RawMessage
+}
+
+// UnknownFieldsError is returned when SchemaDrift.DisallowUnknownFields is set and a response
+// or a stream chunk has unknown fields. The value is still decoded.
+type UnknownFieldsError struct {
+	// Type is the name of the type the response was decoded into.
+	Type   string
+	Fields []UnknownField
+}
+
+func (e *UnknownFieldsError) Error() string {
+	paths := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		paths[i] = field.Path
+	}
+	return fmt.Sprintf("%s has unknown fields: %s", e.Type, strings.Join(paths, ", "))
+}
+
+// SchemaDrift detects when the API returns fields that the types of this package do not capture
+// yet, instead of silently dropping them. It checks the JSON responses and the chunks of streams.
+type SchemaDrift struct {
+	// DisallowUnknownFields fails decoding with an *UnknownFieldsError, like
+	// json.Decoder.DisallowUnknownFields but through nested types with custom decoding. Off by
+	// default, so that new API fields do not break applications.
+	DisallowUnknownFields bool
+	// OnUnknownFields, if set, is called with the type name and the unknown fields of each
+	// response or chunk having some, e.g. to log them.
+	OnUnknownFields func(typeName string, fields []UnknownField)
+}
+
+// schemaDriftUnmarshaler decodes with another unmarshaler, then reports the unknown fields.
+type schemaDriftUnmarshaler struct {
+	next  utils.Unmarshaler
+	drift *SchemaDrift
+}
+
+func withSchemaDrift(unmarshaler JSONUnmarshaler, drift *SchemaDrift) JSONUnmarshaler {
+	if unmarshaler == nil {
+		unmarshaler = &utils.JSONUnmarshaler{}
+	}
+	return &schemaDriftUnmarshaler{next: unmarshaler, drift: drift}
+}
+
+func (u *schemaDriftUnmarshaler) Unmarshal(data []byte, v any) error {
+	if err := u.next.Unmarshal(data, v); err != nil {
+		return err
+	}
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil
+	}
+	fields := unknownFields(data, t)
+	if len(fields) == 0 {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if u.drift.OnUnknownFields != nil {
+		u.drift.OnUnknownFields(t.Name(), fields)
+	}
+	if u.drift.DisallowUnknownFields {
+		return &UnknownFieldsError{Type: t.Name(), Fields: fields}
+	}
+	return nil
+}
+
+// unknownFields returns the members of the JSON value data that no field of t captures.
+func unknownFields(data []byte, t reflect.Type) []UnknownField {
+	var fields []UnknownField
+	collectUnknownFields(data, t, "", &fields)
+	return fields
+}
+
+func collectUnknownFields(data []byte, t reflect.Type, path string, fields *[]UnknownField) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		known := schemaFields(t)
+		scanJSONObject(data, func(key, value []byte) {
+			fieldPath := joinFieldPath(path, string(key))
+			fieldType, ok := known.lookup(string(key))
+			if !ok {
+				*fields = append(*fields, UnknownField{
+					Path:  fieldPath,
+					Value: append(json.RawMessage(nil), value...),
+				})
+				return
+			}
+			collectUnknownFields(value, fieldType, fieldPath, fields)
+		})
+	case reflect.Map:
+		scanJSONObject(data, func(key, value []byte) {
+			collectUnknownFields(value, t.Elem(), joinFieldPath(path, string(key)), fields)
+		})
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		var elements []json.RawMessage
+		if json.Unmarshal(data, &elements) != nil {
+			return
+		}
+		for i, element := range elements {
+			collectUnknownFields(element, t.Elem(), path+"["+strconv.Itoa(i)+"]", fields)
+		}
+	default:
+	}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonSchemaFields maps the JSON names of the fields of a struct type to their types.
+type jsonSchemaFields map[string]reflect.Type
+
+// lookup finds the field of name, case-insensitively like encoding/json.
+func (f jsonSchemaFields) lookup(name string) (reflect.Type, bool) {
+	if t, ok := f[name]; ok {
+		return t, true
+	}
+	for fieldName, t := range f {
+		if strings.EqualFold(fieldName, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+var schemaFieldsCache sync.Map // reflect.Type -> jsonSchemaFields
+
+// schemaFields returns the fields of the struct type t by JSON name, including the promoted
+// fields of embedded structs.
+func schemaFields(t reflect.Type) jsonSchemaFields {
+	if cached, ok := schemaFieldsCache.Load(t); ok {
+		return cached.(jsonSchemaFields) //nolint:forcetypeassert // the cache only holds fields
+	}
+	fields := make(jsonSchemaFields, t.NumField())
+	addSchemaFields(t, fields)
+	schemaFieldsCache.Store(t, fields)
+	return fields
+}
+
+// addSchemaFields adds the fields of t, then the promoted ones that they do not shadow.
+func addSchemaFields(t reflect.Type, fields jsonSchemaFields) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				embedded = append(embedded, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if !hasTag || name == "" {
+			name = field.Name
+		}
+		if _, exists := fields[name]; !exists {
+			fields[name] = field.Type
+		}
+	}
+	for _, embeddedType := range embedded {
+		addSchemaFields(embeddedType, fields)
+	}
+}
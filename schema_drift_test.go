@@ -0,0 +1,92 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const driftedChatCompletion = `{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-4o",` +
+	`"choices":[{"index":0,"message":{"role":"assistant","content":"Hi","annotations_v2":[]},` +
+	`"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2,` +
+	`"PROMPT_TOKENS_DETAILS":{"cached_tokens":0}}}`
+
+func setupSchemaDriftServer(t *testing.T, drift *openai.SchemaDrift) *openai.Client {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.SchemaDrift = drift
+	})
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"},"+
+				"\"content_filter_v2\":{}}]}\n\ndata: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, driftedChatCompletion)
+	})
+	return client
+}
+
+var driftRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4o,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+}
+
+func TestSchemaDriftReportsUnknownFields(t *testing.T) {
+	var reported []openai.UnknownField
+	var typeName string
+	client := setupSchemaDriftServer(t, &openai.SchemaDrift{
+		OnUnknownFields: func(name string, fields []openai.UnknownField) {
+			typeName = name
+			reported = fields
+		},
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), driftRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.Choices[0].Message.Content != "Hi" {
+		t.Errorf("unexpected content %q", resp.Choices[0].Message.Content)
+	}
+	if typeName != "ChatCompletionResponse" {
+		t.Errorf("unexpected type name %q", typeName)
+	}
+	if len(reported) != 1 || reported[0].Path != "choices[0].message.annotations_v2" ||
+		string(reported[0].Value) != "[]" {
+		t.Errorf("unexpected unknown fields %+v", reported)
+	}
+}
+
+func TestSchemaDriftDisallowUnknownFields(t *testing.T) {
+	client := setupSchemaDriftServer(t, &openai.SchemaDrift{DisallowUnknownFields: true})
+
+	_, err := client.CreateChatCompletion(context.Background(), driftRequest)
+	var unknownErr *openai.UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected UnknownFieldsError, got %v", err)
+	}
+	if unknownErr.Error() != "ChatCompletionResponse has unknown fields: choices[0].message.annotations_v2" {
+		t.Errorf("unexpected error %q", unknownErr.Error())
+	}
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), driftRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	_, err = stream.Recv()
+	if !errors.As(err, &unknownErr) || unknownErr.Fields[0].Path != "choices[0].content_filter_v2" {
+		t.Errorf("expected the chunk to be rejected, got %v", err)
+	}
+}
+
+func TestSchemaDriftOffByDefault(t *testing.T) {
+	client := setupSchemaDriftServer(t, nil)
+	_, err := client.CreateChatCompletion(context.Background(), driftRequest)
+	checks.NoError(t, err, "CreateChatCompletion error")
+}
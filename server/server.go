@@ -0,0 +1,254 @@
+// Package server provides the HTTP handlers of an OpenAI-compatible API, decoding requests into
+// the types of go-openai and encoding the responses of a Backend, to build gateways, proxies
+// and local model servers that any OpenAI client can call.
+//
+// A Backend can be a go-openai Client, whose chat completion streams satisfy ChatCompletionStream,
+// to build a gateway in front of the API:
+//
+//	type gateway struct{ client *openai.Client }
+//
+//	func (g gateway) CreateChatCompletion(
+//		ctx context.Context,
+//		request openai.ChatCompletionRequest,
+//	) (openai.ChatCompletionResponse, error) {
+//		return g.client.CreateChatCompletion(ctx, request)
+//	}
+//
+//	func (g gateway) CreateChatCompletionStream(
+//		ctx context.Context,
+//		request openai.ChatCompletionRequest,
+//	) (server.ChatCompletionStream, error) {
+//		return g.client.CreateChatCompletionStream(ctx, request)
+//	}
+//
+//	http.Handle("/v1/", server.NewHandler(gateway{client}))
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxRequestSize bounds the size of the request bodies.
+const maxRequestSize = 32 << 20
+
+// ChatCompletionStream is a stream of chat completion chunks returned by a Backend. Recv
+// returns io.EOF once the stream ended. Close is called from another goroutine when the client
+// disconnects and must unblock a pending Recv.
+type ChatCompletionStream = openai.ChatCompletionStreamReader
+
+// Backend serves chat completions. The context of its calls is canceled when the client
+// disconnects. Errors that are *openai.APIError are sent with their HTTPStatusCode, or 500 if
+// zero; other errors are sent as 500 server errors.
+type Backend interface {
+	CreateChatCompletion(
+		ctx context.Context,
+		request openai.ChatCompletionRequest,
+	) (openai.ChatCompletionResponse, error)
+	CreateChatCompletionStream(
+		ctx context.Context,
+		request openai.ChatCompletionRequest,
+	) (ChatCompletionStream, error)
+}
+
+// ModelLister is implemented by the backends serving GET /v1/models.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]openai.Model, error)
+}
+
+// Option configures a handler.
+type Option func(*handler)
+
+// WithAuthenticator rejects the requests for which authenticate returns an error with 401,
+// e.g. to check the bearer token of the Authorization header.
+func WithAuthenticator(authenticate func(r *http.Request) error) Option {
+	return func(h *handler) {
+		h.authenticate = authenticate
+	}
+}
+
+// WithStreamOptions configures how streams are written, e.g. with openai.WithHeartbeat.
+func WithStreamOptions(opts ...openai.ProxyOption) Option {
+	return func(h *handler) {
+		h.streamOptions = opts
+	}
+}
+
+// WithPrefix sets the path prefix of the endpoints, "/v1" by default.
+func WithPrefix(prefix string) Option {
+	return func(h *handler) {
+		h.prefix = strings.TrimRight(prefix, "/")
+	}
+}
+
+type handler struct {
+	backend       Backend
+	authenticate  func(r *http.Request) error
+	prefix        string
+	streamOptions []openai.ProxyOption
+}
+
+// NewHandler returns a handler serving POST /v1/chat/completions, streamed or not, and
+// GET /v1/models if backend is a ModelLister.
+func NewHandler(backend Backend, opts ...Option) http.Handler {
+	h := &handler{backend: backend, prefix: "/v1"}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.authenticate != nil {
+		if err := h.authenticate(r); err != nil {
+			WriteError(w, &openai.APIError{
+				Message:        err.Error(),
+				Type:           "invalid_request_error",
+				Code:           "invalid_api_key",
+				HTTPStatusCode: http.StatusUnauthorized,
+			})
+			return
+		}
+	}
+
+	var endpoint string
+	if strings.HasPrefix(r.URL.Path, h.prefix) {
+		endpoint = r.URL.Path[len(h.prefix):]
+	}
+	switch endpoint {
+	case "/chat/completions":
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		h.serveChatCompletion(w, r)
+		return
+	case "/models":
+		if lister, ok := h.backend.(ModelLister); ok {
+			h.serveModels(w, r, lister)
+			return
+		}
+	}
+	WriteError(w, &openai.APIError{
+		Message:        fmt.Sprintf("Invalid URL (%s %s)", r.Method, r.URL.Path),
+		Type:           "invalid_request_error",
+		HTTPStatusCode: http.StatusNotFound,
+	})
+}
+
+func (h *handler) serveModels(w http.ResponseWriter, r *http.Request, lister ModelLister) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r, http.MethodGet)
+		return
+	}
+	models, err := lister.ListModels(r.Context())
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	if models == nil {
+		models = []openai.Model{}
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Object string         `json:"object"`
+		Data   []openai.Model `json:"data"`
+	}{"list", models})
+}
+
+func (h *handler) serveChatCompletion(w http.ResponseWriter, r *http.Request) {
+	var request openai.ChatCompletionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&request); err != nil {
+		WriteError(w, invalidRequest("", "We could not parse the JSON body of your request: %v", err))
+		return
+	}
+	if request.Model == "" {
+		WriteError(w, invalidRequest("model", "you must provide a model parameter"))
+		return
+	}
+	if len(request.Messages) == 0 {
+		WriteError(w, invalidRequest("messages", "messages must be a non-empty array"))
+		return
+	}
+
+	if !request.Stream {
+		response, err := h.backend.CreateChatCompletion(r.Context(), request)
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+	stream, err := h.backend.CreateChatCompletionStream(r.Context(), request)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	_ = WriteStream(w, r, stream, h.streamOptions...)
+}
+
+// WriteStream writes the chunks of stream to w as server-sent events, ending with
+// "data: [DONE]", and closes the stream, see openai.ProxyChatCompletionStream. An error of the
+// stream is written as an error event and returned, as is the error of the context of r if the
+// client disconnects.
+func WriteStream(
+	w http.ResponseWriter,
+	r *http.Request,
+	stream ChatCompletionStream,
+	opts ...openai.ProxyOption,
+) error {
+	return openai.ProxyChatCompletionStream(w, r, stream, opts...)
+}
+
+// WriteError writes err as an error response of the API. The status is the HTTPStatusCode of
+// an *openai.APIError, or 500.
+func WriteError(w http.ResponseWriter, err error) {
+	apiErr := apiError(err)
+	status := apiErr.HTTPStatusCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, openai.ErrorResponse{Error: apiErr})
+}
+
+func apiError(err error) *openai.APIError {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return &openai.APIError{Message: err.Error(), Type: "server_error"}
+}
+
+func invalidRequest(param, format string, args ...any) *openai.APIError {
+	apiErr := &openai.APIError{
+		Message:        fmt.Sprintf(format, args...),
+		Type:           "invalid_request_error",
+		HTTPStatusCode: http.StatusBadRequest,
+	}
+	if param != "" {
+		apiErr.Param = &param
+	}
+	return apiErr
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed string) {
+	w.Header().Set("Allow", allowed)
+	WriteError(w, &openai.APIError{
+		Message:        fmt.Sprintf("Invalid method for URL (%s %s)", r.Method, r.URL.Path),
+		Type:           "invalid_request_error",
+		HTTPStatusCode: http.StatusMethodNotAllowed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,258 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/server"
+)
+
+// echoBackend answers with the content of the last message, streamed word by word.
+type echoBackend struct{}
+
+func (echoBackend) CreateChatCompletion(
+	_ context.Context,
+	request openai.ChatCompletionRequest,
+) (openai.ChatCompletionResponse, error) {
+	if request.Model == "overloaded" {
+		return openai.ChatCompletionResponse{}, &openai.APIError{
+			Message:        "The server is overloaded",
+			Type:           "server_error",
+			HTTPStatusCode: http.StatusServiceUnavailable,
+		}
+	}
+	return openai.ChatCompletionResponse{
+		ID:    "chatcmpl-echo",
+		Model: request.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: lastContent(request)},
+			FinishReason: openai.FinishReasonStop,
+		}},
+	}, nil
+}
+
+func (echoBackend) CreateChatCompletionStream(
+	_ context.Context,
+	request openai.ChatCompletionRequest,
+) (server.ChatCompletionStream, error) {
+	stream := &sliceStream{}
+	for _, word := range strings.SplitAfter(lastContent(request), " ") {
+		stream.chunks = append(stream.chunks, openai.ChatCompletionStreamResponse{
+			Model:   request.Model,
+			Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: word}}},
+		})
+	}
+	if request.Model == "broken" {
+		stream.err = errors.New("backend crashed")
+	}
+	if request.Model == "slow" {
+		stream.delay = 60 * time.Millisecond
+	}
+	return stream, nil
+}
+
+func (echoBackend) ListModels(context.Context) ([]openai.Model, error) {
+	return []openai.Model{{ID: "echo", Object: "model", OwnedBy: "me"}}, nil
+}
+
+func lastContent(request openai.ChatCompletionRequest) string {
+	return request.Messages[len(request.Messages)-1].Content
+}
+
+type sliceStream struct {
+	chunks []openai.ChatCompletionStreamResponse
+	err    error
+	delay  time.Duration // Before the first chunk
+	closed bool
+}
+
+func (s *sliceStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	time.Sleep(s.delay)
+	s.delay = 0
+	if len(s.chunks) == 0 {
+		if s.err != nil {
+			return openai.ChatCompletionStreamResponse{}, s.err
+		}
+		return openai.ChatCompletionStreamResponse{}, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *sliceStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func setupServer(t *testing.T, backend server.Backend, opts ...server.Option) *openai.Client {
+	t.Helper()
+	ts := httptest.NewServer(server.NewHandler(backend, opts...))
+	t.Cleanup(ts.Close)
+	config := openai.DefaultConfig("secret")
+	config.BaseURL = ts.URL + "/v1"
+	return openai.NewClientWithConfig(config)
+}
+
+func echoRequest(model, content string) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:    model,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: content}},
+	}
+}
+
+func TestChatCompletion(t *testing.T) {
+	client := setupServer(t, echoBackend{})
+	resp, err := client.CreateChatCompletion(context.Background(), echoRequest("echo", "Hello there"))
+	checks.NoError(t, err, "CreateChatCompletion error")
+	if resp.ID != "chatcmpl-echo" || resp.Choices[0].Message.Content != "Hello there" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), echoRequest("overloaded", "Hello"))
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusServiceUnavailable ||
+		apiErr.Message != "The server is overloaded" {
+		t.Errorf("expected the backend error to be relayed, got %v", err)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	client := setupServer(t, echoBackend{})
+	stream, err := client.CreateChatCompletionStream(context.Background(), echoRequest("echo", "Hello there world"))
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var content []string
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		content = append(content, chunk.Choices[0].Delta.Content)
+	}
+	if strings.Join(content, "|") != "Hello |there |world" {
+		t.Errorf("unexpected chunks %q", content)
+	}
+}
+
+func TestChatCompletionStreamError(t *testing.T) {
+	client := setupServer(t, echoBackend{})
+	stream, err := client.CreateChatCompletionStream(context.Background(), echoRequest("broken", "Hello"))
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	_, err = stream.Recv()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "backend crashed" {
+		t.Errorf("expected the stream error to be relayed, got %v", err)
+	}
+}
+
+func TestWithStreamOptions(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(echoBackend{},
+		server.WithStreamOptions(openai.WithHeartbeat(10*time.Millisecond))))
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/chat/completions", "application/json",
+		strings.NewReader(`{"model":"slow","stream":true,"messages":[{"role":"user","content":"Hi"}]}`))
+	checks.NoError(t, err, "Post error")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	checks.NoError(t, err, "ReadAll error")
+	if !strings.HasPrefix(string(body), ": ping\n\n") || !strings.HasSuffix(string(body), "data: [DONE]\n\n") {
+		t.Errorf("expected heartbeats before the slow first chunk, got %q", body)
+	}
+}
+
+func TestListModels(t *testing.T) {
+	client := setupServer(t, echoBackend{})
+	models, err := client.ListModels(context.Background())
+	checks.NoError(t, err, "ListModels error")
+	if len(models.Models) != 1 || models.Models[0].ID != "echo" {
+		t.Errorf("unexpected models %+v", models.Models)
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(echoBackend{}))
+	defer ts.Close()
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid JSON", http.MethodPost, "/v1/chat/completions", "{", http.StatusBadRequest},
+		{"missing model", http.MethodPost, "/v1/chat/completions", `{"messages":[{"role":"user","content":"Hi"}]}`,
+			http.StatusBadRequest},
+		{"missing messages", http.MethodPost, "/v1/chat/completions", `{"model":"echo"}`, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, "/v1/chat/completions", "", http.StatusMethodNotAllowed},
+		{"unknown path", http.MethodGet, "/v1/embeddings", "", http.StatusNotFound},
+		{"missing prefix", http.MethodPost, "/chat/completions", `{"model":"echo"}`, http.StatusNotFound},
+		{"missing prefix of models", http.MethodGet, "/models", "", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(tc.body))
+			checks.NoError(t, err, "NewRequest error")
+			resp, err := http.DefaultClient.Do(req)
+			checks.NoError(t, err, "Do error")
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tc.status || !strings.Contains(string(body), `"type":"invalid_request_error"`) {
+				t.Errorf("unexpected response %d %s", resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestWithAuthenticator(t *testing.T) {
+	authenticate := func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return errors.New("incorrect API key provided")
+		}
+		return nil
+	}
+	client := setupServer(t, echoBackend{}, server.WithAuthenticator(authenticate))
+	_, err := client.CreateChatCompletion(context.Background(), echoRequest("echo", "Hi"))
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	ts := httptest.NewServer(server.NewHandler(echoBackend{}, server.WithAuthenticator(authenticate)))
+	defer ts.Close()
+	config := openai.DefaultConfig("wrong")
+	config.BaseURL = ts.URL + "/v1"
+	_, err = openai.NewClientWithConfig(config).ListModels(context.Background())
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %v", err)
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	ts := httptest.NewServer(server.NewHandler(echoBackend{}, server.WithPrefix("/openai/v1/")))
+	defer ts.Close()
+	config := openai.DefaultConfig("secret")
+	config.BaseURL = ts.URL + "/openai/v1"
+	_, err := openai.NewClientWithConfig(config).CreateChatCompletion(context.Background(), echoRequest("echo", "Hi"))
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	config.BaseURL = ts.URL + "/v1"
+	_, err = openai.NewClientWithConfig(config).CreateChatCompletion(context.Background(), echoRequest("echo", "Hi"))
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 outside of the prefix, got %v", err)
+	}
+}
@@ -50,6 +50,7 @@ type CreateSpeechRequest struct {
 }
 
 func (c *Client) CreateSpeech(ctx context.Context, request CreateSpeechRequest) (response RawResponse, err error) {
+	request.Model = SpeechModel(c.config.mapModel(string(request.Model)))
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -0,0 +1,188 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ProxyOption configures ProxyChatCompletionStream.
+type ProxyOption func(*proxyOptions)
+
+type proxyOptions struct {
+	onChunk   func(*ChatCompletionStreamResponse)
+	heartbeat time.Duration
+}
+
+// WithChunkRewriter calls rewrite with each chunk before it is written to the client, e.g. to
+// replace the model with the name exposed by a gateway.
+func WithChunkRewriter(rewrite func(*ChatCompletionStreamResponse)) ProxyOption {
+	return func(o *proxyOptions) {
+		o.onChunk = rewrite
+	}
+}
+
+// WithHeartbeat writes a ": ping" comment whenever nothing was written to the client for
+// interval while waiting for the upstream, so that load balancers and proxies between the
+// client and the endpoint do not time out slow streams, e.g. of reasoning models. Clients of
+// server-sent events ignore comments.
+func WithHeartbeat(interval time.Duration) ProxyOption {
+	return func(o *proxyOptions) {
+		o.heartbeat = interval
+	}
+}
+
+// ChatCompletionStreamReader is a stream of chat completion chunks, such as a
+// *ChatCompletionStream. Recv returns io.EOF once the stream ended. Close must unblock a
+// pending Recv.
+type ChatCompletionStreamReader interface {
+	Recv() (ChatCompletionStreamResponse, error)
+	Close() error
+}
+
+// ProxyChatCompletionStream writes stream to w as the server-sent events of the chat completions
+// API, for OpenAI-compatible proxy endpoints: it sets the event stream headers, writes and
+// flushes each chunk, and ends with "data: [DONE]". An error of the stream is written as an
+// error event and returned.
+//
+// When the client disconnects or the deadline of the context of r expires, it closes the
+// stream, so the upstream request is canceled, and returns the error of the context. The
+// stream is closed on return.
+func ProxyChatCompletionStream(
+	w http.ResponseWriter,
+	r *http.Request,
+	stream ChatCompletionStreamReader,
+	opts ...ProxyOption,
+) error {
+	var closeOnce sync.Once
+	closeStream := func() { closeOnce.Do(func() { stream.Close() }) }
+	defer closeStream()
+	var options proxyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx := r.Context()
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Closing the stream unblocks the pending Recv and prevents its retry.
+			closeStream()
+		case <-stopped:
+		}
+	}()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	var mu sync.Mutex
+	lastWrite := time.Now()
+	write := func(frame []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		lastWrite = time.Now()
+		if _, err := w.Write(frame); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+	writeEvent := func(data []byte) error {
+		return write(append(append([]byte("data: "), data...), '\n', '\n'))
+	}
+
+	if options.heartbeat > 0 {
+		var wg sync.WaitGroup
+		heartbeatStopped := make(chan struct{})
+		// Registered after close(stopped), so it runs first: nothing is written after return.
+		defer func() {
+			close(heartbeatStopped)
+			wg.Wait()
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timer := time.NewTimer(options.heartbeat)
+			defer timer.Stop()
+			for {
+				select {
+				case <-heartbeatStopped:
+					return
+				case <-timer.C:
+				}
+				mu.Lock()
+				idle := time.Since(lastWrite)
+				mu.Unlock()
+				if idle >= options.heartbeat {
+					if write([]byte(": ping\n\n")) != nil {
+						return
+					}
+					idle = 0
+				}
+				timer.Reset(options.heartbeat - idle)
+			}
+		}()
+	}
+
+	var chunk ChatCompletionStreamResponse
+	for {
+		err := recvChunk(stream, &chunk)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, io.EOF) {
+			return writeEvent([]byte("[DONE]"))
+		}
+		if err != nil {
+			_ = writeEvent(proxyErrorEvent(err))
+			return err
+		}
+		if options.onChunk != nil {
+			options.onChunk(&chunk)
+		}
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		if err = writeEvent(data); err != nil {
+			return err
+		}
+	}
+}
+
+// recvChunk decodes the next chunk of stream into chunk, reusing its slices if stream supports
+// RecvInto.
+func recvChunk(stream ChatCompletionStreamReader, chunk *ChatCompletionStreamResponse) error {
+	if into, ok := stream.(interface {
+		RecvInto(*ChatCompletionStreamResponse) error
+	}); ok {
+		return into.RecvInto(chunk)
+	}
+	var err error
+	*chunk, err = stream.Recv()
+	return err
+}
+
+// proxyErrorEvent returns the error event of the chat completions API for err.
+func proxyErrorEvent(err error) []byte {
+	apiErr := &APIError{Message: err.Error(), Type: "server_error"}
+	errors.As(err, &apiErr)
+	data, marshalErr := json.Marshal(struct {
+		Error *APIError `json:"error"`
+	}{apiErr})
+	if marshalErr != nil {
+		return []byte(`{"error":{"message":"stream failed","type":"server_error"}}`)
+	}
+	return data
+}
@@ -0,0 +1,211 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// setupProxy starts a proxy endpoint streaming the chat completions of client and sending the
+// error of ProxyChatCompletionStream on proxyErrs.
+func setupProxy(t *testing.T, client *openai.Client, opts ...openai.ProxyOption) (string, chan error) {
+	t.Helper()
+	proxyErrs := make(chan error, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := client.CreateChatCompletionStream(r.Context(), openai.ChatCompletionRequest{
+			Model:    openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			proxyErrs <- err
+			return
+		}
+		proxyErrs <- openai.ProxyChatCompletionStream(w, r, stream, opts...)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy.URL, proxyErrs
+}
+
+func TestProxyChatCompletionStream(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(*openai.ClientConfig) {})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	proxyURL, proxyErrs := setupProxy(t, client, openai.WithChunkRewriter(func(chunk *openai.ChatCompletionStreamResponse) {
+		chunk.Model = "my-gateway-model"
+	}))
+
+	resp, err := http.Get(proxyURL)
+	checks.NoError(t, err, "Get error")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	checks.NoError(t, err, "ReadAll error")
+
+	checks.NoError(t, <-proxyErrs, "ProxyChatCompletionStream error")
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("unexpected Content-Type %q", contentType)
+	}
+	events := strings.Split(string(body), "\n\n")
+	if len(events) != 3 || events[1] != "data: [DONE]" || events[2] != "" {
+		t.Fatalf("unexpected events %q", events)
+	}
+	var chunk openai.ChatCompletionStreamResponse
+	checks.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(events[0], "data: ")), &chunk), "Unmarshal error")
+	if chunk.Model != "my-gateway-model" || chunk.Choices[0].Delta.Content != "Hi" {
+		t.Errorf("unexpected chunk %+v", chunk)
+	}
+}
+
+func TestProxyChatCompletionStreamError(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(*openai.ClientConfig) {})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"error\":{\"message\":\"overloaded\",\"type\":\"server_error\"}}\n\n")
+	})
+	proxyURL, proxyErrs := setupProxy(t, client)
+
+	resp, err := http.Get(proxyURL)
+	checks.NoError(t, err, "Get error")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	checks.NoError(t, err, "ReadAll error")
+
+	var apiErr *openai.APIError
+	if err = <-proxyErrs; !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if want := `data: {"error":{"message":"overloaded","type":"server_error"}}` + "\n\n"; string(body) != want {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestProxyChatCompletionStreamClientDisconnect(t *testing.T) {
+	upstreamDone := make(chan struct{})
+	client, server, teardown := setupResilientTestServer(func(*openai.ClientConfig) {})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(upstreamDone)
+	})
+	proxyURL, proxyErrs := setupProxy(t, client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxyURL, nil)
+	checks.NoError(t, err, "NewRequest error")
+	resp, err := http.DefaultClient.Do(req)
+	checks.NoError(t, err, "Do error")
+	defer resp.Body.Close()
+	_, err = resp.Body.Read(make([]byte, 16))
+	checks.NoError(t, err, "Read error")
+	cancel()
+
+	select {
+	case err = <-proxyErrs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the proxy to stop when the client disconnects")
+	}
+	select {
+	case <-upstreamDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the upstream request to be canceled")
+	}
+}
+
+func TestProxyChatCompletionStreamHeartbeat(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(*openai.ClientConfig) {})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		// A slow first token, e.g. of a reasoning model.
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	proxyURL, proxyErrs := setupProxy(t, client, openai.WithHeartbeat(20*time.Millisecond))
+
+	resp, err := http.Get(proxyURL)
+	checks.NoError(t, err, "Get error")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	checks.NoError(t, err, "ReadAll error")
+	checks.NoError(t, <-proxyErrs, "ProxyChatCompletionStream error")
+
+	events := string(body)
+	chunkAt := strings.Index(events, "data: {")
+	if !strings.HasPrefix(events, ": ping\n\n") || chunkAt < 0 ||
+		!strings.HasSuffix(events, "data: [DONE]\n\n") || strings.Contains(events[chunkAt:], ": ping") {
+		t.Errorf("expected heartbeats only while waiting for the first chunk, got %q", events)
+	}
+}
+
+func TestProxyChatCompletionStreamDisconnectNoRetry(t *testing.T) {
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	// The upstream request outlives the request of the client, e.g. to be logged on completion.
+	proxyErrs := make(chan error, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+			Model:    openai.GPT4o,
+			Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		})
+		checks.NoError(t, err, "CreateChatCompletionStream error")
+		proxyErrs <- openai.ProxyChatCompletionStream(w, r, stream)
+	}))
+	defer proxy.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxy.URL, nil)
+	checks.NoError(t, err, "NewRequest error")
+	// The client leaves before the first chunk.
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if resp, doErr := http.DefaultClient.Do(req); doErr == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	select {
+	case err = <-proxyErrs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the proxy to stop when the client disconnects")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected the upstream request not to be sent again, got %d requests", n)
+	}
+}
@@ -0,0 +1,176 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ChatCompletionsList is a list of stored chat completions, created with Store set.
+type ChatCompletionsList struct {
+	Object          string                   `json:"object"`
+	ChatCompletions []ChatCompletionResponse `json:"data"`
+	FirstID         *string                  `json:"first_id"`
+	LastID          *string                  `json:"last_id"`
+	HasMore         bool                     `json:"has_more"`
+
+	httpHeader
+}
+
+// ListChatCompletionsRequest filters and paginates the stored chat completions.
+type ListChatCompletionsRequest struct {
+	// Model lists only the chat completions of a model.
+	Model string
+	// Metadata lists only the chat completions with all these metadata.
+	Metadata map[string]string
+	Limit    *int
+	// Order is "asc" or "desc", by creation time.
+	Order *string
+	After *string
+}
+
+func (r ListChatCompletionsRequest) values() url.Values {
+	urlValues := url.Values{}
+	if r.Model != "" {
+		urlValues.Add("model", r.Model)
+	}
+	for key, value := range r.Metadata {
+		urlValues.Add(fmt.Sprintf("metadata[%s]", key), value)
+	}
+	if r.Limit != nil {
+		urlValues.Add("limit", fmt.Sprintf("%d", *r.Limit))
+	}
+	if r.Order != nil {
+		urlValues.Add("order", *r.Order)
+	}
+	if r.After != nil {
+		urlValues.Add("after", *r.After)
+	}
+	return urlValues
+}
+
+// StoredChatCompletionMessage is a message of the request of a stored chat completion.
+type StoredChatCompletionMessage struct {
+	ID string
+	ChatCompletionMessage
+}
+
+func (m *StoredChatCompletionMessage) UnmarshalJSON(data []byte) error {
+	var message struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	m.ID = message.ID
+	return m.ChatCompletionMessage.UnmarshalJSON(data)
+}
+
+func (m StoredChatCompletionMessage) MarshalJSON() ([]byte, error) {
+	data, err := m.ChatCompletionMessage.MarshalJSON()
+	if err != nil || m.ID == "" {
+		return data, err
+	}
+	message := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	message["id"], _ = json.Marshal(m.ID)
+	return json.Marshal(message)
+}
+
+// StoredChatCompletionMessagesList is a list of the messages of a stored chat completion.
+type StoredChatCompletionMessagesList struct {
+	Object   string                        `json:"object"`
+	Messages []StoredChatCompletionMessage `json:"data"`
+	FirstID  *string                       `json:"first_id"`
+	LastID   *string                       `json:"last_id"`
+	HasMore  bool                          `json:"has_more"`
+
+	httpHeader
+}
+
+type ChatCompletionDeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+
+	httpHeader
+}
+
+// RetrieveChatCompletion retrieves a stored chat completion.
+func (c *Client) RetrieveChatCompletion(
+	ctx context.Context,
+	completionID string,
+) (response ChatCompletionResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(chatCompletionsSuffix+"/"+completionID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ListChatCompletions lists the stored chat completions.
+func (c *Client) ListChatCompletions(
+	ctx context.Context,
+	request ListChatCompletionsRequest,
+) (response ChatCompletionsList, err error) {
+	urlSuffix := withQuery(chatCompletionsSuffix, request.values())
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ListChatCompletionMessages lists the messages of the request of a stored chat completion.
+func (c *Client) ListChatCompletionMessages(
+	ctx context.Context,
+	completionID string,
+	pagination Pagination,
+) (response StoredChatCompletionMessagesList, err error) {
+	urlSuffix := chatCompletionsSuffix + "/" + completionID + "/messages" + pagination.encode()
+	req, err := c.newRequest(ctx, http.MethodGet, c.fullURL(urlSuffix))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// UpdateChatCompletion replaces the metadata of a stored chat completion.
+func (c *Client) UpdateChatCompletion(
+	ctx context.Context,
+	completionID string,
+	metadata map[string]string,
+) (response ChatCompletionResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(chatCompletionsSuffix+"/"+completionID),
+		withBody(map[string]any{"metadata": metadata}))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// DeleteChatCompletion deletes a stored chat completion.
+func (c *Client) DeleteChatCompletion(
+	ctx context.Context,
+	completionID string,
+) (response ChatCompletionDeleteResponse, err error) {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.fullURL(chatCompletionsSuffix+"/"+completionID))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
@@ -0,0 +1,120 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestStoredChatCompletions(t *testing.T) {
+	const completionID = "chatcmpl-abc123"
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var request map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if request["store"] != true || request["metadata"].(map[string]any)["topic"] != "billing" {
+				t.Errorf("unexpected request %v", request)
+			}
+			fmt.Fprintf(w, `{"id":%q,"object":"chat.completion","choices":[]}`, completionID)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("model") != openai.GPT4o || query.Get("metadata[topic]") != "billing" || query.Get("limit") != "2" {
+			t.Errorf("unexpected list query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"object":"list","data":[{"id":%q,"metadata":{"topic":"billing"}}],`+
+			`"first_id":%[1]q,"last_id":%[1]q,"has_more":false}`, completionID)
+	})
+	server.RegisterHandler("/v1/chat/completions/"+completionID, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			fmt.Fprintf(w, `{"id":%q,"object":"chat.completion.deleted","deleted":true}`, completionID)
+		case http.MethodPost:
+			var request struct {
+				Metadata map[string]string `json:"metadata"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			data, _ := json.Marshal(request.Metadata)
+			fmt.Fprintf(w, `{"id":%q,"object":"chat.completion","metadata":%s}`, completionID, data)
+		default:
+			fmt.Fprintf(w, `{"id":%q,"object":"chat.completion","metadata":{"topic":"billing"},"choices":[]}`,
+				completionID)
+		}
+	})
+	server.RegisterHandler("/v1/chat/completions/"+completionID+"/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("order") != "asc" {
+			t.Errorf("unexpected messages query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"object":"list","data":[{"id":"chatcmpl-abc123-0","role":"user","content":"Hi"}],`+
+			`"has_more":false}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+		Store:    true,
+		Metadata: map[string]string{"topic": "billing"},
+	})
+	checks.NoError(t, err, "CreateChatCompletion error")
+
+	completion, err := client.RetrieveChatCompletion(ctx, completionID)
+	checks.NoError(t, err, "RetrieveChatCompletion error")
+	if completion.ID != completionID || completion.Metadata["topic"] != "billing" {
+		t.Errorf("unexpected completion %+v", completion)
+	}
+
+	limit := 2
+	list, err := client.ListChatCompletions(ctx, openai.ListChatCompletionsRequest{
+		Model:    openai.GPT4o,
+		Metadata: map[string]string{"topic": "billing"},
+		Limit:    &limit,
+	})
+	checks.NoError(t, err, "ListChatCompletions error")
+	if len(list.ChatCompletions) != 1 || *list.LastID != completionID {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	order := "asc"
+	messages, err := client.ListChatCompletionMessages(ctx, completionID, openai.Pagination{Order: &order})
+	checks.NoError(t, err, "ListChatCompletionMessages error")
+	if len(messages.Messages) != 1 || messages.Messages[0].ID != "chatcmpl-abc123-0" ||
+		messages.Messages[0].Content != "Hi" {
+		t.Errorf("unexpected messages %+v", messages.Messages)
+	}
+
+	updated, err := client.UpdateChatCompletion(ctx, completionID, map[string]string{"topic": "refunds"})
+	checks.NoError(t, err, "UpdateChatCompletion error")
+	if updated.Metadata["topic"] != "refunds" {
+		t.Errorf("unexpected metadata %v", updated.Metadata)
+	}
+
+	deleted, err := client.DeleteChatCompletion(ctx, completionID)
+	checks.NoError(t, err, "DeleteChatCompletion error")
+	if !deleted.Deleted {
+		t.Error("expected the chat completion to be deleted")
+	}
+}
+
+func TestStoredChatCompletionMessageMarshal(t *testing.T) {
+	message := openai.StoredChatCompletionMessage{
+		ID:                    "msg-1",
+		ChatCompletionMessage: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "Hi"},
+	}
+	data, err := json.Marshal(message)
+	checks.NoError(t, err, "Marshal error")
+
+	var decoded openai.StoredChatCompletionMessage
+	checks.NoError(t, json.Unmarshal(data, &decoded), "Unmarshal error")
+	if decoded.ID != "msg-1" || decoded.Role != openai.ChatMessageRoleUser || decoded.Content != "Hi" {
+		t.Errorf("unexpected round trip %s: %+v", data, decoded)
+	}
+}
@@ -3,13 +3,36 @@ package openai
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 )
 
 var (
 	ErrTooManyEmptyStreamMessages = errors.New("stream has sent too many empty messages")
+	ErrMalformedChunk             = errors.New("stream chunk could not be decoded")
 )
 
+// MalformedChunkError is returned by Recv for a stream chunk that could not be decoded.
+// Raw holds the data of the chunk so that callers can log, repair or skip it.
+type MalformedChunkError struct {
+	Raw []byte
+	Err error
+}
+
+func (e *MalformedChunkError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrMalformedChunk, e.Err)
+}
+
+// Is reports whether target is ErrMalformedChunk.
+func (e *MalformedChunkError) Is(target error) bool {
+	return target == ErrMalformedChunk //nolint:errorlint // comparing with the sentinel itself
+}
+
+// Unwrap returns the decoding error, e.g. a *json.SyntaxError.
+func (e *MalformedChunkError) Unwrap() error {
+	return e.Err
+}
+
 type CompletionStream struct {
 	*streamReader[CompletionResponse]
 }
@@ -22,6 +45,7 @@ func (c *Client) CreateCompletionStream(
 	ctx context.Context,
 	request CompletionRequest,
 ) (stream *CompletionStream, err error) {
+	request.Model = c.config.mapModel(request.Model)
 	urlSuffix := "/completions"
 	if !checkEndpointSupportsModel(urlSuffix, request.Model) {
 		err = ErrCompletionUnsupportedModel
@@ -34,6 +58,10 @@ func (c *Client) CreateCompletionStream(
 	}
 
 	request.Stream = true
+	if err = request.Validate(); err != nil {
+		return
+	}
+
 	req, err := c.newRequest(
 		ctx,
 		http.MethodPost,
@@ -0,0 +1,59 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+const defaultStreamBufferSize = 64 * 1024
+
+// streamBufferPools holds a streamBufferPool per buffer size, shared by all clients.
+var streamBufferPools sync.Map
+
+// streamBufferPool reuses the read and event buffers of closed streams.
+type streamBufferPool struct {
+	size    int
+	readers sync.Pool
+	buffers sync.Pool
+}
+
+func getStreamBufferPool(size int) *streamBufferPool {
+	if size <= 0 {
+		size = defaultStreamBufferSize
+	}
+	if pool, ok := streamBufferPools.Load(size); ok {
+		return pool.(*streamBufferPool)
+	}
+	pool, _ := streamBufferPools.LoadOrStore(size, &streamBufferPool{size: size})
+	return pool.(*streamBufferPool)
+}
+
+func (p *streamBufferPool) get(body io.Reader) (*bufio.Reader, *bytes.Buffer) {
+	reader, ok := p.readers.Get().(*bufio.Reader)
+	if ok {
+		reader.Reset(body)
+	} else {
+		reader = bufio.NewReaderSize(body, p.size)
+	}
+
+	data, ok := p.buffers.Get().(*bytes.Buffer)
+	if !ok {
+		data = new(bytes.Buffer)
+		data.Grow(p.size)
+	}
+	return reader, data
+}
+
+func (p *streamBufferPool) put(reader *bufio.Reader, data *bytes.Buffer) {
+	if reader != nil && reader.Size() == p.size {
+		reader.Reset(nil)
+		p.readers.Put(reader)
+	}
+	// Don't keep buffers that grew for an unusually large event.
+	if data != nil && data.Cap() <= 4*p.size {
+		data.Reset()
+		p.buffers.Put(data)
+	}
+}
@@ -0,0 +1,114 @@
+package openai //nolint:testpackage // testing private field
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func newTestStreamResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestStreamBufferSize(t *testing.T) {
+	const size = 256 * 1024
+	stream := newStreamReader[ChatCompletionStreamResponse](
+		newTestStreamResponse("data: {}\n\n"), ClientConfig{StreamBufferSize: size})
+	if stream.reader.Size() != size {
+		t.Errorf("expected reader size %d, got %d", size, stream.reader.Size())
+	}
+	if stream.dataBuffer.Cap() < size {
+		t.Errorf("expected preallocated data buffer of %d bytes, got %d", size, stream.dataBuffer.Cap())
+	}
+
+	stream = newStreamReader[ChatCompletionStreamResponse](newTestStreamResponse(""), ClientConfig{})
+	if stream.reader.Size() != defaultStreamBufferSize {
+		t.Errorf("expected default reader size, got %d", stream.reader.Size())
+	}
+}
+
+func TestStreamBufferReuseAfterClose(t *testing.T) {
+	stream := newStreamReader[ChatCompletionStreamResponse](
+		newTestStreamResponse("data: {\"id\":\"1\"}\n\n"), ClientConfig{StreamBufferSize: 1024})
+	resp, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if resp.ID != "1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	checks.NoError(t, stream.Close(), "Close error")
+	checks.NoError(t, stream.Close(), "second Close error")
+	if _, err = stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF after Close, got %v", err)
+	}
+
+	// A new stream may get the recycled buffers; it must not see data of the closed one.
+	next := newStreamReader[ChatCompletionStreamResponse](
+		newTestStreamResponse("data: {\"id\":\"2\"}\n\n"), ClientConfig{StreamBufferSize: 1024})
+	resp, err = next.Recv()
+	checks.NoError(t, err, "Recv error")
+	if resp.ID != "2" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestStreamCloseDuringRecv(t *testing.T) {
+	body, upstream := io.Pipe()
+	defer upstream.Close()
+	resp := newTestStreamResponse("")
+	resp.Body = body
+	stream := newStreamReader[ChatCompletionStreamResponse](resp, ClientConfig{StreamBufferSize: 1024})
+
+	received := make(chan error)
+	go func() {
+		_, err := stream.Recv()
+		received <- err
+		// Blocks until Close.
+		_, err = stream.Recv()
+		received <- err
+	}()
+	go func() {
+		_, _ = io.WriteString(upstream, "data: {\"id\":\"1\"}\n\n")
+	}()
+	checks.NoError(t, <-received, "Recv error")
+
+	time.Sleep(10 * time.Millisecond)
+	checks.NoError(t, stream.Close(), "Close error")
+	checks.HasError(t, <-received, "expected an error from the Recv canceled by Close")
+
+	// The buffers were returned by the goroutine reading the stream once its Recv ended.
+	if stream.reader != nil || stream.dataBuffer != nil {
+		t.Error("expected the buffers to be returned to the pool")
+	}
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF after Close, got %v", err)
+	}
+}
+
+func TestStreamRecvRawKeepsBuffers(t *testing.T) {
+	stream := newStreamReader[ChatCompletionStreamResponse](
+		newTestStreamResponse("data: {\"id\":\"1\"}\n\n"), ClientConfig{StreamBufferSize: 1024})
+	data, err := stream.RecvRaw()
+	checks.NoError(t, err, "RecvRaw error")
+	_, err = stream.RecvRaw()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+	checks.NoError(t, stream.Close(), "Close error")
+
+	// The data returned by RecvRaw may still be held, so its buffer is not recycled.
+	if stream.dataBuffer == nil {
+		t.Error("expected the buffers of a raw stream to be kept")
+	}
+	if string(data) != `{"id":"1"}` {
+		t.Errorf("unexpected data %q", data)
+	}
+}
@@ -0,0 +1,98 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// parseStreamError returns the error carried by the data of a stream event, or nil if the
+// event is not an error. It recognizes the shapes sent by the providers mid-stream:
+//
+//	{"error":{"message":"...","type":"...","param":null,"code":"..."}}  OpenAI, Azure
+//	{"type":"error","error":{"type":"overloaded_error","message":"..."}}  Anthropic
+//	{"object":"error","message":"...","type":"...","code":400}  SGLang, vLLM
+//	{"error":"..."}  gateways
+//
+// as well as the data of events of type error.
+func parseStreamError(eventType string, data []byte) *APIError {
+	var errorValue, objectValue, statusValue []byte
+	scanJSONObject(data, func(key, value []byte) {
+		switch string(key) {
+		case "error":
+			errorValue = value
+		case "object":
+			objectValue = value
+		case "status":
+			statusValue = value
+		}
+	})
+
+	if string(errorValue) == "null" {
+		errorValue = nil
+	}
+	if errorValue == nil && string(objectValue) != `"error"` && eventType != "error" {
+		return nil
+	}
+	apiErr := &APIError{}
+	switch {
+	case len(errorValue) > 0 && errorValue[0] == '"':
+		if json.Unmarshal(errorValue, &apiErr.Message) != nil {
+			return nil
+		}
+	case len(errorValue) > 0 && errorValue[0] == '{':
+		if json.Unmarshal(errorValue, apiErr) != nil {
+			return nil
+		}
+		// Azure reports the HTTP status of the error inside the error object.
+		var inner struct {
+			Status int `json:"status"`
+		}
+		if json.Unmarshal(errorValue, &inner) == nil && inner.Status > 0 {
+			apiErr.HTTPStatusCode = inner.Status
+		}
+	default:
+		if json.Unmarshal(data, apiErr) != nil {
+			return nil
+		}
+	}
+
+	if apiErr.HTTPStatusCode == 0 && len(statusValue) > 0 {
+		_ = json.Unmarshal(statusValue, &apiErr.HTTPStatusCode)
+	}
+	if apiErr.HTTPStatusCode == 0 {
+		// SGLang and vLLM send the HTTP status as the error code.
+		if code, ok := apiErr.Code.(int); ok && code >= http.StatusBadRequest && code < 600 {
+			apiErr.HTTPStatusCode = code
+		}
+	}
+	return apiErr
+}
+
+// setStreamErrorStatus sets the HTTP status of an error received mid-stream to the status the
+// provider reported for it or, if none, to the status of the stream response.
+func setStreamErrorStatus(apiErr *APIError, response *http.Response) {
+	if apiErr.HTTPStatusCode == 0 {
+		// Anthropic reports overload and rate limits in the stream with the status they have
+		// as responses.
+		switch apiErr.Type {
+		case "overloaded_error":
+			apiErr.HTTPStatusCode = anthropicOverloadedStatus
+			apiErr.HTTPStatus = "529 Overloaded"
+		case "rate_limit_error":
+			apiErr.HTTPStatusCode = http.StatusTooManyRequests
+		}
+	}
+	if apiErr.HTTPStatusCode == 0 && response != nil {
+		apiErr.HTTPStatusCode = response.StatusCode
+		apiErr.HTTPStatus = response.Status
+	}
+	if response != nil && apiErr.RetryAfter == 0 {
+		apiErr.RetryAfter = parseRetryAfter(response.Header, apiErr.HTTPStatusCode, time.Now())
+		apiErr.RateLimitHeaders = newRateLimitHeaders(response.Header)
+	}
+	if apiErr.HTTPStatus == "" && apiErr.HTTPStatusCode > 0 {
+		apiErr.HTTPStatus = fmt.Sprintf("%d %s", apiErr.HTTPStatusCode, http.StatusText(apiErr.HTTPStatusCode))
+	}
+}
@@ -0,0 +1,131 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const streamErrorFirstChunk = `data: {"id":"1","object":"chat.completion.chunk","model":"gpt-4o",` +
+	`"choices":[{"index":0,"delta":{"content":"Hi"}}]}`
+
+func TestChatCompletionStreamMidStreamErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		event      string
+		message    string
+		errType    string
+		code       any
+		param      string
+		statusCode int
+	}{
+		{
+			name: "OpenAI",
+			event: `data: {"error":{"message":"The server had an error","type":"server_error",` +
+				`"param":null,"code":"server_error"}}`,
+			message:    "The server had an error",
+			errType:    "server_error",
+			code:       "server_error",
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Azure",
+			event: `data: {"error":{"code":"content_filter","message":"Filtered","param":"prompt",` +
+				`"status":400,"innererror":{"code":"ResponsibleAIPolicyViolation"}}}`,
+			message:    "Filtered",
+			code:       "content_filter",
+			param:      "prompt",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Anthropic",
+			event:      "event: error\n" + `data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			message:    "Overloaded",
+			errType:    "overloaded_error",
+			statusCode: 529,
+		},
+		{
+			name: "SGLang",
+			event: `data: {"object":"error","message":"Requested tokens exceed context length",` +
+				`"type":"BadRequestError","param":null,"code":400}`,
+			message:    "Requested tokens exceed context length",
+			errType:    "BadRequestError",
+			code:       400,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "plain message",
+			event:      `data: {"error":"upstream timeout"}`,
+			message:    "upstream timeout",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "error event",
+			event:      "event: error\n" + `data: {"message":"Rate limited","code":"rate_limit_exceeded"}`,
+			message:    "Rate limited",
+			code:       "rate_limit_exceeded",
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, server, teardown := setupOpenAITestServer()
+			defer teardown()
+			server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprintf(w, "%s\n\n%s\n\n", streamErrorFirstChunk, tc.event)
+			})
+
+			stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+				Model:    openai.GPT4o,
+				Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+			})
+			checks.NoError(t, err, "CreateChatCompletionStream error")
+			defer stream.Close()
+			_, err = stream.Recv()
+			checks.NoError(t, err, "Recv error on the first chunk")
+
+			_, err = stream.Recv()
+			var apiErr *openai.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if apiErr.Message != tc.message || apiErr.Type != tc.errType || apiErr.Code != tc.code {
+				t.Errorf("unexpected error %+v", apiErr)
+			}
+			if (apiErr.Param == nil && tc.param != "") || (apiErr.Param != nil && *apiErr.Param != tc.param) {
+				t.Errorf("unexpected param %v", apiErr.Param)
+			}
+			if apiErr.HTTPStatusCode != tc.statusCode || apiErr.HTTPStatus == "" {
+				t.Errorf("unexpected status %d %q", apiErr.HTTPStatusCode, apiErr.HTTPStatus)
+			}
+		})
+	}
+}
+
+func TestChatCompletionStreamNullErrorField(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"1","error":null,"choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	response, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if response.Choices[0].Delta.Content != "Hi" {
+		t.Errorf("unexpected chunk %+v", response)
+	}
+}
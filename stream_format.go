@@ -0,0 +1,176 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNotEventStream is the error of the *RequestError returned when a stream request receives
+// a JSON response that is not an error, e.g. a completion from a server ignoring the stream
+// parameter. HTML and other non-JSON responses are reported with ErrNonJSONResponse.
+var ErrNotEventStream = errors.New("response is not an event stream")
+
+// StreamFormat selects how the events of streams are framed.
+type StreamFormat string
+
+const (
+	// StreamFormatAuto reads newline-delimited JSON streams when the Content-Type of the response
+	// says so, such as application/x-ndjson, and server-sent events otherwise. Responses whose
+	// Content-Type is JSON, HTML or XML fail the stream request with their body right away.
+	// This is the default.
+	StreamFormatAuto StreamFormat = ""
+	// StreamFormatSSE reads server-sent events, whatever the Content-Type of the response, for
+	// servers labeling their streams wrongly.
+	StreamFormatSSE StreamFormat = "sse"
+	// StreamFormatNDJSON reads newline-delimited JSON (JSON Lines), one chunk per line without
+	// "data:" prefix, as sent by Ollama and some gateways. The stream ends when the connection
+	// is closed, or with a line holding a done marker of the StreamTermination.
+	StreamFormatNDJSON StreamFormat = "ndjson"
+)
+
+// ndjsonContentTypes are the media types of newline-delimited JSON streams.
+var ndjsonContentTypes = map[string]bool{
+	"application/x-ndjson":     true,
+	"application/ndjson":       true,
+	"application/jsonl":        true,
+	"application/x-jsonl":      true,
+	"application/jsonlines":    true,
+	"application/x-jsonlines":  true,
+	"application/json-seq":     true,
+	"application/stream+json":  true,
+	"application/x-json-lines": true,
+}
+
+// isNDJSON reports whether the body of resp is read as newline-delimited JSON with format.
+func (format StreamFormat) isNDJSON(resp *http.Response) bool {
+	switch format {
+	case StreamFormatNDJSON:
+		return true
+	case StreamFormatSSE:
+		return false
+	}
+	if resp == nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && ndjsonContentTypes[mediaType]
+}
+
+// accept returns the Accept header of the stream requests with format.
+func (format StreamFormat) accept() string {
+	if format == StreamFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/event-stream"
+}
+
+// processNDJSONLines returns the next line of a newline-delimited JSON stream.
+func (stream *streamReader[T]) processNDJSONLines() ([]byte, error) {
+	for {
+		line, readErr := stream.readLine()
+		// Records of application/json-seq start with a record separator.
+		line = bytes.TrimSpace(bytes.TrimPrefix(line, []byte{0x1E}))
+		if len(line) > 0 {
+			stream.emptyMessagesCount = 0
+			stream.eventType = ""
+			if stream.terminationPolicy().isDoneMarker(line) {
+				stream.isFinished = true
+				stream.receivedDone = true
+				return nil, io.EOF
+			}
+			return line, nil
+		}
+		if readErr != nil {
+			if readErr != io.EOF {
+				return nil, readErr
+			}
+			stream.isFinished = true
+			stream.receivedEOF = !stream.receivedDone
+			return nil, io.EOF
+		}
+		stream.emptyMessagesCount++
+		if stream.emptyMessagesCount > stream.emptyMessagesLimit {
+			return nil, ErrTooManyEmptyStreamMessages
+		}
+	}
+}
+
+// isNonStreamContentType reports whether the Content-Type of resp is a JSON, HTML or XML
+// document rather than a stream. Responses without Content-Type are read as streams.
+func isNonStreamContentType(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || ndjsonContentTypes[mediaType] {
+		return false
+	}
+	switch mediaType {
+	case "application/json", "text/json", "text/html", "application/xhtml+xml", "text/xml", "application/xml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// nonStreamResponseError returns the error of a successful response to a stream request that is
+// not a stream: its APIError if the body is an error response, and otherwise a *RequestError
+// with the body.
+func (c *Client) nonStreamResponseError(resp *http.Response) error {
+	err := c.handleErrorResp(resp)
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) && reqErr.Err == nil {
+		reqErr.Err = ErrNotEventStream
+	}
+	return err
+}
+
+// decodeCharset returns the body of resp decoded to UTF-8 when its Content-Type declares the
+// ISO-8859-1 charset. Other charsets are read as UTF-8.
+func decodeCharset(resp *http.Response) io.Reader {
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return resp.Body
+	}
+	switch strings.ToLower(params["charset"]) {
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return &latin1Reader{reader: bufio.NewReader(resp.Body)}
+	}
+	return resp.Body
+}
+
+// latin1Reader decodes ISO-8859-1 text to UTF-8.
+type latin1Reader struct {
+	reader  *bufio.Reader
+	pending []byte
+}
+
+func (r *latin1Reader) Read(p []byte) (int, error) {
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	for n < len(p) {
+		b, err := r.reader.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		var encoded [2]byte
+		utf8.EncodeRune(encoded[:], rune(b))
+		written := copy(p[n:], encoded[:])
+		r.pending = append(r.pending, encoded[written:]...)
+		n += written
+		if r.reader.Buffered() == 0 {
+			break
+		}
+	}
+	return n, nil
+}
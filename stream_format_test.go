@@ -0,0 +1,188 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestStreamFormatNDJSONAutoDetected(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "text/event-stream" {
+			t.Errorf("unexpected Accept header %q", accept)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`+"\n")
+		fmt.Fprint(w, "\n")
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"delta":{"content":"lo"},"finish_reason":"stop"}]}`+"\r\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var content string
+	for {
+		chunk, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, recvErr, "Recv error")
+		content += chunk.Choices[0].Delta.Content
+	}
+	if content != "Hello" {
+		t.Errorf("unexpected content %q", content)
+	}
+	if !stream.IsComplete() {
+		t.Error("expected the NDJSON stream to be complete once closed")
+	}
+}
+
+func TestStreamFormatNDJSON(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.StreamFormat = openai.StreamFormatNDJSON
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "application/x-ndjson" {
+			t.Errorf("unexpected Accept header %q", accept)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n")
+		fmt.Fprint(w, `{"error":{"message":"overloaded","type":"server_error"}}`+"\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.Choices[0].Delta.Content != "Hi" {
+		t.Errorf("unexpected chunk %+v", chunk)
+	}
+	_, err = stream.Recv()
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "overloaded" {
+		t.Errorf("expected the error line as an APIError, got %v", err)
+	}
+}
+
+func TestStreamFormatNDJSONDoneMarker(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/jsonl")
+		fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"text":"Hi"}]}`+"\n[DONE]\n"+`{"id":"2"}`+"\n")
+	})
+
+	stream, err := client.CreateCompletionStream(context.Background(), openai.CompletionRequest{
+		Model:  openai.GPT3Babbage002,
+		Prompt: "Hi",
+	})
+	checks.NoError(t, err, "CreateCompletionStream error")
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.Choices[0].Text != "Hi" {
+		t.Errorf("unexpected chunk %+v", chunk)
+	}
+	if _, err = stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after the done marker, got %v", err)
+	}
+}
+
+func TestStreamNonStreamResponse(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	body := `{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})
+	server.RegisterHandler("/v1/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		fmt.Fprint(w, `{"error":{"message":"quota exceeded","type":"insufficient_quota"}}`)
+	})
+
+	_, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	var reqErr *openai.RequestError
+	if !errors.As(err, &reqErr) || !errors.Is(err, openai.ErrNotEventStream) {
+		t.Fatalf("expected a RequestError with ErrNotEventStream, got %v", err)
+	}
+	if string(reqErr.Body) != body || reqErr.ContentType != "application/json" {
+		t.Errorf("unexpected body %q or content type %q", reqErr.Body, reqErr.ContentType)
+	}
+
+	_, err = client.CreateCompletionStream(context.Background(), openai.CompletionRequest{
+		Model:  openai.GPT3Babbage002,
+		Prompt: "Hi",
+	})
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "quota exceeded" || apiErr.HTTPStatusCode != http.StatusOK {
+		t.Errorf("expected the APIError of the body, got %v", err)
+	}
+}
+
+func TestStreamFormatSSEMislabeled(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.StreamFormat = openai.StreamFormatSSE
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.Choices[0].Delta.Content != "Hi" {
+		t.Errorf("unexpected chunk %+v", chunk)
+	}
+}
+
+func TestStreamLatin1Charset(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=ISO-8859-1")
+		// "Café" encoded in ISO-8859-1.
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Caf\xe9\"}}]}\n\ndata: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hi"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if chunk.Choices[0].Delta.Content != "Café" {
+		t.Errorf("unexpected content %q", chunk.Choices[0].Delta.Content)
+	}
+}
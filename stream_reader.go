@@ -3,17 +3,19 @@ package openai
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
-	"regexp"
+	"sync"
+	"time"
 
 	utils "github.com/sashabaranov/go-openai/internal"
 )
 
 var (
-	headerData  = regexp.MustCompile(`^data:\s*`)
-	errorPrefix = regexp.MustCompile(`^data:\s*{"error":`)
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+	newline = []byte{'\n'}
 )
 
 type streamable interface {
@@ -21,150 +23,479 @@ type streamable interface {
 }
 
 type streamReader[T streamable] struct {
-	emptyMessagesLimit uint
-	isFinished         bool
-	receivedDone       bool // Track if we received the [DONE] marker
+	emptyMessagesLimit   uint
+	isFinished           bool
+	receivedDone         bool // Track if we received the [DONE] marker
+	receivedEOF          bool // The body ended without a done marker
+	receivedFinishReason bool
+	delivered            bool           // A chunk was returned by RecvInto
+	retrier              *streamRetrier // Sends the request again, see RetryPolicy
+	termination          *StreamTermination
+	started              bool // Set once the body has been read from
+	emptyMessagesCount   uint // Consecutive lines without data
+	strictChunks         bool // Report malformed chunks instead of skipping them
+	ndjson               bool // The body is newline-delimited JSON rather than server-sent events
+	malformedChunks      int
 
 	reader         *bufio.Reader
 	response       *http.Response
 	errAccumulator utils.ErrorAccumulator
 	unmarshaler    utils.Unmarshaler
 	dataBuffer     *bytes.Buffer // Buffer for accumulating multi-line data
+	lineBuffer     []byte
+	skipLF         bool // The previous line ended with CR, skip a following LF
+	checkedBOM     bool
+	bufferPool     *streamBufferPool // Receives the buffers back once the stream ended
+	rawExposed     bool              // RecvRaw returned data pointing into the buffers
+
+	// mu guards the fields that Close, which may run while Recv is blocked, shares with Recv.
+	mu        sync.Mutex
+	closed    bool
+	receiving bool
+	done      chan struct{} // Closed by Close, see closedChan
+
+	// eventDecoder, if set, decodes events of a provider specific stream format.
+	// It reports handled as false for events it leaves to the regular decoding.
+	eventDecoder func(eventType string, data []byte) (response *T, handled bool, err error)
+
+	sentAt           time.Time // When the request was sent
+	firstEventTime   time.Duration
+	lastEventTime    time.Duration
+	events           int
+	eventBytes       int64
+	completionTokens int
+
+	pendingEventType string
+	eventType        string // Type of the last dispatched event
+	lastEventID      string
 
 	httpHeader
 }
 
 func (stream *streamReader[T]) Recv() (response T, err error) {
-	rawLine, err := stream.RecvRaw()
-	if err != nil {
+	err = stream.RecvInto(&response)
+	return
+}
+
+// resettableResponse is implemented by responses that keep the capacity of their slices when
+// reset for decoding the next chunk.
+type resettableResponse interface {
+	reset()
+}
+
+// RecvInto decodes the next chunk into response, reusing the backing arrays of its slices
+// instead of allocating a new response for every chunk. Slices of a previous chunk decoded into
+// response must not be used after the call. It returns the same errors as Recv.
+func (stream *streamReader[T]) RecvInto(response *T) error {
+	if err := stream.beginRecv(); err != nil {
+		return err
+	}
+	defer stream.endRecv()
+	for {
+		err := stream.decodeNext(response)
+		if err == nil {
+			stream.delivered = true
+			return nil
+		}
+		if err = stream.retryBeforeFirstChunk(err); err == nil {
+			continue
+		}
 		// Check for common network errors that might cause unexpected EOF
 		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
 			// Return EOF consistently for all EOF-like errors
-			return response, io.EOF
+			return stream.endError()
 		}
-		return
+		return err
 	}
+}
 
-	// Check if the response is an error response
-	if bytes.Contains(rawLine, []byte(`"error":`)) {
-		var errResp ErrorResponse
-		if err = stream.unmarshaler.Unmarshal(rawLine, &errResp); err == nil && errResp.Error != nil {
-			return response, errResp.Error
+// decodeNext decodes the next chunk into response.
+func (stream *streamReader[T]) decodeNext(response *T) error {
+	for {
+		rawLine, err := stream.recvRaw()
+		if err != nil {
+			return err
 		}
-	}
 
-	// Try to unmarshal the response
-	err = stream.unmarshaler.Unmarshal(rawLine, &response)
-	if err != nil {
-		// SGLang might send partial JSON for structured output streaming
-		// Log but don't fail if it's a parsing error
-		if bytes.Contains(rawLine, []byte(`"choices"`)) {
-			// Likely a valid response with parsing issues, return empty response
-			return response, nil
+		if stream.eventDecoder != nil && stream.eventType != "" {
+			decoded, handled, decodeErr := stream.eventDecoder(stream.eventType, rawLine)
+			if errors.Is(decodeErr, io.EOF) {
+				stream.isFinished = true
+				stream.receivedDone = true
+				decodeErr = stream.endError()
+			}
+			var apiErr *APIError
+			if errors.As(decodeErr, &apiErr) {
+				setStreamErrorStatus(apiErr, stream.response)
+			}
+			if decodeErr != nil {
+				return decodeErr
+			}
+			if handled {
+				if decoded == nil {
+					continue
+				}
+				*response = *decoded
+				stream.recordChunk(response)
+				return nil
+			}
 		}
-		return
+
+		if apiErr := parseStreamError(stream.eventType, rawLine); apiErr != nil {
+			setStreamErrorStatus(apiErr, stream.response)
+			return apiErr
+		}
+
+		resetResponse(response)
+		err = stream.unmarshaler.Unmarshal(rawLine, response)
+		if err == nil {
+			stream.recordChunk(response)
+			return nil
+		}
+		var unknownErr *UnknownFieldsError
+		if errors.As(err, &unknownErr) {
+			return err
+		}
+		// SGLang might send partial JSON for structured output streaming,
+		// lenient streams skip such chunks.
+		if !stream.strictChunks && bytes.Contains(rawLine, []byte(`"choices"`)) {
+			stream.malformedChunks++
+			resetResponse(response)
+			continue
+		}
+		return &MalformedChunkError{Raw: append([]byte(nil), rawLine...), Err: err}
 	}
-	return response, nil
 }
 
+func resetResponse[T streamable](response *T) {
+	if resettable, ok := any(response).(resettableResponse); ok {
+		resettable.reset()
+	} else {
+		*response = *new(T)
+	}
+}
+
+// MalformedChunks returns the number of malformed chunks a lenient stream has skipped.
+func (stream *streamReader[T]) MalformedChunks() int {
+	return stream.malformedChunks
+}
+
+// RecvRaw returns the data of the next event. The data is only valid until the next call.
 func (stream *streamReader[T]) RecvRaw() ([]byte, error) {
+	if err := stream.beginRecv(); err != nil {
+		return nil, err
+	}
+	defer stream.endRecv()
+	// The caller may keep the data, so the buffers are never reused by another stream.
+	stream.rawExposed = true
+	return stream.recvRaw()
+}
+
+func (stream *streamReader[T]) recvRaw() ([]byte, error) {
 	if stream.isFinished {
-		return nil, io.EOF
+		return nil, stream.endError()
+	}
+	stream.started = true
+
+	data, err := stream.processLines()
+	if errors.Is(err, io.EOF) {
+		err = stream.endError()
+	}
+	if err == nil {
+		stream.lastEventTime = time.Since(stream.sentAt)
+		if stream.events == 0 {
+			stream.firstEventTime = stream.lastEventTime
+		}
+		stream.events++
+		stream.eventBytes += int64(len(data))
+	}
+	return data, err
+}
+
+// recordChunk keeps the completion tokens of the usage chunk, if any, and whether a finish
+// reason was received.
+func (stream *streamReader[T]) recordChunk(response *T) {
+	switch response := any(response).(type) {
+	case *ChatCompletionStreamResponse:
+		if response.Usage != nil && response.Usage.CompletionTokens > 0 {
+			stream.completionTokens = response.Usage.CompletionTokens
+		}
+		for i := range response.Choices {
+			if response.Choices[i].FinishReason != "" {
+				stream.receivedFinishReason = true
+			}
+		}
+	case *CompletionResponse:
+		if response.Usage.CompletionTokens > 0 {
+			stream.completionTokens = response.Usage.CompletionTokens
+		}
+		for i := range response.Choices {
+			if response.Choices[i].FinishReason != "" {
+				stream.receivedFinishReason = true
+			}
+		}
+	}
+}
+
+// StreamStats describes the performance of a stream so far.
+type StreamStats struct {
+	// TimeToFirstToken is the time from sending the request to the first event.
+	TimeToFirstToken time.Duration
+	// Duration is the time from sending the request to the last event.
+	Duration time.Duration
+	// Chunks is the number of events received.
+	Chunks int
+	// Bytes is the size of the data of the events received.
+	Bytes int64
+	// CompletionTokens is the number of generated tokens reported by the usage of the stream,
+	// or zero if usage is not available, see StreamOptions.IncludeUsage.
+	CompletionTokens int
+	// TokensPerSecond is the rate of generation from the first to the last event, or zero if
+	// usage is not available.
+	TokensPerSecond float64
+}
+
+// Stats returns the performance of the stream so far.
+func (stream *streamReader[T]) Stats() StreamStats {
+	stats := StreamStats{
+		TimeToFirstToken: stream.firstEventTime,
+		Duration:         stream.lastEventTime,
+		Chunks:           stream.events,
+		Bytes:            stream.eventBytes,
+		CompletionTokens: stream.completionTokens,
+	}
+	generation := stats.Duration - stats.TimeToFirstToken
+	if generation <= 0 {
+		generation = stats.Duration
+	}
+	if stats.CompletionTokens > 0 && generation > 0 {
+		stats.TokensPerSecond = float64(stats.CompletionTokens) / generation.Seconds()
 	}
+	return stats
+}
 
-	return stream.processLines()
+// TimeToFirstToken returns the time from sending the request to receiving the first event of
+// the stream, or zero if no event has been received yet.
+func (stream *streamReader[T]) TimeToFirstToken() time.Duration {
+	return stream.firstEventTime
 }
 
+// processLines parses the event stream as specified by
+// https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+// and returns the data of the next event. Lines that are not SSE fields, such as a raw JSON
+// error body sent instead of an event stream, are collected by the error accumulator.
+//
 //nolint:gocognit
 func (stream *streamReader[T]) processLines() ([]byte, error) {
-	// Initialize data buffer if needed
 	if stream.dataBuffer == nil {
 		stream.dataBuffer = new(bytes.Buffer)
 	}
-
-	var emptyMessagesCount uint
+	if stream.errAccumulator == nil {
+		stream.errAccumulator = utils.NewErrorAccumulator()
+	}
+	if stream.unmarshaler == nil {
+		stream.unmarshaler = &utils.JSONUnmarshaler{}
+	}
+	if stream.ndjson {
+		return stream.processNDJSONLines()
+	}
 
 	for {
-		rawLine, readErr := stream.reader.ReadBytes('\n')
-		
-		// Handle read errors
+		line, readErr := stream.readLine()
 		if readErr != nil {
-			if readErr == io.EOF {
-				// Check if we have accumulated data
-				if stream.dataBuffer.Len() > 0 {
-					data := stream.dataBuffer.Bytes()
-					stream.dataBuffer.Reset()
-					return data, nil
-				}
-				stream.isFinished = true
-				return nil, io.EOF
+			if len(line) > 0 {
+				// Be lenient with streams that end without a final line break.
+				_, _ = stream.processField(line)
+			}
+			if readErr != io.EOF {
+				return nil, readErr
+			}
+			if respErr := stream.unmarshalError(); respErr != nil {
+				return nil, stream.responseError(respErr)
+			}
+			// Be lenient with streams that end without a blank line after the last event.
+			if data, ok := stream.dispatchEvent(); ok {
+				return data, nil
 			}
-			return nil, readErr
+			stream.isFinished = true
+			if bodyErr := stream.rawBodyError(); bodyErr != nil {
+				return nil, bodyErr
+			}
+			stream.receivedEOF = !stream.receivedDone
+			return nil, io.EOF
 		}
 
-		line := bytes.TrimRight(rawLine, "\r\n")
-		
-		// Empty line signals end of an event
 		if len(line) == 0 {
-			// Check if we have accumulated error data
-			if stream.errAccumulator.Bytes() != nil && len(stream.errAccumulator.Bytes()) > 0 {
-				respErr := stream.unmarshalError()
-				if respErr != nil {
-					return nil, respErr.Error
-				}
-			}
-			
-			if stream.dataBuffer.Len() > 0 {
-				// We have a complete event
-				data := stream.dataBuffer.Bytes()
-				stream.dataBuffer.Reset()
-				// SGLang sometimes sends incomplete JSON chunks for structured output
-				// Skip validation here, let the unmarshaler handle it
+			if data, ok := stream.dispatchEvent(); ok {
+				stream.emptyMessagesCount++
 				return data, nil
 			}
-			emptyMessagesCount++
-			if emptyMessagesCount > stream.emptyMessagesLimit {
-				return nil, ErrTooManyEmptyStreamMessages
+			if stream.isFinished {
+				return nil, io.EOF
+			}
+			// Blank lines may be part of a raw error body as well.
+			if writeErr := stream.errAccumulator.Write(line); writeErr != nil {
+				return nil, writeErr
+			}
+		} else {
+			isData, err := stream.processField(line)
+			if err != nil {
+				return nil, err
+			}
+			if isData {
+				stream.emptyMessagesCount = 0
+				continue
 			}
-			continue
 		}
 
-		// Check for data: prefix
-		if bytes.HasPrefix(line, []byte("data: ")) {
-			data := bytes.TrimPrefix(line, []byte("data: "))
-			
-			// Check for [DONE] marker or SGLang's done indicator
-			dataStr := string(data)
-			if dataStr == "[DONE]" || dataStr == "done" {
-				stream.isFinished = true
-				stream.receivedDone = true
-				return nil, io.EOF
+		if respErr := stream.accumulatedError(line); respErr != nil {
+			return nil, stream.responseError(respErr)
+		}
+		stream.emptyMessagesCount++
+		if stream.emptyMessagesCount > stream.emptyMessagesLimit {
+			if bodyErr := stream.rawBodyError(); bodyErr != nil {
+				return nil, bodyErr
+			}
+			return nil, ErrTooManyEmptyStreamMessages
+		}
+	}
+}
+
+// processField processes a non-empty line and reports whether it was a data field.
+func (stream *streamReader[T]) processField(line []byte) (isData bool, err error) {
+	if line[0] == ':' {
+		// Comment line.
+		return false, nil
+	}
+
+	field, value := line, []byte(nil)
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		field, value = line[:i], line[i+1:]
+		if len(value) > 0 && value[0] == ' ' {
+			value = value[1:]
+		}
+	}
+
+	switch string(field) {
+	case "data":
+		stream.dataBuffer.Write(value)
+		stream.dataBuffer.WriteByte('\n')
+		return true, nil
+	case "event":
+		stream.pendingEventType = string(value)
+	case "id":
+		if bytes.IndexByte(value, 0) < 0 {
+			stream.lastEventID = string(value)
+		}
+	case "retry":
+		// Reconnection is left to the caller.
+	case "error":
+		// Non-standard error field sent by some providers.
+		return false, stream.errAccumulator.Write(value)
+	default:
+		// Not an SSE field, possibly a line of a raw JSON, HTML or plain text error body.
+		if err = stream.errAccumulator.Write(line); err != nil {
+			return false, err
+		}
+		return false, stream.errAccumulator.Write(newline)
+	}
+	return false, nil
+}
+
+// dispatchEvent returns the data of the pending event, if any, and resets the event buffers.
+func (stream *streamReader[T]) dispatchEvent() ([]byte, bool) {
+	eventType := stream.pendingEventType
+	stream.pendingEventType = ""
+	if stream.dataBuffer.Len() == 0 {
+		return nil, false
+	}
+
+	data := bytes.TrimSuffix(stream.dataBuffer.Bytes(), []byte("\n"))
+	stream.dataBuffer.Reset()
+	stream.eventType = eventType
+
+	if len(data) == 0 {
+		// Heartbeat event without data.
+		return nil, false
+	}
+	if stream.terminationPolicy().isDoneMarker(data) {
+		stream.isFinished = true
+		stream.receivedDone = true
+		return nil, false
+	}
+	return data, true
+}
+
+// accumulatedError returns the accumulated error body once it forms a complete error response.
+func (stream *streamReader[T]) accumulatedError(line []byte) *ErrorResponse {
+	if stream.isFinished {
+		return nil
+	}
+	if len(line) > 0 && line[0] != '{' && line[0] != '}' {
+		return nil
+	}
+	return stream.unmarshalError()
+}
+
+// readLine reads a line terminated by CRLF, LF or CR. The returned slice is only valid
+// until the next call.
+func (stream *streamReader[T]) readLine() ([]byte, error) {
+	line := stream.lineBuffer[:0]
+	defer func() { stream.lineBuffer = line[:0] }()
+
+	for {
+		if stream.reader.Buffered() == 0 {
+			if _, err := stream.reader.Peek(1); err != nil {
+				return line, err
 			}
-			
-			// SGLang might send empty data as heartbeat
-			if dataStr == "" {
-				// Continue accumulating, might be a heartbeat
+		}
+		buf, _ := stream.reader.Peek(stream.reader.Buffered())
+
+		if stream.skipLF {
+			stream.skipLF = false
+			if buf[0] == '\n' {
+				_, _ = stream.reader.Discard(1)
 				continue
 			}
-			
-			// Accumulate data (handles multi-line data)
-			if stream.dataBuffer.Len() > 0 {
-				stream.dataBuffer.WriteByte('\n') // Add newline between data lines
+		}
+		if !stream.checkedBOM {
+			if len(buf) < len(utf8BOM) && bytes.HasPrefix(utf8BOM, buf) {
+				// Wait for the rest of a possible BOM.
+				if _, err := stream.reader.Peek(len(utf8BOM)); err == nil {
+					continue
+				}
+			}
+			stream.checkedBOM = true
+			if bytes.HasPrefix(buf, utf8BOM) {
+				_, _ = stream.reader.Discard(len(utf8BOM))
+				continue
 			}
-			stream.dataBuffer.Write(data)
-		} else if bytes.HasPrefix(line, []byte("error: ")) {
-			// Handle error events
-			errData := bytes.TrimPrefix(line, []byte("error: "))
-			stream.errAccumulator.Write(errData)
-		} else if bytes.Contains(line, []byte(`"error":`)) && bytes.HasPrefix(line, []byte("{")) {
-			// Handle raw JSON error (backward compatibility)
-			stream.errAccumulator.Write(line)
-			stream.errAccumulator.Write([]byte("\n"))
 		}
-		// Ignore other event types (like event:, id:, retry:)
+
+		i := bytes.IndexAny(buf, "\r\n")
+		if i < 0 {
+			line = append(line, buf...)
+			_, _ = stream.reader.Discard(len(buf))
+			continue
+		}
+		line = append(line, buf[:i]...)
+		stream.skipLF = buf[i] == '\r'
+		_, _ = stream.reader.Discard(i + 1)
+		return line, nil
 	}
 }
 
+// LastEventID returns the last event ID received on the stream.
+func (stream *streamReader[T]) LastEventID() string {
+	return stream.lastEventID
+}
+
+// responseError returns the error of an error response accumulated from the stream.
+func (stream *streamReader[T]) responseError(errResp *ErrorResponse) error {
+	setStreamErrorStatus(errResp.Error, stream.response)
+	return errResp.Error
+}
+
 func (stream *streamReader[T]) unmarshalError() (errResp *ErrorResponse) {
 	errBytes := stream.errAccumulator.Bytes()
 	if len(errBytes) == 0 {
@@ -172,18 +503,102 @@ func (stream *streamReader[T]) unmarshalError() (errResp *ErrorResponse) {
 	}
 
 	err := stream.unmarshaler.Unmarshal(errBytes, &errResp)
-	if err != nil {
+	if err != nil || errResp.Error == nil {
 		errResp = nil
 	}
 
 	return
 }
 
-func (stream *streamReader[T]) Close() error {
-	return stream.response.Body.Close()
+// rawBodyError returns a RequestError preserving the accumulated body of a stream that sent no
+// event but a body that is not an error response, such as the HTML error page of a proxy.
+func (stream *streamReader[T]) rawBodyError() *RequestError {
+	if stream.events > 0 {
+		return nil
+	}
+	body := stream.errAccumulator.Bytes()
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	reqErr := &RequestError{
+		Err:  ErrNonJSONResponse,
+		Body: append([]byte(nil), body...),
+	}
+	if json.Valid(body) {
+		reqErr.Err = nil
+	}
+	if stream.response != nil {
+		reqErr.HTTPStatus = stream.response.Status
+		reqErr.HTTPStatusCode = stream.response.StatusCode
+		reqErr.ContentType = stream.response.Header.Get("Content-Type")
+	}
+	return reqErr
+}
+
+// beginRecv marks the stream as being read. A closed stream has ended, its buffers may have
+// been returned to the pool.
+func (stream *streamReader[T]) beginRecv() error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.closed {
+		return stream.endError()
+	}
+	stream.receiving = true
+	return nil
+}
+
+// endRecv returns the buffers to the pool once the stream has ended or was closed, as no
+// other Recv can use them then. It runs on the goroutine reading the stream.
+func (stream *streamReader[T]) endRecv() {
+	stream.mu.Lock()
+	stream.receiving = false
+	ended := stream.isFinished || stream.closed
+	stream.mu.Unlock()
+	if !ended || stream.bufferPool == nil || stream.rawExposed {
+		return
+	}
+	stream.bufferPool.put(stream.reader, stream.dataBuffer)
+	stream.bufferPool = nil
+	stream.reader = nil
+	stream.dataBuffer = nil
+	stream.isFinished = true
+}
+
+// isClosed reports whether Close was called.
+func (stream *streamReader[T]) isClosed() bool {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return stream.closed
 }
 
-// IsComplete returns true if the stream received the [DONE] marker
-func (stream *streamReader[T]) IsComplete() bool {
-	return stream.receivedDone
+// closedChan returns a channel closed by Close.
+func (stream *streamReader[T]) closedChan() <-chan struct{} {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.done == nil {
+		stream.done = make(chan struct{})
+		if stream.closed {
+			close(stream.done)
+		}
+	}
+	return stream.done
+}
+
+// Close closes the body of the stream. It may be called from another goroutine to cancel a
+// blocked Recv, which then returns an error; a stream failing before its first chunk is not sent
+// again once closed. Later calls to Recv return the end of the stream.
+func (stream *streamReader[T]) Close() error {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.closed {
+		return nil
+	}
+	stream.closed = true
+	if stream.done != nil {
+		close(stream.done)
+	}
+	if stream.response == nil {
+		return nil
+	}
+	return stream.response.Body.Close()
 }
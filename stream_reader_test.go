@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"io"
+	"reflect"
 	"testing"
 
 	utils "github.com/sashabaranov/go-openai/internal"
@@ -76,3 +78,122 @@ func TestStreamReaderRecvRaw(t *testing.T) {
 		t.Fatalf("Did not return raw line: %v", string(rawLine))
 	}
 }
+
+func newTestStreamReader(body string) *streamReader[ChatCompletionStreamResponse] {
+	return &streamReader[ChatCompletionStreamResponse]{
+		emptyMessagesLimit: defaultEmptyMessagesLimit,
+		reader:             bufio.NewReader(bytes.NewReader([]byte(body))),
+		errAccumulator:     utils.NewErrorAccumulator(),
+		unmarshaler:        &utils.JSONUnmarshaler{},
+	}
+}
+
+func TestStreamReaderSSEParsing(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		events []string
+	}{
+		{"data without space", "data:{\"a\":1}\n\n", []string{`{"a":1}`}},
+		{"only first space is stripped", "data:  x\n\n", []string{" x"}},
+		{"CRLF line endings", "data: a\r\n\r\ndata: b\r\n\r\n", []string{"a", "b"}},
+		{"CR line endings", "data: a\r\rdata: b\r\r", []string{"a", "b"}},
+		{"BOM", "\xEF\xBB\xBFdata: a\n\n", []string{"a"}},
+		{"comments are ignored", ": keep-alive\ndata: a\n: note\n\n", []string{"a"}},
+		{"multi-line data joined with LF", "data: a\ndata:\ndata: b\n\n", []string{"a\n\nb"}},
+		{"fields in any order", "data: a\nid: 7\nevent: message\n\nid: 8\ndata: b\n\n", []string{"a", "b"}},
+		{"events without data are skipped", "event: ping\n\ndata: a\n\n", []string{"a"}},
+		{"colon inside the value", "data: {\"url\":\"http://x\"}\n\n", []string{`{"url":"http://x"}`}},
+		{"done terminates the stream", "data: a\n\ndata: [DONE]\n\ndata: b\n\n", []string{"a"}},
+		{"final event without blank line", "data: a\n\ndata: b", []string{"a", "b"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := newTestStreamReader(tc.body)
+			var events []string
+			for {
+				data, err := stream.RecvRaw()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				checks.NoError(t, err, "RecvRaw error")
+				events = append(events, string(data))
+			}
+			if !reflect.DeepEqual(events, tc.events) {
+				t.Errorf("got events %q, want %q", events, tc.events)
+			}
+		})
+	}
+}
+
+func TestStreamReaderLastEventID(t *testing.T) {
+	stream := newTestStreamReader("id: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+	_, err := stream.RecvRaw()
+	checks.NoError(t, err, "RecvRaw error")
+	if stream.LastEventID() != "1" {
+		t.Errorf("unexpected last event id %q", stream.LastEventID())
+	}
+	_, err = stream.RecvRaw()
+	checks.NoError(t, err, "RecvRaw error")
+	if stream.LastEventID() != "2" {
+		t.Errorf("unexpected last event id %q", stream.LastEventID())
+	}
+}
+
+func TestStreamReaderRawJSONError(t *testing.T) {
+	stream := newTestStreamReader(`{"error": {"message": "bad key", "type": "invalid_request_error"}}`)
+	_, err := stream.RecvRaw()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "bad key" {
+		t.Errorf("expected APIError, got %v", err)
+	}
+}
+
+func TestStreamReaderRecvIntoReusesChoices(t *testing.T) {
+	stream := newTestStreamReader(
+		`data: {"id":"1","choices":[{"index":0,"delta":{"content":"a","tool_calls":[{"id":"t"}]}},{"index":1}]}` + "\n\n" +
+			`data: {"id":"2","choices":[{"index":0,"delta":{"content":"b"}}],"x_extra":{"k":"v"}}` + "\n\n")
+
+	var response ChatCompletionStreamResponse
+	checks.NoError(t, stream.RecvInto(&response), "RecvInto error")
+	first := &response.Choices[:1][0]
+	checks.NoError(t, stream.RecvInto(&response), "RecvInto error")
+	if &response.Choices[0] != first {
+		t.Error("the choices of the previous chunk were not reused")
+	}
+	if response.ID != "2" || len(response.Choices) != 1 || response.Choices[0].Delta.Content != "b" ||
+		response.Choices[0].Delta.ToolCalls != nil {
+		t.Errorf("stale fields of the previous chunk: %+v", response)
+	}
+	if string(response.ExtraFields["x_extra"]) != `{"k":"v"}` {
+		t.Errorf("unexpected extra fields %s", response.ExtraFields)
+	}
+	checks.ErrorIs(t, stream.RecvInto(&response), io.EOF, "expected EOF")
+}
+
+func TestScanJSONObject(t *testing.T) {
+	var members []string
+	scanJSONObject([]byte(` { "a" : [1, {"b":"]"}] ,"c\"d":"x\"}y", "e":null,"f":-1.5e3 , "g":{}}`),
+		func(key, value []byte) {
+			members = append(members, string(key)+"="+string(value))
+		})
+	expected := []string{`a=[1, {"b":"]"}]`, `c"d="x\"}y"`, `e=null`, `f=-1.5e3`, `g={}`}
+	if !reflect.DeepEqual(members, expected) {
+		t.Errorf("expected %q, got %q", expected, members)
+	}
+}
+
+func BenchmarkStreamReaderRecvInto(b *testing.B) {
+	chunk := `data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,` +
+		`"model":"gpt-4o-mini","system_fingerprint":"fp_1",` +
+		`"choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":null}]}` + "\n\n"
+	body := bytes.Repeat([]byte(chunk), 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream := newTestStreamReader(string(body))
+		var response ChatCompletionStreamResponse
+		for stream.RecvInto(&response) == nil {
+		}
+	}
+}
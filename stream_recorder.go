@@ -0,0 +1,143 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrStreamAlreadyStarted = errors.New("stream recording must start before the first Recv")
+)
+
+// streamRecordingOffsetPrefix marks the SSE comment lines a recorder inserts to keep the timing
+// of a stream. SSE parsers ignore comment lines, so recordings remain valid event streams.
+const streamRecordingOffsetPrefix = ": openai-replay-offset-ms="
+
+// RecordTo copies the raw SSE bytes of the stream to w as they are received. With timing set,
+// the elapsed time since recording started is kept in SSE comment lines so that the stream
+// can be replayed at its original pace. RecordTo must be called before the first Recv.
+func (stream *streamReader[T]) RecordTo(w io.Writer, timing bool) error {
+	if stream.started {
+		return ErrStreamAlreadyStarted
+	}
+
+	var source io.Reader = stream.response.Body
+	if timing {
+		source = &timedRecorder{reader: source, w: w, start: time.Now(), atLineStart: true}
+	} else {
+		source = io.TeeReader(source, w)
+	}
+	stream.reader = bufio.NewReaderSize(source, stream.reader.Size())
+	return nil
+}
+
+// timedRecorder copies what is read from reader to w, preceding every chunk that starts
+// on a new line with a comment holding its offset from start.
+type timedRecorder struct {
+	reader      io.Reader
+	w           io.Writer
+	start       time.Time
+	atLineStart bool
+}
+
+func (r *timedRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if r.atLineStart {
+			offset := time.Since(r.start).Milliseconds()
+			if _, werr := fmt.Fprintf(r.w, "%s%d\n", streamRecordingOffsetPrefix, offset); werr != nil {
+				return n, werr
+			}
+		}
+		if _, werr := r.w.Write(p[:n]); werr != nil {
+			return n, werr
+		}
+		r.atLineStart = p[n-1] == '\n'
+	}
+	return n, err
+}
+
+// replayReader replays a recording, optionally sleeping until the recorded offsets.
+type replayReader struct {
+	reader        *bufio.Reader
+	closer        io.Closer
+	respectTiming bool
+	start         time.Time
+	pending       []byte
+}
+
+func (r *replayReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		line, err := r.reader.ReadBytes('\n')
+		if err == nil && bytes.HasPrefix(line, []byte(streamRecordingOffsetPrefix)) {
+			r.wait(line[len(streamRecordingOffsetPrefix):])
+			continue
+		}
+		if len(line) == 0 {
+			return 0, err
+		}
+		r.pending = line
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *replayReader) wait(offset []byte) {
+	if !r.respectTiming {
+		return
+	}
+	ms, err := strconv.ParseInt(string(bytes.TrimSpace(offset)), 10, 64)
+	if err != nil {
+		return
+	}
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+	time.Sleep(time.Until(r.start.Add(time.Duration(ms) * time.Millisecond)))
+}
+
+func (r *replayReader) Close() error {
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+func newReplayResponse(recording io.Reader, respectTiming bool) *http.Response {
+	body := &replayReader{reader: bufio.NewReader(recording), respectTiming: respectTiming}
+	if closer, ok := recording.(io.Closer); ok {
+		body.closer = closer
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       body,
+	}
+}
+
+var replayConfig = ClientConfig{EmptyMessagesLimit: defaultEmptyMessagesLimit}
+
+// NewStreamFromRecording creates a chat completion stream that replays a recording made with
+// RecordTo. With respectTiming set, events are delivered at their recorded pace; otherwise
+// as fast as they are read. Closing the stream closes recording if it is an io.Closer.
+func NewStreamFromRecording(recording io.Reader, respectTiming bool) *ChatCompletionStream {
+	return &ChatCompletionStream{
+		streamReader: newStreamReader[ChatCompletionStreamResponse](
+			newReplayResponse(recording, respectTiming), replayConfig),
+	}
+}
+
+// NewCompletionStreamFromRecording is NewStreamFromRecording for completion streams.
+func NewCompletionStreamFromRecording(recording io.Reader, respectTiming bool) *CompletionStream {
+	return &CompletionStream{
+		streamReader: newStreamReader[CompletionResponse](
+			newReplayResponse(recording, respectTiming), replayConfig),
+	}
+}
@@ -0,0 +1,76 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func collectStreamContent(t *testing.T, stream *openai.ChatCompletionStream) string {
+	t.Helper()
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return content.String()
+		}
+		checks.NoError(t, err, "Recv error")
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+}
+
+func TestStreamRecordAndReplay(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, content := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", content)
+			w.(http.Flusher).Flush()
+			time.Sleep(30 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4oMini,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	var recording bytes.Buffer
+	checks.NoError(t, stream.RecordTo(&recording, true), "RecordTo error")
+	if got := collectStreamContent(t, stream); got != "Hello" {
+		t.Fatalf("unexpected live content %q", got)
+	}
+	stream.Close()
+	if err = stream.RecordTo(io.Discard, false); !errors.Is(err, openai.ErrStreamAlreadyStarted) {
+		t.Errorf("expected ErrStreamAlreadyStarted, got %v", err)
+	}
+
+	start := time.Now()
+	replay := openai.NewStreamFromRecording(bytes.NewReader(recording.Bytes()), false)
+	if got := collectStreamContent(t, replay); got != "Hello" {
+		t.Errorf("unexpected replayed content %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Millisecond {
+		t.Errorf("replay without timing took %s", elapsed)
+	}
+
+	start = time.Now()
+	replay = openai.NewStreamFromRecording(bytes.NewReader(recording.Bytes()), true)
+	if got := collectStreamContent(t, replay); got != "Hello" {
+		t.Errorf("unexpected timed replay content %q", got)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("replay with timing took only %s", elapsed)
+	}
+}
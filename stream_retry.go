@@ -0,0 +1,165 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	utils "github.com/sashabaranov/go-openai/internal"
+)
+
+// anthropicOverloadedStatus is the status Anthropic uses for overloaded errors.
+const anthropicOverloadedStatus = 529
+
+// streamRetrier sends the request of a stream again, see RetryPolicy.
+type streamRetrier struct {
+	policy   *RetryPolicy
+	attempts int
+	send     func() (*http.Response, error)
+	ctx      context.Context
+}
+
+// newStreamRetrier returns the retrier of a stream request, or nil if the client has no retry
+// policy or the request body cannot be replayed.
+func newStreamRetrier(client *Client, req *http.Request) *streamRetrier {
+	policy := client.config.RetryPolicy
+	if policy == nil || policy.MaxRetries <= 0 {
+		return nil
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil
+	}
+	send := func() (*http.Response, error) {
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := client.config.HTTPClient.Do(req) //nolint:bodyclose // body is closed in stream.Close()
+		if err != nil {
+			return nil, err
+		}
+		if isFailureStatusCode(resp) {
+			defer resp.Body.Close()
+			return nil, client.handleErrorResp(resp)
+		}
+		return resp, nil
+	}
+	return &streamRetrier{policy: policy, send: send, ctx: req.Context()}
+}
+
+// retryBeforeFirstChunk sends the request again if the stream failed with err before delivering
+// any chunk. It returns nil if the stream now reads the new response, and otherwise the error
+// to return: err, or the error sending the request again.
+func (stream *streamReader[T]) retryBeforeFirstChunk(err error) error {
+	retrier := stream.retrier
+	if retrier == nil || stream.delivered || stream.receivedDone || stream.reader == nil || stream.isClosed() {
+		return err
+	}
+	if retrier.attempts >= retrier.policy.MaxRetries || retrier.ctx.Err() != nil || !isRetryableStreamError(err) {
+		return err
+	}
+	if errors.Is(err, io.EOF) && stream.receivedEOF && stream.terminationPolicy().EndOnEOF {
+		// The backend ends streams by closing them, the response was empty.
+		return err
+	}
+	var retryAfter time.Duration
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		retryAfter = apiErr.RetryAfter
+	}
+	delay, ok := retrier.policy.delay(retrier.attempts, retryAfter)
+	if !ok {
+		return err
+	}
+	if retrier.policy.Budget != nil && !retrier.policy.Budget.withdraw() {
+		return err
+	}
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-retrier.ctx.Done():
+		timer.Stop()
+		return err
+	case <-stream.closedChan():
+		timer.Stop()
+		return err
+	case <-timer.C:
+	}
+	retrier.attempts++
+
+	stream.mu.Lock()
+	if stream.closed {
+		stream.mu.Unlock()
+		return err
+	}
+	stream.response.Body.Close()
+	stream.mu.Unlock()
+
+	resp, sendErr := retrier.send()
+	if sendErr != nil {
+		stream.isFinished = true
+		return sendErr
+	}
+	if !stream.restart(resp) {
+		// Closed while sending, the new response is not read.
+		resp.Body.Close()
+		return err
+	}
+	return nil
+}
+
+// restart resets the stream to read the body of resp from the start. It reports false if the
+// stream was closed meanwhile.
+func (stream *streamReader[T]) restart(resp *http.Response) bool {
+	stream.mu.Lock()
+	if stream.closed {
+		stream.mu.Unlock()
+		return false
+	}
+	stream.response = resp
+	stream.httpHeader = httpHeader(resp.Header)
+	stream.mu.Unlock()
+
+	stream.reader.Reset(resp.Body)
+	stream.dataBuffer.Reset()
+	stream.errAccumulator = utils.NewErrorAccumulator()
+	stream.isFinished = false
+	stream.receivedEOF = false
+	stream.emptyMessagesCount = 0
+	stream.skipLF = false
+	stream.checkedBOM = false
+	stream.pendingEventType = ""
+	stream.eventType = ""
+	stream.events = 0
+	stream.eventBytes = 0
+	return true
+}
+
+// isRetryableStreamError reports whether a stream failing with err before its first chunk may
+// succeed when sent again: the connection dropped, or the provider reported an overloaded or
+// server error in the stream.
+func isRetryableStreamError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		code, _ := apiErr.Code.(int)
+		return apiErr.Type == "overloaded_error" || code == anthropicOverloadedStatus ||
+			apiErr.HTTPStatusCode == http.StatusTooManyRequests ||
+			apiErr.HTTPStatusCode >= http.StatusInternalServerError
+	}
+	var reqErr *RequestError
+	var chunkErr *MalformedChunkError
+	if errors.As(err, &reqErr) || errors.As(err, &chunkErr) ||
+		errors.Is(err, ErrTooManyEmptyStreamMessages) {
+		return false
+	}
+	// Read errors such as connection resets, and bodies ending before any chunk.
+	return true
+}
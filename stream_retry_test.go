@@ -0,0 +1,178 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const (
+	retryStreamBody = `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hello"}}]}` + "\n\n" +
+		`data: {"id":"1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\ndata: [DONE]\n\n"
+	retryOverloadedEvent = "event: error\n" +
+		`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n"
+)
+
+// setupStreamRetryServer starts a client with a retry policy whose chat completion requests are
+// answered by failFirst for the first failures calls and by a complete stream afterwards.
+func setupStreamRetryServer(
+	t *testing.T,
+	retries int,
+	failures int32,
+	failFirst func(w http.ResponseWriter),
+) (*openai.Client, *int32) {
+	t.Helper()
+	var calls int32
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		if retries > 0 {
+			config.RetryPolicy = &openai.RetryPolicy{MaxRetries: retries, MinBackoff: time.Millisecond}
+		}
+	})
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if atomic.AddInt32(&calls, 1) <= failures {
+			failFirst(w)
+			return
+		}
+		fmt.Fprint(w, retryStreamBody)
+	})
+	return client, &calls
+}
+
+func readStreamContent(t *testing.T, client *openai.Client) (string, error) {
+	t.Helper()
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		response, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			return content.String(), nil
+		}
+		if recvErr != nil {
+			return content.String(), recvErr
+		}
+		if len(response.Choices) > 0 {
+			content.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+}
+
+func TestStreamRetryOverloadedBeforeFirstChunk(t *testing.T) {
+	client, calls := setupStreamRetryServer(t, 3, 2, func(w http.ResponseWriter) {
+		fmt.Fprint(w, retryOverloadedEvent)
+	})
+	content, err := readStreamContent(t, client)
+	checks.NoError(t, err, "stream error")
+	if content != "Hello" || atomic.LoadInt32(calls) != 3 {
+		t.Errorf("unexpected content %q after %d calls", content, atomic.LoadInt32(calls))
+	}
+}
+
+func TestStreamRetryDroppedConnection(t *testing.T) {
+	client, calls := setupStreamRetryServer(t, 1, 1, func(w http.ResponseWriter) {
+		// Announce a longer body than sent, so the client sees the connection drop.
+		w.Header().Set("Content-Length", "1000")
+		fmt.Fprint(w, ": waiting\n\n")
+	})
+	content, err := readStreamContent(t, client)
+	checks.NoError(t, err, "stream error")
+	if content != "Hello" || atomic.LoadInt32(calls) != 2 {
+		t.Errorf("unexpected content %q after %d calls", content, atomic.LoadInt32(calls))
+	}
+}
+
+func TestStreamRetryExhausted(t *testing.T) {
+	client, calls := setupStreamRetryServer(t, 1, 5, func(w http.ResponseWriter) {
+		fmt.Fprint(w, retryOverloadedEvent)
+	})
+	_, err := readStreamContent(t, client)
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != "overloaded_error" {
+		t.Errorf("expected overloaded error, got %v", err)
+	}
+	if atomic.LoadInt32(calls) != 2 {
+		t.Errorf("expected 2 calls, got %d", atomic.LoadInt32(calls))
+	}
+}
+
+func TestStreamNoRetryAfterFirstChunk(t *testing.T) {
+	client, calls := setupStreamRetryServer(t, 3, 1, func(w http.ResponseWriter) {
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`+"\n\n"+retryOverloadedEvent)
+	})
+	content, err := readStreamContent(t, client)
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected APIError, got %v", err)
+	}
+	if content != "Hel" || atomic.LoadInt32(calls) != 1 {
+		t.Errorf("unexpected content %q after %d calls", content, atomic.LoadInt32(calls))
+	}
+}
+
+func TestStreamNoRetryWithoutPolicy(t *testing.T) {
+	client, calls := setupStreamRetryServer(t, 0, 1, func(w http.ResponseWriter) {
+		fmt.Fprint(w, retryOverloadedEvent)
+	})
+	if _, err := readStreamContent(t, client); err == nil {
+		t.Error("expected an error without retry policy")
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected 1 call, got %d", atomic.LoadInt32(calls))
+	}
+}
+
+func TestStreamRetryStopsOnClose(t *testing.T) {
+	var calls int32
+	requested := make(chan struct{}, 1)
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.RetryPolicy = &openai.RetryPolicy{MaxRetries: 3, MinBackoff: time.Second}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, retryOverloadedEvent)
+		requested <- struct{}{}
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	received := make(chan error, 1)
+	go func() {
+		_, recvErr := stream.Recv()
+		received <- recvErr
+	}()
+	<-requested
+	// Close while the stream waits to be sent again.
+	time.Sleep(20 * time.Millisecond)
+	checks.NoError(t, stream.Close(), "Close error")
+
+	select {
+	case err = <-received:
+		checks.HasError(t, err, "expected the overloaded error")
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Recv still waiting to retry after Close")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected no request after Close, got %d requests", n)
+	}
+}
@@ -0,0 +1,76 @@
+package openai
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrStreamIncomplete is returned by Recv instead of io.EOF when a stream whose termination
+// policy requires a finish reason ends without a chunk carrying one.
+var ErrStreamIncomplete = errors.New("stream ended without a finish reason")
+
+// StreamTermination describes how the backend ends a stream successfully.
+type StreamTermination struct {
+	// DoneMarkers are the event data ending the stream, such as "[DONE]".
+	DoneMarkers []string
+	// EndOnEOF treats the connection closing without a done marker as the end of the stream,
+	// for backends that end streams by closing the connection.
+	EndOnEOF bool
+	// RequireFinishReason treats a stream as complete only once a chunk with a finish reason
+	// was received. Recv returns ErrStreamIncomplete instead of io.EOF for streams ending
+	// without one, e.g. because a proxy cut the connection.
+	RequireFinishReason bool
+}
+
+// DefaultStreamTermination returns the termination policy of the streams of apiType, used when
+// ClientConfig.StreamTermination is nil. Anthropic streams end with a message_stop event, the
+// others with "[DONE]" or, for SGLang, "done".
+func DefaultStreamTermination(apiType APIType) StreamTermination {
+	if apiType == APITypeAnthropic {
+		return StreamTermination{}
+	}
+	return StreamTermination{DoneMarkers: []string{"[DONE]", "done"}}
+}
+
+func (c ClientConfig) streamTermination() StreamTermination {
+	if c.StreamTermination != nil {
+		return *c.StreamTermination
+	}
+	return DefaultStreamTermination(c.APIType)
+}
+
+// terminationPolicy returns the termination policy of the stream, the OpenAI one if unset.
+func (stream *streamReader[T]) terminationPolicy() StreamTermination {
+	if stream.termination == nil {
+		return DefaultStreamTermination(APITypeOpenAI)
+	}
+	return *stream.termination
+}
+
+// isDoneMarker reports whether data is a done marker of the termination policy.
+func (t StreamTermination) isDoneMarker(data []byte) bool {
+	for _, marker := range t.DoneMarkers {
+		if string(data) == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// endError returns the error reporting the end of the stream: io.EOF, or ErrStreamIncomplete if
+// the policy requires a finish reason that was not received.
+func (stream *streamReader[T]) endError() error {
+	if stream.terminationPolicy().RequireFinishReason && !stream.receivedFinishReason {
+		return ErrStreamIncomplete
+	}
+	return io.EOF
+}
+
+// IsComplete reports whether the stream ended as its termination policy expects: with a done
+// marker or, if EndOnEOF is set, by closing, and after a finish reason if RequireFinishReason is
+// set. It is false while the stream is being read.
+func (stream *streamReader[T]) IsComplete() bool {
+	termination := stream.terminationPolicy()
+	ended := stream.receivedDone || (termination.EndOnEOF && stream.receivedEOF)
+	return ended && (!termination.RequireFinishReason || stream.receivedFinishReason)
+}
@@ -0,0 +1,129 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+const (
+	terminationChunk = `data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":null}]}` + "\n\n"
+	terminationFinal = `data: {"id":"1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\n"
+)
+
+// readTerminatedStream reads a stream of body until it ends and returns the error ending it.
+func readTerminatedStream(
+	t *testing.T,
+	termination *openai.StreamTermination,
+	body string,
+) (*openai.ChatCompletionStream, error) {
+	t.Helper()
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.StreamTermination = termination
+	})
+	t.Cleanup(teardown)
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, body)
+	})
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+	})
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	t.Cleanup(func() { stream.Close() })
+	for {
+		if _, err = stream.Recv(); err != nil {
+			return stream, err
+		}
+	}
+}
+
+func TestStreamTermination(t *testing.T) {
+	testCases := []struct {
+		name        string
+		termination *openai.StreamTermination
+		body        string
+		err         error
+		complete    bool
+	}{
+		{
+			name:     "default done",
+			body:     terminationChunk + terminationFinal + "data: [DONE]\n\n",
+			err:      io.EOF,
+			complete: true,
+		},
+		{
+			name:     "default SGLang done",
+			body:     terminationChunk + "data: done\n\n",
+			err:      io.EOF,
+			complete: true,
+		},
+		{
+			name: "default closed",
+			body: terminationChunk + terminationFinal,
+			err:  io.EOF,
+		},
+		{
+			name:        "custom done marker",
+			termination: &openai.StreamTermination{DoneMarkers: []string{"[END]"}},
+			body:        terminationChunk + "data: [END]\n\n" + terminationChunk,
+			err:         io.EOF,
+			complete:    true,
+		},
+		{
+			name:        "end on EOF",
+			termination: &openai.StreamTermination{EndOnEOF: true},
+			body:        terminationChunk + terminationFinal,
+			err:         io.EOF,
+			complete:    true,
+		},
+		{
+			name:        "finish reason received",
+			termination: &openai.StreamTermination{EndOnEOF: true, RequireFinishReason: true},
+			body:        terminationChunk + terminationFinal,
+			err:         io.EOF,
+			complete:    true,
+		},
+		{
+			name:        "finish reason missing",
+			termination: &openai.StreamTermination{EndOnEOF: true, RequireFinishReason: true},
+			body:        terminationChunk,
+			err:         openai.ErrStreamIncomplete,
+		},
+		{
+			name:        "done without finish reason",
+			termination: &openai.StreamTermination{DoneMarkers: []string{"[DONE]"}, RequireFinishReason: true},
+			body:        terminationChunk + "data: [DONE]\n\n",
+			err:         openai.ErrStreamIncomplete,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := readTerminatedStream(t, tc.termination, tc.body)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+			if stream.IsComplete() != tc.complete {
+				t.Errorf("expected IsComplete %t", tc.complete)
+			}
+		})
+	}
+}
+
+func TestDefaultStreamTermination(t *testing.T) {
+	if markers := openai.DefaultStreamTermination(openai.APITypeAzure).DoneMarkers; len(markers) == 0 {
+		t.Error("expected done markers for Azure streams")
+	}
+	if markers := openai.DefaultStreamTermination(openai.APITypeAnthropic).DoneMarkers; len(markers) != 0 {
+		t.Errorf("unexpected done markers %v for Anthropic streams", markers)
+	}
+}
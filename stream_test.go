@@ -348,3 +348,106 @@ func compareResponseChoices(c1, c2 openai.CompletionChoice) bool {
 	}
 	return true
 }
+
+func TestCompletionStreamMalformedChunkModes(t *testing.T) {
+	body := "data: {\"id\":\"1\",\"choices\":[{\"text\":\"a\"}]}\n\n" +
+		"data: {\"id\":\"2\",\"choices\":[{\"text\":\n\n" +
+		"data: {\"id\":\"3\",\"choices\":[{\"text\":\"b\"}]}\n\n" +
+		"data: [DONE]\n\n"
+
+	for _, mode := range []openai.StreamChunkMode{openai.StreamChunkModeLenient, openai.StreamChunkModeStrict} {
+		t.Run(string(mode), func(t *testing.T) {
+			client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+				config.StreamChunkMode = mode
+			})
+			defer teardown()
+			server.RegisterHandler("/v1/completions", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte(body))
+			})
+
+			stream, err := client.CreateCompletionStream(context.Background(), openai.CompletionRequest{
+				Prompt: "Hello!",
+				Model:  openai.GPT3Babbage002,
+			})
+			checks.NoError(t, err, "CreateCompletionStream error")
+			defer stream.Close()
+
+			_, err = stream.Recv()
+			checks.NoError(t, err, "first Recv error")
+
+			resp, err := stream.Recv()
+			if mode == openai.StreamChunkModeLenient {
+				checks.NoError(t, err, "lenient stream should skip the malformed chunk")
+				if resp.ID != "3" || stream.MalformedChunks() != 1 {
+					t.Errorf("unexpected chunk %+v after skipping %d", resp, stream.MalformedChunks())
+				}
+				return
+			}
+
+			var chunkErr *openai.MalformedChunkError
+			if !errors.As(err, &chunkErr) || !errors.Is(err, openai.ErrMalformedChunk) {
+				t.Fatalf("expected MalformedChunkError, got %v", err)
+			}
+			if string(chunkErr.Raw) != `{"id":"2","choices":[{"text":` {
+				t.Errorf("unexpected raw chunk %q", chunkErr.Raw)
+			}
+			resp, err = stream.Recv()
+			checks.NoError(t, err, "Recv after a malformed chunk")
+			if resp.ID != "3" {
+				t.Errorf("unexpected chunk %+v", resp)
+			}
+		})
+	}
+}
+
+func TestCreateCompletionStreamEchoSuffix(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/completions", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		checks.NoError(t, json.NewDecoder(r.Body).Decode(&request), "Decode error")
+		if request["echo"] != true || request["suffix"] != " end" || request["stream"] != true {
+			t.Errorf("echo, suffix and stream were not sent: %v", request)
+		}
+		if bias, _ := request["logit_bias"].(map[string]any); bias["50256"] != float64(-100) {
+			t.Errorf("logit_bias was not sent: %v", request["logit_bias"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, text := range []string{"Ex falso", " quodlibet"} {
+			_, err := w.Write([]byte(`data: {"id":"1","object":"text_completion","choices":[{"text":"` + text + `"}]}` + "\n\n"))
+			checks.NoError(t, err, "Write error")
+		}
+		_, err := w.Write([]byte("data: [DONE]\n\n"))
+		checks.NoError(t, err, "Write error")
+	})
+
+	request := openai.CompletionRequest{
+		Prompt: "Ex falso",
+		Model:  "gpt-3.5-turbo-instruct",
+		Echo:   true,
+		Suffix: " end",
+	}
+	request.SetLogitBias(50256, -100)
+	stream, err := client.CreateCompletionStream(context.Background(), request)
+	checks.NoError(t, err, "CreateCompletionStream returned error")
+	defer stream.Close()
+
+	var text string
+	for {
+		response, streamErr := stream.Recv()
+		if errors.Is(streamErr, io.EOF) {
+			break
+		}
+		checks.NoError(t, streamErr, "Recv error")
+		text += response.Choices[0].Text
+	}
+	if text != "Ex falso quodlibet" {
+		t.Errorf("unexpected text %q", text)
+	}
+
+	request.BestOf = 2
+	_, err = client.CreateCompletionStream(context.Background(), request)
+	checks.ErrorIs(t, err, openai.ErrCompletionBestOfStream, "best_of should not be streamed")
+}
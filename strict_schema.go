@@ -0,0 +1,151 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrStrictSchemaInvalid      = errors.New("strict schema is invalid")
+	ErrParallelToolCallsNotBool = errors.New("parallel_tool_calls must be a bool")
+)
+
+// StrictSchemaError describes why a schema used with strict mode would be rejected by the API.
+type StrictSchemaError struct {
+	// Name is the function or response format name the schema belongs to.
+	Name string
+	// Path is the JSON pointer-like location of the offending schema node.
+	Path   string
+	Reason string
+}
+
+func (e *StrictSchemaError) Error() string {
+	return fmt.Sprintf("strict schema %q is invalid at %s: %s", e.Name, e.Path, e.Reason)
+}
+
+func (e *StrictSchemaError) Unwrap() error {
+	return ErrStrictSchemaInvalid
+}
+
+// ValidateStrictSchema checks that schema satisfies the structured outputs rules for strict mode:
+// every object sets additionalProperties to false and lists all of its properties as required.
+// The schema may be anything that serializes to a JSON schema, e.g. jsonschema.Definition or json.RawMessage.
+func ValidateStrictSchema(name string, schema any) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	var node any
+	if err = json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	return validateStrictNode(name, "#", node)
+}
+
+//nolint:gocognit // walks every keyword that may contain a subschema
+func validateStrictNode(name, path string, node any) error {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	properties, hasProperties := schema["properties"].(map[string]any)
+	if hasProperties || schemaHasType(schema, "object") {
+		if additional, isBool := schema["additionalProperties"].(bool); !isBool || additional {
+			return &StrictSchemaError{Name: name, Path: path, Reason: "additionalProperties must be false"}
+		}
+
+		required := make(map[string]bool)
+		if list, isList := schema["required"].([]any); isList {
+			for _, item := range list {
+				if key, isString := item.(string); isString {
+					required[key] = true
+				}
+			}
+		}
+		for key := range properties {
+			if !required[key] {
+				return &StrictSchemaError{
+					Name:   name,
+					Path:   path,
+					Reason: fmt.Sprintf("property %q must be listed in required", key),
+				}
+			}
+		}
+		for key, property := range properties {
+			if err := validateStrictNode(name, path+"/properties/"+key, property); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := validateStrictNode(name, path+"/items", schema["items"]); err != nil {
+		return err
+	}
+	for _, keyword := range []string{"anyOf", "allOf"} {
+		list, _ := schema[keyword].([]any)
+		for i, item := range list {
+			if err := validateStrictNode(name, fmt.Sprintf("%s/%s/%d", path, keyword, i), item); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, _ := schema[keyword].(map[string]any)
+		for key, def := range defs {
+			if err := validateStrictNode(name, path+"/"+keyword+"/"+key, def); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func schemaHasType(schema map[string]any, dataType string) bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return t == dataType
+	case []any:
+		for _, item := range t {
+			if item == dataType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateToolSchemas performs client-side validation of strict function schemas and
+// the parallel_tool_calls flag, so invalid requests fail before being sent.
+func validateToolSchemas(request ChatCompletionRequest) error {
+	switch request.ParallelToolCalls.(type) {
+	case nil, bool, *bool:
+	default:
+		return ErrParallelToolCallsNotBool
+	}
+
+	for _, tool := range request.Tools {
+		if tool.Function == nil || !tool.Function.Strict {
+			continue
+		}
+		if err := ValidateStrictSchema(tool.Function.Name, tool.Function.Parameters); err != nil {
+			return err
+		}
+	}
+	for _, function := range request.Functions {
+		if !function.Strict {
+			continue
+		}
+		if err := ValidateStrictSchema(function.Name, function.Parameters); err != nil {
+			return err
+		}
+	}
+
+	format := request.ResponseFormat
+	if format != nil && format.JSONSchema != nil && format.JSONSchema.Strict {
+		return ValidateStrictSchema(format.JSONSchema.Name, format.JSONSchema.Schema)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+func TestValidateStrictSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  any
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			schema: jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"location": {Type: jsonschema.String},
+					"tags": {
+						Type: jsonschema.Array,
+						Items: &jsonschema.Definition{
+							Type:                 jsonschema.Object,
+							Properties:           map[string]jsonschema.Definition{"name": {Type: jsonschema.String}},
+							Required:             []string{"name"},
+							AdditionalProperties: false,
+						},
+					},
+				},
+				Required:             []string{"location", "tags"},
+				AdditionalProperties: false,
+			},
+		},
+		{
+			name:    "additional_properties_missing",
+			schema:  json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}},"required":["a"]}`),
+			wantErr: true,
+		},
+		{
+			name: "property_not_required",
+			schema: json.RawMessage(
+				`{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"string"}},` +
+					`"required":["a"],"additionalProperties":false}`,
+			),
+			wantErr: true,
+		},
+		{
+			name: "nested_object_invalid",
+			schema: json.RawMessage(
+				`{"type":"object","properties":{"a":{"type":["object","null"],"properties":{}}},` +
+					`"required":["a"],"additionalProperties":false}`,
+			),
+			wantErr: true,
+		},
+		{
+			name: "defs_invalid",
+			schema: json.RawMessage(
+				`{"type":"object","properties":{},"additionalProperties":false,` +
+					`"$defs":{"x":{"type":"object","additionalProperties":true}}}`,
+			),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := openai.ValidateStrictSchema("fn", tt.schema)
+			if !tt.wantErr {
+				checks.NoError(t, err)
+				return
+			}
+			checks.ErrorIs(t, err, openai.ErrStrictSchemaInvalid, "expected ErrStrictSchemaInvalid")
+			var schemaErr *openai.StrictSchemaError
+			if !errors.As(err, &schemaErr) || schemaErr.Name != "fn" {
+				t.Errorf("expected StrictSchemaError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChatCompletionStrictToolValidation(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var fields map[string]any
+		_ = json.Unmarshal(body, &fields)
+		if fields["parallel_tool_calls"] != false {
+			t.Errorf("parallel_tool_calls not sent: %s", body)
+		}
+		if _, ok := fields["tools"]; !ok {
+			t.Errorf("tools not sent: %s", body)
+		}
+		resBytes, _ := json.Marshal(openai.ChatCompletionResponse{})
+		_, _ = w.Write(resBytes)
+	})
+
+	req := openai.ChatCompletionRequest{
+		Model:    openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+		Tools: []openai.Tool{{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:       "get_weather",
+				Strict:     true,
+				Parameters: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+			},
+		}},
+		ParallelToolCalls: false,
+	}
+
+	_, err := client.CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrStrictSchemaInvalid, "invalid strict schema should be rejected")
+
+	_, err = client.CreateChatCompletionStream(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrStrictSchemaInvalid, "invalid strict schema should be rejected for streams")
+
+	req.Tools[0].Function.Parameters = json.RawMessage(
+		`{"type":"object","properties":{"city":{"type":"string"}},"required":["city"],"additionalProperties":false}`,
+	)
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	checks.NoError(t, err, "valid strict schema should be accepted")
+
+	req.ParallelToolCalls = "false"
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	checks.ErrorIs(t, err, openai.ErrParallelToolCallsNotBool, "non-bool parallel_tool_calls should be rejected")
+}
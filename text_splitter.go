@@ -0,0 +1,216 @@
+package openai
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidChunkSize is returned by TextSplitter when ChunkSize is not positive or not larger
+// than ChunkOverlap.
+var ErrInvalidChunkSize = errors.New("chunk size must be positive and larger than the chunk overlap")
+
+// DefaultSeparators split text by paragraphs, lines, sentences, words and finally characters.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", "! ", "? ", "; ", ", ", " ", ""}
+
+// TextSplitter splits texts into chunks sized for embeddings and context windows, e.g. before
+// CreateEmbeddings in a RAG pipeline:
+//
+//	splitter := openai.TextSplitter{ChunkSize: 512, ChunkOverlap: 64, Tokenizer: tokenizer}
+//	chunks, err := splitter.Split(document)
+//
+// The lengths of chunks are measured in tokens if Tokenizer is set, by Length if set, and in
+// characters otherwise. Token counts of chunks are summed from the counts of their pieces, so
+// they can differ slightly from the count of the whole chunk.
+type TextSplitter struct {
+	// ChunkSize is the maximum length of a chunk. Only pieces of text without any separator,
+	// such as a long word when the empty separator is not used, can exceed it.
+	ChunkSize int
+	// ChunkOverlap is the maximum length of the end of a chunk repeated at the start of the
+	// next one, so the context around their boundary is kept in both.
+	ChunkOverlap int
+	Tokenizer    Tokenizer
+	Length       func(text string) int
+	// Separators split the text recursively, from the first one, until its pieces fit in
+	// ChunkSize. The empty separator splits between characters. Defaults to DefaultSeparators.
+	Separators []string
+}
+
+// Split splits text recursively with the separators, then merges the pieces into chunks of
+// up to ChunkSize, overlapping by up to ChunkOverlap. Chunks are trimmed of surrounding spaces.
+func (s TextSplitter) Split(text string) ([]string, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	pieces, err := s.splitPieces(text, s.separators())
+	if err != nil {
+		return nil, err
+	}
+	return s.merge(pieces), nil
+}
+
+// SplitSentences merges whole sentences into chunks of up to ChunkSize, overlapping by up to
+// ChunkOverlap. Sentences longer than ChunkSize are split like in Split.
+func (s TextSplitter) SplitSentences(text string) ([]string, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	var pieces []textPiece
+	for _, sentence := range splitSentences(text) {
+		sentencePieces, err := s.splitPieces(sentence, s.separators())
+		if err != nil {
+			return nil, err
+		}
+		pieces = append(pieces, sentencePieces...)
+	}
+	return s.merge(pieces), nil
+}
+
+// SplitSentences splits text after the periods, exclamation and question marks followed by a
+// space, and at paragraph breaks. Sentences are trimmed of surrounding spaces.
+func SplitSentences(text string) []string {
+	var sentences []string
+	for _, sentence := range splitSentences(text) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// splitSentences splits text into sentences keeping their trailing spaces, so the sentences
+// concatenate back into text.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		endsSentence := strings.ContainsRune(".!?。！？", r) ||
+			r == '\n' && strings.HasPrefix(text[i:], "\n")
+		if !endsSentence {
+			continue
+		}
+		if i < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsSpace(next) {
+				continue
+			}
+		}
+		for i < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			i += nextSize
+		}
+		sentences = append(sentences, text[start:i])
+		start = i
+	}
+	if start < len(text) {
+		sentences = append(sentences, text[start:])
+	}
+	return sentences
+}
+
+func (s TextSplitter) validate() error {
+	if s.ChunkSize <= 0 || s.ChunkOverlap < 0 || s.ChunkOverlap >= s.ChunkSize {
+		return ErrInvalidChunkSize
+	}
+	return nil
+}
+
+func (s TextSplitter) separators() []string {
+	if s.Separators == nil {
+		return DefaultSeparators
+	}
+	return s.Separators
+}
+
+func (s TextSplitter) length(text string) (int, error) {
+	switch {
+	case s.Tokenizer != nil:
+		tokens, err := s.Tokenizer.Encode(text)
+		return len(tokens), err
+	case s.Length != nil:
+		return s.Length(text), nil
+	default:
+		return utf8.RuneCountInString(text), nil
+	}
+}
+
+type textPiece struct {
+	text   string
+	length int
+}
+
+// splitPieces splits text with the first separator it contains, and the pieces still longer
+// than ChunkSize with the next separators.
+func (s TextSplitter) splitPieces(text string, separators []string) ([]textPiece, error) {
+	length, err := s.length(text)
+	if err != nil {
+		return nil, err
+	}
+	if length <= s.ChunkSize {
+		return []textPiece{{text: text, length: length}}, nil
+	}
+
+	for i, separator := range separators {
+		var parts []string
+		switch {
+		case separator == "":
+			parts = strings.Split(text, "")
+		case strings.Contains(text, separator):
+			parts = strings.SplitAfter(text, separator)
+		default:
+			continue
+		}
+		var pieces []textPiece
+		for _, part := range parts {
+			if part == "" {
+				continue
+			}
+			partPieces, err := s.splitPieces(part, separators[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			pieces = append(pieces, partPieces...)
+		}
+		return pieces, nil
+	}
+	return []textPiece{{text: text, length: length}}, nil
+}
+
+// merge joins consecutive pieces into chunks of up to ChunkSize, starting each chunk with the
+// last pieces of the previous one that fit in ChunkOverlap.
+func (s TextSplitter) merge(pieces []textPiece) []string {
+	var chunks []string
+	var window []textPiece
+	total := 0
+	emit := func() {
+		var chunk strings.Builder
+		for _, piece := range window {
+			chunk.WriteString(piece.text)
+		}
+		if text := strings.TrimSpace(chunk.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+	}
+
+	for _, piece := range pieces {
+		if len(window) > 0 && total+piece.length > s.ChunkSize {
+			emit()
+			for len(window) > 0 && (total > s.ChunkOverlap || total+piece.length > s.ChunkSize) {
+				total -= window[0].length
+				window = window[1:]
+			}
+		}
+		window = append(window, piece)
+		total += piece.length
+	}
+	if len(window) > 0 {
+		emit()
+	}
+	return chunks
+}
@@ -0,0 +1,96 @@
+package openai_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestTextSplitterSplit(t *testing.T) {
+	text := "The quick brown fox.\n\nIt jumps over the lazy dog. Then it sleeps."
+	chunks, err := openai.TextSplitter{ChunkSize: 30}.Split(text)
+	checks.NoError(t, err, "Split error")
+	want := []string{"The quick brown fox.", "It jumps over the lazy dog.", "Then it sleeps."}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 30 {
+			t.Errorf("chunk %q exceeds the chunk size", chunk)
+		}
+	}
+}
+
+func TestTextSplitterOverlap(t *testing.T) {
+	chunks, err := openai.TextSplitter{ChunkSize: 11, ChunkOverlap: 6}.Split("one two three four five")
+	checks.NoError(t, err, "Split error")
+	want := []string{"one two", "two three", "three four", "four five"}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+}
+
+func TestTextSplitterCharacters(t *testing.T) {
+	chunks, err := openai.TextSplitter{ChunkSize: 4}.Split("abcdefghij")
+	checks.NoError(t, err, "Split error")
+	if !reflect.DeepEqual(chunks, []string{"abcd", "efgh", "ij"}) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+
+	chunks, err = openai.TextSplitter{ChunkSize: 4, Separators: []string{" "}}.Split("abcdefghij xy")
+	checks.NoError(t, err, "Split error")
+	if !reflect.DeepEqual(chunks, []string{"abcdefghij", "xy"}) {
+		t.Errorf("expected the word without separator to be kept whole, got %q", chunks)
+	}
+}
+
+func TestTextSplitterTokenizer(t *testing.T) {
+	// One token per word.
+	tokenizer := openai.TokenizerFunc(func(text string) ([]int, error) {
+		return make([]int, len(strings.Fields(text))), nil
+	})
+	text := "alpha beta gamma delta epsilon zeta eta"
+	chunks, err := openai.TextSplitter{ChunkSize: 3, ChunkOverlap: 1, Tokenizer: tokenizer}.Split(text)
+	checks.NoError(t, err, "Split error")
+	want := []string{"alpha beta gamma", "gamma delta epsilon", "epsilon zeta eta"}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+
+	failure := errors.New("tokenizer failed")
+	failing := openai.TokenizerFunc(func(string) ([]int, error) { return nil, failure })
+	_, err = openai.TextSplitter{ChunkSize: 3, Tokenizer: failing}.Split(text)
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the tokenizer error, got %v", err)
+	}
+}
+
+func TestTextSplitterSplitSentences(t *testing.T) {
+	text := "Go is fun. It compiles fast! Does it scale? Yes, version 1.22 scales."
+	chunks, err := openai.TextSplitter{ChunkSize: 30}.SplitSentences(text)
+	checks.NoError(t, err, "SplitSentences error")
+	want := []string{"Go is fun. It compiles fast!", "Does it scale?", "Yes, version 1.22 scales."}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	sentences := openai.SplitSentences("First one. Second one!\n\nHeading\n\nPi is 3.14 here.  Last")
+	want := []string{"First one.", "Second one!", "Heading", "Pi is 3.14 here.", "Last"}
+	if !reflect.DeepEqual(sentences, want) {
+		t.Errorf("unexpected sentences %q", sentences)
+	}
+}
+
+func TestTextSplitterInvalidChunkSize(t *testing.T) {
+	for _, splitter := range []openai.TextSplitter{{}, {ChunkSize: 10, ChunkOverlap: 10}, {ChunkSize: 5, ChunkOverlap: -1}} {
+		if _, err := splitter.Split("text"); !errors.Is(err, openai.ErrInvalidChunkSize) {
+			t.Errorf("expected ErrInvalidChunkSize for %+v, got %v", splitter, err)
+		}
+	}
+}
@@ -114,6 +114,9 @@ type ThreadDeleteResponse struct {
 
 // CreateThread creates a new thread.
 func (c *Client) CreateThread(ctx context.Context, request ThreadRequest) (response Thread, err error) {
+	if err = request.validate(); err != nil {
+		return
+	}
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(threadsSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
 	if err != nil {
@@ -143,6 +146,9 @@ func (c *Client) ModifyThread(
 	threadID string,
 	request ModifyThreadRequest,
 ) (response Thread, err error) {
+	if err = ValidateMetadata(request.Metadata); err != nil {
+		return
+	}
 	urlSuffix := threadsSuffix + "/" + threadID
 	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request),
 		withBetaAssistantVersion(c.config.AssistantVersion))
@@ -0,0 +1,116 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+var ErrVectorStoreFileNotCompleted = errors.New("vector store file ingestion did not complete")
+
+// MessageAttachmentFile is a local file to upload and attach to a thread message.
+type MessageAttachmentFile struct {
+	Path string
+	// FileSearch and CodeInterpreter select the tools the file is made available to. With none
+	// set, the file is attached to file_search.
+	FileSearch      bool
+	CodeInterpreter bool
+}
+
+func (f MessageAttachmentFile) tools() []ThreadAttachmentTool {
+	var tools []ThreadAttachmentTool
+	if f.FileSearch || !f.CodeInterpreter {
+		tools = append(tools, ThreadAttachmentTool{Type: string(AssistantToolTypeFileSearch)})
+	}
+	if f.CodeInterpreter {
+		tools = append(tools, ThreadAttachmentTool{Type: string(AssistantToolTypeCodeInterpreter)})
+	}
+	return tools
+}
+
+// MessageWithFilesRequest is a message with local files to attach.
+type MessageWithFilesRequest struct {
+	MessageRequest
+	Files []MessageAttachmentFile
+	// WaitForIngestion waits until the files attached to file_search are indexed in the vector
+	// store of the thread, so that a run started next can search them.
+	WaitForIngestion bool
+}
+
+// CreateMessageWithFiles uploads local files, creates a message on the thread with the files
+// attached to their tools and optionally waits for their ingestion by file_search. If the
+// ingestion of a file fails, the message is returned together with an error wrapping
+// ErrVectorStoreFileNotCompleted.
+func (c *Client) CreateMessageWithFiles(
+	ctx context.Context,
+	threadID string,
+	request MessageWithFilesRequest,
+) (msg Message, err error) {
+	message := request.MessageRequest
+	message.Attachments = append([]ThreadAttachment(nil), message.Attachments...)
+	var searchFileIDs []string
+	for _, attachment := range request.Files {
+		var file File
+		file, err = c.CreateFile(ctx, FileRequest{
+			FileName: filepath.Base(attachment.Path),
+			FilePath: attachment.Path,
+			Purpose:  string(PurposeAssistants),
+		})
+		if err != nil {
+			return
+		}
+
+		tools := attachment.tools()
+		message.Attachments = append(message.Attachments, ThreadAttachment{FileID: file.ID, Tools: tools})
+		if tools[0].Type == string(AssistantToolTypeFileSearch) {
+			searchFileIDs = append(searchFileIDs, file.ID)
+		}
+	}
+
+	msg, err = c.CreateMessage(ctx, threadID, message)
+	if err != nil || !request.WaitForIngestion || len(searchFileIDs) == 0 {
+		return
+	}
+
+	thread, err := c.RetrieveThread(ctx, threadID)
+	if err != nil || thread.ToolResources.FileSearch == nil {
+		return
+	}
+	for _, vectorStoreID := range thread.ToolResources.FileSearch.VectorStoreIDs {
+		for _, fileID := range searchFileIDs {
+			if err = c.waitForVectorStoreFile(ctx, vectorStoreID, fileID); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (c *Client) waitForVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error {
+	interval := runPollMinInterval
+	for {
+		file, err := c.RetrieveVectorStoreFile(ctx, vectorStoreID, fileID)
+		if err != nil {
+			return err
+		}
+		switch file.Status {
+		case "completed":
+			return nil
+		case "failed", "cancelled":
+			return fmt.Errorf("%w: file %s: status %s", ErrVectorStoreFileNotCompleted, fileID, file.Status)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > runPollMaxInterval {
+			interval = runPollMaxInterval
+		}
+	}
+}
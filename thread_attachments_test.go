@@ -0,0 +1,104 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateMessageWithFiles(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	dir := t.TempDir()
+	docPath := filepath.Join(dir, "policy.md")
+	dataPath := filepath.Join(dir, "data.csv")
+	checks.NoError(t, os.WriteFile(docPath, []byte("# Policy"), 0o600))
+	checks.NoError(t, os.WriteFile(dataPath, []byte("a,b"), 0o600))
+
+	var uploads int
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		checks.NoError(t, r.ParseMultipartForm(1<<20))
+		if r.FormValue("purpose") != "assistants" {
+			t.Errorf("unexpected purpose: %s", r.FormValue("purpose"))
+		}
+		uploads++
+		fmt.Fprintf(w, `{"id":"file_%d","object":"file"}`, uploads)
+	})
+	server.RegisterHandler("/v1/threads/thread_1/messages", func(w http.ResponseWriter, r *http.Request) {
+		var request openai.MessageRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		want := []openai.ThreadAttachment{
+			{FileID: "file_1", Tools: []openai.ThreadAttachmentTool{{Type: "file_search"}}},
+			{FileID: "file_2", Tools: []openai.ThreadAttachmentTool{{Type: "code_interpreter"}}},
+		}
+		if !reflect.DeepEqual(request.Attachments, want) {
+			t.Errorf("unexpected attachments: %+v", request.Attachments)
+		}
+		fmt.Fprint(w, `{"id":"msg_1","object":"thread.message","thread_id":"thread_1"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"thread_1","tool_resources":{"file_search":{"vector_store_ids":["vs_1"]}}}`)
+	})
+	var polls int
+	server.RegisterHandler("/v1/vector_stores/vs_1/files/file_1", func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		status := "in_progress"
+		if polls > 1 {
+			status = "completed"
+		}
+		fmt.Fprintf(w, `{"id":"file_1","status":%q}`, status)
+	})
+
+	msg, err := client.CreateMessageWithFiles(context.Background(), "thread_1", openai.MessageWithFilesRequest{
+		MessageRequest: openai.MessageRequest{Role: openai.ChatMessageRoleUser, Content: "Summarize"},
+		Files: []openai.MessageAttachmentFile{
+			{Path: docPath},
+			{Path: dataPath, CodeInterpreter: true},
+		},
+		WaitForIngestion: true,
+	})
+	checks.NoError(t, err, "CreateMessageWithFiles error")
+	if msg.ID != "msg_1" || uploads != 2 || polls != 2 {
+		t.Errorf("unexpected message %s, %d uploads, %d polls", msg.ID, uploads, polls)
+	}
+}
+
+func TestCreateMessageWithFilesIngestionFailed(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	path := filepath.Join(t.TempDir(), "broken.pdf")
+	checks.NoError(t, os.WriteFile(path, []byte("%PDF"), 0o600))
+
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"file_1","object":"file"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_1/messages", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"msg_1","object":"thread.message"}`)
+	})
+	server.RegisterHandler("/v1/threads/thread_1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"thread_1","tool_resources":{"file_search":{"vector_store_ids":["vs_1"]}}}`)
+	})
+	server.RegisterHandler("/v1/vector_stores/vs_1/files/file_1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"file_1","status":"failed"}`)
+	})
+
+	msg, err := client.CreateMessageWithFiles(context.Background(), "thread_1", openai.MessageWithFilesRequest{
+		MessageRequest:   openai.MessageRequest{Role: openai.ChatMessageRoleUser, Content: "Read"},
+		Files:            []openai.MessageAttachmentFile{{Path: path, FileSearch: true}},
+		WaitForIngestion: true,
+	})
+	if !errors.Is(err, openai.ErrVectorStoreFileNotCompleted) || msg.ID != "msg_1" {
+		t.Errorf("expected ErrVectorStoreFileNotCompleted with the message, got %v, %+v", err, msg)
+	}
+}
@@ -0,0 +1,134 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrFirstByteTimeout = errors.New("timed out waiting for the first byte of the response")
+
+// Timeouts bounds the phases of each request attempt separately, instead of a single timeout
+// for the whole request which has to be generous enough for the longest stream. Zero values
+// leave a phase unbounded. When Timeouts is set, the Timeout of an *http.Client is cleared so
+// that Total takes its place.
+type Timeouts struct {
+	// Connect bounds dialing and the TLS handshake of new connections. It applies when
+	// HTTPClient is an *http.Client using an *http.Transport, or the default transport.
+	Connect time.Duration
+	// FirstByte bounds the time from sending the request to receiving the first byte of the
+	// response body: the time to first token of streams, or the whole generation otherwise.
+	FirstByte time.Duration
+	// Total bounds the whole attempt, including reading the response body to its end.
+	Total time.Duration
+}
+
+// withTimeouts returns doer bounding the phases of requests with t.
+func withTimeouts(doer HTTPDoer, t *Timeouts) HTTPDoer {
+	if t.Connect > 0 {
+		doer, _ = configureTransport(doer, func(transport *http.Transport) {
+			setConnectTimeout(transport, t.Connect)
+		})
+	}
+	if httpClient, ok := doer.(*http.Client); ok {
+		configured := *httpClient
+		configured.Timeout = 0
+		doer = &configured
+	}
+	if t.FirstByte <= 0 && t.Total <= 0 {
+		return doer
+	}
+	return &timeoutDoer{doer: doer, timeouts: t}
+}
+
+// setConnectTimeout bounds dialing and the TLS handshake of the connections of transport.
+func setConnectTimeout(transport *http.Transport, timeout time.Duration) {
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{KeepAlive: 30 * time.Second}).DialContext
+	}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return dial(ctx, network, addr)
+	}
+	transport.TLSHandshakeTimeout = timeout
+}
+
+type timeoutDoer struct {
+	doer     HTTPDoer
+	timeouts *Timeouts
+}
+
+func (d *timeoutDoer) Do(req *http.Request) (*http.Response, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if d.timeouts.Total > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), d.timeouts.Total)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	body := &timeoutBody{cancel: cancel}
+	if d.timeouts.FirstByte > 0 {
+		body.firstByte = time.AfterFunc(d.timeouts.FirstByte, func() {
+			atomic.StoreInt32(&body.timedOut, 1)
+			cancel()
+		})
+	}
+
+	resp, err := d.doer.Do(req.WithContext(ctx))
+	if err != nil {
+		body.stop()
+		return resp, body.wrap(err)
+	}
+	body.ReadCloser = resp.Body
+	resp.Body = body
+	return resp, nil
+}
+
+// timeoutBody stops the first byte timer on the first byte read and releases the context of
+// the request when closed.
+type timeoutBody struct {
+	io.ReadCloser
+	cancel    context.CancelFunc
+	firstByte *time.Timer
+	timedOut  int32
+	once      sync.Once
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && b.firstByte != nil {
+		b.firstByte.Stop()
+	}
+	return n, b.wrap(err)
+}
+
+func (b *timeoutBody) Close() error {
+	b.stop()
+	return b.ReadCloser.Close()
+}
+
+func (b *timeoutBody) stop() {
+	b.once.Do(func() {
+		if b.firstByte != nil {
+			b.firstByte.Stop()
+		}
+		b.cancel()
+	})
+}
+
+// wrap reports errors caused by the first byte timeout as ErrFirstByteTimeout.
+func (b *timeoutBody) wrap(err error) error {
+	if err != nil && !errors.Is(err, io.EOF) && atomic.LoadInt32(&b.timedOut) == 1 {
+		return fmt.Errorf("%w: %v", ErrFirstByteTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func slowChatHandler(delay time.Duration) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprint(w, `data: {"id":"1","choices":[{"index":0,"delta":{"content":"hi"}}]}`+"\n\ndata: [DONE]\n\n")
+	}
+}
+
+var timeoutsTestRequest = openai.ChatCompletionRequest{
+	Model:    openai.GPT4oMini,
+	Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "Hello!"}},
+}
+
+func TestTimeoutsFirstByte(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.Timeouts = &openai.Timeouts{FirstByte: 50 * time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", slowChatHandler(time.Second))
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), timeoutsTestRequest)
+	checks.NoError(t, err, "the stream should start before the first token")
+	defer stream.Close()
+	_, err = stream.Recv()
+	checks.ErrorIs(t, err, openai.ErrFirstByteTimeout, "Recv should time out waiting for the first token")
+}
+
+func TestTimeoutsTimeToFirstToken(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.Timeouts = &openai.Timeouts{FirstByte: time.Second, Total: 5 * time.Second}
+		// The coarse client timeout is replaced by Timeouts.Total.
+		config.HTTPClient = &http.Client{Timeout: time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", slowChatHandler(50*time.Millisecond))
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), timeoutsTestRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	if stream.TimeToFirstToken() != 0 {
+		t.Errorf("time to first token should be zero before the first event")
+	}
+	_, err = stream.Recv()
+	checks.NoError(t, err, "Recv error")
+	if ttft := stream.TimeToFirstToken(); ttft < 50*time.Millisecond || ttft > time.Second {
+		t.Errorf("unexpected time to first token %v", ttft)
+	}
+}
+
+func TestTimeoutsTotal(t *testing.T) {
+	client, server, teardown := setupResilientTestServer(func(config *openai.ClientConfig) {
+		config.Timeouts = &openai.Timeouts{Total: 50 * time.Millisecond}
+	})
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", slowChatHandler(time.Second))
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), timeoutsTestRequest)
+	checks.NoError(t, err, "CreateChatCompletionStream error")
+	defer stream.Close()
+	_, err = stream.Recv()
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, openai.ErrFirstByteTimeout) {
+		t.Errorf("expected the total timeout, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrToolNotFound          = errors.New("tool not found")
+	ErrToolAlreadyRegistered = errors.New("tool already registered")
+)
+
+// ToolFunc implements a function tool. It receives the JSON arguments chosen by the model and
+// returns the result passed back to the model.
+type ToolFunc func(ctx context.Context, arguments string) (string, error)
+
+type registeredTool struct {
+	definition FunctionDefinition
+	fn         ToolFunc
+}
+
+// ToolRegistry holds function tools and dispatches the tool calls of the model to them.
+// It is safe for concurrent use.
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	names []string
+	tools map[string]registeredTool
+}
+
+// NewToolRegistry returns an empty registry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]registeredTool)}
+}
+
+// Register adds a function tool. Names must be unique and valid tool names.
+func (r *ToolRegistry) Register(definition FunctionDefinition, fn ToolFunc) error {
+	if !toolNamePattern.MatchString(definition.Name) {
+		return fmt.Errorf("%w: name %q", ErrToolDefinitionInvalid, definition.Name)
+	}
+	if definition.Strict {
+		if err := ValidateStrictSchema(definition.Name, definition.Parameters); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[definition.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrToolAlreadyRegistered, definition.Name)
+	}
+	r.names = append(r.names, definition.Name)
+	r.tools[definition.Name] = registeredTool{definition: definition, fn: fn}
+	return nil
+}
+
+// Tools returns the definitions of the registered tools in registration order, for use as
+// ChatCompletionRequest.Tools.
+func (r *ToolRegistry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := make([]Tool, len(r.names))
+	for i, name := range r.names {
+		definition := r.tools[name].definition
+		tools[i] = Tool{Type: ToolTypeFunction, Function: &definition}
+	}
+	return tools
+}
+
+// Call runs the tool requested by a tool call of the model.
+func (r *ToolRegistry) Call(ctx context.Context, toolCall ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, ok := r.tools[toolCall.Function.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrToolNotFound, toolCall.Function.Name)
+	}
+	return tool.fn(ctx, toolCall.Function.Arguments)
+}
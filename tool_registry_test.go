@@ -0,0 +1,36 @@
+package openai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestToolRegistry(t *testing.T) {
+	registry := openai.NewToolRegistry()
+	echo := func(_ context.Context, arguments string) (string, error) { return arguments, nil }
+	checks.NoError(t, registry.Register(openai.FunctionDefinition{Name: "echo"}, echo), "Register error")
+	checks.NoError(t, registry.Register(openai.FunctionDefinition{Name: "other"}, echo), "Register error")
+
+	err := registry.Register(openai.FunctionDefinition{Name: "echo"}, echo)
+	checks.ErrorIs(t, err, openai.ErrToolAlreadyRegistered, "duplicate names should be rejected")
+	err = registry.Register(openai.FunctionDefinition{Name: "bad name"}, echo)
+	checks.ErrorIs(t, err, openai.ErrToolDefinitionInvalid, "invalid names should be rejected")
+
+	tools := registry.Tools()
+	if len(tools) != 2 || tools[0].Function.Name != "echo" || tools[1].Function.Name != "other" {
+		t.Errorf("unexpected tools %+v", tools)
+	}
+
+	output, err := registry.Call(context.Background(), openai.ToolCall{
+		Function: openai.FunctionCall{Name: "echo", Arguments: `{"a":1}`},
+	})
+	checks.NoError(t, err, "Call error")
+	if output != `{"a":1}` {
+		t.Errorf("unexpected output %q", output)
+	}
+	_, err = registry.Call(context.Background(), openai.ToolCall{Function: openai.FunctionCall{Name: "missing"}})
+	checks.ErrorIs(t, err, openai.ErrToolNotFound, "unknown tools should fail")
+}
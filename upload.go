@@ -0,0 +1,99 @@
+package openai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	utils "github.com/sashabaranov/go-openai/internal"
+)
+
+// UploadProgressFunc is called as the file of an upload is sent, with the number of bytes of the
+// file sent so far and its size, or -1 if the size is unknown.
+type UploadProgressFunc func(sent, total int64)
+
+// progressReader reports the bytes read from r to progress.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress UploadProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// newStreamedForm returns a multipart body which is written by write as the request reads it,
+// so that large files are not buffered in memory. Errors of write are returned by the reads of
+// the body. The body must be closed to stop write if the request fails before reading it all.
+func (c *Client) newStreamedForm(write func(utils.FormBuilder) error) (body *io.PipeReader, contentType string) {
+	body, formWriter := io.Pipe()
+	builder := c.createFormBuilder(formWriter)
+	go func() {
+		formWriter.CloseWithError(write(builder))
+	}()
+	return body, builder.FormDataContentType()
+}
+
+// FileStreamRequest is a file upload whose content is streamed from Reader.
+type FileStreamRequest struct {
+	// Name is the name of the uploaded file.
+	Name   string
+	Reader io.Reader
+	// Size is the size of the content of Reader, reported to Progress. It is read from the file
+	// when Reader is an *os.File and Size is zero.
+	Size    int64
+	Purpose PurposeType
+	// Progress, if set, is called as the content is sent.
+	Progress UploadProgressFunc
+}
+
+// CreateFileStream uploads a file without buffering it in memory, e.g. a fine-tuning or batch
+// file of several gigabytes. The request body is chunked and, as it cannot be replayed, is not
+// retried.
+func (c *Client) CreateFileStream(ctx context.Context, request FileStreamRequest) (file File, err error) {
+	if err = request.Purpose.Validate(); err != nil {
+		return
+	}
+
+	size := request.Size
+	if f, ok := request.Reader.(*os.File); ok && size == 0 {
+		if info, statErr := f.Stat(); statErr == nil {
+			size = info.Size()
+		}
+	}
+	if size <= 0 {
+		size = -1
+	}
+	content := request.Reader
+	if request.Progress != nil {
+		content = &progressReader{r: request.Reader, total: size, progress: request.Progress}
+	}
+
+	body, contentType := c.newStreamedForm(func(builder utils.FormBuilder) error {
+		if err := builder.WriteField("purpose", string(request.Purpose)); err != nil {
+			return err
+		}
+		if err := builder.CreateFormFileReader("file", content, request.Name); err != nil {
+			return err
+		}
+		return builder.Close()
+	})
+	defer body.Close()
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL("/files"),
+		withBody(body), withContentType(contentType))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &file)
+	return
+}
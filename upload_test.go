@@ -0,0 +1,93 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestCreateFileStream(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	content := strings.Repeat(`{"prompt":"a","completion":"b"}`+"\n", 10000)
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("expected a chunked body, got content length %d", r.ContentLength)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if string(data) != content || r.FormValue("purpose") != "fine-tune" {
+			t.Errorf("unexpected upload of %d bytes with purpose %q", len(data), r.FormValue("purpose"))
+		}
+		_, _ = w.Write([]byte(`{"id":"file-1","filename":"` + header.Filename + `"}`))
+	})
+
+	path := filepath.Join(t.TempDir(), "train.jsonl")
+	checks.NoError(t, os.WriteFile(path, []byte(content), 0o600), "WriteFile error")
+	f, err := os.Open(path)
+	checks.NoError(t, err, "Open error")
+	defer f.Close()
+
+	var calls int
+	var sent, total int64
+	file, err := client.CreateFileStream(context.Background(), openai.FileStreamRequest{
+		Name:    "train.jsonl",
+		Reader:  f,
+		Purpose: openai.PurposeFineTune,
+		Progress: func(s, t int64) {
+			calls++
+			sent, total = s, t
+		},
+	})
+	checks.NoError(t, err, "CreateFileStream error")
+	if file.ID != "file-1" || file.FileName != "train.jsonl" {
+		t.Errorf("unexpected file %+v", file)
+	}
+	if calls == 0 || sent != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("unexpected progress: %d calls, %d of %d bytes", calls, sent, total)
+	}
+}
+
+type failingReader struct{}
+
+var errReadFailed = errors.New("read failed")
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errReadFailed
+}
+
+func TestCreateFileStreamReadError(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	_, err := client.CreateFileStream(context.Background(), openai.FileStreamRequest{
+		Name:    "batch.jsonl",
+		Reader:  failingReader{},
+		Purpose: openai.PurposeBatch,
+	})
+	checks.ErrorIs(t, err, errReadFailed, "the read error should be returned")
+
+	_, err = client.CreateFileStream(context.Background(), openai.FileStreamRequest{
+		Name:    "batch.jsonl",
+		Reader:  strings.NewReader("{}"),
+		Purpose: openai.PurposeBatchOutput,
+	})
+	checks.ErrorIs(t, err, openai.ErrInvalidFilePurpose, "the purpose should be validated")
+}
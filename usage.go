@@ -0,0 +1,214 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	organizationUsageSuffix = "/organization/usage"
+	organizationCostsSuffix = "/organization/costs"
+)
+
+// UsageType selects the usage endpoint to query.
+type UsageType string
+
+const (
+	UsageTypeCompletions             UsageType = "completions"
+	UsageTypeEmbeddings              UsageType = "embeddings"
+	UsageTypeModerations             UsageType = "moderations"
+	UsageTypeImages                  UsageType = "images"
+	UsageTypeAudioSpeeches           UsageType = "audio_speeches"
+	UsageTypeAudioTranscriptions     UsageType = "audio_transcriptions"
+	UsageTypeVectorStores            UsageType = "vector_stores"
+	UsageTypeCodeInterpreterSessions UsageType = "code_interpreter_sessions"
+)
+
+// UsageBucketWidth is the width of each time bucket in the response.
+type UsageBucketWidth string
+
+const (
+	UsageBucketWidthMinute UsageBucketWidth = "1m"
+	UsageBucketWidthHour   UsageBucketWidth = "1h"
+	UsageBucketWidthDay    UsageBucketWidth = "1d"
+)
+
+// UsageRequest are the query parameters of the usage endpoints.
+// StartTime is required; times are unix seconds.
+type UsageRequest struct {
+	StartTime   int64
+	EndTime     int64
+	BucketWidth UsageBucketWidth
+	ProjectIDs  []string
+	UserIDs     []string
+	APIKeyIDs   []string
+	Models      []string
+	// Batch filters completions usage to batch (true) or non-batch (false) requests.
+	Batch   *bool
+	GroupBy []string
+	Limit   int
+	// Page is the cursor returned as NextPage by a previous call.
+	Page string
+}
+
+func (r UsageRequest) values() url.Values {
+	urlValues := url.Values{}
+	urlValues.Add("start_time", fmt.Sprintf("%d", r.StartTime))
+	if r.EndTime != 0 {
+		urlValues.Add("end_time", fmt.Sprintf("%d", r.EndTime))
+	}
+	if r.BucketWidth != "" {
+		urlValues.Add("bucket_width", string(r.BucketWidth))
+	}
+	addListValues(urlValues, "project_ids", r.ProjectIDs)
+	addListValues(urlValues, "user_ids", r.UserIDs)
+	addListValues(urlValues, "api_key_ids", r.APIKeyIDs)
+	addListValues(urlValues, "models", r.Models)
+	addListValues(urlValues, "group_by", r.GroupBy)
+	if r.Batch != nil {
+		urlValues.Add("batch", fmt.Sprintf("%t", *r.Batch))
+	}
+	if r.Limit > 0 {
+		urlValues.Add("limit", fmt.Sprintf("%d", r.Limit))
+	}
+	if r.Page != "" {
+		urlValues.Add("page", r.Page)
+	}
+	return urlValues
+}
+
+// CostsRequest are the query parameters of the costs endpoint.
+// StartTime is required; times are unix seconds. Only daily buckets are supported.
+type CostsRequest struct {
+	StartTime  int64
+	EndTime    int64
+	ProjectIDs []string
+	GroupBy    []string
+	Limit      int
+	Page       string
+}
+
+func (r CostsRequest) values() url.Values {
+	urlValues := url.Values{}
+	urlValues.Add("start_time", fmt.Sprintf("%d", r.StartTime))
+	if r.EndTime != 0 {
+		urlValues.Add("end_time", fmt.Sprintf("%d", r.EndTime))
+	}
+	urlValues.Add("bucket_width", string(UsageBucketWidthDay))
+	addListValues(urlValues, "project_ids", r.ProjectIDs)
+	addListValues(urlValues, "group_by", r.GroupBy)
+	if r.Limit > 0 {
+		urlValues.Add("limit", fmt.Sprintf("%d", r.Limit))
+	}
+	if r.Page != "" {
+		urlValues.Add("page", r.Page)
+	}
+	return urlValues
+}
+
+func addListValues(urlValues url.Values, key string, values []string) {
+	for _, value := range values {
+		urlValues.Add(key+"[]", value)
+	}
+}
+
+// UsageResult is a single usage record inside a bucket. Which fields are populated depends on
+// the UsageType that was queried and on the requested grouping.
+type UsageResult struct {
+	Object string `json:"object"`
+
+	InputTokens       int   `json:"input_tokens,omitempty"`
+	OutputTokens      int   `json:"output_tokens,omitempty"`
+	InputCachedTokens int   `json:"input_cached_tokens,omitempty"`
+	InputAudioTokens  int   `json:"input_audio_tokens,omitempty"`
+	OutputAudioTokens int   `json:"output_audio_tokens,omitempty"`
+	NumModelRequests  int   `json:"num_model_requests,omitempty"`
+	Images            int   `json:"images,omitempty"`
+	Characters        int   `json:"characters,omitempty"`
+	Seconds           int   `json:"seconds,omitempty"`
+	UsageBytes        int64 `json:"usage_bytes,omitempty"`
+	NumSessions       int   `json:"num_sessions,omitempty"`
+
+	ProjectID *string `json:"project_id,omitempty"`
+	UserID    *string `json:"user_id,omitempty"`
+	APIKeyID  *string `json:"api_key_id,omitempty"`
+	Model     *string `json:"model,omitempty"`
+	Batch     *bool   `json:"batch,omitempty"`
+	Source    *string `json:"source,omitempty"`
+	Size      *string `json:"size,omitempty"`
+}
+
+// CostAmount is a monetary value.
+type CostAmount struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+// CostResult is a single cost record inside a bucket.
+type CostResult struct {
+	Object    string     `json:"object"`
+	Amount    CostAmount `json:"amount"`
+	LineItem  *string    `json:"line_item,omitempty"`
+	ProjectID *string    `json:"project_id,omitempty"`
+}
+
+// UsageBucket groups the results of one time interval.
+type UsageBucket[T any] struct {
+	Object    string `json:"object"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Results   []T    `json:"results"`
+}
+
+// UsagePage is one page of bucketed time series.
+// When HasMore is true, pass NextPage as Page to fetch the following page.
+type UsagePage[T any] struct {
+	Object   string           `json:"object"`
+	Data     []UsageBucket[T] `json:"data"`
+	HasMore  bool             `json:"has_more"`
+	NextPage string           `json:"next_page"`
+
+	httpHeader
+}
+
+// UsageResponse is a page of usage buckets.
+type UsageResponse UsagePage[UsageResult]
+
+// CostsResponse is a page of cost buckets.
+type CostsResponse UsagePage[CostResult]
+
+// GetUsage returns usage of the organization for the given usage type.
+func (a *AdminClient) GetUsage(
+	ctx context.Context,
+	usageType UsageType,
+	request UsageRequest,
+) (response UsageResponse, err error) {
+	urlSuffix := withQuery(fmt.Sprintf("%s/%s", organizationUsageSuffix, usageType), request.values())
+	err = a.do(ctx, http.MethodGet, urlSuffix, nil, &response)
+	return
+}
+
+// GetCosts returns the costs of the organization.
+func (a *AdminClient) GetCosts(ctx context.Context, request CostsRequest) (response CostsResponse, err error) {
+	err = a.do(ctx, http.MethodGet, withQuery(organizationCostsSuffix, request.values()), nil, &response)
+	return
+}
+
+// TotalCost sums the amounts of every result in the page.
+// Results in other currencies than the first one encountered are ignored.
+func (r *CostsResponse) TotalCost() CostAmount {
+	var total CostAmount
+	for _, bucket := range r.Data {
+		for _, result := range bucket.Results {
+			if total.Currency == "" {
+				total.Currency = result.Amount.Currency
+			}
+			if result.Amount.Currency == total.Currency {
+				total.Value += result.Amount.Value
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,150 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// maxObservedBodySize bounds the JSON responses buffered to read their usage.
+const maxObservedBodySize = 1 << 20
+
+// usageObserverDoer passes the token usage of the successful responses of doer to observe once
+// their body is closed: the usage of JSON responses, or the last usage of the chunks of
+// streams. Responses without usage are observed with a zero Usage.
+type usageObserverDoer struct {
+	doer    HTTPDoer
+	observe func(req *http.Request, model string, usage Usage)
+}
+
+// ObserveUsage returns an HTTPDoer sending requests with doer and passing the model and the
+// token usage of their successful responses to observe once the response body is closed. The
+// usage is read from JSON responses, or from the last chunk reporting one in streams, in the
+// Chat Completions or the Responses API format. Responses without usage are observed with a
+// zero Usage. It lets packages such as metrics account for tokens without parsing responses.
+func ObserveUsage(doer HTTPDoer, observe func(req *http.Request, model string, usage Usage)) HTTPDoer {
+	return &usageObserverDoer{doer: doer, observe: observe}
+}
+
+func (d *usageObserverDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.doer.Do(req)
+	if err != nil || isFailureStatusCode(resp) {
+		return resp, err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	resp.Body = &usageObserverBody{
+		ReadCloser: resp.Body,
+		capture:    strings.HasPrefix(contentType, "application/json") || isEventStreamType(contentType),
+		stream:     isEventStreamType(contentType),
+		done: func(model string, usage Usage) {
+			d.observe(req, model, usage)
+		},
+	}
+	return resp, nil
+}
+
+func isEventStreamType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/event-stream")
+}
+
+// usageObserverBody extracts the usage of a response as it is read.
+type usageObserverBody struct {
+	io.ReadCloser
+	capture bool
+	stream  bool
+	buf     bytes.Buffer
+	model   string
+	usage   Usage
+	done    func(model string, usage Usage)
+	once    sync.Once
+}
+
+func (b *usageObserverBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.capture && n > 0 {
+		b.buf.Write(p[:n])
+		if b.stream {
+			b.scanEvents()
+		} else if b.buf.Len() > maxObservedBodySize {
+			b.capture = false
+			b.buf = bytes.Buffer{}
+		}
+	}
+	return n, err
+}
+
+// scanEvents consumes the complete lines in the buffer, keeping the last usage reported.
+func (b *usageObserverBody) scanEvents() {
+	for {
+		line, err := b.buf.ReadBytes('\n')
+		if err != nil {
+			rest := append([]byte(nil), line...)
+			b.buf.Reset()
+			b.buf.Write(rest)
+			return
+		}
+		data := bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(line), []byte("data:")))
+		if bytes.Contains(data, []byte(`"usage"`)) || (b.model == "" && bytes.Contains(data, []byte(`"model"`))) {
+			b.decode(data)
+		}
+	}
+}
+
+func (b *usageObserverBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		if b.capture && !b.stream {
+			b.decode(b.buf.Bytes())
+		}
+		b.done(b.model, b.usage)
+	})
+	return err
+}
+
+// observedUsage is the model and the usage of a response, a chunk or a Responses API event,
+// in the Chat Completions or the Responses API format.
+type observedUsage struct {
+	Model string `json:"model"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		InputTokens      int `json:"input_tokens"`
+		OutputTokens     int `json:"output_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+		// PromptTokensDetails and InputTokensDetails are the Chat Completions and Responses API
+		// names of the breakdown of the prompt tokens.
+		PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details"`
+		InputTokensDetails  *PromptTokensDetails `json:"input_tokens_details"`
+	} `json:"usage"`
+	Response *observedUsage `json:"response"`
+}
+
+func (b *usageObserverBody) decode(data []byte) {
+	var observed observedUsage
+	if json.Unmarshal(data, &observed) != nil {
+		return
+	}
+	if observed.Response != nil {
+		observed = *observed.Response
+	}
+	if observed.Model != "" {
+		b.model = observed.Model
+	}
+	if usage := observed.Usage; usage != nil {
+		b.usage = Usage{
+			PromptTokens:     usage.PromptTokens + usage.InputTokens,
+			CompletionTokens: usage.CompletionTokens + usage.OutputTokens,
+			TotalTokens:      usage.TotalTokens,
+		}
+		b.usage.PromptTokensDetails = usage.PromptTokensDetails
+		if b.usage.PromptTokensDetails == nil {
+			b.usage.PromptTokensDetails = usage.InputTokensDetails
+		}
+		if b.usage.TotalTokens == 0 {
+			b.usage.TotalTokens = b.usage.PromptTokens + b.usage.CompletionTokens
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package openai_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+func TestAdminGetUsage(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/organization/usage/completions", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("start_time") != "1730419200" || query.Get("bucket_width") != "1h" ||
+			query.Get("models[]") != openai.GPT4o || query.Get("batch") != "false" || query.Get("page") != "page_1" {
+			t.Errorf("unexpected usage query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, `{"object":"page","data":[{"object":"bucket","start_time":1730419200,"end_time":1730422800,`+
+			`"results":[{"object":"organization.usage.completions.result","input_tokens":1000,"output_tokens":500,`+
+			`"num_model_requests":5,"model":"gpt-4o"}]}],"has_more":true,"next_page":"page_2"}`)
+	})
+
+	batch := false
+	usage, err := client.Admin().GetUsage(context.Background(), openai.UsageTypeCompletions, openai.UsageRequest{
+		StartTime:   1730419200,
+		BucketWidth: openai.UsageBucketWidthHour,
+		Models:      []string{openai.GPT4o},
+		Batch:       &batch,
+		Page:        "page_1",
+	})
+	checks.NoError(t, err, "GetUsage error")
+	if !usage.HasMore || usage.NextPage != "page_2" || len(usage.Data) != 1 {
+		t.Fatalf("unexpected usage page: %+v", usage)
+	}
+	result := usage.Data[0].Results[0]
+	if result.InputTokens != 1000 || result.NumModelRequests != 5 || *result.Model != openai.GPT4o {
+		t.Errorf("unexpected usage result: %+v", result)
+	}
+}
+
+func TestAdminGetCosts(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+
+	server.RegisterHandler("/v1/organization/costs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bucket_width") != "1d" || r.URL.Query().Get("group_by[]") != "line_item" {
+			t.Errorf("unexpected costs query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, `{"object":"page","data":[`+
+			`{"object":"bucket","start_time":1,"end_time":2,"results":[`+
+			`{"object":"organization.costs.result","amount":{"value":0.5,"currency":"usd"},"line_item":"gpt-4o, input"}]},`+
+			`{"object":"bucket","start_time":2,"end_time":3,"results":[`+
+			`{"object":"organization.costs.result","amount":{"value":0.25,"currency":"usd"}}]}],"has_more":false}`)
+	})
+
+	costs, err := client.Admin().GetCosts(context.Background(), openai.CostsRequest{
+		StartTime: 1,
+		GroupBy:   []string{"line_item"},
+	})
+	checks.NoError(t, err, "GetCosts error")
+	if total := costs.TotalCost(); total.Value != 0.75 || total.Currency != "usd" {
+		t.Errorf("unexpected total cost: %+v", total)
+	}
+	if *costs.Data[0].Results[0].LineItem != "gpt-4o, input" {
+		t.Errorf("unexpected line item: %+v", costs.Data[0].Results[0])
+	}
+}
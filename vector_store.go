@@ -67,18 +67,57 @@ type VectorStoreDeleteResponse struct {
 }
 
 type VectorStoreFile struct {
-	ID            string `json:"id"`
-	Object        string `json:"object"`
-	CreatedAt     int64  `json:"created_at"`
-	VectorStoreID string `json:"vector_store_id"`
-	UsageBytes    int    `json:"usage_bytes"`
-	Status        string `json:"status"`
+	ID            string         `json:"id"`
+	Object        string         `json:"object"`
+	CreatedAt     int64          `json:"created_at"`
+	VectorStoreID string         `json:"vector_store_id"`
+	UsageBytes    int            `json:"usage_bytes"`
+	Status        string         `json:"status"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
 
 	httpHeader
 }
 
 type VectorStoreFileRequest struct {
 	FileID string `json:"file_id"`
+	// Attributes are the attributes of the file matched by the filters of searches.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// VectorStoreSearchRequest searches the chunks of the files of a vector store.
+type VectorStoreSearchRequest struct {
+	Query string `json:"query"`
+	// MaxNumResults is the maximum number of results, between 1 and 50. Defaults to 10.
+	MaxNumResults  int                       `json:"max_num_results,omitempty"`
+	Filters        *FileSearchFilter         `json:"filters,omitempty"`
+	RankingOptions *FileSearchRankingOptions `json:"ranking_options,omitempty"`
+	// RewriteQuery rewrites the query for vector search.
+	RewriteQuery bool `json:"rewrite_query,omitempty"`
+}
+
+// VectorStoreSearchResult is a chunk of a file found by SearchVectorStore.
+type VectorStoreSearchResult struct {
+	FileID     string                     `json:"file_id"`
+	Filename   string                     `json:"filename"`
+	Score      float64                    `json:"score"`
+	Attributes map[string]any             `json:"attributes,omitempty"`
+	Content    []VectorStoreSearchContent `json:"content"`
+}
+
+type VectorStoreSearchContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// VectorStoreSearchResponse lists the results of a search, best score first.
+type VectorStoreSearchResponse struct {
+	Object      string                    `json:"object"`
+	SearchQuery []string                  `json:"search_query"`
+	Data        []VectorStoreSearchResult `json:"data"`
+	HasMore     bool                      `json:"has_more"`
+	NextPage    *string                   `json:"next_page"`
+
+	httpHeader
 }
 
 type VectorStoreFilesList struct {
@@ -235,6 +274,43 @@ func (c *Client) DeleteVectorStoreFile(
 	return
 }
 
+// AddVectorStoreText uploads text as a file named filename and adds it to a vector store. The
+// file is chunked and embedded asynchronously: it is searchable once its status is completed.
+func (c *Client) AddVectorStoreText(
+	ctx context.Context,
+	vectorStoreID, filename, text string,
+	attributes map[string]any,
+) (response VectorStoreFile, err error) {
+	file, err := c.CreateFileBytes(ctx, FileBytesRequest{
+		Name:    filename,
+		Bytes:   []byte(text),
+		Purpose: PurposeAssistants,
+	})
+	if err != nil {
+		return
+	}
+	return c.CreateVectorStoreFile(ctx, vectorStoreID, VectorStoreFileRequest{
+		FileID:     file.ID,
+		Attributes: attributes,
+	})
+}
+
+// SearchVectorStore searches the chunks of the files of a vector store relevant to a query.
+func (c *Client) SearchVectorStore(
+	ctx context.Context,
+	vectorStoreID string,
+	request VectorStoreSearchRequest,
+) (response VectorStoreSearchResponse, err error) {
+	urlSuffix := fmt.Sprintf("%s/%s/search", vectorStoresSuffix, vectorStoreID)
+	req, err := c.newRequest(ctx, http.MethodPost, c.fullURL(urlSuffix), withBody(request))
+	if err != nil {
+		return
+	}
+
+	err = c.sendRequest(req, &response)
+	return
+}
+
 // ListVectorStoreFiles Lists the currently available files for a vector store.
 func (c *Client) ListVectorStoreFiles(
 	ctx context.Context,
@@ -0,0 +1,454 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownVectorStore is returned by LocalVectorStore for a vector store it does not have.
+	ErrUnknownVectorStore = errors.New("unknown vector store")
+	// ErrUnknownVectorStoreFile is returned by LocalVectorStore for a file it does not have.
+	ErrUnknownVectorStoreFile = errors.New("unknown vector store file")
+)
+
+// VectorIndex is the retrieval interface shared by Client, backed by the hosted Vector Stores
+// API, and LocalVectorStore, so prototypes can switch between local and hosted retrieval.
+type VectorIndex interface {
+	CreateVectorStore(ctx context.Context, request VectorStoreRequest) (VectorStore, error)
+	AddVectorStoreText(
+		ctx context.Context,
+		vectorStoreID, filename, text string,
+		attributes map[string]any,
+	) (VectorStoreFile, error)
+	SearchVectorStore(
+		ctx context.Context,
+		vectorStoreID string,
+		request VectorStoreSearchRequest,
+	) (VectorStoreSearchResponse, error)
+	DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error
+}
+
+var (
+	_ VectorIndex = (*Client)(nil)
+	_ VectorIndex = (*LocalVectorStore)(nil)
+)
+
+// Embedder creates embeddings. It is implemented by Client.
+type Embedder interface {
+	CreateEmbeddings(ctx context.Context, conv EmbeddingRequestConverter) (EmbeddingResponse, error)
+}
+
+// LocalVectorStoreOption configures a LocalVectorStore.
+type LocalVectorStoreOption func(*LocalVectorStore)
+
+// WithLocalEmbeddingModel sets the embedding model of a LocalVectorStore, SmallEmbedding3 by
+// default.
+func WithLocalEmbeddingModel(model EmbeddingModel) LocalVectorStoreOption {
+	return func(s *LocalVectorStore) {
+		s.model = model
+	}
+}
+
+// WithLocalTextSplitter sets how a LocalVectorStore chunks texts. The default splitter makes
+// chunks of up to 3200 characters overlapping by up to 1600, about the 800 tokens overlapping
+// by 400 of the hosted vector stores.
+func WithLocalTextSplitter(splitter TextSplitter) LocalVectorStoreOption {
+	return func(s *LocalVectorStore) {
+		s.splitter = splitter
+	}
+}
+
+// localVectorStoreEmbeddingBatch is the number of chunks embedded per request.
+const localVectorStoreEmbeddingBatch = 256
+
+// LocalVectorStore is an in-process VectorIndex: texts are chunked and embedded with an
+// Embedder, and searched exactly by cosine similarity. Its content can be saved to and loaded
+// from a file. It is safe for concurrent use.
+type LocalVectorStore struct {
+	embedder Embedder
+	model    EmbeddingModel
+	splitter TextSplitter
+
+	mu     sync.RWMutex
+	data   localVectorStoreData
+	stores map[string]*localVectorStoreEntry
+}
+
+// localVectorStoreData is the persisted content of a LocalVectorStore.
+type localVectorStoreData struct {
+	NextID int                      `json:"next_id"`
+	Stores []*localVectorStoreEntry `json:"stores"`
+}
+
+type localVectorStoreEntry struct {
+	Store VectorStore             `json:"store"`
+	Files []*localVectorStoreFile `json:"files"`
+}
+
+type localVectorStoreFile struct {
+	File     VectorStoreFile         `json:"file"`
+	Filename string                  `json:"filename"`
+	Chunks   []localVectorStoreChunk `json:"chunks"`
+}
+
+type localVectorStoreChunk struct {
+	Text   string    `json:"text"`
+	Vector []float32 `json:"vector"`
+}
+
+// NewLocalVectorStore returns an empty LocalVectorStore embedding with embedder, typically a
+// Client.
+func NewLocalVectorStore(embedder Embedder, opts ...LocalVectorStoreOption) *LocalVectorStore {
+	s := &LocalVectorStore{
+		embedder: embedder,
+		model:    SmallEmbedding3,
+		splitter: TextSplitter{ChunkSize: 3200, ChunkOverlap: 1600},
+		stores:   make(map[string]*localVectorStoreEntry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *LocalVectorStore) nextID(prefix string) string {
+	s.data.NextID++
+	return fmt.Sprintf("%s_local_%d", prefix, s.data.NextID)
+}
+
+// CreateVectorStore creates an empty vector store. FileIDs and ExpiresAfter are ignored.
+func (s *LocalVectorStore) CreateVectorStore(_ context.Context, request VectorStoreRequest) (VectorStore, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &localVectorStoreEntry{Store: VectorStore{
+		ID:        s.nextID("vs"),
+		Object:    "vector_store",
+		CreatedAt: time.Now().Unix(),
+		Name:      request.Name,
+		Status:    "completed",
+		Metadata:  request.Metadata,
+	}}
+	s.data.Stores = append(s.data.Stores, entry)
+	s.stores[entry.Store.ID] = entry
+	return entry.Store, nil
+}
+
+// AddVectorStoreText chunks and embeds text, and adds it to a vector store as a file named
+// filename. Unlike with the hosted API, the file is searchable on return.
+func (s *LocalVectorStore) AddVectorStoreText(
+	ctx context.Context,
+	vectorStoreID, filename, text string,
+	attributes map[string]any,
+) (VectorStoreFile, error) {
+	s.mu.RLock()
+	_, ok := s.stores[vectorStoreID]
+	s.mu.RUnlock()
+	if !ok {
+		return VectorStoreFile{}, fmt.Errorf("%w: %s", ErrUnknownVectorStore, vectorStoreID)
+	}
+
+	texts, err := s.splitter.Split(text)
+	if err != nil {
+		return VectorStoreFile{}, err
+	}
+	chunks := make([]localVectorStoreChunk, 0, len(texts))
+	for start := 0; start < len(texts); start += localVectorStoreEmbeddingBatch {
+		end := start + localVectorStoreEmbeddingBatch
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, embedErr := s.embed(ctx, texts[start:end])
+		if embedErr != nil {
+			return VectorStoreFile{}, embedErr
+		}
+		for i, vector := range vectors {
+			chunks = append(chunks, localVectorStoreChunk{Text: texts[start+i], Vector: vector})
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.stores[vectorStoreID]
+	if !ok {
+		return VectorStoreFile{}, fmt.Errorf("%w: %s", ErrUnknownVectorStore, vectorStoreID)
+	}
+	file := &localVectorStoreFile{
+		File: VectorStoreFile{
+			ID:            s.nextID("file"),
+			Object:        "vector_store.file",
+			CreatedAt:     time.Now().Unix(),
+			VectorStoreID: vectorStoreID,
+			UsageBytes:    len(text),
+			Status:        "completed",
+			Attributes:    attributes,
+		},
+		Filename: filename,
+		Chunks:   chunks,
+	}
+	entry.Files = append(entry.Files, file)
+	entry.Store.UsageBytes += len(text)
+	entry.Store.FileCounts.Completed++
+	entry.Store.FileCounts.Total++
+	return file.File, nil
+}
+
+// embed returns the embeddings of texts, in order.
+func (s *LocalVectorStore) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	response, err := s.embedder.CreateEmbeddings(ctx, EmbeddingRequest{Input: texts, Model: s.model})
+	if err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for _, embedding := range response.Data {
+		if embedding.Index >= 0 && embedding.Index < len(vectors) {
+			vectors[embedding.Index] = embedding.Embedding
+		}
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("missing embedding %d of %d", i, len(texts))
+		}
+	}
+	return vectors, nil
+}
+
+// SearchVectorStore returns the chunks most similar to the query, best score first, among the
+// files matching the filters of request. The query is never rewritten.
+func (s *LocalVectorStore) SearchVectorStore(
+	ctx context.Context,
+	vectorStoreID string,
+	request VectorStoreSearchRequest,
+) (VectorStoreSearchResponse, error) {
+	s.mu.RLock()
+	_, ok := s.stores[vectorStoreID]
+	s.mu.RUnlock()
+	if !ok {
+		return VectorStoreSearchResponse{}, fmt.Errorf("%w: %s", ErrUnknownVectorStore, vectorStoreID)
+	}
+	vectors, err := s.embed(ctx, []string{request.Query})
+	if err != nil {
+		return VectorStoreSearchResponse{}, err
+	}
+
+	response := VectorStoreSearchResponse{
+		Object:      "vector_store.search_results.page",
+		SearchQuery: []string{request.Query},
+		Data:        []VectorStoreSearchResult{},
+	}
+	var threshold float64
+	if request.RankingOptions != nil {
+		threshold = request.RankingOptions.ScoreThreshold
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.stores[vectorStoreID]
+	if !ok {
+		return VectorStoreSearchResponse{}, fmt.Errorf("%w: %s", ErrUnknownVectorStore, vectorStoreID)
+	}
+	for _, file := range entry.Files {
+		if request.Filters != nil && !request.Filters.matches(file.File.Attributes) {
+			continue
+		}
+		for _, chunk := range file.Chunks {
+			score, scoreErr := CosineSimilarity(vectors[0], chunk.Vector)
+			if scoreErr != nil {
+				return VectorStoreSearchResponse{}, scoreErr
+			}
+			if float64(score) < threshold {
+				continue
+			}
+			response.Data = append(response.Data, VectorStoreSearchResult{
+				FileID:     file.File.ID,
+				Filename:   file.Filename,
+				Score:      float64(score),
+				Attributes: file.File.Attributes,
+				Content:    []VectorStoreSearchContent{{Type: "text", Text: chunk.Text}},
+			})
+		}
+	}
+	sort.SliceStable(response.Data, func(i, j int) bool {
+		return response.Data[i].Score > response.Data[j].Score
+	})
+	maxResults := request.MaxNumResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	if len(response.Data) > maxResults {
+		response.Data = response.Data[:maxResults]
+	}
+	return response, nil
+}
+
+// DeleteVectorStoreFile removes a file from a vector store.
+func (s *LocalVectorStore) DeleteVectorStoreFile(_ context.Context, vectorStoreID, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.stores[vectorStoreID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownVectorStore, vectorStoreID)
+	}
+	for i, file := range entry.Files {
+		if file.File.ID == fileID {
+			entry.Files = append(entry.Files[:i], entry.Files[i+1:]...)
+			entry.Store.UsageBytes -= file.File.UsageBytes
+			entry.Store.FileCounts.Completed--
+			entry.Store.FileCounts.Total--
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownVectorStoreFile, fileID)
+}
+
+// Save writes the vector stores, including the embeddings of their chunks, to a JSON file.
+// The file is replaced atomically.
+func (s *LocalVectorStore) Save(path string) error {
+	s.mu.RLock()
+	data, err := json.Marshal(s.data)
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load replaces the vector stores with those saved to path by Save.
+func (s *LocalVectorStore) Load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var data localVectorStoreData
+	if err = json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("loading vector stores: %w", err)
+	}
+
+	stores := make(map[string]*localVectorStoreEntry, len(data.Stores))
+	for _, entry := range data.Stores {
+		stores[entry.Store.ID] = entry
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	s.stores = stores
+	return nil
+}
+
+// matches reports whether attributes match the filter, as the hosted file search does.
+func (f *FileSearchFilter) matches(attributes map[string]any) bool {
+	switch f.Type {
+	case FileSearchFilterAnd:
+		for i := range f.Filters {
+			if !f.Filters[i].matches(attributes) {
+				return false
+			}
+		}
+		return true
+	case FileSearchFilterOr:
+		for i := range f.Filters {
+			if f.Filters[i].matches(attributes) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := attributes[f.Key]
+	if !ok {
+		return f.Type == FileSearchFilterNe
+	}
+	comparison, comparable := compareAttributes(value, f.Value)
+	switch f.Type {
+	case FileSearchFilterEq:
+		return comparable && comparison == 0
+	case FileSearchFilterNe:
+		return !comparable || comparison != 0
+	case FileSearchFilterGt:
+		return comparable && comparison > 0
+	case FileSearchFilterGte:
+		return comparable && comparison >= 0
+	case FileSearchFilterLt:
+		return comparable && comparison < 0
+	case FileSearchFilterLte:
+		return comparable && comparison <= 0
+	}
+	return false
+}
+
+// compareAttributes compares two attribute values of the same kind: strings, numbers of any
+// type, or booleans, which are only comparable when equal.
+func compareAttributes(a, b any) (int, bool) {
+	if x, ok := attributeNumber(a); ok {
+		y, ok := attributeNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		return 0, x == y
+	}
+	return 0, false
+}
+
+func attributeNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,157 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/internal/test/checks"
+)
+
+// keywordEmbedder embeds texts as the counts of a few keywords.
+type keywordEmbedder struct {
+	calls int
+}
+
+var embedderKeywords = []string{"cat", "dog", "fish"}
+
+func (e *keywordEmbedder) CreateEmbeddings(
+	_ context.Context,
+	conv openai.EmbeddingRequestConverter,
+) (openai.EmbeddingResponse, error) {
+	e.calls++
+	var response openai.EmbeddingResponse
+	for i, text := range conv.Convert().Input.([]string) {
+		vector := make([]float32, len(embedderKeywords))
+		for j, keyword := range embedderKeywords {
+			vector[j] = float32(strings.Count(strings.ToLower(text), keyword))
+		}
+		response.Data = append(response.Data, openai.Embedding{Index: i, Embedding: vector})
+	}
+	return response, nil
+}
+
+func TestLocalVectorStore(t *testing.T) {
+	ctx := context.Background()
+	embedder := &keywordEmbedder{}
+	var index openai.VectorIndex = openai.NewLocalVectorStore(embedder,
+		openai.WithLocalTextSplitter(openai.TextSplitter{ChunkSize: 40}))
+
+	store, err := index.CreateVectorStore(ctx, openai.VectorStoreRequest{Name: "pets"})
+	checks.NoError(t, err, "CreateVectorStore error")
+	cats, err := index.AddVectorStoreText(ctx, store.ID, "cats.txt",
+		"The cat sleeps all day.\n\nA dog barks at the cat.", map[string]any{"year": 2024, "kind": "cat"})
+	checks.NoError(t, err, "AddVectorStoreText error")
+	_, err = index.AddVectorStoreText(ctx, store.ID, "fish.txt", "The fish swims.", map[string]any{"year": 2020})
+	checks.NoError(t, err, "AddVectorStoreText error")
+	if cats.Status != "completed" || cats.VectorStoreID != store.ID {
+		t.Errorf("unexpected file %+v", cats)
+	}
+
+	results, err := index.SearchVectorStore(ctx, store.ID, openai.VectorStoreSearchRequest{Query: "cat"})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 3 || results.Data[0].Content[0].Text != "The cat sleeps all day." ||
+		results.Data[0].Filename != "cats.txt" || results.Data[0].Score < 0.99 {
+		t.Errorf("unexpected results %+v", results.Data)
+	}
+
+	results, err = index.SearchVectorStore(ctx, store.ID, openai.VectorStoreSearchRequest{
+		Query:          "cat",
+		MaxNumResults:  5,
+		RankingOptions: &openai.FileSearchRankingOptions{ScoreThreshold: 0.5},
+	})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 2 {
+		t.Errorf("expected the fish chunk under the threshold, got %+v", results.Data)
+	}
+
+	filter := openai.FileSearchAnd(
+		openai.FileSearchCompare("year", openai.FileSearchFilterLt, 2022),
+		openai.FileSearchCompare("kind", openai.FileSearchFilterNe, "cat"),
+	)
+	results, err = index.SearchVectorStore(ctx, store.ID, openai.VectorStoreSearchRequest{Query: "cat", Filters: &filter})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 1 || results.Data[0].Filename != "fish.txt" {
+		t.Errorf("unexpected filtered results %+v", results.Data)
+	}
+
+	checks.NoError(t, index.DeleteVectorStoreFile(ctx, store.ID, cats.ID), "DeleteVectorStoreFile error")
+	results, err = index.SearchVectorStore(ctx, store.ID, openai.VectorStoreSearchRequest{Query: "cat"})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 1 {
+		t.Errorf("expected the deleted file to be gone, got %+v", results.Data)
+	}
+
+	err = index.DeleteVectorStoreFile(ctx, store.ID, cats.ID)
+	if !errors.Is(err, openai.ErrUnknownVectorStoreFile) {
+		t.Errorf("expected ErrUnknownVectorStoreFile, got %v", err)
+	}
+	_, err = index.SearchVectorStore(ctx, "vs_missing", openai.VectorStoreSearchRequest{Query: "cat"})
+	if !errors.Is(err, openai.ErrUnknownVectorStore) {
+		t.Errorf("expected ErrUnknownVectorStore, got %v", err)
+	}
+}
+
+func TestLocalVectorStoreSaveLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.json")
+	saved := openai.NewLocalVectorStore(&keywordEmbedder{})
+	store, err := saved.CreateVectorStore(ctx, openai.VectorStoreRequest{Name: "pets"})
+	checks.NoError(t, err, "CreateVectorStore error")
+	_, err = saved.AddVectorStoreText(ctx, store.ID, "dogs.txt", "A dog and another dog.", nil)
+	checks.NoError(t, err, "AddVectorStoreText error")
+	checks.NoError(t, saved.Save(path), "Save error")
+
+	embedder := &keywordEmbedder{}
+	loaded := openai.NewLocalVectorStore(embedder)
+	checks.NoError(t, loaded.Load(path), "Load error")
+	results, err := loaded.SearchVectorStore(ctx, store.ID, openai.VectorStoreSearchRequest{Query: "dog"})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 1 || results.Data[0].Content[0].Text != "A dog and another dog." {
+		t.Errorf("unexpected results %+v", results.Data)
+	}
+	if embedder.calls != 1 {
+		t.Errorf("expected only the query to be embedded, got %d calls", embedder.calls)
+	}
+
+	other, err := loaded.CreateVectorStore(ctx, openai.VectorStoreRequest{})
+	checks.NoError(t, err, "CreateVectorStore error")
+	if other.ID == store.ID {
+		t.Errorf("expected a new vector store ID, got %q", other.ID)
+	}
+	checks.HasError(t, loaded.Load(filepath.Join(t.TempDir(), "missing.json")), "expected an error for a missing file")
+}
+
+func TestSearchVectorStore(t *testing.T) {
+	client, server, teardown := setupOpenAITestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/files", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"file-1","object":"file","purpose":"assistants"}`)
+	})
+	server.RegisterHandler("/v1/vector_stores/vs_1/files", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":"file-1","object":"vector_store.file","vector_store_id":"vs_1",`+
+			`"status":"in_progress","attributes":{"kind":"cat"}}`)
+	})
+	server.RegisterHandler("/v1/vector_stores/vs_1/search", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"object":"vector_store.search_results.page","search_query":["cat"],"data":[`+
+			`{"file_id":"file-1","filename":"cats.txt","score":0.8,"content":[{"type":"text","text":"The cat"}]}],`+
+			`"has_more":false,"next_page":null}`)
+	})
+
+	ctx := context.Background()
+	file, err := client.AddVectorStoreText(ctx, "vs_1", "cats.txt", "The cat", map[string]any{"kind": "cat"})
+	checks.NoError(t, err, "AddVectorStoreText error")
+	if file.ID != "file-1" || file.Attributes["kind"] != "cat" {
+		t.Errorf("unexpected file %+v", file)
+	}
+	results, err := client.SearchVectorStore(ctx, "vs_1", openai.VectorStoreSearchRequest{Query: "cat"})
+	checks.NoError(t, err, "SearchVectorStore error")
+	if len(results.Data) != 1 || results.Data[0].Content[0].Text != "The cat" {
+		t.Errorf("unexpected results %+v", results.Data)
+	}
+}